@@ -151,6 +151,12 @@ func initCommands(
 			}, nil
 		},
 
+		"drift": func() (cli.Command, error) {
+			return &command.DriftCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"env": func() (cli.Command, error) {
 			return &command.WorkspaceCommand{
 				Meta:       meta,
@@ -367,16 +373,52 @@ func initCommands(
 		// Plumbing
 		// -----------------------------------------------------------
 
+		"encryption": func() (cli.Command, error) {
+			return &command.EncryptionCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"encryption inspect": func() (cli.Command, error) {
+			return &command.EncryptionInspectCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"force-unlock": func() (cli.Command, error) {
 			return &command.UnlockCommand{
 				Meta: meta,
 			}, nil
 		},
 
+		"lock": func() (cli.Command, error) {
+			return &command.LockCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"lock status": func() (cli.Command, error) {
+			return &command.LockStatusCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"state": func() (cli.Command, error) {
 			return &command.StateCommand{}, nil
 		},
 
+		"state diff": func() (cli.Command, error) {
+			return &command.StateDiffCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"state history": func() (cli.Command, error) {
+			return &command.StateHistoryCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"state list": func() (cli.Command, error) {
 			return &command.StateListCommand{
 				Meta: meta,
@@ -391,6 +433,14 @@ func initCommands(
 			}, nil
 		},
 
+		"state rollback": func() (cli.Command, error) {
+			return &command.StateRollbackCommand{
+				StateMeta: command.StateMeta{
+					Meta: meta,
+				},
+			}, nil
+		},
+
 		"state rm": func() (cli.Command, error) {
 			return &command.StateRmCommand{
 				StateMeta: command.StateMeta{
@@ -439,6 +489,14 @@ func initCommands(
 			}, nil
 		},
 
+		"state rekey": func() (cli.Command, error) {
+			return &command.StateRekeyCommand{
+				StateMeta: command.StateMeta{
+					Meta: meta,
+				},
+			}, nil
+		},
+
 		"state show": func() (cli.Command, error) {
 			return &command.StateShowCommand{
 				Meta: meta,
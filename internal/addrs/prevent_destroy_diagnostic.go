@@ -0,0 +1,42 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package addrs
+
+import "github.com/opentofu/opentofu/internal/tfdiags"
+
+// DiagnosticExtraPreventDestroy provides an interface for diagnostic ExtraInfo
+// to retrieve the resource instance whose prevent_destroy setting blocked a
+// plan from within a tfdiags.Diagnostic.
+type DiagnosticExtraPreventDestroy interface {
+	// DiagnosticOriginatesFromPreventDestroy returns the resource instance
+	// that the surrounding diagnostic refused to destroy.
+	DiagnosticOriginatesFromPreventDestroy() AbsResourceInstance
+}
+
+// DiagnosticOriginatesFromPreventDestroy checks if the provided diagnostic
+// was raised by the prevent_destroy setting of a resource and returns the
+// address of the protected resource instance and true if it was. This function
+// returns an empty address and false otherwise.
+func DiagnosticOriginatesFromPreventDestroy(diag tfdiags.Diagnostic) (AbsResourceInstance, bool) {
+	maybe := tfdiags.ExtraInfo[DiagnosticExtraPreventDestroy](diag)
+	if maybe == nil {
+		return AbsResourceInstance{}, false
+	}
+	return maybe.DiagnosticOriginatesFromPreventDestroy(), true
+}
+
+// PreventDestroyDiagnosticExtra is an object that can be attached to
+// diagnostics raised because a resource instance with prevent_destroy set was
+// planned for destruction.
+type PreventDestroyDiagnosticExtra struct {
+	Resource AbsResourceInstance
+}
+
+var _ DiagnosticExtraPreventDestroy = (*PreventDestroyDiagnosticExtra)(nil)
+
+func (p *PreventDestroyDiagnosticExtra) DiagnosticOriginatesFromPreventDestroy() AbsResourceInstance {
+	return p.Resource
+}
@@ -293,6 +293,14 @@ type Operation struct {
 	// plan and apply arguments but may not work for all backends.
 	PlanFile *planfile.WrappedPlanFile
 
+	// ApplyProgressPath is the path of the file that records which of the
+	// changes in PlanFile were completed when applying it fails partway
+	// through. ResumeApply resumes that failed apply, applying only the
+	// changes that weren't completed. These are valid only for apply
+	// operations of a local plan file, and may not work for all backends.
+	ApplyProgressPath string
+	ResumeApply       bool
+
 	// The options below are more self-explanatory and affect the runtime
 	// behavior of the operation.
 	PlanMode     plans.Mode
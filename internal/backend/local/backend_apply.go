@@ -136,6 +136,7 @@ func (b *Local) opApply(
 	stateHook.PersistInterval = time.Duration(persistInterval) * time.Second
 
 	var plan *plans.Plan
+	var resumedFrom []addrs.AbsResourceInstance
 	// If we weren't given a plan, then we refresh/plan
 	if op.PlanFile == nil {
 		// Perform the plan
@@ -268,6 +269,19 @@ func (b *Local) opApply(
 			op.ReportResult(runningOp, diags)
 			return
 		}
+		if op.ResumeApply {
+			// We'll apply only the changes which the earlier, failed, apply
+			// didn't complete, starting from the state it left behind.
+			var resumeDiags tfdiags.Diagnostics
+			resumedFrom, plan, resumeDiags = b.resumePlan(ctx, op, lr, opState)
+			diags = diags.Append(resumeDiags)
+			if resumeDiags.HasErrors() {
+				op.ReportResult(runningOp, diags)
+				return
+			}
+			lr.InputState = plan.PriorState
+			runningOp.State = lr.InputState
+		}
 		for _, change := range plan.Changes.Resources {
 			if change.Action != plans.NoOp {
 				op.View.PlannedChange(change)
@@ -278,6 +292,14 @@ func (b *Local) opApply(
 	// Set up our hook for continuous state updates
 	stateHook.StateMgr = opState
 
+	// When applying a saved plan, we'll keep track of which changes were
+	// completed so that a failed apply can be resumed later.
+	var progress *tofu.ApplyProgress
+	if op.PlanFile != nil && op.ApplyProgressPath != "" && lr.ApplyOpts != nil {
+		progress = &tofu.ApplyProgress{}
+		lr.ApplyOpts.Progress = progress
+	}
+
 	// Start to apply in a goroutine so that we can be interrupted.
 	var applyState *states.State
 	var applyDiags tfdiags.Diagnostics
@@ -291,6 +313,12 @@ func (b *Local) opApply(
 	}()
 
 	if b.opWait(doneCh, stopCtx, cancelCtx, lr.Core, opState, op.View) {
+		if progress != nil {
+			err := writeCanceledApplyProgress(op.ApplyProgressPath, stateHook, opState, schemas, func() []addrs.AbsResourceInstance {
+				return mergeCompleted(resumedFrom, progress.Current())
+			})
+			op.View.Diagnostics(diags.Append(applyProgressDiagnostic(op.ApplyProgressPath, err)))
+		}
 		return
 	}
 	diags = diags.Append(applyDiags)
@@ -320,6 +348,15 @@ func (b *Local) opApply(
 		return
 	}
 
+	if progress != nil {
+		if applyDiags.HasErrors() {
+			err = writeApplyProgress(op.ApplyProgressPath, opState, mergeCompleted(resumedFrom, progress.Completed))
+			diags = diags.Append(applyProgressDiagnostic(op.ApplyProgressPath, err))
+		} else if err := removeApplyProgress(op.ApplyProgressPath); err != nil {
+			diags = diags.Append(applyProgressDiagnostic(op.ApplyProgressPath, err))
+		}
+	}
+
 	if applyDiags.HasErrors() {
 		op.ReportResult(runningOp, diags)
 		return
@@ -0,0 +1,205 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+const applyProgressFormatVersion = "1.0"
+
+// applyProgressRecord is the content of the file at the ApplyProgressPath of
+// an operation, written when applying a saved plan fails partway through.
+type applyProgressRecord struct {
+	FormatVersion string `json:"format_version"`
+
+	// Lineage and Serial identify the state snapshot written after the
+	// failed apply. A resumed apply must start from exactly that snapshot.
+	Lineage string `json:"lineage,omitempty"`
+	Serial  uint64 `json:"serial,omitempty"`
+
+	// Completed lists the addresses of the resource instances whose planned
+	// changes were applied.
+	Completed []string `json:"completed"`
+}
+
+// readApplyProgress reads the apply progress record at the given path.
+func readApplyProgress(path string) (*applyProgressRecord, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var record applyProgressRecord
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return nil, fmt.Errorf("invalid apply progress record: %w", err)
+	}
+	if record.FormatVersion != applyProgressFormatVersion {
+		return nil, fmt.Errorf("unsupported apply progress record format version %q", record.FormatVersion)
+	}
+	return &record, nil
+}
+
+// writeApplyProgress records the given completed resource instances, along
+// with the metadata of the state snapshot that was just persisted by the
+// given state manager, at the given path.
+func writeApplyProgress(path string, stateMgr statemgr.Full, completed []addrs.AbsResourceInstance) error {
+	record := applyProgressRecord{
+		FormatVersion: applyProgressFormatVersion,
+		Completed:     make([]string, 0, len(completed)),
+	}
+	if sm, ok := stateMgr.(statemgr.PersistentMeta); ok {
+		meta := sm.StateSnapshotMeta()
+		record.Lineage = meta.Lineage
+		record.Serial = meta.Serial
+	}
+	for _, addr := range completed {
+		record.Completed = append(record.Completed, addr.String())
+	}
+
+	raw, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, raw, 0600)
+}
+
+// writeCanceledApplyProgress persists the latest state written by the given
+// state hook and records the given completed resource instances along with
+// it, for an apply that was canceled before OpenTofu Core returned.
+//
+// The canceled apply is still running, so the completed resource instances
+// must have been taken from the progress of the apply only after the hook
+// wrote the state that is persisted here. The record can then list a change
+// whose new object didn't reach the state, but that object is lost from the
+// state whether or not the apply is resumed. It can never omit a change
+// whose new object was persisted, which resuming would apply again.
+func writeCanceledApplyProgress(path string, stateHook *StateHook, stateMgr statemgr.Full, schemas *tofu.Schemas, completed func() []addrs.AbsResourceInstance) error {
+	// Holding the lock of the state hook keeps the running apply from
+	// writing any further state updates until the record is written.
+	stateHook.Lock()
+	defer stateHook.Unlock()
+
+	if err := stateMgr.PersistState(context.TODO(), schemas); err != nil {
+		return err
+	}
+	return writeApplyProgress(path, stateMgr, completed())
+}
+
+// applyProgressDiagnostic returns the warning to show after writing the
+// apply progress record at the given path, or after failing to update it
+// with the given error.
+func applyProgressDiagnostic(path string, err error) tfdiags.Diagnostic {
+	if err != nil {
+		return tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Failed to record apply progress",
+			fmt.Sprintf("OpenTofu could not update the record of the changes applied from the saved plan at %s: %s.", path, err),
+		)
+	}
+	return tfdiags.Sourceless(
+		tfdiags.Warning,
+		"Apply can be resumed",
+		"OpenTofu applied only some of the changes in the saved plan. Once the cause of any errors is fixed, you can apply the remaining changes without creating a new plan by running \"tofu apply -resume\" with the same plan file.",
+	)
+}
+
+// removeApplyProgress removes the apply progress record at the given path,
+// if there is one, once the saved plan has been applied completely.
+func removeApplyProgress(path string) error {
+	err := os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// resumePlan prepares the saved plan of the given operation for resuming an
+// earlier apply that failed partway through, returning the previously
+// completed resource instances along with the plan containing the remaining
+// changes, which must be applied to the current state of the given state
+// manager.
+func (b *Local) resumePlan(ctx context.Context, op *backend.Operation, lr *backend.LocalRun, stateMgr statemgr.Full) ([]addrs.AbsResourceInstance, *plans.Plan, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	record, err := readApplyProgress(op.ApplyProgressPath)
+	if err != nil {
+		detail := fmt.Sprintf("Failed to read the progress of the earlier apply from %s: %s.", op.ApplyProgressPath, err)
+		if errors.Is(err, fs.ErrNotExist) {
+			detail = "There is no record of an earlier apply of this plan that failed or was interrupted, so there is nothing to resume. OpenTofu records the progress of an apply of a saved plan only when it fails or is interrupted."
+		}
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Cannot resume apply",
+			detail,
+		))
+		return nil, nil, diags
+	}
+
+	// The saved plan isn't checked against the current state when resuming,
+	// because the failed apply necessarily changed it, so we must be able to
+	// check instead that the state is the one the failed apply left behind.
+	sm, ok := stateMgr.(statemgr.PersistentMeta)
+	if !ok || record.Lineage == "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Cannot resume apply",
+			"OpenTofu can't check that the state wasn't changed by another operation after the earlier apply of this plan failed, because the state storage doesn't report the lineage and serial of its snapshots. Create a new plan instead.",
+		))
+		return nil, nil, diags
+	}
+	meta := sm.StateSnapshotMeta()
+	if meta.Lineage != record.Lineage || meta.Serial != record.Serial {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Cannot resume apply",
+			"The state was changed by another operation after the earlier apply of this plan failed, so the remaining changes can no longer be applied. Create a new plan instead.",
+		))
+		return nil, nil, diags
+	}
+
+	completed := make([]addrs.AbsResourceInstance, 0, len(record.Completed))
+	for _, str := range record.Completed {
+		addr, addrDiags := addrs.ParseAbsResourceInstanceStr(str)
+		diags = diags.Append(addrDiags)
+		if addrDiags.HasErrors() {
+			return nil, nil, diags
+		}
+		completed = append(completed, addr)
+	}
+
+	plan, moreDiags := lr.Core.ResumePlan(ctx, lr.Plan, lr.Config, stateMgr.State(), completed)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return nil, nil, diags
+	}
+	return completed, plan, diags
+}
+
+// mergeCompleted returns the union of the resource instances completed by an
+// earlier apply and those completed by a resumed apply, so that a resumed
+// apply that fails can itself be resumed.
+func mergeCompleted(earlier, completed []addrs.AbsResourceInstance) []addrs.AbsResourceInstance {
+	ret := append([]addrs.AbsResourceInstance(nil), earlier...)
+	seen := addrs.MakeSet(earlier...)
+	for _, addr := range completed {
+		if !seen.Has(addr) {
+			ret = append(ret, addr)
+		}
+	}
+	return ret
+}
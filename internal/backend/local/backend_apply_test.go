@@ -7,6 +7,7 @@ package local
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"os"
 	"path/filepath"
@@ -26,8 +27,10 @@ import (
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/initwd"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/plans/planfile"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/terminal"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -251,6 +254,306 @@ test_instance.foo:
 	}
 }
 
+func TestLocal_applyResume(t *testing.T) {
+	r := testResumableApply(t)
+	b := r.b
+
+	r.fail = false
+	run, output := r.apply(context.Background(), t, true)
+	if run.Result != backend.OperationSuccess {
+		t.Fatalf("resumed operation failed:\n%s", output.Stderr())
+	}
+	if got, want := strings.Join(r.applied, ","), "error"; got != want {
+		t.Fatalf("wrong applied changes %q; want %q", got, want)
+	}
+	if _, err := os.Stat(r.progressPath); !os.IsNotExist(err) {
+		t.Fatalf("apply progress record was not removed: %v", err)
+	}
+
+	checkState(t, b.StateOutPath, `
+test_instance.bar:
+  ID = foo
+  provider = provider["registry.opentofu.org/hashicorp/test"]
+  ami = error
+test_instance.foo:
+  ID = foo
+  provider = provider["registry.opentofu.org/hashicorp/test"]
+  ami = bar
+	`)
+
+	// the backend should be unlocked after a run
+	assertBackendStateUnlocked(t, b)
+}
+
+func TestLocal_applyResumeStaleState(t *testing.T) {
+	r := testResumableApply(t)
+	b := r.b
+
+	// Another operation changes the state after the apply failed.
+	f, err := os.Open(b.StateOutPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stateFile, err := statefile.Read(f, encryption.StateEncryptionDisabled())
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stateFile.Serial++
+	f, err = os.Create(b.StateOutPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = statefile.Write(stateFile, f, encryption.StateEncryptionDisabled())
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.fail = false
+	run, output := r.apply(context.Background(), t, true)
+	if run.Result == backend.OperationSuccess {
+		t.Fatal("resumed operation succeeded; want failure")
+	}
+	if got, want := output.Stderr(), "The state was changed by another operation"; !strings.Contains(got, want) {
+		t.Fatalf("unexpected error output:\n%s\nwant: %s", got, want)
+	}
+	if len(r.applied) != 0 {
+		t.Fatalf("changes were applied to a stale state: %q", r.applied)
+	}
+
+	// the backend should be unlocked after a run
+	assertBackendStateUnlocked(t, b)
+}
+
+func TestLocal_applyResumeNoSnapshotMeta(t *testing.T) {
+	r := testResumableApply(t)
+
+	// The progress record has no lineage when the state manager doesn't
+	// report the metadata of its snapshots, so there's no way to tell whether
+	// the state was changed since.
+	record, err := readApplyProgress(r.progressPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record.Lineage = ""
+	record.Serial = 0
+	raw, err := json.Marshal(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(r.progressPath, raw, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	r.fail = false
+	run, output := r.apply(context.Background(), t, true)
+	if run.Result == backend.OperationSuccess {
+		t.Fatal("resumed operation succeeded; want failure")
+	}
+	if got, want := output.Stderr(), "report the lineage and serial"; !strings.Contains(got, want) {
+		t.Fatalf("unexpected error output:\n%s\nwant: %s", got, want)
+	}
+	if len(r.applied) != 0 {
+		t.Fatalf("changes were applied without checking the state: %q", r.applied)
+	}
+}
+
+func TestLocal_applyResumeInterrupted(t *testing.T) {
+	r := newResumableApply(t, "./testdata/apply-interrupted")
+	b := r.b
+
+	// The apply is interrupted while test_instance.foo is being applied, so
+	// test_instance.bar, which depends on it, is never applied.
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	r.interrupt = stop
+	r.interruptAMI = "bar"
+	run, output := r.apply(ctx, t, false)
+	if run.Result == backend.OperationSuccess {
+		t.Fatal("operation succeeded; want failure")
+	}
+	if got, want := output.Stdout(), "Apply can be resumed"; !strings.Contains(got, want) {
+		t.Fatalf("unexpected output:\n%s\nwant: %s", got, want)
+	}
+	record, err := readApplyProgress(r.progressPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(record.Completed, ","), "test_instance.foo"; got != want {
+		t.Fatalf("wrong completed instances %q; want %q", got, want)
+	}
+
+	r.interrupt = nil
+	r.applied = nil
+	run, output = r.apply(context.Background(), t, true)
+	if run.Result != backend.OperationSuccess {
+		t.Fatalf("resumed operation failed:\n%s", output.Stderr())
+	}
+	if got, want := strings.Join(r.applied, ","), "baz"; got != want {
+		t.Fatalf("wrong applied changes %q; want %q", got, want)
+	}
+	if _, err := os.Stat(r.progressPath); !os.IsNotExist(err) {
+		t.Fatalf("apply progress record was not removed: %v", err)
+	}
+
+	checkState(t, b.StateOutPath, `
+test_instance.bar:
+  ID = foo
+  provider = provider["registry.opentofu.org/hashicorp/test"]
+  ami = baz
+
+  Dependencies:
+    test_instance.foo
+test_instance.foo:
+  ID = foo
+  provider = provider["registry.opentofu.org/hashicorp/test"]
+  ami = bar
+	`)
+
+	// the backend should be unlocked after a run
+	assertBackendStateUnlocked(t, b)
+}
+
+// resumableApply is an apply of a saved plan that stopped partway through,
+// as set up by testResumableApply or testInterruptedApply.
+type resumableApply struct {
+	b            *Local
+	configDir    string
+	planPath     string
+	progressPath string
+
+	lock    sync.Mutex
+	fail    bool
+	applied []string
+
+	// interrupt, if set, is called once the provider has applied the
+	// interruptAMI, which then only completes once OpenTofu Core has asked
+	// the provider to stop.
+	interrupt    func()
+	interruptAMI string
+}
+
+// testResumableApply saves a plan of the apply-error fixture and applies it,
+// with the provider failing to apply the "error" AMI, so that the apply
+// can be resumed.
+func testResumableApply(t *testing.T) *resumableApply {
+	t.Helper()
+
+	r := newResumableApply(t, "./testdata/apply-error")
+	r.fail = true
+
+	run, output := r.apply(context.Background(), t, false)
+	if run.Result == backend.OperationSuccess {
+		t.Fatal("operation succeeded; want failure")
+	}
+	if got, want := output.Stdout(), "Apply can be resumed"; !strings.Contains(got, want) {
+		t.Fatalf("unexpected output:\n%s\nwant: %s", got, want)
+	}
+	record, err := readApplyProgress(r.progressPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(record.Completed, ","), "test_instance.foo"; got != want {
+		t.Fatalf("wrong completed instances %q; want %q", got, want)
+	}
+
+	r.applied = nil
+	return r
+}
+
+// newResumableApply saves a plan of the configuration in the given directory,
+// for a provider that records the AMIs it applies and that fails to apply the
+// "error" AMI while fail is set.
+func newResumableApply(t *testing.T, configDir string) *resumableApply {
+	t.Helper()
+
+	b := TestLocal(t)
+	p := TestLocalProvider(t, b, "test", applyFixtureSchema())
+
+	planPath := filepath.Join(t.TempDir(), "plan.tfplan")
+	r := &resumableApply{
+		b:            b,
+		configDir:    configDir,
+		planPath:     planPath,
+		progressPath: planPath + ".progress",
+	}
+	stopped := make(chan struct{})
+	var stopOnce sync.Once
+	p.StopFn = func() error {
+		stopOnce.Do(func() { close(stopped) })
+		return nil
+	}
+	p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+
+		ami := req.Config.GetAttr("ami").AsString()
+		if r.fail && ami == "error" {
+			return providers.ApplyResourceChangeResponse{
+				NewState:    cty.NullVal(req.PlannedState.Type()),
+				Diagnostics: tfdiags.Diagnostics.Append(nil, errors.New("ami error")),
+			}
+		}
+		r.applied = append(r.applied, ami)
+		if r.interrupt != nil && ami == r.interruptAMI {
+			r.interrupt()
+			<-stopped
+		}
+		return providers.ApplyResourceChangeResponse{
+			NewState: cty.ObjectVal(map[string]cty.Value{
+				"id":  cty.StringVal("foo"),
+				"ami": cty.StringVal(ami),
+			}),
+		}
+	}
+
+	op, done := testOperationPlan(t, configDir)
+	op.PlanRefresh = true
+	op.PlanOutPath = planPath
+	cfg := cty.ObjectVal(map[string]cty.Value{
+		"path": cty.StringVal(b.StatePath),
+	})
+	cfgRaw, err := plans.NewDynamicValue(cfg, cfg.Type())
+	if err != nil {
+		t.Fatal(err)
+	}
+	op.PlanOutBackend = &plans.Backend{
+		Type:   "local",
+		Config: cfgRaw,
+	}
+	run, err := b.Operation(context.Background(), op)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	<-run.Done()
+	if run.Result != backend.OperationSuccess {
+		t.Fatalf("plan operation failed:\n%s", done(t).Stderr())
+	}
+	return r
+}
+
+// apply applies the saved plan, resuming the failed apply if resume is set.
+// Canceling the given context interrupts the apply.
+func (r *resumableApply) apply(ctx context.Context, t *testing.T, resume bool) (*backend.RunningOperation, *terminal.TestOutput) {
+	t.Helper()
+
+	planFile, err := planfile.OpenWrapped(r.planPath, encryption.PlanEncryptionDisabled())
+	if err != nil {
+		t.Fatal(err)
+	}
+	op, done := testOperationApply(t, r.configDir)
+	op.PlanFile = planFile
+	op.ApplyProgressPath = r.progressPath
+	op.ResumeApply = resume
+	run, err := r.b.Operation(ctx, op)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	<-run.Done()
+	return run, done(t)
+}
+
 func TestLocal_applyBackendFail(t *testing.T) {
 	b := TestLocal(t)
 
@@ -387,7 +387,10 @@ func (b *Local) localRunForPlanFile(ctx context.Context, op *backend.Operation,
 				"The given plan file can not be applied because it was created from a different state lineage.",
 			))
 
-		case priorStateFile.Serial != currentStateMeta.Serial:
+		// When resuming an apply that failed, the state has necessarily
+		// changed since the plan was created. The apply operation checks
+		// instead that it's the state the failed apply left behind.
+		case priorStateFile.Serial != currentStateMeta.Serial && !op.ResumeApply:
 			diags = diags.Append(tfdiags.Sourceless(
 				tfdiags.Error,
 				"Saved plan is stale",
@@ -66,11 +66,13 @@ func (b *Backend) StateMgr(_ context.Context, name string) (statemgr.Full, error
 	blobClient := b.containerClient.NewBlockBlobClient(b.path(name))
 
 	client := &RemoteClient{
-		blobClient:   blobClient,
-		snapshot:     b.snapshot,
-		timeout:      b.timeout,
-		cpkInfo:      b.cpkInfo,
-		cpkScopeInfo: b.cpkScopeInfo,
+		blobClient:      blobClient,
+		containerClient: b.containerClient,
+		blobName:        b.path(name),
+		snapshot:        b.snapshot,
+		timeout:         b.timeout,
+		cpkInfo:         b.cpkInfo,
+		cpkScopeInfo:    b.cpkScopeInfo,
 	}
 
 	stateMgr := remote.NewState(client, b.encryption)
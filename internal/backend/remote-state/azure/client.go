@@ -14,12 +14,14 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/lease"
 	"github.com/hashicorp/go-uuid"
 	"github.com/opentofu/opentofu/internal/states/remote"
@@ -28,6 +30,14 @@ import (
 
 const (
 	lockInfoMetaKey = "terraformlockid"
+
+	// The version IDs we return from Versions are prefixed to distinguish
+	// blob snapshots from blob versions, since both are identified by
+	// timestamps. The current blob in a container without versioning
+	// enabled has neither, and so is identified by currentVersionID.
+	snapshotVersionPrefix = "snapshot:"
+	blobVersionPrefix     = "version:"
+	currentVersionID      = "current"
 )
 
 type RemoteClient struct {
@@ -37,6 +47,11 @@ type RemoteClient struct {
 	timeout      time.Duration
 	cpkInfo      *blob.CPKInfo
 	cpkScopeInfo *blob.CPKScopeInfo
+
+	// containerClient and blobName are used to list the snapshots and
+	// versions of the blob, which the blob client alone cannot do.
+	containerClient azureClient
+	blobName        string
 }
 
 func (c *RemoteClient) Get(ctx context.Context) (*remote.Payload, error) {
@@ -120,6 +135,106 @@ func (c *RemoteClient) Delete(ctx context.Context) error {
 	return nil
 }
 
+// Versions lists the blob snapshots created when the backend's snapshot
+// option is enabled, along with any blob versions retained when versioning
+// is enabled on the storage account.
+func (c *RemoteClient) Versions(ctx context.Context) ([]*remote.Version, error) {
+	ctx, ctxCancel := c.getContextWithTimeout(ctx)
+	defer ctxCancel()
+
+	pager := c.containerClient.NewListBlobsFlatPager(&container.ListBlobsFlatOptions{
+		Prefix: &c.blobName,
+		Include: container.ListBlobsInclude{
+			Snapshots: true,
+			Versions:  true,
+		},
+	})
+
+	var ret []*remote.Version
+	for pager.More() {
+		resp, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error listing blob versions: %w", err)
+		}
+
+		for _, obj := range resp.Segment.BlobItems {
+			// We listed by prefix, so we might also see other blobs whose
+			// names happen to start with our blob name.
+			if obj.Name == nil || *obj.Name != c.blobName {
+				continue
+			}
+
+			v := &remote.Version{}
+			switch {
+			case obj.Snapshot != nil && *obj.Snapshot != "":
+				v.ID = snapshotVersionPrefix + *obj.Snapshot
+			case obj.VersionID != nil && *obj.VersionID != "":
+				v.ID = blobVersionPrefix + *obj.VersionID
+				v.IsLatest = obj.IsCurrentVersion != nil && *obj.IsCurrentVersion
+			default:
+				v.ID = currentVersionID
+				v.IsLatest = true
+			}
+			if obj.Properties != nil && obj.Properties.LastModified != nil {
+				v.Created = *obj.Properties.LastModified
+			}
+			ret = append(ret, v)
+		}
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		if ret[i].IsLatest != ret[j].IsLatest {
+			return ret[i].IsLatest
+		}
+		return ret[i].Created.After(ret[j].Created)
+	})
+	return ret, nil
+}
+
+func (c *RemoteClient) GetVersion(ctx context.Context, id string) (*remote.Payload, error) {
+	blobClient := c.blobClient
+	var err error
+	switch {
+	case id == currentVersionID:
+		// The current blob is read through the client as-is.
+	case strings.HasPrefix(id, snapshotVersionPrefix):
+		blobClient, err = c.blobClient.WithSnapshot(strings.TrimPrefix(id, snapshotVersionPrefix))
+	case strings.HasPrefix(id, blobVersionPrefix):
+		blobClient, err = c.blobClient.WithVersionID(strings.TrimPrefix(id, blobVersionPrefix))
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid blob version %q: %w", id, err)
+	}
+
+	ctx, ctxCancel := c.getContextWithTimeout(ctx)
+	defer ctxCancel()
+	resp, err := blobClient.DownloadStream(ctx, &blob.DownloadStreamOptions{
+		CPKInfo:      c.cpkInfo,
+		CPKScopeInfo: c.cpkScopeInfo,
+	})
+	if err != nil {
+		if notFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error downloading azure blob version %q: %w", id, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading azure blob version %q: %w", id, err)
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	return &remote.Payload{
+		Data: data,
+	}, nil
+}
+
 func (c *RemoteClient) Lock(ctx context.Context, info *statemgr.LockInfo) (string, error) {
 	info.Path = c.blobClient.URL()
 
@@ -195,12 +310,34 @@ func (c *RemoteClient) Lock(ctx context.Context, info *statemgr.LockInfo) (strin
 	return info.ID, nil
 }
 
+// CurrentLock returns the lock info recorded in the metadata of the state
+// blob if the blob is currently leased, without acquiring the lease.
+func (c *RemoteClient) CurrentLock(ctx context.Context) (*statemgr.LockInfo, error) {
+	properties, err := c.getBlobProperties(ctx)
+	if err != nil {
+		if notFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting lock info: %w", err)
+	}
+
+	if properties.LeaseStatus == nil || *properties.LeaseStatus != lease.StatusTypeLocked {
+		return nil, nil
+	}
+	return decodeLockInfo(properties)
+}
+
 func (c *RemoteClient) getLockInfo(ctx context.Context) (*statemgr.LockInfo, error) {
 	properties, err := c.getBlobProperties(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error getting lock info: %w", err)
 	}
+	return decodeLockInfo(properties)
+}
 
+// decodeLockInfo returns the lock info recorded in the metadata of the state
+// blob by Lock.
+func decodeLockInfo(properties blob.GetPropertiesResponse) (*statemgr.LockInfo, error) {
 	raw := properties.Metadata[lockInfoMetaKey]
 	if raw == nil || *raw == "" {
 		return nil, fmt.Errorf("blob metadata %q was empty", lockInfoMetaKey)
@@ -22,6 +22,8 @@ import (
 func TestRemoteClient_impl(t *testing.T) {
 	var _ remote.Client = new(RemoteClient)
 	var _ remote.ClientLocker = new(RemoteClient)
+	var _ remote.ClientHistory = new(RemoteClient)
+	var _ remote.ClientLockInspector = new(RemoteClient)
 }
 
 func TestPutMaintainsMetadata(t *testing.T) {
@@ -146,6 +148,7 @@ func TestAccRemoteClientAccessKeyBasic(t *testing.T) {
 	}
 
 	remote.TestRemoteLocks(t, s1.(*remote.State).Client, s2.(*remote.State).Client)
+	remote.TestLockInspector(t, s1.(*remote.State).Client, s2.(*remote.State).Client)
 }
 
 func TestAccRemoteClientSASToken(t *testing.T) {
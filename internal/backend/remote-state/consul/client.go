@@ -361,6 +361,40 @@ func (c *RemoteClient) getLockInfo() (*statemgr.LockInfo, error) {
 	return li, nil
 }
 
+// CurrentLock returns the lock info of the consul session currently holding
+// the lock, if any, without acquiring it.
+func (c *RemoteClient) CurrentLock(_ context.Context) (*statemgr.LockInfo, error) {
+	if !c.lockState {
+		return nil, nil
+	}
+
+	pair, _, err := c.Client.KV().Get(c.lockPath()+lockSuffix, nil)
+	if err != nil {
+		return nil, err
+	}
+	if pair == nil || pair.Session == "" {
+		// The lock key is left behind once released, but is no longer
+		// associated with a session.
+		return nil, nil
+	}
+
+	info, err := c.getLockInfo()
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		// The lock info is written just after the lock is acquired, so we
+		// may be looking in between the two.
+		info = &statemgr.LockInfo{
+			Path: c.Path,
+			Info: "consul session: " + pair.Session,
+		}
+	}
+	// The session is what force-unlock needs to release the lock.
+	info.ID = pair.Session
+	return info, nil
+}
+
 func (c *RemoteClient) Lock(_ context.Context, info *statemgr.LockInfo) (string, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
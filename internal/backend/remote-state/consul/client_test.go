@@ -27,6 +27,7 @@ import (
 func TestRemoteClient_impl(t *testing.T) {
 	var _ remote.Client = new(RemoteClient)
 	var _ remote.ClientLocker = new(RemoteClient)
+	var _ remote.ClientLockInspector = new(RemoteClient)
 }
 
 func TestRemoteClient(t *testing.T) {
@@ -271,6 +272,7 @@ func TestConsul_stateLock(t *testing.T) {
 			}
 
 			remote.TestRemoteLocks(t, sA.(*remote.State).Client, sB.(*remote.State).Client)
+			remote.TestLockInspector(t, sA.(*remote.State).Client, sB.(*remote.State).Client)
 		})
 	}
 }
@@ -162,6 +162,7 @@ func TestRemoteLocks(t *testing.T) {
 	}
 
 	remote.TestRemoteLocks(t, c0, c1)
+	remote.TestLockInspector(t, c0, c1)
 }
 
 func TestBackend(t *testing.T) {
@@ -11,17 +11,20 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
 
 	"cloud.google.com/go/storage"
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/opentofu/opentofu/internal/states/remote"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
+	"google.golang.org/api/iterator"
 )
 
 // remoteClient is used by "state/remote".State to read and write
 // blobs representing state.
-// Implements "state/remote".ClientLocker
+// Implements "state/remote".ClientLocker, "state/remote".ClientLockInspector
+// and "state/remote".ClientHistory
 type remoteClient struct {
 	storageClient *storage.Client
 	bucketName    string
@@ -86,6 +89,76 @@ func (c *remoteClient) Delete(ctx context.Context) error {
 	return nil
 }
 
+// Versions lists the generations of the state file. Noncurrent generations
+// are only retained when object versioning is enabled on the bucket.
+func (c *remoteClient) Versions(ctx context.Context) ([]*remote.Version, error) {
+	objs := c.storageClient.Bucket(c.bucketName).Objects(ctx, &storage.Query{
+		Prefix:   c.stateFilePath,
+		Versions: true,
+	})
+
+	var ret []*remote.Version
+	for {
+		attrs, err := objs.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Failed to list generations of state file %v: %w", c.stateFileURL(), err)
+		}
+
+		// We listed by prefix, so we might also see other objects whose
+		// names happen to start with our state file path.
+		if attrs.Name != c.stateFilePath {
+			continue
+		}
+
+		ret = append(ret, &remote.Version{
+			ID:       strconv.FormatInt(attrs.Generation, 10),
+			Created:  attrs.Created,
+			Who:      attrs.Owner,
+			IsLatest: attrs.Deleted.IsZero(),
+		})
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Created.After(ret[j].Created)
+	})
+	return ret, nil
+}
+
+func (c *remoteClient) GetVersion(ctx context.Context, id string) (*remote.Payload, error) {
+	gen, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("State version ID should be numerical value, got '%s'", id)
+	}
+
+	obj := c.stateFile().Generation(gen)
+	r, err := obj.NewReader(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Failed to open state file generation %d at %v: %w", gen, c.stateFileURL(), err)
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read state file generation %d from %v: %w", gen, c.stateFileURL(), err)
+	}
+
+	attrs, err := obj.Attrs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read state file generation %d attrs from %v: %w", gen, c.stateFileURL(), err)
+	}
+
+	return &remote.Payload{
+		Data: data,
+		MD5:  attrs.MD5,
+	}, nil
+}
+
 // Lock writes to a lock file, ensuring file creation. Returns the generation
 // number, which must be passed to Unlock().
 func (c *remoteClient) Lock(ctx context.Context, info *statemgr.LockInfo) (string, error) {
@@ -129,6 +202,19 @@ func (c *remoteClient) Unlock(ctx context.Context, id string) error {
 	return nil
 }
 
+// CurrentLock reads the lock file, if there is one, without acquiring the
+// lock.
+func (c *remoteClient) CurrentLock(ctx context.Context) (*statemgr.LockInfo, error) {
+	info, err := c.lockInfo(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %q failed: %w", c.lockFileURL(), err)
+	}
+	return info, nil
+}
+
 func (c *remoteClient) lockError(ctx context.Context, err error) *statemgr.LockError {
 	lockErr := &statemgr.LockError{
 		Err: err,
@@ -67,6 +67,18 @@ func New(enc encryption.StateEncryption) backend.Backend {
 				DefaultFunc: schema.EnvDefaultFunc("TF_HTTP_UNLOCK_METHOD", "UNLOCK"),
 				Description: "The HTTP method to use when unlocking",
 			},
+			"lock_status_address": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("TF_HTTP_LOCK_STATUS_ADDRESS", nil),
+				Description: "The address of the REST endpoint reporting the current lock",
+			},
+			"lock_status_method": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("TF_HTTP_LOCK_STATUS_METHOD", "GET"),
+				Description: "The HTTP method to use when reading the current lock",
+			},
 			"username": &schema.Schema{
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -248,6 +260,20 @@ func (b *Backend) configure(ctx context.Context) error {
 
 	unlockMethod := data.Get("unlock_method").(string)
 
+	var lockStatusURL *url.URL
+	if v, ok := data.GetOk("lock_status_address"); ok && v.(string) != "" {
+		var err error
+		lockStatusURL, err = url.Parse(v.(string))
+		if err != nil {
+			return fmt.Errorf("failed to parse lockStatusAddress URL: %w", err)
+		}
+		if lockStatusURL.Scheme != "http" && lockStatusURL.Scheme != "https" {
+			return fmt.Errorf("lockStatusAddress must be HTTP or HTTPS")
+		}
+	}
+
+	lockStatusMethod := data.Get("lock_status_method").(string)
+
 	username := data.Get("username").(string)
 	password := data.Get("password").(string)
 
@@ -293,6 +319,9 @@ func (b *Backend) configure(ctx context.Context) error {
 		UnlockURL:    unlockURL,
 		UnlockMethod: unlockMethod,
 
+		LockStatusURL:    lockStatusURL,
+		LockStatusMethod: lockStatusMethod,
+
 		Headers:  headers,
 		Username: username,
 		Password: password,
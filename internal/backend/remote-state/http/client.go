@@ -34,6 +34,10 @@ type httpClient struct {
 	UnlockURL    *url.URL
 	UnlockMethod string
 
+	// Lock inspection
+	LockStatusURL    *url.URL
+	LockStatusMethod string
+
 	// HTTP
 	Client   *retryablehttp.Client
 	Headers  map[string]string
@@ -140,6 +144,50 @@ func (c *httpClient) Lock(ctx context.Context, info *statemgr.LockInfo) (string,
 	}
 }
 
+// CurrentLock asks the lock status endpoint for the lock info of the current
+// lock. The endpoint should respond with 200: OK and the lock info in the
+// body when the state is locked, or with 204: No Content or 404: Not Found
+// when it isn't.
+func (c *httpClient) CurrentLock(ctx context.Context) (*statemgr.LockInfo, error) {
+	if c.LockStatusURL == nil {
+		return nil, fmt.Errorf("lock_status_address is not configured")
+	}
+
+	resp, err := c.httpRequest(ctx, c.LockStatusMethod, c.LockStatusURL, nil, "read lock status")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read lock status: %w", err)
+		}
+		info := &statemgr.LockInfo{}
+		if err := json.Unmarshal(body, info); err != nil {
+			return nil, fmt.Errorf("Failed to unmarshal lock status: %w", err)
+		}
+		return info, nil
+	case http.StatusNoContent, http.StatusNotFound:
+		return nil, nil
+	case http.StatusUnauthorized:
+		return nil, fmt.Errorf("HTTP remote state endpoint requires auth")
+	case http.StatusForbidden:
+		return nil, fmt.Errorf("HTTP remote state endpoint invalid auth")
+	default:
+		log.Printf("[DEBUG] LOCK STATUS, %d: %s", resp.StatusCode, parseResponseBodyForLog(resp))
+		return nil, fmt.Errorf("Unexpected HTTP response code %d", resp.StatusCode)
+	}
+}
+
+// IsLockInspectionEnabled returns true if an endpoint reporting the current
+// lock is configured.
+func (c *httpClient) IsLockInspectionEnabled() bool {
+	return c.LockStatusURL != nil
+}
+
 func (c *httpClient) Unlock(ctx context.Context, id string) error {
 	if c.UnlockURL == nil {
 		return nil
@@ -24,6 +24,7 @@ import (
 func TestHTTPClient_impl(t *testing.T) {
 	var _ remote.Client = new(httpClient)
 	var _ remote.ClientLocker = new(httpClient)
+	var _ remote.OptionalClientLockInspector = new(httpClient)
 }
 
 func TestHTTPClient(t *testing.T) {
@@ -94,8 +95,12 @@ func TestHTTPClient(t *testing.T) {
 		UnlockURL:    url,
 		UnlockMethod: "UNLOCK",
 		Client:       retryablehttp.NewClient(),
+
+		LockStatusURL:    url,
+		LockStatusMethod: "LOCKSTATUS",
 	}
 	remote.TestRemoteLocks(t, a, b)
+	remote.TestLockInspector(t, a, b)
 
 	// test a WebDAV-ish backend
 	davhandler := new(testHTTPHandler)
@@ -130,8 +135,9 @@ func TestHTTPClient(t *testing.T) {
 }
 
 type testHTTPHandler struct {
-	Data   []byte
-	Locked bool
+	Data     []byte
+	Locked   bool
+	LockInfo []byte
 }
 
 func (h *testHTTPHandler) Handle(w http.ResponseWriter, r *http.Request) {
@@ -162,9 +168,19 @@ func (h *testHTTPHandler) Handle(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(423)
 		} else {
 			h.Locked = true
+			h.LockInfo, _ = io.ReadAll(r.Body)
 		}
 	case "UNLOCK":
 		h.Locked = false
+		h.LockInfo = nil
+	case "LOCKSTATUS":
+		if !h.Locked {
+			w.WriteHeader(204)
+			return
+		}
+		if _, err := w.Write(h.LockInfo); err != nil {
+			w.WriteHeader(500)
+		}
 	case "DELETE":
 		h.Data = nil
 		w.WriteHeader(200)
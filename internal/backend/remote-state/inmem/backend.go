@@ -232,3 +232,18 @@ func (l *lockMap) unlock(name, id string) error {
 	delete(l.m, name)
 	return nil
 }
+
+// info returns a copy of the lock info for the named state, or nil if the
+// state is not currently locked.
+func (l *lockMap) info(name string) *statemgr.LockInfo {
+	l.Lock()
+	defer l.Unlock()
+
+	lockInfo := l.m[name]
+	if lockInfo == nil {
+		return nil
+	}
+
+	ret := *lockInfo
+	return &ret
+}
@@ -8,6 +8,8 @@ package inmem
 import (
 	"context"
 	"crypto/md5"
+	"strconv"
+	"time"
 
 	"github.com/opentofu/opentofu/internal/states/remote"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
@@ -18,8 +20,21 @@ type RemoteClient struct {
 	Data []byte
 	MD5  []byte
 	Name string
+
+	// versions retains every payload written by Put, oldest first, so that
+	// the client can also be used to test state history.
+	versions []*version
+}
+
+type version struct {
+	remote.Version
+	data []byte
+	md5  []byte
 }
 
+var _ remote.ClientHistory = (*RemoteClient)(nil)
+var _ remote.ClientLockInspector = (*RemoteClient)(nil)
+
 func (c *RemoteClient) Get(_ context.Context) (*remote.Payload, error) {
 	if c.Data == nil {
 		return nil, nil
@@ -36,18 +51,58 @@ func (c *RemoteClient) Put(_ context.Context, data []byte) error {
 
 	c.Data = data
 	c.MD5 = md5[:]
+
+	v := &version{
+		Version: remote.Version{
+			ID:      strconv.Itoa(len(c.versions) + 1),
+			Created: time.Now().UTC(),
+		},
+		data: data,
+		md5:  md5[:],
+	}
+	if info := locks.info(c.Name); info != nil {
+		v.Who = info.Who
+	}
+	c.versions = append(c.versions, v)
 	return nil
 }
 
 func (c *RemoteClient) Delete(_ context.Context) error {
 	c.Data = nil
 	c.MD5 = nil
+	c.versions = nil
 	return nil
 }
 
+func (c *RemoteClient) Versions(_ context.Context) ([]*remote.Version, error) {
+	ret := make([]*remote.Version, 0, len(c.versions))
+	for i := len(c.versions) - 1; i >= 0; i-- {
+		v := c.versions[i].Version
+		v.IsLatest = i == len(c.versions)-1
+		ret = append(ret, &v)
+	}
+	return ret, nil
+}
+
+func (c *RemoteClient) GetVersion(_ context.Context, id string) (*remote.Payload, error) {
+	for _, v := range c.versions {
+		if v.ID == id {
+			return &remote.Payload{
+				Data: v.data,
+				MD5:  v.md5,
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
 func (c *RemoteClient) Lock(_ context.Context, info *statemgr.LockInfo) (string, error) {
 	return locks.lock(c.Name, info)
 }
 func (c *RemoteClient) Unlock(_ context.Context, id string) error {
 	return locks.unlock(c.Name, id)
 }
+
+func (c *RemoteClient) CurrentLock(_ context.Context) (*statemgr.LockInfo, error) {
+	return locks.info(c.Name), nil
+}
@@ -17,6 +17,8 @@ import (
 func TestRemoteClient_impl(t *testing.T) {
 	var _ remote.Client = new(RemoteClient)
 	var _ remote.ClientLocker = new(RemoteClient)
+	var _ remote.ClientHistory = new(RemoteClient)
+	var _ remote.ClientLockInspector = new(RemoteClient)
 }
 
 func TestRemoteClient(t *testing.T) {
@@ -39,4 +41,26 @@ func TestInmemLocks(t *testing.T) {
 	}
 
 	remote.TestRemoteLocks(t, s.(*remote.State).Client, s.(*remote.State).Client)
+	remote.TestLockInspector(t, s.(*remote.State).Client, s.(*remote.State).Client)
+}
+
+func TestRemoteClientHistory(t *testing.T) {
+	defer Reset()
+	b := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), hcl.EmptyBody())
+
+	s, err := b.StateMgr(t.Context(), backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := s.(*remote.State).Client
+	remote.TestClientHistory(t, c)
+
+	p, err := c.(remote.ClientHistory).GetVersion(t.Context(), "does-not-exist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p != nil {
+		t.Fatalf("expected no payload for unknown version, got: %q", string(p.Data))
+	}
 }
@@ -211,6 +211,38 @@ func (c *RemoteClient) Lock(ctx context.Context, info *statemgr.LockInfo) (strin
 	return info.ID, err
 }
 
+// CurrentLock returns the lock info recorded on the lease if it currently
+// has a holder, without acquiring it.
+func (c *RemoteClient) CurrentLock(ctx context.Context) (*statemgr.LockInfo, error) {
+	leaseName, err := c.createLeaseName()
+	if err != nil {
+		return nil, err
+	}
+
+	lease, err := c.getLease(ctx, leaseName)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if lease.Spec.HolderIdentity == nil {
+		return nil, nil
+	}
+
+	lockInfo, err := c.getLockInfo(lease)
+	if err != nil {
+		return nil, err
+	}
+	if lockInfo == nil {
+		lockInfo = &statemgr.LockInfo{}
+	}
+	// The holder identity is what Unlock checks against.
+	lockInfo.ID = *lease.Spec.HolderIdentity
+	return lockInfo, nil
+}
+
 func (c *RemoteClient) Unlock(ctx context.Context, id string) error {
 	leaseName, err := c.createLeaseName()
 	if err != nil {
@@ -58,6 +58,7 @@ func TestRemoteClientLocks(t *testing.T) {
 	}
 
 	remote.TestRemoteLocks(t, s1.(*remote.State).Client, s2.(*remote.State).Client)
+	remote.TestLockInspector(t, s1.(*remote.State).Client, s2.(*remote.State).Client)
 }
 
 func TestForceUnlock(t *testing.T) {
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/lib/pq"
 
@@ -34,6 +35,16 @@ func defaultBoolFunc(k string, dv bool) schema.SchemaDefaultFunc {
 	}
 }
 
+func defaultIntFunc(k string, dv int) schema.SchemaDefaultFunc {
+	return func() (interface{}, error) {
+		if v := os.Getenv(k); v != "" {
+			return strconv.Atoi(v)
+		}
+
+		return dv, nil
+	}
+}
+
 // New creates a new backend for Postgres remote state.
 func New(enc encryption.StateEncryption) backend.Backend {
 	s := &schema.Backend{
@@ -86,6 +97,20 @@ func New(enc encryption.StateEncryption) backend.Backend {
 				Description: "If set to `true`, OpenTofu won't try to create the Postgres index",
 				DefaultFunc: defaultBoolFunc("PG_SKIP_INDEX_CREATION", false),
 			},
+
+			"keep_versions": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Number of earlier versions of the state to retain for each workspace; `0` disables state history",
+				DefaultFunc: defaultIntFunc("PG_KEEP_VERSIONS", 0),
+			},
+
+			"keep_versions_max_age": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Maximum age of the earlier versions of the state to retain, as a duration such as `720h`",
+				DefaultFunc: schema.EnvDefaultFunc("PG_KEEP_VERSIONS_MAX_AGE", ""),
+			},
 		},
 	}
 
@@ -105,6 +130,13 @@ type Backend struct {
 	schemaName string
 	tableName  string
 	indexName  string
+
+	// keepVersions and keepVersionsMaxAge configure the retention of
+	// earlier versions of the state, which are stored in the table named
+	// versionsTableName. State history is disabled if keepVersions is zero.
+	keepVersions       int
+	keepVersionsMaxAge time.Duration
+	versionsTableName  string
 }
 
 func (b *Backend) configure(ctx context.Context) error {
@@ -119,6 +151,21 @@ func (b *Backend) configure(ctx context.Context) error {
 	skipSchemaCreation := data.Get("skip_schema_creation").(bool)
 	skipTableCreation := data.Get("skip_table_creation").(bool)
 	skipIndexCreation := data.Get("skip_index_creation").(bool)
+	b.keepVersions = data.Get("keep_versions").(int)
+	if b.keepVersions < 0 {
+		return fmt.Errorf("keep_versions must not be negative")
+	}
+	if v := data.Get("keep_versions_max_age").(string); v != "" {
+		maxAge, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid keep_versions_max_age: %w", err)
+		}
+		if maxAge <= 0 {
+			return fmt.Errorf("keep_versions_max_age must be positive")
+		}
+		b.keepVersionsMaxAge = maxAge
+	}
+	b.versionsTableName = b.tableName + "_versions"
 
 	db, err := sql.Open("postgres", b.connStr)
 	if err != nil {
@@ -167,6 +214,28 @@ func (b *Backend) configure(ctx context.Context) error {
 		}
 	}
 
+	// Earlier versions of the state are only stored when keep_versions is
+	// set, so the table and its index are only created in that case.
+	if !skipTableCreation && b.keepVersions > 0 {
+		query = fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.%s (
+			id bigserial PRIMARY KEY,
+			name text NOT NULL,
+			data text,
+			created timestamptz NOT NULL DEFAULT now(),
+			who text NOT NULL DEFAULT '',
+			operation text NOT NULL DEFAULT '',
+			lock_id text NOT NULL DEFAULT ''
+			)`, pq.QuoteIdentifier(b.schemaName), pq.QuoteIdentifier(b.versionsTableName))
+		if _, err = db.Exec(query); err != nil {
+			return err
+		}
+
+		query = fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s.%s (name, id)`, pq.QuoteIdentifier(b.indexName+"_versions"), pq.QuoteIdentifier(b.schemaName), pq.QuoteIdentifier(b.versionsTableName))
+		if _, err = db.Exec(query); err != nil {
+			return err
+		}
+	}
+
 	if !skipIndexCreation {
 		query = fmt.Sprintf(`CREATE UNIQUE INDEX IF NOT EXISTS %s ON %s.%s (name)`, pq.QuoteIdentifier(b.indexName), pq.QuoteIdentifier(b.schemaName), pq.QuoteIdentifier(b.tableName))
 		if _, err = db.Exec(query); err != nil {
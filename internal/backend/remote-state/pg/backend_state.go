@@ -43,32 +43,17 @@ func (b *Backend) Workspaces(context.Context) ([]string, error) {
 	return result, nil
 }
 
-func (b *Backend) DeleteWorkspace(_ context.Context, name string, _ bool) error {
+func (b *Backend) DeleteWorkspace(ctx context.Context, name string, _ bool) error {
 	if name == backend.DefaultStateName || name == "" {
 		return fmt.Errorf("can't delete default state")
 	}
 
-	query := fmt.Sprintf(`DELETE FROM %s.%s WHERE name = $1`, pq.QuoteIdentifier(b.schemaName), pq.QuoteIdentifier(b.tableName))
-	_, err := b.db.Exec(query, name)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return b.client(name).Delete(ctx)
 }
 
 func (b *Backend) StateMgr(ctx context.Context, name string) (statemgr.Full, error) {
 	// Build the state client
-	var stateMgr statemgr.Full = remote.NewState(
-		&RemoteClient{
-			Client:     b.db,
-			Name:       name,
-			SchemaName: b.schemaName,
-			TableName:  b.tableName,
-			IndexName:  b.indexName,
-		},
-		b.encryption,
-	)
+	var stateMgr statemgr.Full = remote.NewState(b.client(name), b.encryption)
 
 	// Check to see if this state already exists.
 	// If the state doesn't exist, we have to assume this
@@ -124,3 +109,16 @@ func (b *Backend) StateMgr(ctx context.Context, name string) (statemgr.Full, err
 
 	return stateMgr, nil
 }
+
+func (b *Backend) client(name string) *RemoteClient {
+	return &RemoteClient{
+		Client:             b.db,
+		Name:               name,
+		SchemaName:         b.schemaName,
+		TableName:          b.tableName,
+		IndexName:          b.indexName,
+		KeepVersions:       b.keepVersions,
+		KeepVersionsMaxAge: b.keepVersionsMaxAge,
+		VersionsTableName:  b.versionsTableName,
+	}
+}
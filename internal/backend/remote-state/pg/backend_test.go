@@ -370,6 +370,33 @@ func TestBackendConfig(t *testing.T) {
 
 }
 
+// TestBackendConfigKeepVersionsInvalid doesn't require a database, because
+// the settings are validated before connecting.
+func TestBackendConfigKeepVersionsInvalid(t *testing.T) {
+	testCases := map[string]map[string]interface{}{
+		"negative keep_versions": {
+			"keep_versions": -1,
+		},
+		"invalid keep_versions_max_age": {
+			"keep_versions":         5,
+			"keep_versions_max_age": "forever",
+		},
+		"negative keep_versions_max_age": {
+			"keep_versions":         5,
+			"keep_versions_max_age": "-1h",
+		},
+	}
+	for name, config := range testCases {
+		t.Run(name, func(t *testing.T) {
+			config["conn_str"] = "postgres://localhost/unused"
+			_, _, errs := backend.TestBackendConfigWarningsAndErrors(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(config))
+			if len(errs) == 0 {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
 func TestBackendConfigSkipOptions(t *testing.T) {
 	testACC(t)
 	connStr := getDatabaseUrl()
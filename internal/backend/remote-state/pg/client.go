@@ -11,6 +11,8 @@ import (
 	"database/sql"
 	"fmt"
 	"hash/fnv"
+	"strconv"
+	"time"
 
 	"github.com/lib/pq"
 
@@ -27,6 +29,14 @@ type RemoteClient struct {
 	TableName  string
 	IndexName  string
 
+	// KeepVersions is the number of earlier versions of the state to retain
+	// in the table named VersionsTableName, alongside the latest one, and
+	// KeepVersionsMaxAge is the maximum age of the earlier versions, if
+	// non-zero. State history is disabled if KeepVersions is zero.
+	KeepVersions       int
+	KeepVersionsMaxAge time.Duration
+	VersionsTableName  string
+
 	info *statemgr.LockInfo
 }
 
@@ -50,24 +60,163 @@ func (c *RemoteClient) Get(_ context.Context) (*remote.Payload, error) {
 	}
 }
 
-func (c *RemoteClient) Put(_ context.Context, data []byte) error {
+func (c *RemoteClient) Put(ctx context.Context, data []byte) error {
+	// With history enabled the latest state is also recorded as a version,
+	// so that it's still available once it has been superseded, and both
+	// writes must succeed or fail together.
+	tx, err := c.Client.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// Rolling back has no effect once the transaction is committed.
+	defer func() { _ = tx.Rollback() }()
+
 	query := fmt.Sprintf(`INSERT INTO %s.%s (name, data) VALUES ($1, $2)
 		ON CONFLICT (name) DO UPDATE
 		SET data = $2 WHERE %s.name = $1`, pq.QuoteIdentifier(c.SchemaName), pq.QuoteIdentifier(c.TableName), pq.QuoteIdentifier(c.TableName))
-	_, err := c.Client.Exec(query, c.Name, data)
-	if err != nil {
+	if _, err := tx.ExecContext(ctx, query, c.Name, data); err != nil {
+		return err
+	}
+
+	if c.KeepVersions > 0 {
+		who, operation, lockID := statemgr.NewLockInfo().Who, "", ""
+		if c.info != nil {
+			who, operation, lockID = c.info.Who, c.info.Operation, c.info.ID
+		}
+		query = fmt.Sprintf(`INSERT INTO %s.%s (name, data, who, operation, lock_id) VALUES ($1, $2, $3, $4, $5)`,
+			pq.QuoteIdentifier(c.SchemaName), pq.QuoteIdentifier(c.VersionsTableName))
+		if _, err := tx.ExecContext(ctx, query, c.Name, data, who, operation, lockID); err != nil {
+			return fmt.Errorf("failed to record state version: %w", err)
+		}
+
+		if err := c.pruneVersions(ctx, tx); err != nil {
+			return fmt.Errorf("failed to prune state versions: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// pruneVersions deletes the versions of the state that are no longer
+// retained according to KeepVersions and KeepVersionsMaxAge. The latest
+// version is always retained.
+func (c *RemoteClient) pruneVersions(ctx context.Context, tx *sql.Tx) error {
+	table := fmt.Sprintf("%s.%s", pq.QuoteIdentifier(c.SchemaName), pq.QuoteIdentifier(c.VersionsTableName))
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE name = $1 AND id NOT IN (
+		SELECT id FROM %s WHERE name = $1 ORDER BY id DESC LIMIT $2
+		)`, table, table)
+	if _, err := tx.ExecContext(ctx, query, c.Name, c.KeepVersions+1); err != nil {
 		return err
 	}
+
+	if c.KeepVersionsMaxAge > 0 {
+		query = fmt.Sprintf(`DELETE FROM %s WHERE name = $1 AND created < $2 AND id <> (
+			SELECT max(id) FROM %s WHERE name = $1
+			)`, table, table)
+		if _, err := tx.ExecContext(ctx, query, c.Name, time.Now().Add(-c.KeepVersionsMaxAge)); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-func (c *RemoteClient) Delete(_ context.Context) error {
-	query := fmt.Sprintf(`DELETE FROM %s.%s WHERE name = $1`, pq.QuoteIdentifier(c.SchemaName), pq.QuoteIdentifier(c.TableName))
-	_, err := c.Client.Exec(query, c.Name)
+func (c *RemoteClient) Delete(ctx context.Context) error {
+	// The versions of the state are deleted along with it, even if history
+	// is now disabled, so that a new workspace with the same name doesn't
+	// inherit them.
+	tx, err := c.Client.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
-	return nil
+	// Rolling back has no effect once the transaction is committed.
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf(`DELETE FROM %s.%s WHERE name = $1`, pq.QuoteIdentifier(c.SchemaName), pq.QuoteIdentifier(c.TableName))
+	if _, err := tx.ExecContext(ctx, query, c.Name); err != nil {
+		return err
+	}
+
+	// The versions table only exists if history was ever enabled.
+	versionsTable := fmt.Sprintf("%s.%s", pq.QuoteIdentifier(c.SchemaName), pq.QuoteIdentifier(c.VersionsTableName))
+	var exists bool
+	if err := tx.QueryRowContext(ctx, `SELECT to_regclass($1) IS NOT NULL`, versionsTable).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		query = fmt.Sprintf(`DELETE FROM %s WHERE name = $1`, versionsTable)
+		if _, err := tx.ExecContext(ctx, query, c.Name); err != nil {
+			return fmt.Errorf("failed to delete state versions: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// IsHistoryEnabled returns true if the client is configured to retain
+// earlier versions of the state.
+func (c *RemoteClient) IsHistoryEnabled() bool {
+	return c.KeepVersions > 0
+}
+
+// Versions returns the versions of the state retained for the workspace,
+// newest first. The ID of each version is the id of its row in the versions
+// table.
+func (c *RemoteClient) Versions(ctx context.Context) ([]*remote.Version, error) {
+	query := fmt.Sprintf(`SELECT id, created, who FROM %s.%s WHERE name = $1 ORDER BY id DESC`,
+		pq.QuoteIdentifier(c.SchemaName), pq.QuoteIdentifier(c.VersionsTableName))
+	rows, err := c.Client.QueryContext(ctx, query, c.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ret []*remote.Version
+	for rows.Next() {
+		var id int64
+		var created time.Time
+		var who string
+		if err := rows.Scan(&id, &created, &who); err != nil {
+			return nil, err
+		}
+		ret = append(ret, &remote.Version{
+			ID:       strconv.FormatInt(id, 10),
+			Created:  created.UTC(),
+			Who:      who,
+			IsLatest: len(ret) == 0,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (c *RemoteClient) GetVersion(ctx context.Context, id string) (*remote.Payload, error) {
+	rowID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		// Not an ID that Versions could have returned.
+		return nil, nil
+	}
+
+	query := fmt.Sprintf(`SELECT data FROM %s.%s WHERE name = $1 AND id = $2`,
+		pq.QuoteIdentifier(c.SchemaName), pq.QuoteIdentifier(c.VersionsTableName))
+	var data []byte
+	err = c.Client.QueryRowContext(ctx, query, c.Name, rowID).Scan(&data)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	md5 := md5.Sum(data)
+	return &remote.Payload{
+		Data: data,
+		MD5:  md5[:],
+	}, nil
 }
 
 func (c *RemoteClient) Lock(_ context.Context, info *statemgr.LockInfo) (string, error) {
@@ -152,6 +301,63 @@ func (c *RemoteClient) Unlock(_ context.Context, id string) error {
 	return nil
 }
 
+// CurrentLock reports on the advisory lock currently held on the workspace,
+// if any, without acquiring it.
+//
+// Postgres advisory locks don't carry any information about why they were
+// taken, so the result only describes the database session holding the
+// lock: Who is the database user and client address of the session, Info
+// includes the process ID of the session's backend, and Created is the time
+// at which the session connected. The ID is always empty, because the lock
+// can only be released by the session holding it, or by terminating that
+// session.
+func (c *RemoteClient) CurrentLock(_ context.Context) (*statemgr.LockInfo, error) {
+	// If the workspace doesn't exist yet then Lock takes the creation lock
+	// instead, so that's the one to inspect.
+	lockKey := c.composeCreationLockID()
+	query := fmt.Sprintf(`SELECT id FROM %s.%s WHERE name = $1`, pq.QuoteIdentifier(c.SchemaName), pq.QuoteIdentifier(c.TableName))
+	var id int64
+	err := c.Client.QueryRow(query, c.Name).Scan(&id)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return nil, err
+	default:
+		lockKey = strconv.FormatInt(id, 10)
+	}
+
+	// Advisory locks taken with a single bigint key are listed in pg_locks
+	// with the high-order half of the key in classid and the low-order half
+	// in objid.
+	query = `SELECT a.pid, a.usename, COALESCE(host(a.client_addr), 'local'), a.application_name, a.backend_start
+		FROM pg_locks l JOIN pg_stat_activity a ON a.pid = l.pid
+		WHERE l.locktype = 'advisory' AND l.granted AND l.objsubid = 1
+		AND l.database = (SELECT oid FROM pg_database WHERE datname = current_database())
+		AND ((l.classid::bigint << 32) | l.objid::bigint) = $1::bigint
+		LIMIT 1`
+	var pid int
+	var user, host, application string
+	var started time.Time
+	err = c.Client.QueryRow(query, lockKey).Scan(&pid, &user, &host, &application, &started)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	info := fmt.Sprintf("held by Postgres backend process %d", pid)
+	if application != "" {
+		info = fmt.Sprintf("%s (%s)", info, application)
+	}
+	return &statemgr.LockInfo{
+		Who:     fmt.Sprintf("%s@%s", user, host),
+		Info:    info,
+		Created: started.UTC(),
+		Path:    lockKey,
+	}, nil
+}
+
 func (c *RemoteClient) composeCreationLockID() string {
 	hash := fnv.New32()
 	hash.Write([]byte(c.SchemaName + "\x00" + c.TableName))
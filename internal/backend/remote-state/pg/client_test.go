@@ -11,9 +11,12 @@ package pg
 import (
 	"database/sql"
 	"fmt"
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/lib/pq"
+
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states/remote"
@@ -23,6 +26,8 @@ import (
 func TestRemoteClient_impl(t *testing.T) {
 	var _ remote.Client = new(RemoteClient)
 	var _ remote.ClientLocker = new(RemoteClient)
+	var _ remote.ClientLockInspector = new(RemoteClient)
+	var _ remote.OptionalClientHistory = new(RemoteClient)
 }
 
 func TestRemoteClient(t *testing.T) {
@@ -91,6 +96,232 @@ func TestRemoteLocks(t *testing.T) {
 	remote.TestRemoteLocks(t, s1.(*remote.State).Client, s2.(*remote.State).Client)
 }
 
+func TestRemoteClient_CurrentLock(t *testing.T) {
+	testACC(t)
+	connStr := getDatabaseUrl()
+	schemaName := fmt.Sprintf("terraform_%s", t.Name())
+	tableName := fmt.Sprintf("terraform_%s", t.Name())
+	indexName := fmt.Sprintf("terraform_%s", t.Name())
+	dbCleaner, err := sql.Open("postgres", connStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dropSchema(t, dbCleaner, schemaName)
+
+	config := backend.TestWrapConfig(map[string]interface{}{
+		"conn_str":    connStr,
+		"schema_name": schemaName,
+		"table_name":  tableName,
+		"index_name":  indexName,
+	})
+
+	b1 := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), config).(*Backend)
+	s1, err := b1.StateMgr(t.Context(), backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b2 := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), config).(*Backend)
+	s2, err := b2.StateMgr(t.Context(), backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inspector := s2.(*remote.State).Client.(*RemoteClient)
+
+	// Advisory locks don't record the LockInfo given to Lock, so the
+	// generic remote.TestLockInspector doesn't apply here.
+	info, err := inspector.CurrentLock(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info != nil {
+		t.Fatalf("expected no lock, got:\n%s", info)
+	}
+
+	lockID, err := s1.Lock(t.Context(), statemgr.NewLockInfo())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err = inspector.CurrentLock(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info == nil || info.Who == "" || info.Created.IsZero() {
+		t.Errorf("expected details of the session holding the lock, got:\n%s", info)
+	}
+
+	if err := s1.Unlock(t.Context(), lockID); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err = inspector.CurrentLock(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info != nil {
+		t.Fatalf("expected no lock after unlocking, got:\n%s", info)
+	}
+}
+
+func TestRemoteClientHistory(t *testing.T) {
+	testACC(t)
+	connStr := getDatabaseUrl()
+	schemaName := fmt.Sprintf("terraform_%s", t.Name())
+	dbCleaner, err := sql.Open("postgres", connStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dropSchema(t, dbCleaner, schemaName)
+
+	config := backend.TestWrapConfig(map[string]interface{}{
+		"conn_str":      connStr,
+		"schema_name":   schemaName,
+		"keep_versions": 2,
+	})
+	b := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), config).(*Backend)
+
+	s, err := b.StateMgr(t.Context(), backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := statemgr.HistoryFor(s); !ok {
+		t.Fatal("history should be enabled")
+	}
+
+	remote.TestClientHistory(t, s.(*remote.State).Client)
+
+	// Writing more versions than keep_versions allows prunes the oldest,
+	// while always retaining the latest version as well.
+	c := b.client(backend.DefaultStateName)
+	for _, data := range []string{"a", "b", "c", "d"} {
+		if err := c.Put(t.Context(), []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	versions, err := c.Versions(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, v := range versions {
+		p, err := c.GetVersion(t.Context(), v.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, string(p.Data))
+	}
+	if want := []string{"d", "c", "b"}; !slices.Equal(got, want) {
+		t.Fatalf("wrong versions %q; want %q", got, want)
+	}
+	if !versions[0].IsLatest || versions[0].Who == "" {
+		t.Fatalf("wrong metadata for the latest version: %#v", versions[0])
+	}
+}
+
+func TestRemoteClientHistory_maxAge(t *testing.T) {
+	testACC(t)
+	connStr := getDatabaseUrl()
+	schemaName := fmt.Sprintf("terraform_%s", t.Name())
+	dbCleaner, err := sql.Open("postgres", connStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dropSchema(t, dbCleaner, schemaName)
+
+	config := backend.TestWrapConfig(map[string]interface{}{
+		"conn_str":              connStr,
+		"schema_name":           schemaName,
+		"keep_versions":         10,
+		"keep_versions_max_age": "1h",
+	})
+	b := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), config).(*Backend)
+	c := b.client(backend.DefaultStateName)
+
+	for _, data := range []string{"a", "b"} {
+		if err := c.Put(t.Context(), []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Backdate the existing versions so that they're all too old to be
+	// retained once the next version is written, except for the latest.
+	query := fmt.Sprintf(`UPDATE %s.%s SET created = now() - interval '2 hours'`, pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(b.versionsTableName))
+	if _, err := dbCleaner.Exec(query); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Put(t.Context(), []byte("c")); err != nil {
+		t.Fatal(err)
+	}
+
+	versions, err := c.Versions(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 {
+		t.Fatalf("expected only the latest version to be retained, got %d versions", len(versions))
+	}
+}
+
+func TestRemoteClientHistory_delete(t *testing.T) {
+	testACC(t)
+	connStr := getDatabaseUrl()
+	schemaName := fmt.Sprintf("terraform_%s", t.Name())
+	dbCleaner, err := sql.Open("postgres", connStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dropSchema(t, dbCleaner, schemaName)
+
+	// Without history, the versions table isn't created.
+	config := backend.TestWrapConfig(map[string]interface{}{
+		"conn_str":    connStr,
+		"schema_name": schemaName,
+	})
+	b := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), config).(*Backend)
+	var exists bool
+	query := `SELECT to_regclass($1) IS NOT NULL`
+	versionsTable := fmt.Sprintf("%s.%s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(b.versionsTableName))
+	if err := dbCleaner.QueryRow(query, versionsTable).Scan(&exists); err != nil {
+		t.Fatal(err)
+	}
+	if exists {
+		t.Fatal("the versions table should only be created when keep_versions is set")
+	}
+
+	config = backend.TestWrapConfig(map[string]interface{}{
+		"conn_str":      connStr,
+		"schema_name":   schemaName,
+		"keep_versions": 2,
+	})
+	b = backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), config).(*Backend)
+	c := b.client("foo")
+	for _, data := range []string{"a", "b"} {
+		if err := c.Put(t.Context(), []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Deleting the workspace deletes its versions as well, even once
+	// history has been disabled again.
+	config = backend.TestWrapConfig(map[string]interface{}{
+		"conn_str":    connStr,
+		"schema_name": schemaName,
+	})
+	b = backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), config).(*Backend)
+	if err := b.client("foo").Delete(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+
+	versions, err := c.Versions(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 0 {
+		t.Fatalf("expected the versions to be deleted with the workspace, got %d versions", len(versions))
+	}
+}
+
 // TestConcurrentCreationLocksInDifferentSchemas tests whether backends with different schemas
 // affect each other while taking global workspace creation locks.
 func TestConcurrentCreationLocksInDifferentSchemas(t *testing.T) {
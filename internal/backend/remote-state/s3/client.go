@@ -18,6 +18,7 @@ import (
 	"io"
 	"log"
 	"net/url"
+	"sort"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -25,6 +26,7 @@ import (
 	dtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 	multierror "github.com/hashicorp/go-multierror"
 	uuid "github.com/hashicorp/go-uuid"
 
@@ -249,6 +251,108 @@ func (c *RemoteClient) Delete(ctx context.Context) error {
 	return nil
 }
 
+// Versions lists the object versions of the state object, which are only
+// retained when versioning is enabled on the bucket. Otherwise, S3 reports
+// just the single current object.
+func (c *RemoteClient) Versions(ctx context.Context) ([]*remote.Version, error) {
+	ctx, _ = attachLoggerToContext(ctx)
+
+	input := &s3.ListObjectVersionsInput{
+		Bucket: &c.bucketName,
+		Prefix: &c.path,
+	}
+
+	var ret []*remote.Version
+	for {
+		output, err := c.s3Client.ListObjectVersions(ctx, input)
+		if err != nil {
+			var nb *types.NoSuchBucket
+			if errors.As(err, &nb) {
+				return nil, fmt.Errorf(errS3NoSuchBucket, err)
+			}
+			return nil, fmt.Errorf("failed to list state object versions: %w", err)
+		}
+
+		for _, obj := range output.Versions {
+			// We listed by prefix, so we might also see other objects whose
+			// keys happen to start with our state key.
+			if aws.ToString(obj.Key) != c.path {
+				continue
+			}
+
+			v := &remote.Version{
+				ID:       aws.ToString(obj.VersionId),
+				Created:  aws.ToTime(obj.LastModified),
+				IsLatest: aws.ToBool(obj.IsLatest),
+			}
+			if obj.Owner != nil {
+				v.Who = aws.ToString(obj.Owner.DisplayName)
+				if v.Who == "" {
+					v.Who = aws.ToString(obj.Owner.ID)
+				}
+			}
+			ret = append(ret, v)
+		}
+
+		if !aws.ToBool(output.IsTruncated) {
+			break
+		}
+		input.KeyMarker = output.NextKeyMarker
+		input.VersionIdMarker = output.NextVersionIdMarker
+	}
+
+	// S3 already returns the versions of each key newest first, but we'll
+	// make sure of it since other S3-compatible storage might not.
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Created.After(ret[j].Created)
+	})
+	return ret, nil
+}
+
+func (c *RemoteClient) GetVersion(ctx context.Context, id string) (*remote.Payload, error) {
+	ctx, _ = attachLoggerToContext(ctx)
+
+	input := &s3.GetObjectInput{
+		Bucket:    &c.bucketName,
+		Key:       &c.path,
+		VersionId: aws.String(id),
+	}
+
+	if c.serverSideEncryption && c.customerEncryptionKey != nil {
+		input.SSECustomerKey = aws.String(base64.StdEncoding.EncodeToString(c.customerEncryptionKey))
+		input.SSECustomerAlgorithm = aws.String(s3EncryptionAlgorithm)
+		input.SSECustomerKeyMD5 = aws.String(c.getSSECustomerKeyMD5())
+	}
+
+	output, err := c.s3Client.GetObject(ctx, input, s3optDisableDefaultChecksum(c.skipS3Checksum))
+	if err != nil {
+		var nk *types.NoSuchKey
+		if errors.As(err, &nk) {
+			return nil, nil
+		}
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchVersion" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get state object version %q: %w", id, err)
+	}
+	defer output.Body.Close()
+
+	data, err := io.ReadAll(output.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read remote state version %q: %w", id, err)
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	sum := md5.Sum(data)
+	return &remote.Payload{
+		Data: data,
+		MD5:  sum[:],
+	}, nil
+}
+
 func (c *RemoteClient) Lock(ctx context.Context, info *statemgr.LockInfo) (string, error) {
 	if !c.IsLockingEnabled() {
 		return "", nil
@@ -432,6 +536,19 @@ func (c *RemoteClient) deleteMD5(ctx context.Context) error {
 }
 
 func (c *RemoteClient) getLockInfoFromDynamoDB(ctx context.Context) (*statemgr.LockInfo, error) {
+	lockInfo, err := c.findLockInfoInDynamoDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if lockInfo == nil {
+		return nil, fmt.Errorf("no lock info found for: %q within the DynamoDB table: %s", c.lockPath(), c.ddbTable)
+	}
+	return lockInfo, nil
+}
+
+// findLockInfoInDynamoDB returns the lock info recorded in the DynamoDB
+// table, or nil if there is no lock item for the state.
+func (c *RemoteClient) findLockInfoInDynamoDB(ctx context.Context) (*statemgr.LockInfo, error) {
 	getParams := &dynamodb.GetItemInput{
 		Key: map[string]dtypes.AttributeValue{
 			"LockID": &dtypes.AttributeValueMemberS{Value: c.lockPath()},
@@ -448,7 +565,7 @@ func (c *RemoteClient) getLockInfoFromDynamoDB(ctx context.Context) (*statemgr.L
 	}
 
 	if len(resp.Item) == 0 {
-		return nil, fmt.Errorf("no lock info found for: %q within the DynamoDB table: %s", c.lockPath(), c.ddbTable)
+		return nil, nil
 	}
 
 	var infoData string
@@ -498,6 +615,32 @@ func (c *RemoteClient) getLockInfoFromS3(ctx context.Context) (*statemgr.LockInf
 	return lockInfo, nil
 }
 
+// CurrentLock returns the lock info of the current lock, without acquiring
+// it. When both locking mechanisms are enabled the S3 lock file is checked
+// first, since a lock is always acquired there before DynamoDB.
+func (c *RemoteClient) CurrentLock(ctx context.Context) (*statemgr.LockInfo, error) {
+	if c.useLockfile {
+		lockInfo, err := c.getLockInfoFromS3(ctx)
+		var nk *types.NoSuchKey
+		switch {
+		case errors.As(err, &nk):
+			// Not locked in S3, but still check DynamoDB below.
+		case err != nil:
+			return nil, fmt.Errorf("failed to retrieve s3 lock info: %w", err)
+		default:
+			return lockInfo, nil
+		}
+	}
+	if c.ddbTable != "" {
+		lockInfo, err := c.findLockInfoInDynamoDB(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve lock info: %w", err)
+		}
+		return lockInfo, nil
+	}
+	return nil, nil
+}
+
 func (c *RemoteClient) Unlock(ctx context.Context, id string) error {
 	// Attempt to release the lock from both sources.
 	// We want to do so to be sure that we are leaving no locks unhandled
@@ -32,6 +32,8 @@ import (
 func TestRemoteClient_impl(t *testing.T) {
 	var _ remote.Client = new(RemoteClient)
 	var _ remote.ClientLocker = new(RemoteClient)
+	var _ remote.ClientHistory = new(RemoteClient)
+	var _ remote.ClientLockInspector = new(RemoteClient)
 }
 
 func TestRemoteClient(t *testing.T) {
@@ -91,6 +93,7 @@ func TestRemoteClientLocks(t *testing.T) {
 	}
 
 	remote.TestRemoteLocks(t, s1.(*remote.State).Client, s2.(*remote.State).Client)
+	remote.TestLockInspector(t, s1.(*remote.State).Client, s2.(*remote.State).Client)
 }
 
 func TestRemoteS3ClientLocks(t *testing.T) {
@@ -140,6 +143,8 @@ func TestRemoteS3ClientLocks(t *testing.T) {
 
 			//nolint:errcheck // don't need to check the error from type assertion
 			remote.TestRemoteLocks(t, s1.(*remote.State).Client, s2.(*remote.State).Client)
+			//nolint:errcheck // don't need to check the error from type assertion
+			remote.TestLockInspector(t, s1.(*remote.State).Client, s2.(*remote.State).Client)
 		})
 	}
 }
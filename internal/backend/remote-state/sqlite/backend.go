@@ -0,0 +1,152 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	// Registers the "sqlite" database/sql driver, which is a pure Go
+	// implementation of SQLite and so doesn't require cgo.
+	_ "modernc.org/sqlite"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/legacy/helper/schema"
+)
+
+const (
+	statesTableName   = "states"
+	locksTableName    = "locks"
+	versionsTableName = "state_versions"
+)
+
+// uriPathEscaper escapes the characters that have a special meaning in the
+// path of an SQLite URI filename.
+var uriPathEscaper = strings.NewReplacer("%", "%25", "?", "%3f", "#", "%23")
+
+func defaultIntFunc(k string, dv int) schema.SchemaDefaultFunc {
+	return func() (interface{}, error) {
+		if v := os.Getenv(k); v != "" {
+			return strconv.Atoi(v)
+		}
+
+		return dv, nil
+	}
+}
+
+// New creates a new backend for SQLite state storage.
+func New(enc encryption.StateEncryption) backend.Backend {
+	s := &schema.Backend{
+		Schema: map[string]*schema.Schema{
+			"path": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Path to the SQLite database file that stores the states of all workspaces",
+				DefaultFunc: schema.EnvDefaultFunc("TF_SQLITE_PATH", nil),
+			},
+
+			"busy_timeout": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Milliseconds to wait for another process to finish writing to the database before failing",
+				DefaultFunc: defaultIntFunc("TF_SQLITE_BUSY_TIMEOUT", 5000),
+			},
+
+			"keep_versions": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Number of earlier versions of the state to retain for each workspace; `0` disables state history",
+				DefaultFunc: defaultIntFunc("TF_SQLITE_KEEP_VERSIONS", 0),
+			},
+		},
+	}
+
+	result := &Backend{Backend: s, encryption: enc}
+	result.Backend.ConfigureFunc = result.configure
+	return result
+}
+
+type Backend struct {
+	*schema.Backend
+	encryption encryption.StateEncryption
+
+	// The fields below are set from configure
+	db           *sql.DB
+	configData   *schema.ResourceData
+	path         string
+	keepVersions int
+}
+
+func (b *Backend) configure(ctx context.Context) error {
+	// Grab the resource data
+	b.configData = schema.FromContextBackendConfig(ctx)
+	data := b.configData
+
+	b.path = data.Get("path").(string)
+	if b.path == "" {
+		return fmt.Errorf("path must not be empty")
+	}
+	busyTimeout := data.Get("busy_timeout").(int)
+	if busyTimeout < 0 {
+		return fmt.Errorf("busy_timeout must not be negative")
+	}
+	b.keepVersions = data.Get("keep_versions").(int)
+	if b.keepVersions < 0 {
+		return fmt.Errorf("keep_versions must not be negative")
+	}
+
+	// The rollback journal is used rather than write-ahead logging, because
+	// the latter relies on shared memory and so is unsafe for a database
+	// file on a network filesystem.
+	query := url.Values{}
+	query.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeout))
+	query.Add("_pragma", "journal_mode(DELETE)")
+	query.Set("_txlock", "immediate")
+	dsn := fmt.Sprintf("file:%s?%s", uriPathEscaper.Replace(b.path), query.Encode())
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return err
+	}
+
+	// Prepare the database tables.
+	queries := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			name TEXT NOT NULL PRIMARY KEY,
+			data BLOB
+			)`, statesTableName),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			name TEXT NOT NULL PRIMARY KEY,
+			id TEXT NOT NULL,
+			info TEXT NOT NULL
+			)`, locksTableName),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			data BLOB,
+			created TEXT NOT NULL,
+			who TEXT NOT NULL
+			)`, versionsTableName),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s_by_name ON %s (name, id)`, versionsTableName, versionsTableName),
+	}
+	for _, query := range queries {
+		if _, err = db.ExecContext(ctx, query); err != nil {
+			_ = db.Close()
+			return fmt.Errorf("failed to prepare SQLite database %q: %w", b.path, err)
+		}
+	}
+
+	// Assign db after its tables are prepared.
+	b.db = db
+
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package sqlite
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/states/remote"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+// testBackend returns a backend configured to use a new database in a
+// temporary directory, along with the configuration used so that tests can
+// create other backends sharing the same database.
+func testBackend(t *testing.T, extra map[string]interface{}) (*Backend, map[string]interface{}) {
+	t.Helper()
+
+	config := map[string]interface{}{
+		"path": filepath.Join(t.TempDir(), "terraform.tfstate.db"),
+	}
+	for k, v := range extra {
+		config[k] = v
+	}
+
+	b := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(config)).(*Backend)
+	t.Cleanup(func() {
+		if err := b.db.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+	return b, config
+}
+
+func TestBackend_impl(t *testing.T) {
+	var _ backend.Backend = new(Backend)
+}
+
+func TestBackendConfig(t *testing.T) {
+	b, config := testBackend(t, nil)
+
+	if b.path != config["path"] {
+		t.Fatalf("wrong path %q; want %q", b.path, config["path"])
+	}
+	if b.keepVersions != 0 {
+		t.Fatalf("wrong keep_versions %d; want 0", b.keepVersions)
+	}
+
+	s, err := b.StateMgr(t.Context(), backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := s.(*remote.State).Client.(*RemoteClient)
+	if c.Name != backend.DefaultStateName {
+		t.Fatal("client name is not configured")
+	}
+	if _, ok := statemgr.HistoryFor(s); ok {
+		t.Fatal("history should be disabled by default")
+	}
+}
+
+func TestBackendConfig_invalid(t *testing.T) {
+	tests := map[string]map[string]interface{}{
+		"empty path": {
+			"path": "",
+		},
+		"negative busy_timeout": {
+			"path":         filepath.Join(t.TempDir(), "terraform.tfstate.db"),
+			"busy_timeout": -1,
+		},
+		"negative keep_versions": {
+			"path":          filepath.Join(t.TempDir(), "terraform.tfstate.db"),
+			"keep_versions": -1,
+		},
+	}
+	for name, config := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, _, errs := backend.TestBackendConfigWarningsAndErrors(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(config))
+			if len(errs) == 0 {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestBackendStates(t *testing.T) {
+	b, _ := testBackend(t, nil)
+	backend.TestBackendStates(t, b)
+}
+
+func TestBackendStateLocks(t *testing.T) {
+	b, config := testBackend(t, nil)
+	bb := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(config)).(*Backend)
+
+	backend.TestBackendStateLocks(t, b, bb)
+	backend.TestBackendStateForceUnlock(t, b, bb)
+}
+
+func TestBackendDeleteWorkspace(t *testing.T) {
+	b, _ := testBackend(t, map[string]interface{}{
+		"keep_versions": 2,
+	})
+
+	if _, err := b.StateMgr(t.Context(), "foo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.DeleteWorkspace(t.Context(), "foo", false); err != nil {
+		t.Fatal(err)
+	}
+
+	workspaces, err := b.Workspaces(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slices.Contains(workspaces, "foo") {
+		t.Fatalf("workspace was not deleted: %v", workspaces)
+	}
+
+	versions, err := b.client("foo").Versions(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 0 {
+		t.Fatalf("expected the versions of the workspace to be deleted, got %d", len(versions))
+	}
+
+	if err := b.DeleteWorkspace(t.Context(), backend.DefaultStateName, false); err == nil {
+		t.Fatal("expected an error deleting the default workspace")
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package sqlite
+
+import (
+	"context"
+	"crypto/md5"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	uuid "github.com/hashicorp/go-uuid"
+	"github.com/opentofu/opentofu/internal/states/remote"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+// RemoteClient is a remote client that stores data in an SQLite database
+type RemoteClient struct {
+	Client *sql.DB
+	Name   string
+
+	// KeepVersions is the number of earlier versions of the state to retain
+	// alongside the latest one. Zero disables state history.
+	KeepVersions int
+
+	info *statemgr.LockInfo
+}
+
+func (c *RemoteClient) Get(ctx context.Context) (*remote.Payload, error) {
+	query := fmt.Sprintf(`SELECT data FROM %s WHERE name = ?`, statesTableName)
+	row := c.Client.QueryRowContext(ctx, query, c.Name)
+	var data []byte
+	err := row.Scan(&data)
+	switch {
+	case err == sql.ErrNoRows:
+		// No existing state returns empty.
+		return nil, nil
+	case err != nil:
+		return nil, err
+	default:
+		md5 := md5.Sum(data)
+		return &remote.Payload{
+			Data: data,
+			MD5:  md5[:],
+		}, nil
+	}
+}
+
+func (c *RemoteClient) Put(ctx context.Context, data []byte) error {
+	tx, err := c.Client.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// Rolling back has no effect once the transaction is committed.
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf(`INSERT INTO %s (name, data) VALUES (?, ?)
+		ON CONFLICT (name) DO UPDATE SET data = excluded.data`, statesTableName)
+	if _, err := tx.ExecContext(ctx, query, c.Name, data); err != nil {
+		return err
+	}
+
+	if c.KeepVersions > 0 {
+		// The latest state is also recorded as a version, so that it's
+		// still available once it has been superseded.
+		who := statemgr.NewLockInfo().Who
+		if c.info != nil {
+			who = c.info.Who
+		}
+		query = fmt.Sprintf(`INSERT INTO %s (name, data, created, who) VALUES (?, ?, ?, ?)`, versionsTableName)
+		if _, err := tx.ExecContext(ctx, query, c.Name, data, time.Now().UTC().Format(time.RFC3339Nano), who); err != nil {
+			return err
+		}
+
+		query = fmt.Sprintf(`DELETE FROM %s WHERE name = ? AND id NOT IN (
+			SELECT id FROM %s WHERE name = ? ORDER BY id DESC LIMIT ?
+			)`, versionsTableName, versionsTableName)
+		if _, err := tx.ExecContext(ctx, query, c.Name, c.Name, c.KeepVersions+1); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (c *RemoteClient) Delete(ctx context.Context) error {
+	tx, err := c.Client.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// Rolling back has no effect once the transaction is committed.
+	defer func() { _ = tx.Rollback() }()
+
+	for _, table := range []string{statesTableName, versionsTableName} {
+		query := fmt.Sprintf(`DELETE FROM %s WHERE name = ?`, table)
+		if _, err := tx.ExecContext(ctx, query, c.Name); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (c *RemoteClient) Lock(ctx context.Context, info *statemgr.LockInfo) (string, error) {
+	var err error
+	var lockID string
+
+	if info.ID == "" {
+		lockID, err = uuid.GenerateUUID()
+		if err != nil {
+			return "", err
+		}
+		info.ID = lockID
+	}
+	info.Path = c.Name
+
+	// The lock is a row in the locks table, so the uniqueness of its name
+	// ensures that only one client can hold it.
+	query := fmt.Sprintf(`INSERT INTO %s (name, id, info) VALUES (?, ?, ?)
+		ON CONFLICT (name) DO NOTHING`, locksTableName)
+	result, err := c.Client.ExecContext(ctx, query, c.Name, info.ID, string(info.Marshal()))
+	if err != nil {
+		return "", &statemgr.LockError{Info: info, Err: err}
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return "", &statemgr.LockError{Info: info, Err: err}
+	}
+	if n == 0 {
+		lockErr := &statemgr.LockError{Err: fmt.Errorf("Workspace is already locked: %s", c.Name)}
+		existing, err := c.CurrentLock(ctx)
+		if err != nil {
+			lockErr.Err = fmt.Errorf("Workspace is already locked, and the lock info could not be read: %w", err)
+		}
+		lockErr.Info = existing
+		return "", lockErr
+	}
+	c.info = info
+
+	return info.ID, nil
+}
+
+func (c *RemoteClient) Unlock(ctx context.Context, id string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE name = ? AND id = ?`, locksTableName)
+	result, err := c.Client.ExecContext(ctx, query, c.Name, id)
+	if err != nil {
+		return &statemgr.LockError{Info: c.info, Err: err}
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return &statemgr.LockError{Info: c.info, Err: err}
+	}
+	if n == 0 {
+		lockErr := &statemgr.LockError{Err: fmt.Errorf("Workspace is not locked with lock ID %q: %s", id, c.Name)}
+		existing, err := c.CurrentLock(ctx)
+		if err != nil {
+			lockErr.Err = fmt.Errorf("failed to read the lock info: %w", err)
+		}
+		lockErr.Info = existing
+		return lockErr
+	}
+	c.info = nil
+
+	return nil
+}
+
+// CurrentLock returns the information recorded by whoever is holding the
+// lock on the workspace, or nil if it is not locked.
+func (c *RemoteClient) CurrentLock(ctx context.Context) (*statemgr.LockInfo, error) {
+	query := fmt.Sprintf(`SELECT info FROM %s WHERE name = ?`, locksTableName)
+	var raw string
+	err := c.Client.QueryRowContext(ctx, query, c.Name).Scan(&raw)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	info := &statemgr.LockInfo{}
+	if err := json.Unmarshal([]byte(raw), info); err != nil {
+		return nil, fmt.Errorf("invalid lock info for workspace %q: %w", c.Name, err)
+	}
+	return info, nil
+}
+
+// IsHistoryEnabled returns true if the client is configured to retain
+// earlier versions of the state.
+func (c *RemoteClient) IsHistoryEnabled() bool {
+	return c.KeepVersions > 0
+}
+
+// Versions returns the versions of the state retained for the workspace,
+// newest first. The ID of each version is the row ID of the version.
+func (c *RemoteClient) Versions(ctx context.Context) ([]*remote.Version, error) {
+	query := fmt.Sprintf(`SELECT id, created, who FROM %s WHERE name = ? ORDER BY id DESC`, versionsTableName)
+	rows, err := c.Client.QueryContext(ctx, query, c.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ret []*remote.Version
+	for rows.Next() {
+		var id int64
+		var created, who string
+		if err := rows.Scan(&id, &created, &who); err != nil {
+			return nil, err
+		}
+		v := &remote.Version{
+			ID:       strconv.FormatInt(id, 10),
+			Who:      who,
+			IsLatest: len(ret) == 0,
+		}
+		if t, err := time.Parse(time.RFC3339Nano, created); err == nil {
+			v.Created = t
+		}
+		ret = append(ret, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (c *RemoteClient) GetVersion(ctx context.Context, id string) (*remote.Payload, error) {
+	rowID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		// Not an ID that Versions could have returned.
+		return nil, nil
+	}
+
+	query := fmt.Sprintf(`SELECT data FROM %s WHERE name = ? AND id = ?`, versionsTableName)
+	var data []byte
+	err = c.Client.QueryRowContext(ctx, query, c.Name, rowID).Scan(&data)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	md5 := md5.Sum(data)
+	return &remote.Payload{
+		Data: data,
+		MD5:  md5[:],
+	}, nil
+}
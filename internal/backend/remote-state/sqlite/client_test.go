@@ -0,0 +1,109 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package sqlite
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/states/remote"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+func TestRemoteClient_impl(t *testing.T) {
+	var _ remote.Client = new(RemoteClient)
+	var _ remote.ClientLocker = new(RemoteClient)
+	var _ remote.ClientLockInspector = new(RemoteClient)
+	var _ remote.OptionalClientHistory = new(RemoteClient)
+}
+
+func TestRemoteClient(t *testing.T) {
+	b, _ := testBackend(t, nil)
+
+	s, err := b.StateMgr(t.Context(), backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remote.TestClient(t, s.(*remote.State).Client)
+}
+
+func TestRemoteLocks(t *testing.T) {
+	b1, config := testBackend(t, nil)
+	s1, err := b1.StateMgr(t.Context(), backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b2 := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(config)).(*Backend)
+	s2, err := b2.StateMgr(t.Context(), backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remote.TestRemoteLocks(t, s1.(*remote.State).Client, s2.(*remote.State).Client)
+	remote.TestLockInspector(t, s1.(*remote.State).Client, s2.(*remote.State).Client)
+}
+
+func TestRemoteClientHistory(t *testing.T) {
+	b, _ := testBackend(t, map[string]interface{}{
+		"keep_versions": 2,
+	})
+
+	s, err := b.StateMgr(t.Context(), backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := statemgr.HistoryFor(s); !ok {
+		t.Fatal("history should be enabled")
+	}
+
+	c := s.(*remote.State).Client
+	remote.TestClientHistory(t, c)
+
+	p, err := c.(remote.ClientHistory).GetVersion(t.Context(), "does-not-exist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p != nil {
+		t.Fatalf("expected no payload for unknown version, got: %q", string(p.Data))
+	}
+}
+
+func TestRemoteClientHistory_pruning(t *testing.T) {
+	b, _ := testBackend(t, map[string]interface{}{
+		"keep_versions": 2,
+	})
+	c := b.client(backend.DefaultStateName)
+
+	for _, data := range []string{"a", "b", "c", "d"} {
+		if err := c.Put(t.Context(), []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	versions, err := c.Versions(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The latest version is retained in addition to keep_versions earlier
+	// versions.
+	var got []string
+	for _, v := range versions {
+		p, err := c.GetVersion(t.Context(), v.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, string(p.Data))
+	}
+	want := []string{"d", "c", "b"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("wrong versions %q; want %q", got, want)
+	}
+}
@@ -7,6 +7,7 @@ package tf
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 
@@ -147,6 +148,16 @@ func dataSourceRemoteStateRead(ctx context.Context, d cty.Value, enc encryption.
 	}
 
 	if err := state.RefreshState(ctx); err != nil {
+		var unencryptedErr *encryption.ErrUnencryptedRemoteState
+		if errors.As(err, &unencryptedErr) {
+			diags = diags.Append(tfdiags.AttributeValue(
+				tfdiags.Error,
+				"Unencrypted remote state",
+				fmt.Sprintf("The remote state for %s is not encrypted, but the remote_state_data_sources encryption configuration requires it to be. Encrypt the remote state, or add %q to allow_unencrypted if reading unencrypted state is intended.", path, unencryptedErr.Name),
+				cty.Path(nil).GetAttr("backend"),
+			))
+			return cty.NilVal, diags
+		}
 		diags = diags.Append(err)
 		return cty.NilVal, diags
 	}
@@ -16,8 +16,10 @@ import (
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/encryption"
+	encryptionConfig "github.com/opentofu/opentofu/internal/encryption/config"
 	"github.com/opentofu/opentofu/internal/lang/marks"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -353,6 +355,58 @@ func TestState_basic(t *testing.T) {
 	}
 }
 
+func TestState_encryptionEnforced(t *testing.T) {
+	cfg, diags := encryptionConfig.LoadConfigFromString("test", `
+		remote_state_data_sources {
+			enforced = true
+			allow_unencrypted = ["allowed"]
+		}
+	`)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+	enc, diags := encryption.New(t.Context(), encryption.DefaultRegistry, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+
+	schema := dataSourceRemoteStateGetSchema().Block
+	config, err := schema.CoerceValue(cty.ObjectVal(map[string]cty.Value{
+		"backend": cty.StringVal("local"),
+		"config": cty.ObjectVal(map[string]cty.Value{
+			"path": cty.StringVal("./testdata/basic.tfstate"),
+		}),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	read := func(name string) tfdiags.Diagnostics {
+		_, diags := dataSourceRemoteStateRead(t.Context(), config, enc.RemoteState(name), addrs.AbsResourceInstance{
+			Resource: addrs.ResourceInstance{
+				Resource: addrs.Resource{
+					Mode: addrs.DataResourceMode,
+					Type: "terraform_remote_state",
+					Name: name,
+				},
+			},
+		})
+		return diags
+	}
+
+	enforcedDiags := read("enforced")
+	if !enforcedDiags.HasErrors() {
+		t.Fatal("succeeded reading unencrypted remote state; want error")
+	}
+	if got, want := enforcedDiags[0].Description().Summary, "Unencrypted remote state"; got != want {
+		t.Errorf("wrong error summary %q; want %q", got, want)
+	}
+
+	if allowedDiags := read("allowed"); allowedDiags.HasErrors() {
+		t.Fatalf("unexpected errors reading allow-listed remote state: %s", allowedDiags.Err())
+	}
+}
+
 func TestState_validation(t *testing.T) {
 	// The main test TestState_basic covers both validation and reading of
 	// state snapshots, so this additional test is here only to verify that
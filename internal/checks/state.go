@@ -273,6 +273,37 @@ func (c *State) ObjectFailureMessages(addr addrs.Checkable) []string {
 	return ret
 }
 
+// ObjectCheckRuleStatuses returns the status of each of the individual
+// checks for the object with the given address, grouped by the type of the
+// checks and ordered as they are declared in the configuration.
+//
+// The given address must refer to a checkable object that OpenTofu Core
+// previously reported while doing a graph walk, or this method will panic.
+func (c *State) ObjectCheckRuleStatuses(addr addrs.Checkable) map[addrs.CheckRuleType][]Status {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	configAddr := addr.ConfigCheckable()
+
+	st, ok := c.statuses.GetOk(configAddr)
+	if !ok {
+		panic(fmt.Sprintf("request for status of unknown object %s", addr))
+	}
+	if st.objects.Elems == nil {
+		panic(fmt.Sprintf("request for status of %s before establishing the checkable objects for %s", addr, configAddr))
+	}
+	checksByType, ok := st.objects.GetOk(addr)
+	if !ok {
+		panic(fmt.Sprintf("request for status of unknown object %s", addr))
+	}
+
+	ret := make(map[addrs.CheckRuleType][]Status, len(checksByType))
+	for checkType, statuses := range checksByType {
+		ret[checkType] = append([]Status(nil), statuses...)
+	}
+	return ret
+}
+
 func summarizeCheckStatuses(errorCount, failCount, unknownCount int) Status {
 	switch {
 	case errorCount > 0:
@@ -71,6 +71,23 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 	// Inject variables from args into meta for static evaluation
 	c.Meta.variableArgs = args.Vars.All()
 
+	if args.WorkspacePattern.Enabled() {
+		// We can't prompt for input in each of several concurrent applies.
+		args.ViewOptions.InputEnabled = false
+		c.Meta.input = false
+		return c.runInWorkspaces(ctx, args.WorkspacePattern, args.ViewOptions.ViewType, func(ctx context.Context, meta *Meta) int {
+			cmd := &ApplyCommand{Meta: *meta, Destroy: c.Destroy}
+			return cmd.apply(ctx, args, views.NewApply(args.ViewOptions, c.Destroy, cmd.View))
+		})
+	}
+
+	return c.apply(ctx, args, view)
+}
+
+// apply applies the changes for the currently-selected workspace.
+func (c *ApplyCommand) apply(ctx context.Context, args *arguments.Apply, view views.Apply) int {
+	var diags tfdiags.Diagnostics
+
 	// Load the encryption configuration
 	enc, encDiags := c.Encryption(ctx)
 	diags = diags.Append(encDiags)
@@ -269,6 +286,19 @@ func (c *ApplyCommand) OperationRequest(
 		opReq.Hooks = append(opReq.Hooks, &e2eTestingApplyHook{})
 	}
 	opReq.PlanFile = planFile
+	if planFile.IsLocal() {
+		// The progress of applying a local plan file is recorded next to
+		// it, so that a failed apply can be resumed later.
+		opReq.ApplyProgressPath = applyArgs.PlanPath + ".progress"
+	} else if applyArgs.Resume {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Cannot resume apply",
+			"The -resume option can only be used with a plan file saved by \"tofu plan -out\".",
+		))
+		return nil, diags
+	}
+	opReq.ResumeApply = applyArgs.Resume
 	opReq.PlanRefresh = applyArgs.Operation.Refresh
 	opReq.Targets = applyArgs.Operation.Targets
 	opReq.Excludes = applyArgs.Operation.Excludes
@@ -354,6 +384,11 @@ Options:
   -parallelism=n               Limit the number of parallel resource operations.
                                Defaults to 10.
 
+  -resume                      Apply only the changes of the given saved plan
+                               that were not completed by an earlier apply of
+                               the same plan which failed or was interrupted,
+                               starting from the state that apply left behind.
+
   -state=path                  Path to read and save state (unless state-out
                                is specified). Defaults to "terraform.tfstate".
 
@@ -367,6 +402,17 @@ Options:
                                operation completes successfully but leaves
                                forgotten instances behind.
 
+  -workspace-pattern=pattern   Apply the changes in each of the workspaces
+                               whose names match the given glob pattern, such
+                               as "prod-*", instead of only in the selected
+                               workspace, and show a summary of the results.
+                               Requires -auto-approve, and cannot be used with
+                               a saved plan file or -state.
+
+  -workspace-parallelism=n     Limit the number of workspaces applied
+                               concurrently with -workspace-pattern.
+                               Defaults to 4.
+
   -var 'foo=bar'               Set a variable in the OpenTofu configuration.
                                This flag can be set multiple times.
 
@@ -417,6 +463,17 @@ Options:
                                operation completes successfully but leaves
                                forgotten instances behind.
 
+  -workspace-pattern=pattern   Apply the changes in each of the workspaces
+                               whose names match the given glob pattern, such
+                               as "prod-*", instead of only in the selected
+                               workspace, and show a summary of the results.
+                               Requires -auto-approve, and cannot be used with
+                               a saved plan file or -state.
+
+  -workspace-parallelism=n     Limit the number of workspaces applied
+                               concurrently with -workspace-pattern.
+                               Defaults to 4.
+
   This command also accepts many of the plan-customization options accepted by
   the tofu plan command. For more information on those options, run:
       tofu plan -help
@@ -833,6 +833,8 @@ func TestApply_plan_remoteState(t *testing.T) {
 		"unlock_address":            cty.NullVal(cty.String),
 		"lock_method":               cty.NullVal(cty.String),
 		"unlock_method":             cty.NullVal(cty.String),
+		"lock_status_address":       cty.NullVal(cty.String),
+		"lock_status_method":        cty.NullVal(cty.String),
 		"username":                  cty.NullVal(cty.String),
 		"password":                  cty.NullVal(cty.String),
 		"skip_cert_verification":    cty.NullVal(cty.Bool),
@@ -1192,6 +1194,85 @@ func TestApply_shutdown(t *testing.T) {
 	}
 }
 
+func TestApply_workspacePatternShutdown(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply-shutdown"), td)
+	t.Chdir(td)
+
+	testStateFileWorkspaceDefault(t, "dev", states.NewState())
+	testStateFileWorkspaceDefault(t, "prod", states.NewState())
+
+	shutdownCh := make(chan struct{})
+
+	// The first apply interrupts the batch while the command is running in
+	// both the "default" and "dev" workspaces, so the interrupt must reach
+	// both of them and the "prod" workspace must never start.
+	var once sync.Once
+	newProvider := func() (providers.Interface, error) {
+		p := testProvider()
+		p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) (resp providers.ApplyResourceChangeResponse) {
+			once.Do(func() {
+				shutdownCh <- struct{}{}
+			})
+			time.Sleep(200 * time.Millisecond)
+
+			resp.NewState = req.PlannedState
+			return
+		}
+		p.GetProviderSchemaResponse = &providers.GetProviderSchemaResponse{
+			ResourceTypes: map[string]providers.Schema{
+				"test_instance": {
+					Block: &configschema.Block{
+						Attributes: map[string]*configschema.Attribute{
+							"ami": {Type: cty.String, Optional: true},
+						},
+					},
+				},
+			},
+		}
+		return p, nil
+	}
+
+	// Each workspace gets its own provider instances, as it would outside
+	// of tests, because the mock provider's Stop isn't safe to call
+	// concurrently.
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			WorkingDir: workdir.NewDir("."),
+			testingOverrides: &testingOverrides{
+				Providers: map[addrs.Provider]providers.Factory{
+					addrs.NewDefaultProvider("test"): newProvider,
+				},
+			},
+			View:       view,
+			ShutdownCh: shutdownCh,
+		},
+	}
+
+	code := c.Run([]string{
+		"-no-color",
+		"-auto-approve",
+		"-workspace-pattern=*",
+		"-workspace-parallelism=2",
+	})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit status %d; want 1\nstderr: %s", code, output.Stderr())
+	}
+
+	got := output.Stdout()
+	if got, want := strings.Count(got, "Interrupt received."), 2; got != want {
+		t.Errorf("interrupted %d workspaces; want %d\noutput:\n%s", got, want, output.Stdout())
+	}
+	if strings.Contains(got, `Workspace "prod":`) {
+		t.Errorf("command started in the \"prod\" workspace after the interrupt\noutput:\n%s", got)
+	}
+	if want := "  prod     skipped\n"; !strings.HasSuffix(got, want) {
+		t.Errorf("wrong summary\ngot:\n%s\nwant suffix:\n%s", got, want)
+	}
+}
+
 func TestApply_state(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
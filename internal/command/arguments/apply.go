@@ -25,6 +25,10 @@ type Apply struct {
 	// PlanPath contains an optional path to a stored plan file
 	PlanPath string
 
+	// Resume applies only the changes of the stored plan file that were not
+	// completed by an earlier apply of the same plan which failed.
+	Resume bool
+
 	// ViewOptions specifies which view options to use
 	ViewOptions ViewOptions
 
@@ -34,6 +38,9 @@ type Apply struct {
 	// SuppressForgetErrorsDuringDestroy suppresses the error that occurs when a
 	// destroy operation completes successfully but leaves forgotten instances behind.
 	SuppressForgetErrorsDuringDestroy bool
+
+	// WorkspacePattern optionally selects several workspaces to apply.
+	WorkspacePattern *WorkspacePattern
 }
 
 // ParseApply processes CLI arguments, returning an Apply value, a closer function, and errors.
@@ -42,17 +49,20 @@ type Apply struct {
 func ParseApply(args []string) (*Apply, func(), tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 	apply := &Apply{
-		State:     &State{},
-		Operation: &Operation{},
-		Vars:      &Vars{},
+		State:            &State{},
+		Operation:        &Operation{},
+		Vars:             &Vars{},
+		WorkspacePattern: &WorkspacePattern{},
 	}
 
 	cmdFlags := extendedFlagSet("apply", apply.Operation, apply.Vars)
 	cmdFlags.BoolVar(&apply.AutoApprove, "auto-approve", false, "auto-approve")
 	cmdFlags.BoolVar(&apply.ShowSensitive, "show-sensitive", false, "displays sensitive values")
 	cmdFlags.BoolVar(&apply.SuppressForgetErrorsDuringDestroy, "suppress-forget-errors", false, "suppress errors in destroy mode due to resources being forgotten")
+	cmdFlags.BoolVar(&apply.Resume, "resume", false, "resume")
 
 	apply.State.addFlags(cmdFlags, stateFlagAll)
+	apply.WorkspacePattern.addFlags(cmdFlags)
 	apply.ViewOptions.AddFlags(cmdFlags, true)
 
 	if err := cmdFlags.Parse(args); err != nil {
@@ -88,9 +98,42 @@ func ParseApply(args []string) (*Apply, func(), tfdiags.Diagnostics) {
 		))
 	}
 
+	// Only a saved plan can be resumed, since otherwise there would be no
+	// record of the changes that were planned.
+	if apply.Resume && apply.PlanPath == "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Plan file required",
+			"The -resume option applies the remaining changes of a saved plan whose earlier apply failed, so it requires the path of that plan file.",
+		))
+	}
+
+	// Similarly, we can't ask for approval of the changes for each of
+	// several workspaces, and a saved plan belongs to a single workspace.
+	if apply.WorkspacePattern.Enabled() {
+		if apply.PlanPath != "" {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Incompatible command line options",
+				"A saved plan file cannot be applied together with -workspace-pattern, because each plan belongs to a single workspace.",
+			))
+		} else if !apply.AutoApprove {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Auto-approve required",
+				"OpenTofu cannot ask for interactive approval of the changes for each workspace when -workspace-pattern is set. Enable the -auto-approve option to apply the changes without approval.",
+			))
+		}
+	}
+
 	diags = diags.Append(apply.Operation.Parse())
 	closer, moreDiags := apply.ViewOptions.Parse()
 	diags = diags.Append(moreDiags)
+	diags = diags.Append(apply.WorkspacePattern.Parse(apply.ViewOptions, map[string]bool{
+		"state":     apply.State.StatePath != "",
+		"state-out": apply.State.StateOutPath != "",
+		"backup":    apply.State.BackupPath != "",
+	}))
 
 	return apply, closer, diags
 }
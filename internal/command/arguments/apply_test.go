@@ -32,9 +32,10 @@ func TestParseApply_basicValid(t *testing.T) {
 					InputEnabled: true,
 					ViewType:     ViewHuman,
 				},
-				PlanPath: "",
-				State:    &State{Lock: true},
-				Vars:     &Vars{},
+				PlanPath:         "",
+				State:            &State{Lock: true},
+				Vars:             &Vars{},
+				WorkspacePattern: &WorkspacePattern{Parallelism: DefaultWorkspaceParallelism},
 				Operation: &Operation{
 					PlanMode:    plans.NormalMode,
 					Parallelism: 10,
@@ -50,9 +51,30 @@ func TestParseApply_basicValid(t *testing.T) {
 					InputEnabled: false,
 					ViewType:     ViewHuman,
 				},
-				PlanPath: "saved.tfplan",
-				State:    &State{Lock: true},
-				Vars:     &Vars{},
+				PlanPath:         "saved.tfplan",
+				State:            &State{Lock: true},
+				Vars:             &Vars{},
+				WorkspacePattern: &WorkspacePattern{Parallelism: DefaultWorkspaceParallelism},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+		"resume plan": {
+			[]string{"-resume", "saved.tfplan"},
+			&Apply{
+				AutoApprove: false,
+				ViewOptions: ViewOptions{
+					InputEnabled: true,
+					ViewType:     ViewHuman,
+				},
+				PlanPath:         "saved.tfplan",
+				Resume:           true,
+				State:            &State{Lock: true},
+				Vars:             &Vars{},
+				WorkspacePattern: &WorkspacePattern{Parallelism: DefaultWorkspaceParallelism},
 				Operation: &Operation{
 					PlanMode:    plans.NormalMode,
 					Parallelism: 10,
@@ -68,9 +90,10 @@ func TestParseApply_basicValid(t *testing.T) {
 					InputEnabled: true,
 					ViewType:     ViewHuman,
 				},
-				PlanPath: "",
-				State:    &State{Lock: true},
-				Vars:     &Vars{},
+				PlanPath:         "",
+				State:            &State{Lock: true},
+				Vars:             &Vars{},
+				WorkspacePattern: &WorkspacePattern{Parallelism: DefaultWorkspaceParallelism},
 				Operation: &Operation{
 					PlanMode:    plans.DestroyMode,
 					Parallelism: 10,
@@ -86,9 +109,10 @@ func TestParseApply_basicValid(t *testing.T) {
 					InputEnabled: false,
 					ViewType:     ViewJSON,
 				},
-				PlanPath: "",
-				State:    &State{Lock: true},
-				Vars:     &Vars{},
+				PlanPath:         "",
+				State:            &State{Lock: true},
+				Vars:             &Vars{},
+				WorkspacePattern: &WorkspacePattern{Parallelism: DefaultWorkspaceParallelism},
 				Operation: &Operation{
 					PlanMode:    plans.NormalMode,
 					Parallelism: 10,
@@ -179,6 +203,16 @@ func TestParseApply_tooManyArguments(t *testing.T) {
 	}
 }
 
+func TestParseApply_resumeWithoutPlan(t *testing.T) {
+	_, _, diags := ParseApply([]string{"-resume"})
+	if len(diags) == 0 {
+		t.Fatal("expected diags but got none")
+	}
+	if got, want := diags.Err().Error(), "Plan file required"; !strings.Contains(got, want) {
+		t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+	}
+}
+
 func TestParseApply_targets(t *testing.T) {
 	foobarbaz, _ := addrs.ParseTargetStr("foo_bar.baz")
 	boop, _ := addrs.ParseTargetStr("module.boop")
@@ -774,8 +808,9 @@ func TestParseApplyDestroy_basicValid(t *testing.T) {
 					InputEnabled: true,
 					ViewType:     ViewHuman,
 				},
-				State: &State{Lock: true},
-				Vars:  &Vars{},
+				State:            &State{Lock: true},
+				Vars:             &Vars{},
+				WorkspacePattern: &WorkspacePattern{Parallelism: DefaultWorkspaceParallelism},
 				Operation: &Operation{
 					PlanMode:    plans.DestroyMode,
 					Parallelism: 10,
@@ -791,8 +826,9 @@ func TestParseApplyDestroy_basicValid(t *testing.T) {
 					InputEnabled: false,
 					ViewType:     ViewHuman,
 				},
-				State: &State{Lock: true},
-				Vars:  &Vars{},
+				State:            &State{Lock: true},
+				Vars:             &Vars{},
+				WorkspacePattern: &WorkspacePattern{Parallelism: DefaultWorkspaceParallelism},
 				Operation: &Operation{
 					PlanMode:    plans.DestroyMode,
 					Parallelism: 10,
@@ -831,3 +867,47 @@ func TestParseApplyDestroy_invalid(t *testing.T) {
 		}
 	})
 }
+
+func TestParseApply_workspacePattern(t *testing.T) {
+	testCases := map[string]struct {
+		args        []string
+		wantErrText string
+	}{
+		"with auto-approve": {
+			args: []string{"-workspace-pattern=*", "-auto-approve"},
+		},
+		"without auto-approve": {
+			args:        []string{"-workspace-pattern=*"},
+			wantErrText: "Auto-approve required",
+		},
+		"with plan file": {
+			args:        []string{"-workspace-pattern=*", "-auto-approve", "saved.tfplan"},
+			wantErrText: "A saved plan file cannot be applied together with -workspace-pattern",
+		},
+		"with state-out": {
+			args:        []string{"-workspace-pattern=*", "-auto-approve", "-state-out=foo.tfstate"},
+			wantErrText: "The -state-out option cannot be used together with -workspace-pattern.",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, _, diags := ParseApply(tc.args)
+			if tc.wantErrText == "" {
+				if len(diags) > 0 {
+					t.Fatalf("unexpected diags: %v", diags)
+				}
+				if !got.WorkspacePattern.Enabled() {
+					t.Fatal("expected the workspace pattern to be enabled")
+				}
+				return
+			}
+			if len(diags) == 0 {
+				t.Fatal("expected diags but got none")
+			}
+			if got, want := diags.Err().Error(), tc.wantErrText; !strings.Contains(got, want) {
+				t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// Drift represents the command-line arguments for the drift command.
+type Drift struct {
+	// State, Operation, and Vars are the common extended flags
+	State     *State
+	Operation *Operation
+	Vars      *Vars
+
+	// SARIFOutPath is an optional path to write the drift report to, in the
+	// Static Analysis Results Interchange Format.
+	SARIFOutPath string
+
+	// ViewOptions specifies which view options to use
+	ViewOptions ViewOptions
+
+	// ShowSensitive is used to display the value of attributes marked as
+	// sensitive in the drift report.
+	ShowSensitive bool
+
+	// WorkspacePattern optionally selects several workspaces to check for
+	// drift.
+	WorkspacePattern *WorkspacePattern
+}
+
+// ParseDrift processes CLI arguments, returning a Drift value, a closer
+// function, and errors. If errors are encountered, a Drift value is still
+// returned representing the best effort interpretation of the arguments.
+func ParseDrift(args []string) (*Drift, func(), tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	drift := &Drift{
+		State:            &State{},
+		Operation:        &Operation{},
+		Vars:             &Vars{},
+		WorkspacePattern: &WorkspacePattern{},
+	}
+
+	cmdFlags := extendedFlagSet("drift", drift.Operation, drift.Vars)
+	drift.State.addFlags(cmdFlags, stateFlagAll)
+	cmdFlags.StringVar(&drift.SARIFOutPath, "sarif-out", "", "sarif-out")
+	cmdFlags.BoolVar(&drift.ShowSensitive, "show-sensitive", false, "displays sensitive values")
+	drift.WorkspacePattern.addFlags(cmdFlags)
+
+	drift.ViewOptions.AddFlags(cmdFlags, true)
+
+	if err := cmdFlags.Parse(args); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to parse command-line flags",
+			err.Error(),
+		))
+	}
+
+	args = cmdFlags.Args()
+	if len(args) > 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Too many command line arguments",
+			"To specify a working directory for the drift report, use the global -chdir flag.",
+		))
+	}
+
+	diags = diags.Append(drift.Operation.Parse())
+
+	// "tofu drift" always creates a refresh-only plan, so none of the other
+	// mode options make sense here.
+	switch drift.Operation.PlanMode {
+	case plans.NormalMode:
+		drift.Operation.PlanMode = plans.RefreshOnlyMode
+	case plans.RefreshOnlyMode:
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid mode option",
+			"The -refresh-only option is not valid for \"tofu drift\", because this command always runs in refresh-only mode.",
+		))
+	case plans.DestroyMode:
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid mode option",
+			"The -destroy option is not valid for \"tofu drift\".",
+		))
+	default:
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid mode option",
+			fmt.Sprintf("The \"tofu drift\" command doesn't support %s.", drift.Operation.PlanMode),
+		))
+	}
+	if !drift.Operation.Refresh {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Incompatible refresh options",
+			"The -refresh=false option is not valid for \"tofu drift\", because drift can only be detected by refreshing.",
+		))
+	}
+	if len(drift.Operation.ForceReplace) > 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid replace option",
+			"The -replace option is not valid for \"tofu drift\", because this command never proposes any changes.",
+		))
+	}
+
+	closer, moreDiags := drift.ViewOptions.Parse()
+	diags = diags.Append(moreDiags)
+	diags = diags.Append(drift.WorkspacePattern.Parse(drift.ViewOptions, map[string]bool{
+		"sarif-out": drift.SARIFOutPath != "",
+		"state":     drift.State.StatePath != "",
+		"state-out": drift.State.StateOutPath != "",
+		"backup":    drift.State.BackupPath != "",
+	}))
+
+	return drift, closer, diags
+}
@@ -0,0 +1,142 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+func TestParseDrift_basicValid(t *testing.T) {
+	testCases := map[string]struct {
+		args []string
+		want *Drift
+	}{
+		"defaults": {
+			nil,
+			&Drift{
+				ViewOptions: ViewOptions{
+					InputEnabled: true,
+					ViewType:     ViewHuman,
+				},
+				State:            &State{Lock: true},
+				Vars:             &Vars{},
+				WorkspacePattern: &WorkspacePattern{Parallelism: DefaultWorkspaceParallelism},
+				Operation: &Operation{
+					PlanMode:    plans.RefreshOnlyMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+		"setting all options": {
+			[]string{"-input=false", "-sarif-out=drift.sarif", "-show-sensitive"},
+			&Drift{
+				SARIFOutPath:  "drift.sarif",
+				ShowSensitive: true,
+				ViewOptions: ViewOptions{
+					InputEnabled: false,
+					ViewType:     ViewHuman,
+				},
+				State:            &State{Lock: true},
+				Vars:             &Vars{},
+				WorkspacePattern: &WorkspacePattern{Parallelism: DefaultWorkspaceParallelism},
+				Operation: &Operation{
+					PlanMode:    plans.RefreshOnlyMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+		"JSON view disables input": {
+			[]string{"-json"},
+			&Drift{
+				ViewOptions: ViewOptions{
+					InputEnabled: false,
+					ViewType:     ViewJSON,
+				},
+				State:            &State{Lock: true},
+				Vars:             &Vars{},
+				WorkspacePattern: &WorkspacePattern{Parallelism: DefaultWorkspaceParallelism},
+				Operation: &Operation{
+					PlanMode:    plans.RefreshOnlyMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+	}
+
+	cmpOpts := cmpopts.IgnoreUnexported(Operation{}, Vars{}, State{}, ViewOptions{})
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, _, diags := ParseDrift(tc.args)
+			if len(diags) > 0 {
+				t.Fatalf("unexpected diags: %v", diags)
+			}
+			if diff := cmp.Diff(tc.want, got, cmpOpts); diff != "" {
+				t.Errorf("unexpected result\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseDrift_invalid(t *testing.T) {
+	testCases := map[string]struct {
+		args    []string
+		wantErr string
+	}{
+		"unknown flag": {
+			[]string{"-frob"},
+			"flag provided but not defined",
+		},
+		"too many arguments": {
+			[]string{"foo"},
+			"Too many command line arguments",
+		},
+		"refresh-only": {
+			[]string{"-refresh-only"},
+			"The -refresh-only option is not valid for \"tofu drift\"",
+		},
+		"destroy": {
+			[]string{"-destroy"},
+			"The -destroy option is not valid for \"tofu drift\"",
+		},
+		"refresh=false": {
+			[]string{"-refresh=false"},
+			"The -refresh=false option is not valid for \"tofu drift\"",
+		},
+		"replace": {
+			[]string{"-replace=test_instance.foo"},
+			"The -replace option is not valid for \"tofu drift\"",
+		},
+		"sarif-out with workspace pattern": {
+			[]string{"-workspace-pattern=*", "-sarif-out=drift.sarif"},
+			"The -sarif-out option cannot be used together with -workspace-pattern.",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, _, diags := ParseDrift(tc.args)
+			if len(diags) == 0 {
+				t.Fatal("expected diags but got none")
+			}
+			if got, want := diags.Err().Error(), tc.wantErr; !strings.Contains(got, want) {
+				t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+			}
+			if got.ViewOptions.ViewType != ViewHuman {
+				t.Fatalf("wrong view type, got %#v, want %#v", got.ViewOptions.ViewType, ViewHuman)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// EncryptionInspect represents the command-line arguments for the encryption
+// inspect command.
+type EncryptionInspect struct {
+	// Path is the path of the state or plan file to inspect.
+	Path string
+
+	// DetailedExitCode makes the command exit with status 2 instead of 0 when
+	// the file can be decrypted, but not with the primary encryption method.
+	DetailedExitCode bool
+
+	// Vars holds and provides information for the flags related to variables that a user can give into the process
+	Vars *Vars
+	// ViewOptions specifies which view options to use
+	ViewOptions ViewOptions
+}
+
+// ParseEncryptionInspect processes CLI arguments, returning an
+// EncryptionInspect value, a closer function, and errors. If errors are
+// encountered, an EncryptionInspect value is still returned representing the
+// best effort interpretation of the arguments.
+func ParseEncryptionInspect(args []string) (*EncryptionInspect, func(), tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	arguments := &EncryptionInspect{
+		Vars: &Vars{},
+	}
+
+	cmdFlags := extendedFlagSet("encryption inspect", nil, arguments.Vars)
+	cmdFlags.BoolVar(&arguments.DetailedExitCode, "detailed-exitcode", false, "detailed-exitcode")
+	arguments.ViewOptions.AddFlags(cmdFlags, false)
+
+	if err := cmdFlags.Parse(args); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to parse command-line flags",
+			err.Error(),
+		))
+	}
+
+	closer, moreDiags := arguments.ViewOptions.Parse()
+	diags = diags.Append(moreDiags)
+	if diags.HasErrors() {
+		return arguments, closer, diags
+	}
+	if len(cmdFlags.Args()) != 1 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid arguments",
+			"The encryption inspect command expects exactly one argument: the path of the state or plan file to inspect.",
+		))
+		return arguments, closer, diags
+	}
+	arguments.Path = cmdFlags.Args()[0]
+
+	return arguments, closer, diags
+}
@@ -0,0 +1,95 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestParseEncryptionInspect_basicValidation(t *testing.T) {
+	testCases := map[string]struct {
+		args        []string
+		want        *EncryptionInspect
+		wantErrText string
+	}{
+		"path": {
+			args: []string{"terraform.tfstate"},
+			want: encryptionInspectArgsWithDefaults(func(a *EncryptionInspect) {
+				a.Path = "terraform.tfstate"
+			}),
+		},
+		"detailed exit code": {
+			args: []string{"-detailed-exitcode", "tfplan"},
+			want: encryptionInspectArgsWithDefaults(func(a *EncryptionInspect) {
+				a.Path = "tfplan"
+				a.DetailedExitCode = true
+			}),
+		},
+		"json": {
+			args: []string{"-json", "tfplan"},
+			want: encryptionInspectArgsWithDefaults(func(a *EncryptionInspect) {
+				a.Path = "tfplan"
+				a.ViewOptions.ViewType = ViewJSON
+			}),
+		},
+		"without arguments": {
+			args:        nil,
+			want:        encryptionInspectArgsWithDefaults(nil),
+			wantErrText: "Invalid arguments: The encryption inspect command expects exactly one argument",
+		},
+		"too many arguments": {
+			args:        []string{"terraform.tfstate", "tfplan"},
+			want:        encryptionInspectArgsWithDefaults(nil),
+			wantErrText: "Invalid arguments: The encryption inspect command expects exactly one argument",
+		},
+		"invalid flag": {
+			args:        []string{"-invalid", "tfplan"},
+			want:        encryptionInspectArgsWithDefaults(nil),
+			wantErrText: "Failed to parse command-line flags: flag provided but not defined: -invalid",
+		},
+	}
+
+	cmpOpts := cmpopts.IgnoreUnexported(Vars{}, ViewOptions{})
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, closer, diags := ParseEncryptionInspect(tc.args)
+			defer closer()
+
+			if tc.wantErrText != "" && len(diags) == 0 {
+				t.Errorf("test wanted error but got nothing")
+			} else if tc.wantErrText == "" && len(diags) > 0 {
+				t.Errorf("test didn't expect errors but got some: %s", diags.ErrWithWarnings())
+			} else if tc.wantErrText != "" && len(diags) > 0 {
+				errStr := diags.ErrWithWarnings().Error()
+				if !strings.Contains(errStr, tc.wantErrText) {
+					t.Errorf("the returned diagnostics does not contain the expected error message.\ndiags:\n%s\nwanted: %s\n", errStr, tc.wantErrText)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got, cmpOpts); diff != "" {
+				t.Errorf("unexpected result\n%s", diff)
+			}
+		})
+	}
+}
+
+func encryptionInspectArgsWithDefaults(mutate func(a *EncryptionInspect)) *EncryptionInspect {
+	ret := &EncryptionInspect{
+		ViewOptions: ViewOptions{
+			ViewType:     ViewHuman,
+			InputEnabled: false,
+		},
+		Vars: &Vars{},
+	}
+	if mutate != nil {
+		mutate(ret)
+	}
+	return ret
+}
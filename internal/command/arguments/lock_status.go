@@ -0,0 +1,61 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// LockStatus represents the command-line arguments for the lock status
+// command.
+type LockStatus struct {
+	// DetailedExitCode makes the command exit with status 2 instead of 0 when
+	// the state is locked.
+	DetailedExitCode bool
+
+	// Vars holds and provides information for the flags related to variables that a user can give into the process
+	Vars *Vars
+	// ViewOptions specifies which view options to use
+	ViewOptions ViewOptions
+}
+
+// ParseLockStatus processes CLI arguments, returning a LockStatus value, a
+// closer function, and errors. If errors are encountered, a LockStatus value
+// is still returned representing the best effort interpretation of the
+// arguments.
+func ParseLockStatus(args []string) (*LockStatus, func(), tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	arguments := &LockStatus{
+		Vars: &Vars{},
+	}
+
+	cmdFlags := extendedFlagSet("lock status", nil, arguments.Vars)
+	cmdFlags.BoolVar(&arguments.DetailedExitCode, "detailed-exitcode", false, "detailed-exitcode")
+	arguments.ViewOptions.AddFlags(cmdFlags, false)
+
+	if err := cmdFlags.Parse(args); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to parse command-line flags",
+			err.Error(),
+		))
+	}
+
+	closer, moreDiags := arguments.ViewOptions.Parse()
+	diags = diags.Append(moreDiags)
+	if diags.HasErrors() {
+		return arguments, closer, diags
+	}
+	if args := cmdFlags.Args(); len(args) > 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Too many command line arguments",
+			"Expected no positional arguments.",
+		))
+	}
+
+	return arguments, closer, diags
+}
@@ -0,0 +1,86 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestParseLockStatus_basicValidation(t *testing.T) {
+	testCases := map[string]struct {
+		args        []string
+		want        *LockStatus
+		wantErrText string
+	}{
+		"without arguments": {
+			args: nil,
+			want: lockStatusArgsWithDefaults(nil),
+		},
+		"detailed exit code": {
+			args: []string{"-detailed-exitcode"},
+			want: lockStatusArgsWithDefaults(func(a *LockStatus) {
+				a.DetailedExitCode = true
+			}),
+		},
+		"json": {
+			args: []string{"-json"},
+			want: lockStatusArgsWithDefaults(func(a *LockStatus) {
+				a.ViewOptions.ViewType = ViewJSON
+			}),
+		},
+		"too many arguments": {
+			args:        []string{"lockid"},
+			want:        lockStatusArgsWithDefaults(nil),
+			wantErrText: "Too many command line arguments: Expected no positional arguments.",
+		},
+		"invalid flag": {
+			args:        []string{"-invalid"},
+			want:        lockStatusArgsWithDefaults(nil),
+			wantErrText: "Failed to parse command-line flags: flag provided but not defined: -invalid",
+		},
+	}
+
+	cmpOpts := cmpopts.IgnoreUnexported(Vars{}, ViewOptions{})
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, closer, diags := ParseLockStatus(tc.args)
+			defer closer()
+
+			if tc.wantErrText != "" && len(diags) == 0 {
+				t.Errorf("test wanted error but got nothing")
+			} else if tc.wantErrText == "" && len(diags) > 0 {
+				t.Errorf("test didn't expect errors but got some: %s", diags.ErrWithWarnings())
+			} else if tc.wantErrText != "" && len(diags) > 0 {
+				errStr := diags.ErrWithWarnings().Error()
+				if !strings.Contains(errStr, tc.wantErrText) {
+					t.Errorf("the returned diagnostics does not contain the expected error message.\ndiags:\n%s\nwanted: %s\n", errStr, tc.wantErrText)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got, cmpOpts); diff != "" {
+				t.Errorf("unexpected result\n%s", diff)
+			}
+		})
+	}
+}
+
+func lockStatusArgsWithDefaults(mutate func(a *LockStatus)) *LockStatus {
+	ret := &LockStatus{
+		ViewOptions: ViewOptions{
+			ViewType:     ViewHuman,
+			InputEnabled: false,
+		},
+		Vars: &Vars{},
+	}
+	if mutate != nil {
+		mutate(ret)
+	}
+	return ret
+}
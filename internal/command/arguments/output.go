@@ -22,6 +22,10 @@ type Output struct {
 	// Vars and State are the common extended flags
 	Vars  *Vars
 	State *State
+
+	// WorkspacePattern optionally selects several workspaces to show the
+	// outputs of.
+	WorkspacePattern *WorkspacePattern
 }
 
 // ParseOutput processes CLI arguments, returning an Output value, a closer function, and errors.
@@ -30,14 +34,16 @@ type Output struct {
 func ParseOutput(args []string) (*Output, func(), tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 	output := &Output{
-		Vars:  &Vars{},
-		State: &State{},
+		Vars:             &Vars{},
+		State:            &State{},
+		WorkspacePattern: &WorkspacePattern{},
 	}
 
 	var rawOutput bool
 	cmdFlags := extendedFlagSet("output", nil, output.Vars)
 	cmdFlags.BoolVar(&rawOutput, "raw", false, "raw")
 	output.State.addFlags(cmdFlags, stateFlagStateIn)
+	output.WorkspacePattern.addFlags(cmdFlags)
 	cmdFlags.BoolVar(&output.ShowSensitive, "show-sensitive", false, "displays sensitive values")
 
 	output.ViewOptions.AddFlags(cmdFlags, false)
@@ -88,5 +94,10 @@ func ParseOutput(args []string) (*Output, func(), tfdiags.Diagnostics) {
 		))
 	}
 
+	diags = diags.Append(output.WorkspacePattern.Parse(output.ViewOptions, map[string]bool{
+		"raw":   rawOutput,
+		"state": output.State.StatePath != "",
+	}))
+
 	return output, closer, diags
 }
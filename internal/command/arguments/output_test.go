@@ -71,6 +71,22 @@ func TestParseOutput_basicValidation(t *testing.T) {
 			}),
 			wantErrText: "Unexpected argument: The output command expects exactly one argument with the name of an output variable or no arguments to show all outputs.",
 		},
+		"workspace pattern": {
+			args: []string{"-workspace-pattern=prod-*", "-json"},
+			want: outputArgsWithDefaults(func(a *Output) {
+				a.ViewOptions.ViewType = ViewJSON
+				a.WorkspacePattern.Pattern = "prod-*"
+			}),
+		},
+		"workspace pattern and raw specified": {
+			args: []string{"-workspace-pattern=prod-*", "-raw", "foo"},
+			want: outputArgsWithDefaults(func(a *Output) {
+				a.Name = "foo"
+				a.ViewOptions.ViewType = ViewRaw
+				a.WorkspacePattern.Pattern = "prod-*"
+			}),
+			wantErrText: "Incompatible command line options: The -raw option cannot be used together with -workspace-pattern.",
+		},
 	}
 	cmpOpts := cmpopts.IgnoreUnexported(ViewOptions{}, Vars{})
 	for name, tc := range testCases {
@@ -101,8 +117,9 @@ func outputArgsWithDefaults(mutate func(a *Output)) *Output {
 		ViewOptions: ViewOptions{
 			ViewType: ViewHuman,
 		},
-		Vars:  &Vars{},
-		State: &State{},
+		Vars:             &Vars{},
+		State:            &State{},
+		WorkspacePattern: &WorkspacePattern{Parallelism: DefaultWorkspaceParallelism},
 	}
 	if mutate != nil {
 		mutate(ret)
@@ -33,6 +33,9 @@ type Plan struct {
 
 	// ShowSensitive is used to display the value of variables marked as sensitive.
 	ShowSensitive bool
+
+	// WorkspacePattern optionally selects several workspaces to plan.
+	WorkspacePattern *WorkspacePattern
 }
 
 // ParsePlan processes CLI arguments, returning a Plan value, a closer function, and errors.
@@ -41,9 +44,10 @@ type Plan struct {
 func ParsePlan(args []string) (*Plan, func(), tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 	plan := &Plan{
-		State:     &State{},
-		Operation: &Operation{},
-		Vars:      &Vars{},
+		State:            &State{},
+		Operation:        &Operation{},
+		Vars:             &Vars{},
+		WorkspacePattern: &WorkspacePattern{},
 	}
 
 	cmdFlags := extendedFlagSet("plan", plan.Operation, plan.Vars)
@@ -52,6 +56,7 @@ func ParsePlan(args []string) (*Plan, func(), tfdiags.Diagnostics) {
 	cmdFlags.StringVar(&plan.OutPath, "out", "", "out")
 	cmdFlags.StringVar(&plan.GenerateConfigPath, "generate-config-out", "", "generate-config-out")
 	cmdFlags.BoolVar(&plan.ShowSensitive, "show-sensitive", false, "displays sensitive values")
+	plan.WorkspacePattern.addFlags(cmdFlags)
 
 	plan.ViewOptions.AddFlags(cmdFlags, true)
 
@@ -76,6 +81,13 @@ func ParsePlan(args []string) (*Plan, func(), tfdiags.Diagnostics) {
 	diags = diags.Append(plan.Operation.Parse())
 	closer, moreDiags := plan.ViewOptions.Parse()
 	diags = diags.Append(moreDiags)
+	diags = diags.Append(plan.WorkspacePattern.Parse(plan.ViewOptions, map[string]bool{
+		"out":                 plan.OutPath != "",
+		"generate-config-out": plan.GenerateConfigPath != "",
+		"state":               plan.State.StatePath != "",
+		"state-out":           plan.State.StateOutPath != "",
+		"backup":              plan.State.BackupPath != "",
+	}))
 
 	return plan, closer, diags
 }
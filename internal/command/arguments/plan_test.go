@@ -32,9 +32,10 @@ func TestParsePlan_basicValid(t *testing.T) {
 					InputEnabled: true,
 					ViewType:     ViewHuman,
 				},
-				OutPath: "",
-				State:   &State{Lock: true},
-				Vars:    &Vars{},
+				OutPath:          "",
+				State:            &State{Lock: true},
+				Vars:             &Vars{},
+				WorkspacePattern: &WorkspacePattern{Parallelism: DefaultWorkspaceParallelism},
 				Operation: &Operation{
 					PlanMode:    plans.NormalMode,
 					Parallelism: 10,
@@ -50,9 +51,10 @@ func TestParsePlan_basicValid(t *testing.T) {
 					InputEnabled: false,
 					ViewType:     ViewHuman,
 				},
-				OutPath: "saved.tfplan",
-				State:   &State{Lock: true},
-				Vars:    &Vars{},
+				OutPath:          "saved.tfplan",
+				State:            &State{Lock: true},
+				Vars:             &Vars{},
+				WorkspacePattern: &WorkspacePattern{Parallelism: DefaultWorkspaceParallelism},
 				Operation: &Operation{
 					PlanMode:    plans.DestroyMode,
 					Parallelism: 10,
@@ -68,9 +70,10 @@ func TestParsePlan_basicValid(t *testing.T) {
 					InputEnabled: false,
 					ViewType:     ViewJSON,
 				},
-				OutPath: "",
-				State:   &State{Lock: true},
-				Vars:    &Vars{},
+				OutPath:          "",
+				State:            &State{Lock: true},
+				Vars:             &Vars{},
+				WorkspacePattern: &WorkspacePattern{Parallelism: DefaultWorkspaceParallelism},
 				Operation: &Operation{
 					PlanMode:    plans.NormalMode,
 					Parallelism: 10,
@@ -659,3 +662,64 @@ func TestParsePlan_vars(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePlan_workspacePattern(t *testing.T) {
+	testCases := map[string]struct {
+		args        []string
+		want        *WorkspacePattern
+		wantErrText string
+	}{
+		"disabled by default": {
+			args: nil,
+			want: &WorkspacePattern{Parallelism: DefaultWorkspaceParallelism},
+		},
+		"pattern and parallelism": {
+			args: []string{"-workspace-pattern=prod-*", "-workspace-parallelism=2"},
+			want: &WorkspacePattern{Pattern: "prod-*", Parallelism: 2},
+		},
+		"invalid pattern": {
+			args:        []string{"-workspace-pattern=prod-["},
+			want:        &WorkspacePattern{Pattern: "prod-[", Parallelism: DefaultWorkspaceParallelism},
+			wantErrText: "Invalid workspace pattern",
+		},
+		"invalid parallelism": {
+			args:        []string{"-workspace-pattern=*", "-workspace-parallelism=0"},
+			want:        &WorkspacePattern{Pattern: "*", Parallelism: 0},
+			wantErrText: "Invalid workspace parallelism",
+		},
+		"with out": {
+			args:        []string{"-workspace-pattern=*", "-out=saved.tfplan"},
+			want:        &WorkspacePattern{Pattern: "*", Parallelism: DefaultWorkspaceParallelism},
+			wantErrText: "The -out option cannot be used together with -workspace-pattern.",
+		},
+		"with state": {
+			args:        []string{"-workspace-pattern=*", "-state=foo.tfstate"},
+			want:        &WorkspacePattern{Pattern: "*", Parallelism: DefaultWorkspaceParallelism},
+			wantErrText: "The -state option cannot be used together with -workspace-pattern.",
+		},
+		"parallelism ignored without pattern": {
+			args: []string{"-workspace-parallelism=0"},
+			want: &WorkspacePattern{Parallelism: 0},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, _, diags := ParsePlan(tc.args)
+			if tc.wantErrText == "" && len(diags) > 0 {
+				t.Fatalf("unexpected diags: %v", diags)
+			}
+			if tc.wantErrText != "" {
+				if len(diags) == 0 {
+					t.Fatal("expected diags but got none")
+				}
+				if got, want := diags.Err().Error(), tc.wantErrText; !strings.Contains(got, want) {
+					t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got.WorkspacePattern); diff != "" {
+				t.Errorf("unexpected result\n%s", diff)
+			}
+		})
+	}
+}
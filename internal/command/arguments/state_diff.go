@@ -0,0 +1,144 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+const (
+	// stateDiffWorkspacePrefix introduces a state diff source that refers to
+	// the latest state of a workspace in the configured backend.
+	stateDiffWorkspacePrefix = "workspace:"
+
+	// stateDiffHistoryPrefix introduces a state diff source that refers to a
+	// snapshot listed by "tofu state history" for the current workspace.
+	stateDiffHistoryPrefix = "history:"
+)
+
+// StateDiff represents the command-line arguments for the 'state diff' command.
+type StateDiff struct {
+	// From and To are the two states to compare. Changes are reported as
+	// what would need to happen to From to produce To.
+	From, To StateDiffSource
+
+	// ShowSensitive forces the human view to also print the sensitive values
+	// that differ between the two states.
+	ShowSensitive bool
+
+	// ViewOptions specifies which view options to use
+	ViewOptions ViewOptions
+
+	// Vars are the common extended flags
+	Vars *Vars
+}
+
+// StateDiffSource describes where to read one of the states compared by the
+// 'state diff' command. Exactly one of the fields is set.
+type StateDiffSource struct {
+	// Path is the path of a local state file.
+	Path string
+
+	// Workspace is the name of a workspace in the configured backend.
+	Workspace string
+
+	// VersionID is the ID of an earlier snapshot of the state of the
+	// currently-selected workspace.
+	VersionID string
+}
+
+func (s StateDiffSource) String() string {
+	switch {
+	case s.Workspace != "":
+		return stateDiffWorkspacePrefix + s.Workspace
+	case s.VersionID != "":
+		return stateDiffHistoryPrefix + s.VersionID
+	default:
+		return s.Path
+	}
+}
+
+// ParseStateDiff processes CLI arguments, returning a StateDiff value, a closer function, and errors.
+// If errors are encountered, a StateDiff value is still returned representing
+// the best effort interpretation of the arguments.
+func ParseStateDiff(args []string) (*StateDiff, func(), tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	ret := &StateDiff{
+		Vars: &Vars{},
+	}
+
+	cmdFlags := extendedFlagSet("state diff", nil, ret.Vars)
+	cmdFlags.BoolVar(&ret.ShowSensitive, "show-sensitive", false, "displays sensitive values")
+	ret.ViewOptions.AddFlags(cmdFlags, false)
+
+	if err := cmdFlags.Parse(args); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to parse command-line flags",
+			err.Error(),
+		))
+	}
+
+	args = cmdFlags.Args()
+	if len(args) != 2 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid number of arguments",
+			"Exactly two arguments expected: the states to compare",
+		))
+	} else {
+		var moreDiags tfdiags.Diagnostics
+		ret.From, moreDiags = parseStateDiffSource(args[0])
+		diags = diags.Append(moreDiags)
+		ret.To, moreDiags = parseStateDiffSource(args[1])
+		diags = diags.Append(moreDiags)
+	}
+
+	closer, moreDiags := ret.ViewOptions.Parse()
+	diags = diags.Append(moreDiags)
+
+	return ret, closer, diags
+}
+
+func parseStateDiffSource(raw string) (StateDiffSource, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	var ret StateDiffSource
+
+	switch {
+	case strings.HasPrefix(raw, stateDiffWorkspacePrefix):
+		ret.Workspace = strings.TrimPrefix(raw, stateDiffWorkspacePrefix)
+		if ret.Workspace == "" {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid state to compare",
+				fmt.Sprintf("The argument %q must include the name of a workspace after %q.", raw, stateDiffWorkspacePrefix),
+			))
+		}
+	case strings.HasPrefix(raw, stateDiffHistoryPrefix):
+		ret.VersionID = strings.TrimPrefix(raw, stateDiffHistoryPrefix)
+		if ret.VersionID == "" {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid state to compare",
+				fmt.Sprintf("The argument %q must include a version ID from \"tofu state history\" after %q.", raw, stateDiffHistoryPrefix),
+			))
+		}
+	case raw == "":
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid state to compare",
+			"The states to compare must not be empty strings.",
+		))
+	default:
+		ret.Path = raw
+	}
+
+	return ret, diags
+}
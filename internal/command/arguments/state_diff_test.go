@@ -0,0 +1,135 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestParseStateDiff_basicValidation(t *testing.T) {
+	testCases := map[string]struct {
+		args        []string
+		want        *StateDiff
+		wantErrText string
+	}{
+		"two files": {
+			args: []string{"before.tfstate", "after.tfstate"},
+			want: stateDiffArgsWithDefaults(func(stateDiff *StateDiff) {
+				stateDiff.From = StateDiffSource{Path: "before.tfstate"}
+				stateDiff.To = StateDiffSource{Path: "after.tfstate"}
+			}),
+		},
+		"workspaces": {
+			args: []string{"workspace:staging", "workspace:production"},
+			want: stateDiffArgsWithDefaults(func(stateDiff *StateDiff) {
+				stateDiff.From = StateDiffSource{Workspace: "staging"}
+				stateDiff.To = StateDiffSource{Workspace: "production"}
+			}),
+		},
+		"history snapshot and file": {
+			args: []string{"history:terraform.tfstate.backup", "terraform.tfstate"},
+			want: stateDiffArgsWithDefaults(func(stateDiff *StateDiff) {
+				stateDiff.From = StateDiffSource{VersionID: "terraform.tfstate.backup"}
+				stateDiff.To = StateDiffSource{Path: "terraform.tfstate"}
+			}),
+		},
+		"show-sensitive and json": {
+			args: []string{"-show-sensitive", "-json", "a.tfstate", "b.tfstate"},
+			want: stateDiffArgsWithDefaults(func(stateDiff *StateDiff) {
+				stateDiff.ShowSensitive = true
+				stateDiff.ViewOptions.ViewType = ViewJSON
+				stateDiff.From = StateDiffSource{Path: "a.tfstate"}
+				stateDiff.To = StateDiffSource{Path: "b.tfstate"}
+			}),
+		},
+		"missing workspace name": {
+			args: []string{"workspace:", "b.tfstate"},
+			want: stateDiffArgsWithDefaults(func(stateDiff *StateDiff) {
+				stateDiff.To = StateDiffSource{Path: "b.tfstate"}
+			}),
+			wantErrText: `The argument "workspace:" must include the name of a workspace`,
+		},
+		"missing version ID": {
+			args: []string{"a.tfstate", "history:"},
+			want: stateDiffArgsWithDefaults(func(stateDiff *StateDiff) {
+				stateDiff.From = StateDiffSource{Path: "a.tfstate"}
+			}),
+			wantErrText: `The argument "history:" must include a version ID`,
+		},
+		"one argument": {
+			args:        []string{"a.tfstate"},
+			want:        stateDiffArgsWithDefaults(nil),
+			wantErrText: "Invalid number of arguments",
+		},
+		"too many arguments": {
+			args:        []string{"a.tfstate", "b.tfstate", "c.tfstate"},
+			want:        stateDiffArgsWithDefaults(nil),
+			wantErrText: "Invalid number of arguments",
+		},
+		"unknown flag": {
+			args:        []string{"-unknown-flag"},
+			want:        stateDiffArgsWithDefaults(nil),
+			wantErrText: "Failed to parse command-line flags: flag provided but not defined: -unknown-flag",
+		},
+	}
+
+	cmpOpts := cmp.Options{
+		cmpopts.IgnoreUnexported(Vars{}, ViewOptions{}),
+		cmpopts.IgnoreFields(ViewOptions{}, "JSONInto"), // We ignore JSONInto because it contains a file which is not really diffable
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, closer, diags := ParseStateDiff(tc.args)
+			defer closer()
+
+			if tc.wantErrText != "" && len(diags) == 0 {
+				t.Errorf("test wanted error but got nothing")
+			} else if tc.wantErrText == "" && len(diags) > 0 {
+				t.Errorf("test didn't expect errors but got some: %s", diags.ErrWithWarnings())
+			} else if tc.wantErrText != "" && len(diags) > 0 {
+				errStr := diags.ErrWithWarnings().Error()
+				if !strings.Contains(errStr, tc.wantErrText) {
+					t.Errorf("the returned diagnostics does not contain the expected error message.\ndiags:\n%s\nwanted: %s\n", errStr, tc.wantErrText)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got, cmpOpts); diff != "" {
+				t.Errorf("unexpected result\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStateDiffSource_String(t *testing.T) {
+	for _, raw := range []string{"a.tfstate", "workspace:default", "history:3"} {
+		got, diags := parseStateDiffSource(raw)
+		if diags.HasErrors() {
+			t.Fatalf("unexpected errors: %s", diags.Err())
+		}
+		if got.String() != raw {
+			t.Errorf("wrong result\ngot:  %s\nwant: %s", got.String(), raw)
+		}
+	}
+}
+
+func stateDiffArgsWithDefaults(mutate func(stateDiff *StateDiff)) *StateDiff {
+	ret := &StateDiff{
+		ShowSensitive: false,
+		ViewOptions: ViewOptions{
+			ViewType:     ViewHuman,
+			InputEnabled: false,
+		},
+		Vars: &Vars{},
+	}
+	if mutate != nil {
+		mutate(ret)
+	}
+	return ret
+}
@@ -0,0 +1,64 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// StateHistory represents the command-line arguments for the 'state history' command.
+type StateHistory struct {
+	// VersionID is the ID of a single retained snapshot to show, as reported by 'state history'. If empty, all the
+	// retained snapshots are listed.
+	VersionID string
+
+	// ViewOptions specifies which view options to use
+	ViewOptions ViewOptions
+
+	// Vars and State are the common extended flags
+	Vars  *Vars
+	State *State
+}
+
+// ParseStateHistory processes CLI arguments, returning a StateHistory value, a closer function, and errors.
+// If errors are encountered, a StateHistory value is still returned representing
+// the best effort interpretation of the arguments.
+func ParseStateHistory(args []string) (*StateHistory, func(), tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	ret := &StateHistory{
+		Vars:  &Vars{},
+		State: &State{},
+	}
+
+	cmdFlags := extendedFlagSet("state history", nil, ret.Vars)
+	ret.State.addFlags(cmdFlags, stateFlagStateIn)
+	ret.ViewOptions.AddFlags(cmdFlags, false)
+
+	if err := cmdFlags.Parse(args); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to parse command-line flags",
+			err.Error(),
+		))
+	}
+
+	args = cmdFlags.Args()
+	if len(args) > 1 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unexpected argument",
+			"Too many command line arguments. Did you mean to use -chdir?",
+		))
+	} else if len(args) == 1 {
+		ret.VersionID = args[0]
+	}
+
+	closer, moreDiags := ret.ViewOptions.Parse()
+	diags = diags.Append(moreDiags)
+
+	return ret, closer, diags
+}
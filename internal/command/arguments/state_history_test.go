@@ -0,0 +1,96 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestParseStateHistory_basicValidation(t *testing.T) {
+	testCases := map[string]struct {
+		args        []string
+		want        *StateHistory
+		wantErrText string
+	}{
+		"defaults": {
+			args: []string{},
+			want: stateHistoryArgsWithDefaults(nil),
+		},
+		"custom state path": {
+			args: []string{"-state=/path/to/state.tfstate"},
+			want: stateHistoryArgsWithDefaults(func(v *StateHistory) {
+				v.State.StatePath = "/path/to/state.tfstate"
+			}),
+		},
+		"json": {
+			args: []string{"-json"},
+			want: stateHistoryArgsWithDefaults(func(v *StateHistory) {
+				v.ViewOptions.ViewType = ViewJSON
+			}),
+		},
+		"version ID": {
+			args: []string{"foo"},
+			want: stateHistoryArgsWithDefaults(func(v *StateHistory) {
+				v.VersionID = "foo"
+			}),
+		},
+		"too many arguments": {
+			args:        []string{"foo", "bar"},
+			want:        stateHistoryArgsWithDefaults(nil),
+			wantErrText: "Too many command line arguments",
+		},
+		"invalid flags": {
+			args:        []string{"-unknown"},
+			want:        stateHistoryArgsWithDefaults(nil),
+			wantErrText: "Failed to parse command-line flags: flag provided but not defined: -unknown",
+		},
+	}
+
+	cmpOpts := cmp.Options{
+		cmpopts.IgnoreUnexported(Vars{}, ViewOptions{}),
+		cmpopts.IgnoreFields(ViewOptions{}, "JSONInto"), // We ignore JSONInto because it contains a file which is not really diffable
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, closer, diags := ParseStateHistory(tc.args)
+			defer closer()
+
+			if tc.wantErrText != "" && len(diags) == 0 {
+				t.Errorf("test wanted error but got nothing")
+			} else if tc.wantErrText == "" && len(diags) > 0 {
+				t.Errorf("test didn't expect errors but got some: %s", diags.ErrWithWarnings())
+			} else if tc.wantErrText != "" && len(diags) > 0 {
+				errStr := diags.ErrWithWarnings().Error()
+				if !strings.Contains(errStr, tc.wantErrText) {
+					t.Errorf("the returned diagnostics does not contain the expected error message.\ndiags:\n\t%s\nwanted:\n\t%s\n", errStr, tc.wantErrText)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got, cmpOpts); diff != "" {
+				t.Errorf("unexpected result\n%s", diff)
+			}
+		})
+	}
+}
+
+func stateHistoryArgsWithDefaults(mutate func(v *StateHistory)) *StateHistory {
+	ret := &StateHistory{
+		State: &State{},
+		ViewOptions: ViewOptions{
+			ViewType:     ViewHuman,
+			InputEnabled: false,
+		},
+		Vars: &Vars{},
+	}
+	if mutate != nil {
+		mutate(ret)
+	}
+	return ret
+}
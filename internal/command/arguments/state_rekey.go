@@ -0,0 +1,68 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// StateRekey represents the command-line arguments for the 'state rekey' command.
+type StateRekey struct {
+	// WorkspacePattern optionally selects several workspaces to re-encrypt the state of.
+	WorkspacePattern *WorkspacePattern
+
+	// ViewOptions specifies which view options to use
+	ViewOptions ViewOptions
+
+	// Vars, Backend and State are the common extended flags
+	Vars    *Vars
+	Backend *Backend
+	State   *State
+}
+
+// ParseStateRekey processes CLI arguments, returning a StateRekey value, a closer function, and errors.
+// If errors are encountered, a StateRekey value is still returned representing
+// the best effort interpretation of the arguments.
+func ParseStateRekey(args []string) (*StateRekey, func(), tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	ret := &StateRekey{
+		WorkspacePattern: &WorkspacePattern{},
+		Vars:             &Vars{},
+		Backend:          &Backend{},
+		State:            &State{},
+	}
+	cmdFlags := extendedFlagSet("state rekey", nil, ret.Vars)
+	ret.Backend.AddIgnoreRemoteVersionFlag(cmdFlags)
+	// The -state option is omitted because a state file given on the command
+	// line is never encrypted, so there would be nothing to re-encrypt.
+	ret.State.addFlags(cmdFlags, stateFlagLock)
+	ret.WorkspacePattern.addFlags(cmdFlags)
+	ret.ViewOptions.AddFlags(cmdFlags, false)
+
+	if err := cmdFlags.Parse(args); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to parse command-line flags",
+			err.Error(),
+		))
+	}
+
+	args = cmdFlags.Args()
+	if len(args) > 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Too many command line arguments",
+			"Expected no positional arguments.",
+		))
+	}
+
+	closer, moreDiags := ret.ViewOptions.Parse()
+	diags = diags.Append(moreDiags)
+	diags = diags.Append(ret.WorkspacePattern.Parse(ret.ViewOptions, nil))
+
+	return ret, closer, diags
+}
@@ -0,0 +1,108 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestParseStateRekey_basicValidation(t *testing.T) {
+	testCases := map[string]struct {
+		args        []string
+		want        *StateRekey
+		wantErrText string
+	}{
+		"defaults": {
+			args: nil,
+			want: stateRekeyArgsWithDefaults(nil),
+		},
+		"too many arguments": {
+			args:        []string{"foo"},
+			want:        stateRekeyArgsWithDefaults(nil),
+			wantErrText: "Too many command line arguments",
+		},
+		"lock flags": {
+			args: []string{"-lock=false", "-lock-timeout=30s"},
+			want: stateRekeyArgsWithDefaults(func(v *StateRekey) {
+				v.State.Lock = false
+				v.State.LockTimeout = 30000000000 // 30s in nanoseconds
+			}),
+		},
+		"workspace pattern": {
+			args: []string{"-workspace-pattern=*", "-workspace-parallelism=2"},
+			want: stateRekeyArgsWithDefaults(func(v *StateRekey) {
+				v.WorkspacePattern.Pattern = "*"
+				v.WorkspacePattern.Parallelism = 2
+			}),
+		},
+		"invalid workspace pattern": {
+			args: []string{"-workspace-pattern=["},
+			want: stateRekeyArgsWithDefaults(func(v *StateRekey) {
+				v.WorkspacePattern.Pattern = "["
+			}),
+			wantErrText: "Invalid workspace pattern",
+		},
+		"ignore-remote-version flag": {
+			args: []string{"-ignore-remote-version"},
+			want: stateRekeyArgsWithDefaults(func(v *StateRekey) {
+				v.Backend.IgnoreRemoteVersion = true
+			}),
+		},
+		"state flag": {
+			args:        []string{"-state=foo.tfstate"},
+			want:        stateRekeyArgsWithDefaults(nil),
+			wantErrText: "Failed to parse command-line flags: flag provided but not defined: -state",
+		},
+	}
+
+	cmpOpts := cmpopts.IgnoreUnexported(Vars{}, ViewOptions{}, Backend{})
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, closer, diags := ParseStateRekey(tc.args)
+			defer closer()
+
+			if tc.wantErrText != "" && len(diags) == 0 {
+				t.Errorf("test wanted error but got nothing")
+			} else if tc.wantErrText == "" && len(diags) > 0 {
+				t.Errorf("test didn't expect errors but got some: %s", diags.ErrWithWarnings())
+			} else if tc.wantErrText != "" && len(diags) > 0 {
+				errStr := diags.ErrWithWarnings().Error()
+				if !strings.Contains(errStr, tc.wantErrText) {
+					t.Errorf("the returned diagnostics does not contain the expected error message.\ndiags:\n%s\nwanted: %s\n", errStr, tc.wantErrText)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got, cmpOpts); diff != "" {
+				t.Errorf("unexpected result\n%s", diff)
+			}
+		})
+	}
+}
+
+func stateRekeyArgsWithDefaults(mutate func(v *StateRekey)) *StateRekey {
+	ret := &StateRekey{
+		WorkspacePattern: &WorkspacePattern{
+			Parallelism: DefaultWorkspaceParallelism,
+		},
+		ViewOptions: ViewOptions{
+			ViewType:     ViewHuman,
+			InputEnabled: false,
+		},
+		Vars:    &Vars{},
+		Backend: &Backend{},
+		State: &State{
+			Lock: true,
+		},
+	}
+	if mutate != nil {
+		mutate(ret)
+	}
+	return ret
+}
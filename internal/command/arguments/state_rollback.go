@@ -0,0 +1,70 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// StateRollback represents the command-line arguments for the 'state rollback' command.
+type StateRollback struct {
+	// VersionID is the ID of the retained snapshot to restore, as reported by 'state history'.
+	VersionID string
+	// Force allows restoring a snapshot whose lineage differs from the lineage of the current state.
+	Force bool
+
+	// ViewOptions specifies which view options to use
+	ViewOptions ViewOptions
+
+	// Vars, Backend and State are the common extended flags
+	Vars    *Vars
+	Backend *Backend
+	State   *State
+}
+
+// ParseStateRollback processes CLI arguments, returning a StateRollback value, a closer function, and errors.
+// If errors are encountered, a StateRollback value is still returned representing
+// the best effort interpretation of the arguments.
+func ParseStateRollback(args []string) (*StateRollback, func(), tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	ret := &StateRollback{
+		Vars:    &Vars{},
+		Backend: &Backend{},
+		State:   &State{},
+	}
+	cmdFlags := extendedFlagSet("state rollback", nil, ret.Vars)
+	ret.Backend.AddIgnoreRemoteVersionFlag(cmdFlags)
+	// StateFlagBackup omitted here to be added later with a different default value
+	ret.State.addFlags(cmdFlags, stateFlagLock|stateFlagStateIn)
+	ret.State.AddBackupFlag(cmdFlags, "-")
+	cmdFlags.BoolVar(&ret.Force, "force", false, "")
+	ret.ViewOptions.AddFlags(cmdFlags, false)
+
+	if err := cmdFlags.Parse(args); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to parse command-line flags",
+			err.Error(),
+		))
+	}
+
+	args = cmdFlags.Args()
+	if len(args) != 1 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid number of arguments",
+			"Exactly one argument expected: the version ID of the snapshot to restore",
+		))
+	} else {
+		ret.VersionID = args[0]
+	}
+
+	closer, moreDiags := ret.ViewOptions.Parse()
+	diags = diags.Append(moreDiags)
+
+	return ret, closer, diags
+}
@@ -0,0 +1,118 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestParseStateRollback_basicValidation(t *testing.T) {
+	testCases := map[string]struct {
+		args        []string
+		want        *StateRollback
+		wantErrText string
+	}{
+		"no arguments": {
+			args:        nil,
+			want:        stateRollbackArgsWithDefaults(nil),
+			wantErrText: "Exactly one argument expected",
+		},
+		"too many arguments": {
+			args:        []string{"1", "2"},
+			want:        stateRollbackArgsWithDefaults(nil),
+			wantErrText: "Exactly one argument expected",
+		},
+		"version id": {
+			args: []string{"terraform.tfstate.backup"},
+			want: stateRollbackArgsWithDefaults(func(v *StateRollback) {
+				v.VersionID = "terraform.tfstate.backup"
+			}),
+		},
+		"force flag": {
+			args: []string{"-force", "3"},
+			want: stateRollbackArgsWithDefaults(func(v *StateRollback) {
+				v.Force = true
+				v.VersionID = "3"
+			}),
+		},
+		"lock flags": {
+			args: []string{"-lock=false", "-lock-timeout=30s", "3"},
+			want: stateRollbackArgsWithDefaults(func(v *StateRollback) {
+				v.State.Lock = false
+				v.State.LockTimeout = 30000000000 // 30s in nanoseconds
+				v.VersionID = "3"
+			}),
+		},
+		"state and backup paths": {
+			args: []string{"-state=foo.tfstate", "-backup=foo.backup", "3"},
+			want: stateRollbackArgsWithDefaults(func(v *StateRollback) {
+				v.State.StatePath = "foo.tfstate"
+				v.State.BackupPath = "foo.backup"
+				v.VersionID = "3"
+			}),
+		},
+		"ignore-remote-version flag": {
+			args: []string{"-ignore-remote-version", "3"},
+			want: stateRollbackArgsWithDefaults(func(v *StateRollback) {
+				v.Backend.IgnoreRemoteVersion = true
+				v.VersionID = "3"
+			}),
+		},
+		"unknown flag": {
+			args: []string{"-unknown-flag", "3"},
+			want: stateRollbackArgsWithDefaults(func(v *StateRollback) {
+				v.VersionID = "3"
+			}),
+			wantErrText: "Failed to parse command-line flags: flag provided but not defined: -unknown-flag",
+		},
+	}
+
+	cmpOpts := cmpopts.IgnoreUnexported(Vars{}, ViewOptions{}, Backend{})
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, closer, diags := ParseStateRollback(tc.args)
+			defer closer()
+
+			if tc.wantErrText != "" && len(diags) == 0 {
+				t.Errorf("test wanted error but got nothing")
+			} else if tc.wantErrText == "" && len(diags) > 0 {
+				t.Errorf("test didn't expect errors but got some: %s", diags.ErrWithWarnings())
+			} else if tc.wantErrText != "" && len(diags) > 0 {
+				errStr := diags.ErrWithWarnings().Error()
+				if !strings.Contains(errStr, tc.wantErrText) {
+					t.Errorf("the returned diagnostics does not contain the expected error message.\ndiags:\n%s\nwanted: %s\n", errStr, tc.wantErrText)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got, cmpOpts); diff != "" {
+				t.Errorf("unexpected result\n%s", diff)
+			}
+		})
+	}
+}
+
+func stateRollbackArgsWithDefaults(mutate func(v *StateRollback)) *StateRollback {
+	ret := &StateRollback{
+		ViewOptions: ViewOptions{
+			ViewType:     ViewHuman,
+			InputEnabled: false,
+		},
+		Vars:    &Vars{},
+		Backend: &Backend{},
+		State: &State{
+			Lock:       true,
+			BackupPath: "-",
+		},
+	}
+	if mutate != nil {
+		mutate(ret)
+	}
+	return ret
+}
@@ -26,6 +26,42 @@ type Test struct {
 	// ViewOptions specifies which view options to use
 	ViewOptions ViewOptions
 
+	// Coverage tells the test command to report which objects in the module
+	// under test were exercised by the tests.
+	Coverage bool
+
+	// CoveragePath is the path of a file to write the coverage to in the
+	// lcov format. Setting it implies Coverage.
+	CoveragePath string
+
+	// JUnitXMLPath is the path of a file to write a JUnit XML report of the
+	// test results to, in addition to the regular output. If empty, no
+	// report is written.
+	JUnitXMLPath string
+
+	// Parallelism is the number of test files and run blocks that the test
+	// command executes concurrently. The default of 1 executes everything in
+	// order.
+	Parallelism int
+
+	// RecordPath is the path of a cassette file to record the calls made to
+	// the provider plugins into. If empty, nothing is recorded.
+	RecordPath string
+
+	// ReplayPath is the path of a cassette file to replay the calls made to
+	// the provider plugins from, instead of launching the plugins recorded in
+	// it. If empty, nothing is replayed.
+	ReplayPath string
+
+	// TAP tells the test command to print the test results in the Test
+	// Anything Protocol format instead of the human-readable format.
+	TAP bool
+
+	// UpdateSnapshots tells the test command to write the plans of the run
+	// blocks that declare expect_plan_snapshot to their golden files,
+	// instead of comparing the plans against them.
+	UpdateSnapshots bool
+
 	// You can specify common variables for all tests from the command line.
 	Vars *Vars
 
@@ -46,6 +82,14 @@ func ParseTest(args []string) (*Test, func(), tfdiags.Diagnostics) {
 	cmdFlags.Var((*flags.FlagStringSlice)(&test.Filter), "filter", "filter")
 	cmdFlags.StringVar(&test.TestDirectory, "test-directory", configs.DefaultTestDirectory, "test-directory")
 	cmdFlags.BoolVar(&test.Verbose, "verbose", false, "verbose")
+	cmdFlags.BoolVar(&test.Coverage, "coverage", false, "coverage")
+	cmdFlags.StringVar(&test.CoveragePath, "coverage-out", "", "coverage-out")
+	cmdFlags.StringVar(&test.JUnitXMLPath, "junit-xml", "", "junit-xml")
+	cmdFlags.BoolVar(&test.TAP, "tap", false, "tap")
+	cmdFlags.StringVar(&test.RecordPath, "record", "", "record")
+	cmdFlags.StringVar(&test.ReplayPath, "replay", "", "replay")
+	cmdFlags.IntVar(&test.Parallelism, "parallelism", 1, "parallelism")
+	cmdFlags.BoolVar(&test.UpdateSnapshots, "update-snapshots", false, "update-snapshots")
 
 	test.ViewOptions.AddFlags(cmdFlags, false)
 
@@ -56,8 +100,36 @@ func ParseTest(args []string) (*Test, func(), tfdiags.Diagnostics) {
 			err.Error()))
 	}
 
+	if test.CoveragePath != "" {
+		test.Coverage = true
+	}
+
 	closer, moreDiags := test.ViewOptions.Parse()
 	diags = diags.Append(moreDiags)
 
+	if test.Parallelism < 1 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid parallelism",
+			"The -parallelism option must be at least 1.",
+		))
+	}
+
+	if test.TAP && test.ViewOptions.ViewType == ViewJSON {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Incompatible command line options",
+			"The -tap and -json options are mutually exclusive. Use -json-into to write the JSON output to a file alongside the TAP output.",
+		))
+	}
+
+	if test.RecordPath != "" && test.ReplayPath != "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Incompatible command line options",
+			"The -record and -replay options are mutually exclusive.",
+		))
+	}
+
 	return &test, closer, diags
 }
@@ -80,6 +80,7 @@ func TestParseTest(t *testing.T) {
 				Filter:        nil,
 				TestDirectory: "tests",
 				ViewOptions:   ViewOptions{ViewType: ViewHuman},
+				Parallelism:   1,
 				Vars:          &Vars{},
 			},
 			wantDiags: nil,
@@ -90,6 +91,7 @@ func TestParseTest(t *testing.T) {
 				Filter:        []string{"one.tftest.hcl", "two.tftest.hcl"},
 				TestDirectory: "tests",
 				ViewOptions:   ViewOptions{ViewType: ViewHuman},
+				Parallelism:   1,
 				Vars:          &Vars{},
 			},
 			wantDiags: nil,
@@ -100,6 +102,7 @@ func TestParseTest(t *testing.T) {
 				Filter:        nil,
 				TestDirectory: "tests",
 				ViewOptions:   ViewOptions{ViewType: ViewJSON},
+				Parallelism:   1,
 				Vars:          &Vars{},
 			},
 			wantDiags: nil,
@@ -110,6 +113,7 @@ func TestParseTest(t *testing.T) {
 				Filter:        nil,
 				TestDirectory: "other",
 				ViewOptions:   ViewOptions{ViewType: ViewHuman},
+				Parallelism:   1,
 				Vars:          &Vars{},
 			},
 			wantDiags: nil,
@@ -120,16 +124,160 @@ func TestParseTest(t *testing.T) {
 				Filter:        nil,
 				TestDirectory: "tests",
 				ViewOptions:   ViewOptions{ViewType: ViewHuman},
+				Parallelism:   1,
 				Verbose:       true,
 				Vars:          &Vars{},
 			},
 		},
+		"junit-xml": {
+			args: []string{"-junit-xml=results.xml"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewOptions:   ViewOptions{ViewType: ViewHuman},
+				Parallelism:   1,
+				JUnitXMLPath:  "results.xml",
+				Vars:          &Vars{},
+			},
+		},
+		"coverage": {
+			args: []string{"-coverage"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewOptions:   ViewOptions{ViewType: ViewHuman},
+				Parallelism:   1,
+				Coverage:      true,
+				Vars:          &Vars{},
+			},
+		},
+		"coverage-out": {
+			args: []string{"-coverage-out=coverage.lcov"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewOptions:   ViewOptions{ViewType: ViewHuman},
+				Parallelism:   1,
+				Coverage:      true,
+				CoveragePath:  "coverage.lcov",
+				Vars:          &Vars{},
+			},
+		},
+		"record": {
+			args: []string{"-record=cassette.json"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewOptions:   ViewOptions{ViewType: ViewHuman},
+				Parallelism:   1,
+				RecordPath:    "cassette.json",
+				Vars:          &Vars{},
+			},
+		},
+		"replay": {
+			args: []string{"-replay=cassette.json"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewOptions:   ViewOptions{ViewType: ViewHuman},
+				Parallelism:   1,
+				ReplayPath:    "cassette.json",
+				Vars:          &Vars{},
+			},
+		},
+		"record and replay": {
+			args: []string{"-record=cassette.json", "-replay=cassette.json"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewOptions:   ViewOptions{ViewType: ViewHuman},
+				Parallelism:   1,
+				RecordPath:    "cassette.json",
+				ReplayPath:    "cassette.json",
+				Vars:          &Vars{},
+			},
+			wantDiags: tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Incompatible command line options",
+					"The -record and -replay options are mutually exclusive.",
+				),
+			},
+		},
+		"tap": {
+			args: []string{"-tap"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewOptions:   ViewOptions{ViewType: ViewHuman},
+				Parallelism:   1,
+				TAP:           true,
+				Vars:          &Vars{},
+			},
+		},
+		"update snapshots": {
+			args: []string{"-update-snapshots"},
+			want: &Test{
+				Filter:          nil,
+				TestDirectory:   "tests",
+				ViewOptions:     ViewOptions{ViewType: ViewHuman},
+				Parallelism:     1,
+				UpdateSnapshots: true,
+				Vars:            &Vars{},
+			},
+		},
+		"tap and json": {
+			args: []string{"-tap", "-json"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewOptions:   ViewOptions{ViewType: ViewJSON},
+				Parallelism:   1,
+				TAP:           true,
+				Vars:          &Vars{},
+			},
+			wantDiags: tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Incompatible command line options",
+					"The -tap and -json options are mutually exclusive. Use -json-into to write the JSON output to a file alongside the TAP output.",
+				),
+			},
+		},
+		"parallelism": {
+			args: []string{"-parallelism=4"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewOptions:   ViewOptions{ViewType: ViewHuman},
+				Parallelism:   4,
+				Vars:          &Vars{},
+			},
+		},
+		"invalid parallelism": {
+			args: []string{"-parallelism=0"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewOptions:   ViewOptions{ViewType: ViewHuman},
+				Parallelism:   0,
+				Vars:          &Vars{},
+			},
+			wantDiags: tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid parallelism",
+					"The -parallelism option must be at least 1.",
+				),
+			},
+		},
 		"unknown flag": {
 			args: []string{"-boop"},
 			want: &Test{
 				Filter:        nil,
 				TestDirectory: "tests",
 				ViewOptions:   ViewOptions{ViewType: ViewHuman},
+				Parallelism:   1,
 				Vars:          &Vars{},
 			},
 			wantDiags: tfdiags.Diagnostics{
@@ -0,0 +1,98 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"flag"
+	"fmt"
+	"path"
+	"slices"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// DefaultWorkspaceParallelism is the number of workspaces a command selecting
+// workspaces with -workspace-pattern runs in concurrently by default.
+const DefaultWorkspaceParallelism = 4
+
+// WorkspacePattern describes arguments which are used to run a command in
+// each of the workspaces whose names match a pattern, rather than only in the
+// currently-selected workspace.
+type WorkspacePattern struct {
+	// Pattern is a glob pattern, using the syntax of path.Match, which
+	// selects the workspaces to run the command in. The default value is
+	// blank, which runs the command in the currently-selected workspace only.
+	Pattern string
+
+	// Parallelism is the maximum number of workspaces the command runs in
+	// concurrently.
+	Parallelism int
+}
+
+func (w *WorkspacePattern) addFlags(f *flag.FlagSet) {
+	f.StringVar(&w.Pattern, "workspace-pattern", "", "workspace-pattern")
+	f.IntVar(&w.Parallelism, "workspace-parallelism", DefaultWorkspaceParallelism, "workspace-parallelism")
+}
+
+// Enabled returns true if the command should run in each of the workspaces
+// matching Pattern.
+func (w *WorkspacePattern) Enabled() bool {
+	return w.Pattern != ""
+}
+
+// Match returns true if the given workspace name matches Pattern.
+func (w *WorkspacePattern) Match(workspace string) bool {
+	// Parse has already checked that the pattern is valid, so this can't
+	// fail.
+	matched, _ := path.Match(w.Pattern, workspace)
+	return matched
+}
+
+// Parse validates the pattern and the parallelism, and also checks that
+// none of the given options that can't be combined with -workspace-pattern
+// were set. The conflicts map has the names of options, without the leading
+// dash, as keys and whether each option was set as values.
+func (w *WorkspacePattern) Parse(viewOptions ViewOptions, conflicts map[string]bool) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if !w.Enabled() {
+		return diags
+	}
+
+	if _, err := path.Match(w.Pattern, ""); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid workspace pattern",
+			fmt.Sprintf("The -workspace-pattern value %q is not a valid pattern: %s.", w.Pattern, err),
+		))
+	}
+	if w.Parallelism < 1 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid workspace parallelism",
+			"The -workspace-parallelism option must be at least 1.",
+		))
+	}
+
+	var names []string
+	if viewOptions.JSONInto != nil {
+		names = append(names, "json-into")
+	}
+	for name, set := range conflicts {
+		if set {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Incompatible command line options",
+			fmt.Sprintf("The -%s option cannot be used together with -workspace-pattern.", name),
+		))
+	}
+
+	return diags
+}
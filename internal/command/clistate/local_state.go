@@ -17,6 +17,7 @@ import (
 	"time"
 
 	multierror "github.com/hashicorp/go-multierror"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/flock"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 )
@@ -33,6 +34,10 @@ type LocalState struct {
 	// overridden (e.g. via TF_DATA_DIR).
 	DataDirOverridden bool
 
+	// Encryption is used to encrypt the state when it is written and decrypt
+	// it when it is read. If nil, the state is read and written unencrypted.
+	Encryption encryption.BackendConfigEncryption
+
 	// the file handle corresponding to PathOut
 	stateFileOut *os.File
 
@@ -49,6 +54,10 @@ type LocalState struct {
 	mu      sync.Mutex
 	state   *CLIState
 	written bool
+
+	// encryptionStatus is the encryption status of the state most recently
+	// read by RefreshState.
+	encryptionStatus encryption.EncryptionStatus
 }
 
 // SetState will force a specific state in-memory for this local state.
@@ -102,9 +111,17 @@ func (s *LocalState) writeState(state *CLIState) error {
 		return nil
 	}
 
-	if err := WriteState(s.state, s.stateFileOut); err != nil {
+	var buf bytes.Buffer
+	if err := WriteState(s.state, &buf); err != nil {
 		return err
 	}
+	data, err := s.encryption().EncryptBackendConfig(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to encrypt CLI state: %w", err)
+	}
+	if _, err := s.stateFileOut.Write(data); err != nil {
+		return fmt.Errorf("failed to write CLI state: %w", err)
+	}
 
 	s.written = true
 	return nil
@@ -165,7 +182,19 @@ func (s *LocalState) RefreshState(_ context.Context) error {
 		reader = s.stateFileOut
 	}
 
-	state, err := ReadState(reader, s.DataDirOverridden)
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("reading CLI state file failed: %w", err)
+	}
+	s.encryptionStatus = encryption.StatusSatisfied
+	if len(data) > 0 {
+		data, s.encryptionStatus, err = s.encryption().DecryptBackendConfig(data)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt CLI state: %w", err)
+		}
+	}
+
+	state, err := ReadState(bytes.NewReader(data), s.DataDirOverridden)
 	// if there's no state we just assign the nil return value
 	if err != nil && err != ErrNoState {
 		return err
@@ -175,6 +204,26 @@ func (s *LocalState) RefreshState(_ context.Context) error {
 	return nil
 }
 
+// StateEncryptionStatus returns the encryption status of the state most
+// recently read by RefreshState. If it is encryption.StatusMigration, the
+// state was read using a fallback method, and should be written again so
+// that it is encrypted with the primary method.
+func (s *LocalState) StateEncryptionStatus() encryption.EncryptionStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.encryptionStatus
+}
+
+// encryption returns the encryption configured for the state, or a pass
+// through implementation if none is configured.
+func (s *LocalState) encryption() encryption.BackendConfigEncryption {
+	if s.Encryption == nil {
+		return encryption.BackendConfigEncryptionDisabled()
+	}
+	return s.Encryption
+}
+
 // Lock implements a local filesystem state.Locker.
 func (s *LocalState) Lock(_ context.Context, info *statemgr.LockInfo) (string, error) {
 	s.mu.Lock()
@@ -0,0 +1,94 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package clistate
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/encryption"
+)
+
+// prefixEncryption is a fake encryption.BackendConfigEncryption which marks
+// the data as "encrypted" by prefixing it, and reads unprefixed data as if it
+// was decrypted with a fallback method.
+type prefixEncryption struct{}
+
+var encryptedPrefix = []byte("encrypted:")
+
+func (prefixEncryption) EncryptBackendConfig(data []byte) ([]byte, error) {
+	return append(append([]byte{}, encryptedPrefix...), data...), nil
+}
+
+func (prefixEncryption) DecryptBackendConfig(data []byte) ([]byte, encryption.EncryptionStatus, error) {
+	if plain, ok := bytes.CutPrefix(data, encryptedPrefix); ok {
+		return plain, encryption.StatusSatisfied, nil
+	}
+	return data, encryption.StatusMigration, nil
+}
+
+func TestLocalState_encryption(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "terraform.tfstate")
+	state := &CLIState{
+		Version: StateVersion,
+		Backend: &BackendState{
+			Type:      "s3",
+			ConfigRaw: []byte(`{"secret_key":"hunter2"}`),
+			Hash:      12345,
+		},
+	}
+
+	// Write the state unencrypted first, as an earlier version would have
+	plain := &LocalState{Path: path}
+	if err := plain.WriteState(state); err != nil {
+		t.Fatalf("WriteState failed: %v", err)
+	}
+
+	s := &LocalState{Path: path, Encryption: prefixEncryption{}}
+	if err := s.RefreshState(t.Context()); err != nil {
+		t.Fatalf("RefreshState failed: %v", err)
+	}
+	if got := s.StateEncryptionStatus(); got != encryption.StatusMigration {
+		t.Errorf("wrong status %d; want %d", got, encryption.StatusMigration)
+	}
+	if got := s.State(); got.Backend == nil || got.Backend.Hash != 12345 {
+		t.Fatalf("wrong state read: %#v", got)
+	}
+
+	if err := s.WriteState(s.State()); err != nil {
+		t.Fatalf("WriteState failed: %v", err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(raw, encryptedPrefix) {
+		t.Fatalf("state was not encrypted when written: %s", raw)
+	}
+
+	s = &LocalState{Path: path, Encryption: prefixEncryption{}}
+	if err := s.RefreshState(t.Context()); err != nil {
+		t.Fatalf("RefreshState failed: %v", err)
+	}
+	if got := s.StateEncryptionStatus(); got != encryption.StatusSatisfied {
+		t.Errorf("wrong status %d; want %d", got, encryption.StatusSatisfied)
+	}
+	if got := s.State(); got.Backend == nil || got.Backend.Type != "s3" {
+		t.Fatalf("wrong state read: %#v", got)
+	}
+}
+
+func TestLocalState_encryptionNoState(t *testing.T) {
+	s := &LocalState{Path: filepath.Join(t.TempDir(), "terraform.tfstate"), Encryption: prefixEncryption{}}
+	if err := s.RefreshState(t.Context()); err != nil {
+		t.Fatalf("RefreshState failed: %v", err)
+	}
+	if got := s.State(); got != nil {
+		t.Fatalf("expected no state, got %#v", got)
+	}
+}
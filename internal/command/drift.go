@@ -0,0 +1,353 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/configs/configload"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+	"github.com/opentofu/opentofu/version"
+)
+
+// DriftCommand is a Command implementation that creates a refresh-only plan
+// and reports the changes made outside of OpenTofu to the remote objects it
+// manages.
+type DriftCommand struct {
+	Meta
+}
+
+func (c *DriftCommand) Run(rawArgs []string) int {
+	ctx := c.CommandContext()
+
+	// Parse and apply global view arguments
+	common, rawArgs := arguments.ParseView(rawArgs)
+	c.View.Configure(common)
+
+	// Parse and validate flags
+	args, closer, diags := arguments.ParseDrift(rawArgs)
+	defer closer()
+
+	c.View.SetShowSensitive(args.ShowSensitive)
+
+	// Instantiate the view, even if there are flag errors, so that we render
+	// diagnostics according to the desired view
+	view := views.NewDrift(args.ViewOptions, c.View)
+
+	if diags.HasErrors() {
+		view.Diagnostics(diags)
+		view.HelpPrompt()
+		return 1
+	}
+
+	// Check for user-supplied plugin path
+	var err error
+	if c.pluginPath, err = c.loadPluginPath(); err != nil {
+		diags = diags.Append(err)
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// FIXME: the -input and -parallelism flag values are needed to initialize
+	// the backend and the operation, so we mutate the Meta object state in
+	// the same way as the plan command.
+	c.Meta.input = args.ViewOptions.InputEnabled
+	c.Meta.parallelism = args.Operation.Parallelism
+
+	diags = diags.Append(c.providerDevOverrideRuntimeWarnings())
+
+	// Inject variables from args into meta for static evaluation
+	c.Meta.variableArgs = args.Vars.All()
+
+	if args.WorkspacePattern.Enabled() {
+		view.Diagnostics(diags)
+
+		// We can't prompt for input in each of several concurrent plans.
+		args.ViewOptions.InputEnabled = false
+		c.Meta.input = false
+		return c.runInWorkspaces(ctx, args.WorkspacePattern, args.ViewOptions.ViewType, func(ctx context.Context, meta *Meta) int {
+			cmd := &DriftCommand{Meta: *meta}
+			return cmd.drift(ctx, args, views.NewDrift(args.ViewOptions, cmd.View), nil)
+		})
+	}
+
+	return c.drift(ctx, args, view, diags)
+}
+
+// drift reports the drift for the currently-selected workspace, appending to
+// the given diagnostics any further diagnostics generated along the way.
+//
+// The exit status is 0 if there is no drift, 1 if an error occurred and 2 if
+// any drift was detected.
+func (c *DriftCommand) drift(ctx context.Context, args *arguments.Drift, view views.Drift, diags tfdiags.Diagnostics) int {
+	// Load the encryption configuration
+	enc, encDiags := c.Encryption(ctx)
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// Prepare the backend with the backend-specific arguments
+	be, beDiags := c.PrepareBackend(ctx, args.State, view, enc)
+	diags = diags.Append(beDiags)
+	if diags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// Build the operation request
+	opView := &driftOperation{Operation: view.Operation()}
+	opReq, opDiags := c.OperationRequest(ctx, be, view, opView, args.Operation, enc)
+	diags = diags.Append(opDiags)
+	if diags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// Before we delegate to the backend, we'll print any warning diagnostics
+	// we've accumulated here, since the backend will start fresh with its own
+	// diagnostics.
+	view.Diagnostics(diags)
+	diags = nil
+
+	// Perform the operation
+	op, diags := c.RunOperation(ctx, be, opReq)
+	view.Diagnostics(diags)
+	if diags.HasErrors() {
+		return 1
+	}
+	if op.Result != backend.OperationSuccess {
+		return op.Result.ExitStatus()
+	}
+
+	if opView.plan == nil {
+		// Backends that run operations remotely render the plan themselves,
+		// so we never see it.
+		view.Diagnostics(tfdiags.Diagnostics{tfdiags.Sourceless(
+			tfdiags.Error,
+			"Drift report unavailable",
+			"The configured backend runs operations remotely, so OpenTofu cannot produce a drift report locally. Use \"tofu plan -refresh-only\" to review changes made outside of OpenTofu instead.",
+		)})
+		return 1
+	}
+
+	report, err := jsonplan.MarshalDriftReport(opView.plan, opView.schemas, args.ShowSensitive)
+	if err != nil {
+		view.Diagnostics(tfdiags.Diagnostics{tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to build drift report",
+			fmt.Sprintf("OpenTofu could not describe the detected drift: %s.", err),
+		)})
+		return 1
+	}
+	view.DriftReport(report)
+
+	if args.SARIFOutPath != "" {
+		raw, err := jsonplan.MarshalDriftSARIF(report, version.String())
+		if err == nil {
+			err = os.WriteFile(args.SARIFOutPath, raw, 0644)
+		}
+		if err != nil {
+			view.Diagnostics(tfdiags.Diagnostics{tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to write SARIF report",
+				fmt.Sprintf("Error writing the drift report to %s: %s.", args.SARIFOutPath, err),
+			)})
+			return 1
+		}
+	}
+
+	if report.Summary.Total() > 0 {
+		return 2
+	}
+	return 0
+}
+
+func (c *DriftCommand) PrepareBackend(ctx context.Context, args *arguments.State, view views.Drift, enc encryption.Encryption) (backend.Enhanced, tfdiags.Diagnostics) {
+	c.Meta.stateArgs = *args
+
+	backendConfig, diags := c.loadBackendConfig(ctx, ".")
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	// Load the backend
+	be, beDiags := c.Backend(ctx, &BackendOpts{
+		Config: backendConfig,
+		View:   view.Backend(),
+	}, enc.State())
+	diags = diags.Append(beDiags)
+	if beDiags.HasErrors() {
+		return nil, diags
+	}
+
+	return be, diags
+}
+
+func (c *DriftCommand) OperationRequest(
+	ctx context.Context,
+	be backend.Enhanced,
+	view views.Drift,
+	opView views.Operation,
+	args *arguments.Operation,
+	enc encryption.Encryption,
+) (*backend.Operation, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	// Build the operation
+	opReq := c.Operation(ctx, be, view.Backend(), enc)
+	opReq.ConfigDir = "."
+	opReq.PlanMode = args.PlanMode
+	opReq.Hooks = view.Hooks()
+	opReq.PlanRefresh = args.Refresh
+	opReq.Targets = args.Targets
+	opReq.Excludes = args.Excludes
+	opReq.Type = backend.OperationTypePlan
+	opReq.View = opView
+
+	var err error
+	opReq.ConfigLoader, err = configload.Initialise(c.configLoader())
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("Failed to initialize config loader: %w", err))
+		return nil, diags
+	}
+
+	return opReq, diags
+}
+
+// driftOperation is an operation view which captures the refresh-only plan
+// for the drift report, instead of rendering it.
+type driftOperation struct {
+	views.Operation
+
+	plan    *plans.Plan
+	schemas *tofu.Schemas
+}
+
+func (v *driftOperation) Plan(plan *plans.Plan, schemas *tofu.Schemas) {
+	v.plan = plan
+	v.schemas = schemas
+}
+
+func (v *driftOperation) PlanNextStep(planPath string, genConfigPath string) {
+	// The drift report replaces the usual suggestions about what to do next.
+}
+
+func (c *DriftCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] drift [options]
+
+  Checks whether the remote objects managed by OpenTofu have been changed
+  outside of OpenTofu since the most recent apply, and reports the drift for
+  each resource instance. This command creates a refresh-only plan, but never
+  updates the state.
+
+  The exit status is 0 if no drift was detected, 1 if an error occurred, and
+  2 if any drift was detected.
+
+Options:
+
+  -compact-warnings            If OpenTofu produces any warnings that are not
+                               accompanied by errors, shows them in a more
+                               compact form that includes only the summary
+                               messages.
+
+  -consolidate-warnings=false  If OpenTofu produces any warnings, do not
+                               attempt to consolidate similar messages. All
+                               locations for all warnings will be listed.
+
+  -consolidate-errors          If OpenTofu produces any errors, attempt to
+                               consolidate similar messages into a single item.
+
+  -input=false                 Disable prompting for required input variables
+                               that are not set some other way.
+
+  -lock=false                  Don't hold a state lock during the operation.
+                               This is dangerous if others might concurrently
+                               run commands against the same workspace.
+
+  -lock-timeout=duration       Duration to retry a state lock, such as "5s"
+                               to represent five seconds.
+
+  -no-color                    Disable virtual terminal escape sequences.
+
+  -concise                     Disable progress-related messages.
+
+  -parallelism=n               Limit the number of concurrent operations.
+                               Defaults to 10.
+
+  -sarif-out=path              Also write the drift report to the given path
+                               in the Static Analysis Results Interchange
+                               Format (SARIF), for use with code scanning
+                               tools. Cannot be used with -workspace-pattern.
+
+  -state=statefile             A legacy option used for the local backend only.
+                               Refer to the local backend's documentation for
+                               more information.
+
+  -show-sensitive              If specified, sensitive values will not be
+                               redacted in the drift report.
+
+  -target=resource             Limit the check to only the given module,
+                               resource, or resource instance and all of its
+                               dependencies. You can use this option multiple
+                               times to include more than one object.
+
+  -target-file=filename        Similar to -target, but specifies zero or more
+                               resource addresses from a file.
+
+  -exclude=resource            Limit the check to not include the given module,
+                               resource, or resource instance and all of the
+                               resources and modules that depend on it.
+
+  -exclude-file=filename       Similar to -exclude, but specifies zero or more
+                               resource addresses from a file.
+
+  -var 'foo=bar'               Set a value for one of the input variables in
+                               the root module of the configuration. Use this
+                               option more than once to set more than one
+                               variable.
+
+  -var-file=filename           Load variable values from the given file, in
+                               addition to the default files terraform.tfvars
+                               and *.auto.tfvars. Use this option more than
+                               once to include more than one variables file.
+
+  -workspace-pattern=pattern   Check each of the workspaces whose names match
+                               the given glob pattern, such as "prod-*",
+                               instead of only the selected workspace, and show
+                               a summary of the results.
+
+  -workspace-parallelism=n     Limit the number of workspaces checked
+                               concurrently with -workspace-pattern.
+                               Defaults to 4.
+
+  -json                        Produce output in a machine-readable JSON
+                               format, suitable for use in text editor
+                               integrations and other automated systems.
+
+  -json-into=out.json          Produce the same output as -json, but sent directly
+                               to the given file. This allows automation to preserve
+                               the original human-readable output streams, while
+                               capturing more detailed logs for machine analysis.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *DriftCommand) Synopsis() string {
+	return "Report changes made outside of OpenTofu"
+}
@@ -0,0 +1,214 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/command/workdir"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+func driftFixtureState() *states.State {
+	return states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "test_instance",
+				Name: "foo",
+			}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"id":"bar","ami":"bar","network_interface":[]}`),
+				Status:    states.ObjectReady,
+			},
+			addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+			addrs.NoKey,
+		)
+	})
+}
+
+// driftFixtureProvider returns a mock provider for the configuration in
+// testdata/plan which reports that the ami of test_instance.foo has been
+// changed to the given value.
+func driftFixtureProvider(ami string) *tofu.MockProvider {
+	p := planFixtureProvider()
+	p.ReadResourceFn = func(req providers.ReadResourceRequest) providers.ReadResourceResponse {
+		attrs := req.PriorState.AsValueMap()
+		attrs["ami"] = cty.StringVal(ami)
+		return providers.ReadResourceResponse{
+			NewState: cty.ObjectVal(attrs),
+		}
+	}
+	return p
+}
+
+func TestDrift_noDrift(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	t.Chdir(td)
+
+	statePath := testStateFile(t, driftFixtureState())
+
+	p := driftFixtureProvider("bar")
+	view, done := testView(t)
+	c := &DriftCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-state", statePath})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("wrong exit status %d; want 0\nstderr: %s", code, output.Stderr())
+	}
+	if !p.ReadResourceCalled {
+		t.Fatal("ReadResource should be called")
+	}
+	if got, want := output.Stdout(), "No drift detected."; !strings.Contains(got, want) {
+		t.Fatalf("wrong output\ngot: %s\nwant substring: %s", got, want)
+	}
+}
+
+func TestDrift_drift(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	t.Chdir(td)
+
+	statePath := testStateFile(t, driftFixtureState())
+	originalState, err := os.ReadFile(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sarifPath := filepath.Join(td, "drift.sarif")
+
+	p := driftFixtureProvider("baz")
+	view, done := testView(t)
+	c := &DriftCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-no-color", "-state", statePath, "-sarif-out", sarifPath})
+	output := done(t)
+	if code != 2 {
+		t.Fatalf("wrong exit status %d; want 2\nstderr: %s", code, output.Stderr())
+	}
+	for _, want := range []string{
+		"test_instance.foo has changed outside of OpenTofu",
+		`ami: "bar" -> "baz"`,
+		"Drift summary: 1 changed.",
+	} {
+		if got := output.Stdout(); !strings.Contains(got, want) {
+			t.Errorf("wrong output\ngot: %s\nwant substring: %s", got, want)
+		}
+	}
+
+	raw, err := os.ReadFile(sarifPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sarif struct {
+		Runs []struct {
+			Results []struct {
+				RuleID string `json:"ruleId"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(raw, &sarif); err != nil {
+		t.Fatalf("invalid SARIF log: %s\n%s", err, raw)
+	}
+	if len(sarif.Runs) != 1 || len(sarif.Runs[0].Results) != 1 || sarif.Runs[0].Results[0].RuleID != "drift/changed" {
+		t.Fatalf("wrong SARIF log:\n%s", raw)
+	}
+
+	// The drift command must never update the state.
+	if got, err := os.ReadFile(statePath); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(got, originalState) {
+		t.Fatalf("state was updated\n%s", got)
+	}
+}
+
+func TestDrift_json(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	t.Chdir(td)
+
+	statePath := testStateFile(t, driftFixtureState())
+
+	p := driftFixtureProvider("baz")
+	view, done := testView(t)
+	c := &DriftCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-state", statePath, "-json"})
+	output := done(t)
+	if code != 2 {
+		t.Fatalf("wrong exit status %d; want 2\nstderr: %s", code, output.Stderr())
+	}
+
+	var found bool
+	for _, line := range strings.Split(strings.TrimSpace(output.Stdout()), "\n") {
+		var msg map[string]any
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			t.Fatalf("invalid JSON line %q: %s", line, err)
+		}
+		if msg["type"] != "drift_report" {
+			continue
+		}
+		found = true
+		summary := msg["drift_report"].(map[string]any)["summary"].(map[string]any)
+		if summary["changed"] != float64(1) {
+			t.Errorf("wrong summary %#v", summary)
+		}
+	}
+	if !found {
+		t.Fatalf("no drift_report message in output:\n%s", output.Stdout())
+	}
+}
+
+func TestDrift_invalidMode(t *testing.T) {
+	view, done := testView(t)
+	c := &DriftCommand{
+		Meta: Meta{
+			WorkingDir: workdir.NewDir("."),
+			View:       view,
+		},
+	}
+
+	code := c.Run([]string{"-destroy"})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit status %d; want 1\nstdout: %s", code, output.Stdout())
+	}
+	if got, want := output.Stderr(), "Invalid mode option"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot: %s\nwant substring: %s", got, want)
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+// EncryptionCommand is a Command implementation that just shows help for
+// the subcommands nested below it.
+type EncryptionCommand struct {
+	Meta
+}
+
+func (c *EncryptionCommand) Run(_ []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *EncryptionCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] encryption <subcommand> [options] [args]
+
+  This command has subcommands for inspecting the encryption of state and
+  plan files.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *EncryptionCommand) Synopsis() string {
+	return "State and plan encryption introspection"
+}
@@ -0,0 +1,193 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tracing"
+)
+
+// EncryptionInspectCommand is a cli.Command implementation that reports how a
+// state or plan file is encrypted, and whether it can be decrypted with the
+// current encryption configuration.
+type EncryptionInspectCommand struct {
+	Meta
+}
+
+func (c *EncryptionInspectCommand) Run(rawArgs []string) int {
+	ctx := c.CommandContext()
+	ctx, span := tracing.Tracer().Start(ctx, "Encryption inspect")
+	defer span.End()
+
+	common, rawArgs := arguments.ParseView(rawArgs)
+	c.View.Configure(common)
+
+	// Parse and validate flags
+	args, closer, diags := arguments.ParseEncryptionInspect(rawArgs)
+	defer closer()
+
+	// Instantiate the view, even if there are flag errors, so that we render
+	// diagnostics according to the desired view
+	view := views.NewEncryptionInspect(args.ViewOptions, c.View)
+
+	if diags.HasErrors() {
+		view.Diagnostics(diags)
+		if args.ViewOptions.ViewType == arguments.ViewJSON {
+			return 1 // in case it's json, do not print the help of the command
+		}
+		return cli.RunResultHelp
+	}
+	c.Meta.variableArgs = args.Vars.All()
+
+	data, err := os.ReadFile(args.Path)
+	if err != nil {
+		view.Diagnostics(diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read file",
+			fmt.Sprintf("Could not read %s: %s.", args.Path, err),
+		)))
+		return 1
+	}
+	kind, ok := encryptedFileKind(data)
+	if !ok {
+		view.Diagnostics(diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unrecognized file",
+			fmt.Sprintf("The file %s is neither a state file nor a plan file, whether encrypted or not.", args.Path),
+		)))
+		return 1
+	}
+
+	// This gets the current directory as full path.
+	configPath := c.WorkingDir.NormalizePath(c.WorkingDir.RootModuleDir())
+
+	// Load the encryption configuration
+	enc, encDiags := c.EncryptionFromPath(ctx, configPath)
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	var inspection *encryption.Inspection
+	if kind == "plan" {
+		inspection = encryption.InspectPlan(ctx, enc.Plan(), data)
+	} else {
+		inspection = encryption.InspectState(ctx, enc.State(), data)
+	}
+
+	view.Diagnostics(diags)
+	view.Inspection(args.Path, kind, inspection)
+
+	switch inspection.Status {
+	case encryption.StatusSatisfied:
+		return 0
+	case encryption.StatusMigration:
+		if args.DetailedExitCode {
+			return 2
+		}
+		return 0
+	default:
+		view.Diagnostics(tfdiags.Diagnostics{}.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to decrypt file",
+			fmt.Sprintf("The %s file %s cannot be decrypted with the current encryption configuration: %s.", kind, args.Path, inspection.Err),
+		)))
+		return 1
+	}
+}
+
+// encryptedFileKind returns whether the given data is a state file or a plan
+// file, either encrypted or not, as "state" or "plan".
+func encryptedFileKind(data []byte) (string, bool) {
+	// Unencrypted plan files are zip archives.
+	if bytes.HasPrefix(data, []byte("PK")) {
+		return "plan", true
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return "", false
+	}
+	if _, ok := fields["terraform_version"]; ok {
+		return "state", true
+	}
+	if _, ok := fields["encryption_version"]; !ok {
+		return "", false
+	}
+	// Encrypted state files carry the serial and lineage of the state
+	// unencrypted, while encrypted plan files carry nothing besides the
+	// encrypted payload.
+	_, hasSerial := fields["serial"]
+	_, hasLineage := fields["lineage"]
+	if hasSerial || hasLineage {
+		return "state", true
+	}
+	return "plan", true
+}
+
+func (c *EncryptionInspectCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] encryption inspect [options] FILE
+
+  Show how the given state or plan file is encrypted, and whether it can be
+  decrypted with the encryption configuration of the current working
+  directory.
+
+  For an encrypted file, this shows the metadata of the key providers that
+  was stored along with it, which never includes any keys, and the method
+  of the current configuration that is able to decrypt it. It also reports
+  whether the file can only be decrypted with the fallback method, such as
+  after a key rotation, in which case a state file can be re-encrypted
+  with the primary method using "tofu state rekey".
+
+  This will not modify the given file.
+
+Options:
+
+  -detailed-exitcode     Return detailed exit codes when the command exits.
+                         This will change the meaning of exit codes to:
+                         0 - Succeeded, the file can be decrypted with the
+                             primary method, or encryption is not configured
+                         1 - Errored, or the file cannot be decrypted
+                         2 - Succeeded, but the file can only be decrypted
+                             with the fallback method
+
+  -var 'foo=bar'         Set a value for one of the input variables in the root
+                         module of the configuration. Use this option more than
+                         once to set more than one variable.
+
+  -var-file=filename     Load variable values from the given file, in addition
+                         to the default files terraform.tfvars and *.auto.tfvars.
+                         Use this option more than once to include more than one
+                         variables file.
+
+  -json                  Produce output in a machine-readable JSON format,
+                         suitable for use in text editor integrations and other
+                         automated systems. Always disables color.
+
+  -json-into=out.json    Produce the same output as -json, but sent directly
+                         to the given file. This allows automation to preserve
+                         the original human-readable output streams, while
+                         capturing more detailed logs for machine analysis.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *EncryptionInspectCommand) Synopsis() string {
+	return "Show how a state or plan file is encrypted"
+}
@@ -0,0 +1,142 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/workdir"
+)
+
+func testEncryptionInspect(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+
+	view, done := testView(t)
+	c := &EncryptionInspectCommand{
+		Meta: Meta{
+			WorkingDir: workdir.NewDir("."),
+			View:       view,
+		},
+	}
+	code := c.Run(args)
+	output := done(t)
+	return code, output.Stdout(), output.Stderr()
+}
+
+func TestEncryptionInspect_state(t *testing.T) {
+	testCwdTemp(t)
+	testStateFileDefault(t, testState())
+
+	writeConfig := func(primary, fallback string) {
+		t.Helper()
+		if err := os.WriteFile("main.tf", []byte(stateRekeyConfig(primary, fallback)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Encrypt the state with the old key.
+	writeConfig("method.aes_gcm.old", "method.unencrypted.migrate")
+	if code, _, stderr := testStateRekey(t); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, stderr)
+	}
+
+	// With the old key as the primary method
+	code, stdout, stderr := testEncryptionInspect(t, "-no-color", "-detailed-exitcode", arguments.DefaultStateFilename)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, stderr)
+	}
+	for _, want := range []string{
+		`The state file "terraform.tfstate" is encrypted.`,
+		"key_provider.pbkdf2.old: {",
+		"It can be read with the primary encryption method, method.aes_gcm.old.",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected output to contain %q\ngot: %s", want, stdout)
+		}
+	}
+
+	// After rotating to the new key
+	writeConfig("method.aes_gcm.new", "method.aes_gcm.old")
+	code, stdout, stderr = testEncryptionInspect(t, "-no-color", "-detailed-exitcode", arguments.DefaultStateFilename)
+	if code != 2 {
+		t.Fatalf("bad: %d; want 2\n\n%s", code, stderr)
+	}
+	if want := "It can only be read with the fallback encryption method, method.aes_gcm.old."; !strings.Contains(stdout, want) {
+		t.Errorf("expected output to contain %q\ngot: %s", want, stdout)
+	}
+
+	// Without the old key
+	writeConfig("method.aes_gcm.new", "")
+	code, stdout, stderr = testEncryptionInspect(t, "-no-color", arguments.DefaultStateFilename)
+	if code != 1 {
+		t.Fatalf("bad: %d; want 1\n\n%s", code, stdout)
+	}
+	if want := "It cannot be read with the current encryption configuration."; !strings.Contains(stdout, want) {
+		t.Errorf("expected output to contain %q\ngot: %s", want, stdout)
+	}
+	if want := "Failed to decrypt file"; !strings.Contains(stderr, want) {
+		t.Errorf("expected error to contain %q\ngot: %s", want, stderr)
+	}
+}
+
+func TestEncryptionInspect_notEncrypted(t *testing.T) {
+	testCwdTemp(t)
+	testStateFileDefault(t, testState())
+
+	code, stdout, stderr := testEncryptionInspect(t, "-no-color", arguments.DefaultStateFilename)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, stderr)
+	}
+	for _, want := range []string{
+		`The state file "terraform.tfstate" is not encrypted.`,
+		"Encryption is not configured for state files.",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected output to contain %q\ngot: %s", want, stdout)
+		}
+	}
+}
+
+func TestEncryptionInspect_unrecognized(t *testing.T) {
+	testCwdTemp(t)
+	if err := os.WriteFile("main.tf", []byte("# not a state file\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	code, _, stderr := testEncryptionInspect(t, "-no-color", "main.tf")
+	if code != 1 {
+		t.Fatalf("bad: %d; want 1", code)
+	}
+	if want := "Unrecognized file"; !strings.Contains(stderr, want) {
+		t.Errorf("expected error to contain %q\ngot: %s", want, stderr)
+	}
+}
+
+func TestEncryptedFileKind(t *testing.T) {
+	tests := map[string]struct {
+		data     string
+		wantKind string
+		wantOk   bool
+	}{
+		"plan":            {"PK\x03\x04", "plan", true},
+		"encrypted plan":  {`{"meta":{},"encrypted_data":"","encryption_version":"v0"}`, "plan", true},
+		"state":           {`{"version":4,"terraform_version":"1.9.0","serial":1}`, "state", true},
+		"encrypted state": {`{"serial":1,"lineage":"x","meta":{},"encrypted_data":"","encryption_version":"v0"}`, "state", true},
+		"other json":      {`{"hello":"world"}`, "", false},
+		"empty":           {"", "", false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			kind, ok := encryptedFileKind([]byte(tc.data))
+			if kind != tc.wantKind || ok != tc.wantOk {
+				t.Errorf("got %q, %t; want %q, %t", kind, ok, tc.wantKind, tc.wantOk)
+			}
+		})
+	}
+}
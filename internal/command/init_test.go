@@ -199,6 +199,88 @@ func TestInit_get(t *testing.T) {
 	}
 }
 
+func TestInit_getEncryptedModuleManifest(t *testing.T) {
+	// Create a temporary working directory that is empty
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("init-get"), td)
+	t.Chdir(td)
+	encryptionConfig := `
+terraform {
+  encryption {
+    key_provider "pbkdf2" "main" {
+      passphrase = "passphrase-for-testing"
+    }
+    method "aes_gcm" "main" {
+      keys = key_provider.pbkdf2.main
+    }
+    module_manifest {
+      method = method.aes_gcm.main
+    }
+  }
+}
+`
+	if err := os.WriteFile("encryption.tf", []byte(encryptionConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	view, done := testView(t)
+	c := &InitCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			View:             view,
+		},
+	}
+	if code := c.Run([]string{}); code != 0 {
+		t.Fatalf("bad: \n%s", done(t).Stderr())
+	}
+	done(t)
+
+	manifest, err := os.ReadFile(filepath.Join(".terraform", "modules", "modules.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := encryption.IsEncryptionPayload(manifest); err != nil || !ok {
+		t.Fatalf("module manifest is not encrypted: %s", manifest)
+	}
+	if bytes.Contains(manifest, []byte("./foo")) {
+		t.Fatalf("module manifest contains the module source address: %s", manifest)
+	}
+
+	// Commands that otherwise don't use encryption can still load the modules
+	view, done = testView(t)
+	validate := &ValidateCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			View:             view,
+		},
+	}
+	if code := validate.Run([]string{}); code != 0 {
+		t.Fatalf("validate failed: \n%s", done(t).Stderr())
+	}
+	done(t)
+
+	// Without the encryption configuration, the modules cannot be loaded
+	if err := os.Remove("encryption.tf"); err != nil {
+		t.Fatal(err)
+	}
+	view, done = testView(t)
+	validate = &ValidateCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			View:             view,
+		},
+	}
+	if code := validate.Run([]string{}); code == 0 {
+		t.Fatal("validate succeeded without the module manifest encryption configured")
+	}
+	if got, want := done(t).Stderr(), "module manifest is encrypted"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
 func TestInit_getUpgradeModules(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
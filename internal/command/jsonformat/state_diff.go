@@ -0,0 +1,124 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonformat
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/command/format"
+	"github.com/opentofu/opentofu/internal/command/jsonformat/computed"
+	"github.com/opentofu/opentofu/internal/command/jsonformat/computed/renderers"
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
+	"github.com/opentofu/opentofu/internal/command/jsonprovider"
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+// StateDiff describes the differences between two states, using the same
+// structures that describe the changes in a plan. See
+// jsonplan.MarshalStateDiffForRenderer.
+type StateDiff struct {
+	PlanFormatVersion string                     `json:"plan_format_version"`
+	OutputChanges     map[string]jsonplan.Change `json:"output_changes"`
+	ResourceChanges   []jsonplan.ResourceChange  `json:"resource_changes"`
+
+	ProviderFormatVersion string                            `json:"provider_format_version"`
+	ProviderSchemas       map[string]*jsonprovider.Provider `json:"provider_schemas"`
+}
+
+func (renderer Renderer) RenderHumanStateDiff(stateDiff StateDiff) {
+	// We reuse the plan diffing logic, since a state diff is essentially a
+	// plan that has already been applied.
+	plan := Plan{
+		PlanFormatVersion:     stateDiff.PlanFormatVersion,
+		OutputChanges:         stateDiff.OutputChanges,
+		ResourceChanges:       stateDiff.ResourceChanges,
+		ProviderFormatVersion: stateDiff.ProviderFormatVersion,
+		ProviderSchemas:       stateDiff.ProviderSchemas,
+	}
+	diffs := precomputeDiffs(plan, plans.NormalMode)
+
+	counts := make(map[plans.Action]int)
+	var changes []diff
+	for _, diff := range diffs.changes {
+		action := jsonplan.UnmarshalActions(diff.change.Change.Actions)
+		if action == plans.NoOp {
+			continue
+		}
+		changes = append(changes, diff)
+		counts[action]++
+	}
+	outputs := renderHumanDiffOutputs(renderer, diffs.outputs)
+
+	if len(changes) == 0 && len(outputs) == 0 {
+		renderer.Streams.Print(renderer.Colorize.Color("\n[reset][bold][green]No differences.[reset][bold] The two states contain the same resource instances and output values.[reset]\n"))
+		return
+	}
+
+	if len(changes) > 0 {
+		renderer.Streams.Println(format.WordWrap(
+			"\nResource instances are compared using the following symbols:",
+			renderer.Streams.Stdout.Columns()))
+		if counts[plans.Create] > 0 {
+			renderer.Streams.Println(renderer.Colorize.Color("  [green]+[reset] only in the second state"))
+		}
+		if counts[plans.Update] > 0 {
+			renderer.Streams.Println(renderer.Colorize.Color("  [yellow]~[reset] changed between the states"))
+		}
+		if counts[plans.Delete] > 0 {
+			renderer.Streams.Println(renderer.Colorize.Color("  [red]-[reset] only in the first state"))
+		}
+
+		opts := computed.NewRenderHumanOpts(renderer.Colorize, renderer.ShowSensitive)
+		for _, change := range changes {
+			action := jsonplan.UnmarshalActions(change.change.Change.Actions)
+			renderer.Streams.Println()
+			renderer.Streams.Println(fmt.Sprintf(
+				"%s%s %s %s",
+				renderer.Colorize.Color(stateDiffComment(change.change, action)),
+				renderer.Colorize.Color(renderers.DiffActionSymbol(action)),
+				resourceChangeHeader(change.change),
+				change.diff.RenderHuman(0, opts),
+			))
+		}
+
+		renderer.Streams.Printf(
+			renderer.Colorize.Color("\n[bold]Differences:[reset] %d added, %d changed, %d removed.\n"),
+			counts[plans.Create],
+			counts[plans.Update],
+			counts[plans.Delete],
+		)
+	}
+
+	if len(outputs) > 0 {
+		renderer.Streams.Print("\nChanges to Outputs:\n")
+		renderer.Streams.Printf("%s\n", outputs)
+	}
+}
+
+func stateDiffComment(resource jsonplan.ResourceChange, action plans.Action) string {
+	var buf bytes.Buffer
+
+	dispAddr := resource.Address
+	if len(resource.Deposed) != 0 {
+		dispAddr = fmt.Sprintf("%s (deposed object %s)", dispAddr, resource.Deposed)
+	}
+
+	switch action {
+	case plans.Create:
+		buf.WriteString(fmt.Sprintf("[bold]  # %s[reset] was added", dispAddr))
+	case plans.Update:
+		buf.WriteString(fmt.Sprintf("[bold]  # %s[reset] has changed", dispAddr))
+	case plans.Delete:
+		buf.WriteString(fmt.Sprintf("[bold]  # %s[reset] was removed", dispAddr))
+	default:
+		// should never happen, since states can only differ in the above ways
+		buf.WriteString(fmt.Sprintf("%s has an action the state diff renderer doesn't support (this is a bug)", dispAddr))
+	}
+	buf.WriteString("\n")
+
+	return buf.String()
+}
@@ -0,0 +1,126 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonformat
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/colorstring"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
+	"github.com/opentofu/opentofu/internal/command/jsonprovider"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/terminal"
+)
+
+func TestRenderHumanStateDiff(t *testing.T) {
+	color := &colorstring.Colorize{Colors: colorstring.DefaultColors, Disable: true}
+
+	before := basicState(t)
+	after := basicState(t)
+	after.RootModule().SetOutputValue("bar", cty.StringVal("new bar value"), false, "")
+	after.RootModule().SetResourceInstanceCurrent(
+		addrs.Resource{
+			Mode: addrs.ManagedResourceMode,
+			Type: "test_resource",
+			Name: "baz",
+		}.Instance(addrs.IntKey(0)),
+		&states.ResourceInstanceObjectSrc{
+			Status:    states.ObjectReady,
+			AttrsJSON: []byte(`{"woozles":"unconfuzled"}`),
+		},
+		addrs.AbsProviderConfig{
+			Provider: addrs.NewDefaultProvider("test"),
+			Module:   addrs.RootModule,
+		},
+		addrs.NoKey,
+	)
+	after.RootModule().SetResourceInstanceCurrent(
+		addrs.Resource{
+			Mode: addrs.ManagedResourceMode,
+			Type: "test_resource",
+			Name: "baz",
+		}.Instance(addrs.IntKey(1)),
+		&states.ResourceInstanceObjectSrc{
+			Status:    states.ObjectReady,
+			AttrsJSON: []byte(`{"woozles":"brand new"}`),
+		},
+		addrs.AbsProviderConfig{
+			Provider: addrs.NewDefaultProvider("test"),
+			Module:   addrs.RootModule,
+		},
+		addrs.NoKey,
+	)
+
+	tests := map[string]struct {
+		Before, After *states.State
+		Want          []string
+	}{
+		"no differences": {
+			Before: before,
+			After:  before,
+			Want: []string{
+				"No differences. The two states contain the same resource instances and output values.",
+			},
+		},
+		"differences": {
+			Before: before,
+			After:  after,
+			Want: []string{
+				"+ only in the second state",
+				"~ changed between the states",
+				"# test_resource.baz[0] has changed",
+				`~ woozles = "confuzles" -> "unconfuzled"`,
+				"# test_resource.baz[1] was added",
+				`+ woozles = "brand new"`,
+				"Differences: 1 added, 1 changed, 0 removed.",
+				"Changes to Outputs:",
+				`~ bar = "bar value" -> "new bar value"`,
+			},
+		},
+		"removals": {
+			Before: after,
+			After:  before,
+			Want: []string{
+				"- only in the first state",
+				"# test_resource.baz[1] was removed",
+				"Differences: 0 added, 1 changed, 1 removed.",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			outputs, changes, err := jsonplan.MarshalStateDiffForRenderer(tt.Before, tt.After, testSchemas())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			streams, done := terminal.StreamsForTesting(t)
+			renderer := Renderer{
+				Colorize: color,
+				Streams:  streams,
+			}
+			renderer.RenderHumanStateDiff(StateDiff{
+				PlanFormatVersion:     jsonplan.FormatVersion,
+				OutputChanges:         outputs,
+				ResourceChanges:       changes,
+				ProviderFormatVersion: jsonprovider.FormatVersion,
+				ProviderSchemas:       jsonprovider.MarshalForRenderer(testSchemas()),
+			})
+
+			result := done(t).All()
+			for _, want := range tt.Want {
+				if !strings.Contains(result, want) {
+					t.Errorf("output is missing %q\ngot:\n%s", want, result)
+				}
+			}
+		})
+	}
+}
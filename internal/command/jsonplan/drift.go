@@ -0,0 +1,427 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonplan
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/lang/marks"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// DriftFormatVersion represents the version of the json format of a drift
+// report and will be incremented for any change to this format that requires
+// changes to a consuming parser.
+const DriftFormatVersion = "1.0"
+
+// The kinds of drift reported for each resource instance in a DriftReport.
+const (
+	DriftChanged = "changed"
+	DriftDeleted = "deleted"
+	DriftMoved   = "moved"
+)
+
+// DriftReport describes the changes made outside of OpenTofu to the remote
+// objects of the managed resource instances in a state, as detected by a
+// refresh-only plan.
+type DriftReport struct {
+	FormatVersion string            `json:"format_version"`
+	Summary       DriftSummary      `json:"summary"`
+	Resources     []DriftedResource `json:"resources"`
+}
+
+// DriftSummary counts the resource instances in a DriftReport by the kind of
+// drift detected.
+type DriftSummary struct {
+	Changed int `json:"changed"`
+	Deleted int `json:"deleted"`
+	Moved   int `json:"moved"`
+}
+
+// Total returns the number of resource instances that have drifted.
+func (s DriftSummary) Total() int {
+	return s.Changed + s.Deleted + s.Moved
+}
+
+// DriftedResource describes the drift detected for a single resource
+// instance.
+type DriftedResource struct {
+	// Address is the absolute address of the resource instance.
+	Address string `json:"address"`
+
+	// PreviousAddress is the address the resource instance had at the end
+	// of the previous run, if it has since been moved.
+	PreviousAddress string `json:"previous_address,omitempty"`
+
+	Type         string `json:"type"`
+	ProviderName string `json:"provider_name"`
+
+	// Drift is one of DriftChanged, DriftDeleted or DriftMoved. Resource
+	// instances that have both moved and changed are reported as changed,
+	// with PreviousAddress set.
+	Drift string `json:"drift"`
+
+	// Attributes describes each of the attributes whose values changed,
+	// for resource instances that changed.
+	Attributes []DriftedAttribute `json:"attributes,omitempty"`
+}
+
+// DriftedAttribute describes the change to the value of a single attribute,
+// or of an element of a collection, of a resource instance that has drifted.
+type DriftedAttribute struct {
+	// Path is the path of the attribute within the resource instance, in a
+	// syntax similar to the HCL expression language, such as
+	// `tags["Name"]`.
+	Path string `json:"path"`
+
+	// Before and After are the JSON values of the attribute as recorded in
+	// the previous state and as found by refreshing, respectively. Both are
+	// null if Sensitive is true.
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+
+	// Sensitive is true if either of the values is sensitive, in which case
+	// they are not included in the report.
+	Sensitive bool `json:"sensitive,omitempty"`
+}
+
+// MarshalDriftReport builds a DriftReport from the resource drift recorded in
+// the given refresh-only plan.
+//
+// If showSensitive is true then the values of sensitive attributes are
+// included in the report, and otherwise they are omitted.
+func MarshalDriftReport(plan *plans.Plan, schemas *tofu.Schemas, showSensitive bool) (*DriftReport, error) {
+	report := &DriftReport{
+		FormatVersion: DriftFormatVersion,
+		Resources:     []DriftedResource{},
+	}
+
+	for _, rc := range plan.DriftedResources {
+		addr := rc.Addr
+		if addr.Resource.Resource.Mode != addrs.ManagedResourceMode || rc.DeposedKey != "" {
+			continue
+		}
+
+		r := DriftedResource{
+			Address:      addr.String(),
+			Type:         addr.Resource.Resource.Type,
+			ProviderName: rc.ProviderAddr.Provider.String(),
+		}
+		if !rc.PrevRunAddr.Equal(addr) && rc.PrevRunAddr.Resource.Resource.Type != "" {
+			r.PreviousAddress = rc.PrevRunAddr.String()
+		}
+
+		switch rc.Action {
+		case plans.Delete:
+			r.Drift = DriftDeleted
+			report.Summary.Deleted++
+		case plans.NoOp:
+			if r.PreviousAddress == "" {
+				// Not actually drift, so there's nothing to report.
+				continue
+			}
+			r.Drift = DriftMoved
+			report.Summary.Moved++
+		default:
+			schema, _ := schemas.ResourceTypeConfig(
+				rc.ProviderAddr.Provider,
+				addr.Resource.Resource.Mode,
+				addr.Resource.Resource.Type,
+			)
+			if schema == nil {
+				return nil, fmt.Errorf("no schema found for %s (in provider %s)", addr, rc.ProviderAddr.Provider)
+			}
+			change, err := rc.Decode(schema)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode the drift of %s: %w", addr, err)
+			}
+
+			before, beforeMarks := change.Before.UnmarkDeepWithPaths()
+			after, afterMarks := change.After.UnmarkDeepWithPaths()
+			if schema.Block.ContainsMarks() {
+				beforeMarks = append(beforeMarks, schema.Block.ValueMarks(before, nil, nil)...)
+				afterMarks = append(afterMarks, schema.Block.ValueMarks(after, nil, nil)...)
+			}
+			var sensitive []cty.Path
+			if !showSensitive {
+				for _, pvm := range slices.Concat(beforeMarks, afterMarks) {
+					if _, ok := pvm.Marks[marks.Sensitive]; ok {
+						sensitive = append(sensitive, pvm.Path)
+					}
+				}
+			}
+
+			r.Drift = DriftChanged
+			r.Attributes, err = driftedAttributes(nil, before, after, sensitive)
+			if err != nil {
+				return nil, fmt.Errorf("failed to describe the drift of %s: %w", addr, err)
+			}
+			report.Summary.Changed++
+		}
+
+		report.Resources = append(report.Resources, r)
+	}
+
+	return report, nil
+}
+
+// driftedAttributes compares the before and after values at the given path,
+// returning the paths at which they differ. Objects and maps are compared
+// element by element, as are lists and tuples of the same length, while
+// other values, including sets, are compared as a whole.
+func driftedAttributes(path cty.Path, before, after cty.Value, sensitive []cty.Path) ([]DriftedAttribute, error) {
+	if before.RawEquals(after) {
+		return nil, nil
+	}
+
+	ty := before.Type()
+	descend := !before.IsNull() && !after.IsNull() && before.IsKnown() && after.IsKnown()
+	switch {
+	case descend && (ty.IsObjectType() || ty.IsMapType()) && after.Type().Equals(ty):
+		keys := make(map[string]struct{})
+		for k := range before.AsValueMap() {
+			keys[k] = struct{}{}
+		}
+		for k := range after.AsValueMap() {
+			keys[k] = struct{}{}
+		}
+		var ret []DriftedAttribute
+		for _, k := range sortedKeys(keys) {
+			var step cty.PathStep = cty.IndexStep{Key: cty.StringVal(k)}
+			if ty.IsObjectType() {
+				step = cty.GetAttrStep{Name: k}
+			}
+			b, a := driftElement(before, step), driftElement(after, step)
+			attrs, err := driftedAttributes(append(path.Copy(), step), b, a, sensitive)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, attrs...)
+		}
+		return ret, nil
+
+	case descend && (ty.IsListType() || ty.IsTupleType()) && after.Type().Equals(ty) && before.LengthInt() == after.LengthInt():
+		var ret []DriftedAttribute
+		for i := range before.LengthInt() {
+			step := cty.IndexStep{Key: cty.NumberIntVal(int64(i))}
+			attrs, err := driftedAttributes(append(path.Copy(), step), driftElement(before, step), driftElement(after, step), sensitive)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, attrs...)
+		}
+		return ret, nil
+	}
+
+	attr := DriftedAttribute{
+		Path: driftPathString(path),
+	}
+	for _, s := range sensitive {
+		if s.HasPrefix(path) || path.HasPrefix(s) {
+			attr.Sensitive = true
+			break
+		}
+	}
+	if attr.Sensitive {
+		attr.Before = json.RawMessage("null")
+		attr.After = json.RawMessage("null")
+		return []DriftedAttribute{attr}, nil
+	}
+
+	var err error
+	if attr.Before, err = driftValueJSON(before); err != nil {
+		return nil, err
+	}
+	if attr.After, err = driftValueJSON(after); err != nil {
+		return nil, err
+	}
+	return []DriftedAttribute{attr}, nil
+}
+
+// driftElement returns the element of the given object, map, list or tuple
+// at the given step, or a null value if there is no such element.
+func driftElement(v cty.Value, step cty.PathStep) cty.Value {
+	ret, err := step.Apply(v)
+	if err != nil {
+		return cty.NullVal(cty.DynamicPseudoType)
+	}
+	return ret
+}
+
+func driftValueJSON(v cty.Value) (json.RawMessage, error) {
+	if v.IsNull() || !v.IsWhollyKnown() {
+		return json.RawMessage("null"), nil
+	}
+	return ctyjson.Marshal(v, v.Type())
+}
+
+func driftPathString(path cty.Path) string {
+	ret := tfdiags.FormatCtyPath(path)
+	if len(ret) > 0 && ret[0] == '.' {
+		ret = ret[1:]
+	}
+	return ret
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	ret := make([]string, 0, len(m))
+	for k := range m {
+		ret = append(ret, k)
+	}
+	slices.Sort(ret)
+	return ret
+}
+
+// The SARIF version and schema of the logs returned by MarshalDriftSARIF.
+const (
+	driftSARIFVersion = "2.1.0"
+	driftSARIFSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// MarshalDriftSARIF renders a drift report as a log in the Static Analysis
+// Results Interchange Format (SARIF), so that it can be consumed by tools
+// that aggregate findings from many sources, such as code scanning services.
+//
+// Each drifted resource instance is reported as a result of a rule named
+// after its kind of drift, with the resource instance address as its logical
+// location. toolVersion is the version of OpenTofu producing the log.
+func MarshalDriftSARIF(report *DriftReport, toolVersion string) ([]byte, error) {
+	rules := []sarifRule{
+		{
+			ID:               "drift/" + DriftChanged,
+			ShortDescription: sarifMessage{Text: "Resource instance changed outside of OpenTofu"},
+		},
+		{
+			ID:               "drift/" + DriftDeleted,
+			ShortDescription: sarifMessage{Text: "Resource instance deleted outside of OpenTofu"},
+		},
+		{
+			ID:               "drift/" + DriftMoved,
+			ShortDescription: sarifMessage{Text: "Resource instance moved to a new address"},
+		},
+	}
+
+	results := make([]sarifResult, 0, len(report.Resources))
+	for _, r := range report.Resources {
+		result := sarifResult{
+			RuleID: "drift/" + r.Drift,
+			Level:  "warning",
+			Locations: []sarifLocation{
+				{
+					LogicalLocations: []sarifLogicalLocation{
+						{FullyQualifiedName: r.Address, Kind: "resource"},
+					},
+				},
+			},
+			Properties: map[string]interface{}{
+				"type":          r.Type,
+				"provider_name": r.ProviderName,
+			},
+		}
+
+		switch r.Drift {
+		case DriftDeleted:
+			result.Message.Text = fmt.Sprintf("%s has been deleted outside of OpenTofu.", r.Address)
+		case DriftMoved:
+			result.Message.Text = fmt.Sprintf("%s has moved to %s.", r.PreviousAddress, r.Address)
+			result.Level = "note"
+		default:
+			paths := make([]string, len(r.Attributes))
+			for i, attr := range r.Attributes {
+				paths[i] = attr.Path
+			}
+			result.Message.Text = fmt.Sprintf("%s has changed outside of OpenTofu.", r.Address)
+			if len(paths) > 0 {
+				result.Message.Text = fmt.Sprintf("%s has changed outside of OpenTofu: %s.", r.Address, strings.Join(paths, ", "))
+			}
+			result.Properties["attributes"] = r.Attributes
+		}
+		if r.PreviousAddress != "" {
+			result.Properties["previous_address"] = r.PreviousAddress
+		}
+
+		results = append(results, result)
+	}
+
+	log := sarifLog{
+		Version: driftSARIFVersion,
+		Schema:  driftSARIFSchema,
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:           "OpenTofu",
+						Version:        toolVersion,
+						InformationURI: "https://opentofu.org",
+						Rules:          rules,
+					},
+				},
+				Results: results,
+			},
+		},
+	}
+	return json.MarshalIndent(log, "", "  ")
+}
+
+// The types below are the subset of the SARIF object model used by
+// MarshalDriftSARIF.
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
@@ -0,0 +1,258 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonplan
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/lang/marks"
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+func TestMarshalDriftReport(t *testing.T) {
+	provider := addrs.AbsProviderConfig{
+		Provider: addrs.NewDefaultProvider("test"),
+		Module:   addrs.RootModule,
+	}
+	ty := testSchemas().Providers[provider.Provider].ResourceTypes["test_thing"].Block.ImpliedType()
+	thing := func(woozles, foozles string) plans.DynamicValue {
+		v, err := plans.NewDynamicValue(cty.ObjectVal(map[string]cty.Value{
+			"woozles": cty.StringVal(woozles),
+			"foozles": cty.StringVal(foozles),
+		}), ty)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	null, err := plans.NewDynamicValue(cty.NullVal(ty), ty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sensitiveFoozles := []cty.PathValueMarks{
+		{
+			Path:  cty.GetAttrPath("foozles"),
+			Marks: cty.NewValueMarks(marks.Sensitive),
+		},
+	}
+	drift := func(addr, prevAddr string, action plans.Action, before, after plans.DynamicValue) *plans.ResourceInstanceChangeSrc {
+		return &plans.ResourceInstanceChangeSrc{
+			Addr:         mustAddr(addr),
+			PrevRunAddr:  mustAddr(prevAddr),
+			ProviderAddr: provider,
+			ChangeSrc: plans.ChangeSrc{
+				Action:         action,
+				Before:         before,
+				After:          after,
+				BeforeValMarks: sensitiveFoozles,
+				AfterValMarks:  sensitiveFoozles,
+			},
+		}
+	}
+
+	plan := &plans.Plan{
+		UIMode: plans.RefreshOnlyMode,
+		DriftedResources: []*plans.ResourceInstanceChangeSrc{
+			drift("test_thing.changed", "test_thing.changed", plans.Update, thing("old", "secret"), thing("new", "other secret")),
+			drift("test_thing.deleted", "test_thing.deleted", plans.Delete, thing("gone", "secret"), null),
+			drift("test_thing.moved", "test_thing.old", plans.NoOp, thing("same", "secret"), thing("same", "secret")),
+			drift("test_thing.same", "test_thing.same", plans.NoOp, thing("same", "secret"), thing("same", "secret")),
+		},
+	}
+
+	t.Run("redacted", func(t *testing.T) {
+		got, err := MarshalDriftReport(plan, testSchemas(), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &DriftReport{
+			FormatVersion: DriftFormatVersion,
+			Summary:       DriftSummary{Changed: 1, Deleted: 1, Moved: 1},
+			Resources: []DriftedResource{
+				{
+					Address:      "test_thing.changed",
+					Type:         "test_thing",
+					ProviderName: "registry.opentofu.org/hashicorp/test",
+					Drift:        DriftChanged,
+					Attributes: []DriftedAttribute{
+						{
+							Path:      "foozles",
+							Before:    json.RawMessage(`null`),
+							After:     json.RawMessage(`null`),
+							Sensitive: true,
+						},
+						{
+							Path:   "woozles",
+							Before: json.RawMessage(`"old"`),
+							After:  json.RawMessage(`"new"`),
+						},
+					},
+				},
+				{
+					Address:      "test_thing.deleted",
+					Type:         "test_thing",
+					ProviderName: "registry.opentofu.org/hashicorp/test",
+					Drift:        DriftDeleted,
+				},
+				{
+					Address:         "test_thing.moved",
+					PreviousAddress: "test_thing.old",
+					Type:            "test_thing",
+					ProviderName:    "registry.opentofu.org/hashicorp/test",
+					Drift:           DriftMoved,
+				},
+			},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("wrong report\n%s", diff)
+		}
+		if got.Summary.Total() != 3 {
+			t.Errorf("wrong total %d; want 3", got.Summary.Total())
+		}
+	})
+
+	t.Run("show sensitive", func(t *testing.T) {
+		got, err := MarshalDriftReport(plan, testSchemas(), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := DriftedAttribute{
+			Path:   "foozles",
+			Before: json.RawMessage(`"secret"`),
+			After:  json.RawMessage(`"other secret"`),
+		}
+		if diff := cmp.Diff(want, got.Resources[0].Attributes[0]); diff != "" {
+			t.Errorf("wrong attribute\n%s", diff)
+		}
+	})
+}
+
+func TestDriftedAttributes(t *testing.T) {
+	tests := map[string]struct {
+		before, after cty.Value
+		sensitive     []cty.Path
+		want          []string
+	}{
+		"equal": {
+			before: cty.ObjectVal(map[string]cty.Value{"a": cty.StringVal("x")}),
+			after:  cty.ObjectVal(map[string]cty.Value{"a": cty.StringVal("x")}),
+			want:   nil,
+		},
+		"map keys": {
+			before: cty.MapVal(map[string]cty.Value{"a": cty.StringVal("x"), "b": cty.StringVal("y")}),
+			after:  cty.MapVal(map[string]cty.Value{"a": cty.StringVal("z"), "c": cty.StringVal("y")}),
+			want:   []string{`["a"]`, `["b"]`, `["c"]`},
+		},
+		"list same length": {
+			before: cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+			after:  cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("c")}),
+			want:   []string{`[1]`},
+		},
+		"list different length": {
+			before: cty.ListVal([]cty.Value{cty.StringVal("a")}),
+			after:  cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+			want:   []string{``},
+		},
+		"set": {
+			before: cty.SetVal([]cty.Value{cty.StringVal("a")}),
+			after:  cty.SetVal([]cty.Value{cty.StringVal("b")}),
+			want:   []string{``},
+		},
+		"nested object": {
+			before: cty.ObjectVal(map[string]cty.Value{
+				"block": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{"a": cty.StringVal("x")})}),
+			}),
+			after: cty.ObjectVal(map[string]cty.Value{
+				"block": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{"a": cty.StringVal("y")})}),
+			}),
+			want: []string{`block[0].a`},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			attrs, err := driftedAttributes(nil, test.before, test.after, test.sensitive)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, attr := range attrs {
+				got = append(got, attr.Path)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong paths\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMarshalDriftSARIF(t *testing.T) {
+	report := &DriftReport{
+		FormatVersion: DriftFormatVersion,
+		Summary:       DriftSummary{Changed: 1, Deleted: 1},
+		Resources: []DriftedResource{
+			{
+				Address:      "test_thing.changed",
+				Type:         "test_thing",
+				ProviderName: "registry.opentofu.org/hashicorp/test",
+				Drift:        DriftChanged,
+				Attributes: []DriftedAttribute{
+					{Path: "woozles", Before: json.RawMessage(`"old"`), After: json.RawMessage(`"new"`)},
+				},
+			},
+			{
+				Address:      "test_thing.deleted",
+				Type:         "test_thing",
+				ProviderName: "registry.opentofu.org/hashicorp/test",
+				Drift:        DriftDeleted,
+			},
+		},
+	}
+
+	raw, err := MarshalDriftSARIF(report, "1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Version string `json:"version"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID  string `json:"ruleId"`
+				Message struct {
+					Text string `json:"text"`
+				} `json:"message"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != "2.1.0" || len(got.Runs) != 1 || got.Runs[0].Tool.Driver.Version != "1.2.3" {
+		t.Fatalf("unexpected SARIF log:\n%s", raw)
+	}
+	var results []string
+	for _, r := range got.Runs[0].Results {
+		results = append(results, r.RuleID+": "+r.Message.Text)
+	}
+	want := []string{
+		"drift/changed: test_thing.changed has changed outside of OpenTofu: woozles.",
+		"drift/deleted: test_thing.deleted has been deleted outside of OpenTofu.",
+	}
+	if diff := cmp.Diff(want, results); diff != "" {
+		t.Errorf("wrong results\n%s", diff)
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonplan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/command/jsonstate"
+	"github.com/opentofu/opentofu/internal/configs"
+)
+
+// sensitiveValueMask replaces each sensitive value masked by MaskSensitiveValues.
+var sensitiveValueMask = json.RawMessage(`"(sensitive value)"`)
+
+// MaskSensitiveValues replaces the sensitive values in the given JSON
+// representation of a plan with a placeholder, using the sensitive flags of
+// the root module variables and outputs declared in the given configuration
+// and the sensitive paths of the resources.
+//
+// Plans are otherwise marshalled with the sensitive values in the clear, so
+// this is for the callers that write them where secrets must not end up,
+// such as the plan snapshots of tofu test, which are usually committed.
+func MaskSensitiveValues(output *Plan, config *configs.Config) error {
+	for name, variable := range output.Variables {
+		if decl, ok := config.Module.Variables[name]; ok && decl.Sensitive && variable != nil {
+			variable.Value = maskedValue(variable.Value)
+		}
+	}
+
+	for name, out := range output.PlannedValues.Outputs {
+		if out.Sensitive {
+			out.Value = maskedValue(out.Value)
+			output.PlannedValues.Outputs[name] = out
+		}
+	}
+	if err := maskPlannedModule(&output.PlannedValues.RootModule); err != nil {
+		return err
+	}
+
+	for _, changes := range [][]ResourceChange{output.ResourceDrift, output.ResourceChanges} {
+		for i := range changes {
+			if err := maskChange(&changes[i].Change); err != nil {
+				return fmt.Errorf("%s: %w", changes[i].Address, err)
+			}
+		}
+	}
+	for name, change := range output.OutputChanges {
+		if err := maskChange(&change); err != nil {
+			return fmt.Errorf("output.%s: %w", name, err)
+		}
+		output.OutputChanges[name] = change
+	}
+
+	if len(output.PriorState) > 0 {
+		var state jsonstate.State
+		if err := json.Unmarshal(output.PriorState, &state); err != nil {
+			return err
+		}
+		if state.Values != nil {
+			for name, out := range state.Values.Outputs {
+				if out.Sensitive {
+					out.Value = maskedValue(out.Value)
+					state.Values.Outputs[name] = out
+				}
+			}
+			if err := maskStateModule(&state.Values.RootModule); err != nil {
+				return err
+			}
+		}
+		src, err := json.Marshal(state)
+		if err != nil {
+			return err
+		}
+		output.PriorState = src
+	}
+	return nil
+}
+
+func maskPlannedModule(module *Module) error {
+	for i := range module.Resources {
+		resource := &module.Resources[i]
+		var sensitive map[string]json.RawMessage
+		if len(resource.SensitiveValues) > 0 {
+			if err := json.Unmarshal(resource.SensitiveValues, &sensitive); err != nil {
+				return fmt.Errorf("%s: %w", resource.Address, err)
+			}
+		}
+		for name, paths := range sensitive {
+			value, ok := resource.AttributeValues[name].(json.RawMessage)
+			if !ok {
+				continue
+			}
+			masked, err := maskJSON(value, paths)
+			if err != nil {
+				return fmt.Errorf("%s: %w", resource.Address, err)
+			}
+			resource.AttributeValues[name] = masked
+		}
+	}
+	for i := range module.ChildModules {
+		if err := maskPlannedModule(&module.ChildModules[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func maskStateModule(module *jsonstate.Module) error {
+	for i := range module.Resources {
+		resource := &module.Resources[i]
+		var sensitive map[string]json.RawMessage
+		if len(resource.SensitiveValues) > 0 {
+			if err := json.Unmarshal(resource.SensitiveValues, &sensitive); err != nil {
+				return fmt.Errorf("%s: %w", resource.Address, err)
+			}
+		}
+		for name, paths := range sensitive {
+			value, ok := resource.AttributeValues[name]
+			if !ok {
+				continue
+			}
+			masked, err := maskJSON(value, paths)
+			if err != nil {
+				return fmt.Errorf("%s: %w", resource.Address, err)
+			}
+			resource.AttributeValues[name] = masked
+		}
+	}
+	for i := range module.ChildModules {
+		if err := maskStateModule(&module.ChildModules[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func maskChange(change *Change) error {
+	var err error
+	if change.Before, err = maskJSON(change.Before, change.BeforeSensitive); err != nil {
+		return err
+	}
+	if change.After, err = maskJSON(change.After, change.AfterSensitive); err != nil {
+		return err
+	}
+	return nil
+}
+
+// maskJSON replaces the parts of a JSON value that are marked as
+// sensitive in the matching JSON value of sensitive paths, which holds true
+// for each sensitive value and omits the others.
+func maskJSON(value, sensitive json.RawMessage) (json.RawMessage, error) {
+	if len(value) == 0 || len(sensitive) == 0 {
+		return value, nil
+	}
+	var paths any
+	if err := json.Unmarshal(sensitive, &paths); err != nil {
+		return nil, err
+	}
+	if paths == true {
+		return maskedValue(value), nil
+	}
+
+	// We keep the numbers as they are, rather than converting them to
+	// floating point and back.
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(value))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(maskPaths(v, paths))
+}
+
+func maskPaths(value, paths any) any {
+	switch paths := paths.(type) {
+	case bool:
+		if paths && value != nil {
+			return sensitiveValueMask
+		}
+	case map[string]any:
+		if obj, ok := value.(map[string]any); ok {
+			for k, p := range paths {
+				if v, ok := obj[k]; ok {
+					obj[k] = maskPaths(v, p)
+				}
+			}
+		}
+	case []any:
+		if list, ok := value.([]any); ok {
+			for i := range min(len(list), len(paths)) {
+				list[i] = maskPaths(list[i], paths[i])
+			}
+		}
+	}
+	return value
+}
+
+// maskedValue returns the value to write in place of the given
+// sensitive value. Null values are kept, as they don't reveal anything.
+func maskedValue(value json.RawMessage) json.RawMessage {
+	if len(value) == 0 || string(value) == "null" {
+		return value
+	}
+	return sensitiveValueMask
+}
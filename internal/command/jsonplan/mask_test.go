@@ -0,0 +1,160 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonplan
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/command/jsonstate"
+	"github.com/opentofu/opentofu/internal/configs"
+)
+
+func TestMaskSensitiveValues(t *testing.T) {
+	config := &configs.Config{
+		Module: &configs.Module{
+			Variables: map[string]*configs.Variable{
+				"password": {Name: "password", Sensitive: true},
+				"region":   {Name: "region"},
+			},
+		},
+	}
+	output := &Plan{
+		Variables: Variables{
+			"password": {Value: json.RawMessage(`"hunter2"`)},
+			"region":   {Value: json.RawMessage(`"eu-west-1"`)},
+		},
+		PlannedValues: StateValues{
+			Outputs: map[string]Output{
+				"secret": {Sensitive: true, Value: json.RawMessage(`"hunter2"`)},
+				"empty":  {Sensitive: true, Value: json.RawMessage(`null`)},
+				"public": {Value: json.RawMessage(`"hello"`)},
+			},
+			RootModule: Module{
+				ChildModules: []Module{{
+					Address: "module.child",
+					Resources: []Resource{{
+						Address: "module.child.test_thing.a",
+						AttributeValues: AttributeValues{
+							"id":   json.RawMessage(`"a"`),
+							"tags": json.RawMessage(`{"owner":"me","token":"hunter2"}`),
+						},
+						SensitiveValues: json.RawMessage(`{"tags":{"token":true}}`),
+					}},
+				}},
+			},
+		},
+		ResourceChanges: []ResourceChange{{
+			Address: "test_thing.b",
+			Change: Change{
+				Before:          json.RawMessage(`{"keys":["one","two"],"size":10000000000000000001}`),
+				BeforeSensitive: json.RawMessage(`{"keys":[false,true]}`),
+				After:           json.RawMessage(`{"keys":["one","three"],"size":10000000000000000001}`),
+				AfterSensitive:  json.RawMessage(`true`),
+			},
+		}},
+		OutputChanges: map[string]Change{
+			"secret": {
+				After:          json.RawMessage(`"hunter2"`),
+				AfterSensitive: json.RawMessage(`true`),
+			},
+		},
+		PriorState: json.RawMessage(`{"values":{"outputs":{"secret":{"sensitive":true,"value":"hunter2"}},"root_module":{"resources":[{"address":"test_thing.c","values":{"password":"hunter2","name":"c"},"sensitive_values":{"password":true}}]}}}`),
+	}
+
+	if err := MaskSensitiveValues(output, config); err != nil {
+		t.Fatal(err)
+	}
+
+	var priorState jsonstate.State
+	if err := json.Unmarshal(output.PriorState, &priorState); err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{
+		"var.password":                   string(output.Variables["password"].Value),
+		"var.region":                     string(output.Variables["region"].Value),
+		"output.secret":                  string(output.PlannedValues.Outputs["secret"].Value),
+		"output.empty":                   string(output.PlannedValues.Outputs["empty"].Value),
+		"output.public":                  string(output.PlannedValues.Outputs["public"].Value),
+		"module.child.test_thing.a.id":   string(output.PlannedValues.RootModule.ChildModules[0].Resources[0].AttributeValues["id"].(json.RawMessage)),
+		"module.child.test_thing.a.tags": string(output.PlannedValues.RootModule.ChildModules[0].Resources[0].AttributeValues["tags"].(json.RawMessage)),
+		"test_thing.b before":            string(output.ResourceChanges[0].Change.Before),
+		"test_thing.b after":             string(output.ResourceChanges[0].Change.After),
+		"output.secret after":            string(output.OutputChanges["secret"].After),
+		"prior output.secret":            string(priorState.Values.Outputs["secret"].Value),
+		"prior test_thing.c.password":    string(priorState.Values.RootModule.Resources[0].AttributeValues["password"]),
+		"prior test_thing.c.name":        string(priorState.Values.RootModule.Resources[0].AttributeValues["name"]),
+	}
+	want := map[string]string{
+		"var.password":                   `"(sensitive value)"`,
+		"var.region":                     `"eu-west-1"`,
+		"output.secret":                  `"(sensitive value)"`,
+		"output.empty":                   `null`,
+		"output.public":                  `"hello"`,
+		"module.child.test_thing.a.id":   `"a"`,
+		"module.child.test_thing.a.tags": `{"owner":"me","token":"(sensitive value)"}`,
+		"test_thing.b before":            `{"keys":["one","(sensitive value)"],"size":10000000000000000001}`,
+		"test_thing.b after":             `"(sensitive value)"`,
+		"output.secret after":            `"(sensitive value)"`,
+		"prior output.secret":            `"(sensitive value)"`,
+		"prior test_thing.c.password":    `"(sensitive value)"`,
+		"prior test_thing.c.name":        `"c"`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
+
+func TestMaskJSON(t *testing.T) {
+	tests := map[string]struct {
+		value     string
+		sensitive string
+		want      string
+	}{
+		"no sensitive paths": {
+			value: `{"a":"b"}`,
+			want:  `{"a":"b"}`,
+		},
+		"whole value": {
+			value:     `{"a":"b"}`,
+			sensitive: `true`,
+			want:      `"(sensitive value)"`,
+		},
+		"null value": {
+			value:     `null`,
+			sensitive: `true`,
+			want:      `null`,
+		},
+		"nested": {
+			value:     `{"a":{"b":"c","d":[1,2]},"e":"f"}`,
+			sensitive: `{"a":{"d":[false,true]}}`,
+			want:      `{"a":{"b":"c","d":[1,"(sensitive value)"]},"e":"f"}`,
+		},
+		"null nested value": {
+			value:     `{"a":null}`,
+			sensitive: `{"a":true}`,
+			want:      `{"a":null}`,
+		},
+		"large numbers": {
+			value:     `{"a":12345678901234567890,"b":1}`,
+			sensitive: `{"b":true}`,
+			want:      `{"a":12345678901234567890,"b":"(sensitive value)"}`,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := maskJSON(json.RawMessage(tc.value), json.RawMessage(tc.sensitive))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,461 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonplan
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// MarshalStateDiffForRenderer compares two states and describes the
+// differences between them using the same structures that MarshalForRenderer
+// returns for the changes in a plan, so that they can be presented by the
+// same renderer.
+//
+// Each resource instance object that exists only in the "before" state is
+// reported as a deletion, each one that exists only in the "after" state as a
+// creation, and each one that exists in both as either an update or a no-op,
+// depending on whether its value differs. Output values of the root module
+// are compared in the same way.
+//
+// Each object is decoded using the schema of the provider that manages it in
+// the corresponding state. If the provider differs between the two states and
+// the objects of the two providers have different types, the object is
+// reported as a deletion and a creation instead, each described using its own
+// provider's schema. Resource instance objects whose provider schema is
+// unavailable are compared using the schema returned by StateDiffSchemas
+// instead.
+//
+// As with plans, deletions of data resource instances are not reported.
+func MarshalStateDiffForRenderer(before, after *states.State, schemas *tofu.Schemas) (map[string]Change, []ResourceChange, error) {
+	changes := plans.NewChanges()
+	schemas, inferred := stateDiffSchemas(before, after, schemas)
+
+	for _, obj := range stateDiffObjects(before, after) {
+		objs := []*stateDiffObject{obj}
+		if obj.before != nil && obj.after != nil {
+			beforeSchema, err := stateDiffSchema(schemas, obj.beforeProvider, obj.addr)
+			if err != nil {
+				return nil, nil, err
+			}
+			afterSchema, err := stateDiffSchema(schemas, obj.afterProvider, obj.addr)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !beforeSchema.Block.ImpliedType().Equals(afterSchema.Block.ImpliedType()) {
+				// A single change can't describe objects of different types.
+				objs = []*stateDiffObject{
+					{addr: obj.addr, deposed: obj.deposed, beforeProvider: obj.beforeProvider, before: obj.before},
+					{addr: obj.addr, deposed: obj.deposed, afterProvider: obj.afterProvider, after: obj.after},
+				}
+			}
+		}
+
+		for _, obj := range objs {
+			src, err := stateDiffChange(obj, schemas, inferred)
+			if err != nil {
+				return nil, nil, err
+			}
+			changes.Resources = append(changes.Resources, src)
+		}
+	}
+
+	var beforeOutputs, afterOutputs map[string]*states.OutputValue
+	if before != nil {
+		beforeOutputs = before.RootModule().OutputValues
+	}
+	if after != nil {
+		afterOutputs = after.RootModule().OutputValues
+	}
+	names := make(map[string]struct{})
+	for name := range beforeOutputs {
+		names[name] = struct{}{}
+	}
+	for name := range afterOutputs {
+		names[name] = struct{}{}
+	}
+	for name := range names {
+		beforeOV, afterOV := beforeOutputs[name], afterOutputs[name]
+		beforeV, afterV := cty.NullVal(cty.DynamicPseudoType), cty.NullVal(cty.DynamicPseudoType)
+		sensitive := false
+		if beforeOV != nil {
+			beforeV = beforeOV.Value
+			sensitive = sensitive || beforeOV.Sensitive
+		}
+		if afterOV != nil {
+			afterV = afterOV.Value
+			sensitive = sensitive || afterOV.Sensitive
+		}
+
+		oc := &plans.OutputChange{
+			Addr: addrs.OutputValue{Name: name}.Absolute(addrs.RootModuleInstance),
+			Change: plans.Change{
+				Action: stateDiffAction(beforeOV != nil, afterOV != nil, beforeV, afterV),
+				Before: beforeV,
+				After:  afterV,
+			},
+			Sensitive: sensitive,
+		}
+		src, err := oc.Encode()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encode the differences for output %q: %w", name, err)
+		}
+		changes.Outputs = append(changes.Outputs, src)
+	}
+
+	outputs, err := MarshalOutputChanges(changes)
+	if err != nil {
+		return nil, nil, err
+	}
+	resources, err := MarshalResourceChanges(changes.Resources, schemas)
+	if err != nil {
+		return nil, nil, err
+	}
+	return outputs, resources, nil
+}
+
+// stateDiffChange describes the differences between the two sides of the given
+// object, decoding each side using the schema of its own provider.
+func stateDiffChange(obj *stateDiffObject, schemas *tofu.Schemas, inferred map[*configschema.Block]bool) (*plans.ResourceInstanceChangeSrc, error) {
+	decode := func(src *states.ResourceInstanceObjectSrc, provider addrs.AbsProviderConfig) (cty.Value, *providers.Schema, error) {
+		if src == nil {
+			return cty.NilVal, nil, nil
+		}
+		schema, err := stateDiffSchema(schemas, provider, obj.addr)
+		if err != nil {
+			return cty.NilVal, nil, err
+		}
+		if inferred[schema.Block] {
+			v, err := stateDiffInferredObjectValue(src, schema.Block.ImpliedType())
+			return v, schema, err
+		}
+		v, err := stateDiffObjectValue(src, schema.Block.ImpliedType())
+		return v, schema, err
+	}
+
+	beforeV, beforeSchema, err := decode(obj.before, obj.beforeProvider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s in the first state: %w", obj.addr, err)
+	}
+	afterV, afterSchema, err := decode(obj.after, obj.afterProvider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s in the second state: %w", obj.addr, err)
+	}
+
+	// The change is encoded using the schema of the provider that manages
+	// the object from now on, whose type the other side also has.
+	schema := afterSchema
+	if obj.after == nil {
+		schema = beforeSchema
+	}
+	ty := schema.Block.ImpliedType()
+	if obj.before == nil {
+		beforeV = cty.NullVal(ty)
+	}
+	if obj.after == nil {
+		afterV = cty.NullVal(ty)
+	}
+
+	rc := &plans.ResourceInstanceChange{
+		Addr:         obj.addr,
+		PrevRunAddr:  obj.addr,
+		DeposedKey:   obj.deposed,
+		ProviderAddr: obj.provider(),
+		Change: plans.Change{
+			Action: stateDiffAction(obj.before != nil, obj.after != nil, beforeV, afterV),
+			Before: beforeV,
+			After:  afterV,
+		},
+	}
+	src, err := rc.Encode(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the differences for %s: %w", obj.addr, err)
+	}
+	return src, nil
+}
+
+// stateDiffSchema returns the schema of the given resource instance, as
+// managed by the given provider.
+func stateDiffSchema(schemas *tofu.Schemas, provider addrs.AbsProviderConfig, addr addrs.AbsResourceInstance) (*providers.Schema, error) {
+	schema, _ := schemas.ResourceTypeConfig(
+		provider.Provider,
+		addr.Resource.Resource.Mode,
+		addr.Resource.Resource.Type,
+	)
+	if schema == nil {
+		// stateDiffSchemas returns a schema for every object in the two
+		// states, so this should never happen.
+		return nil, fmt.Errorf("no schema found for %s (in provider %s)", addr, provider.Provider)
+	}
+	return schema, nil
+}
+
+// stateDiffObject pairs up the objects with the same address and deposed key
+// from the two states being compared. Either side is nil if the object is
+// absent from the corresponding state.
+//
+// The two sides are managed by different providers if the provider of the
+// resource changed, for example with "tofu state replace-provider", and so
+// each side has its own provider configuration.
+type stateDiffObject struct {
+	addr    addrs.AbsResourceInstance
+	deposed states.DeposedKey
+
+	beforeProvider, afterProvider addrs.AbsProviderConfig
+	before, after                 *states.ResourceInstanceObjectSrc
+}
+
+// provider returns the provider configuration that manages the object from
+// now on, which is the one from the second state unless the object is absent
+// from it.
+func (obj *stateDiffObject) provider() addrs.AbsProviderConfig {
+	if obj.after == nil {
+		return obj.beforeProvider
+	}
+	return obj.afterProvider
+}
+
+func stateDiffObjects(before, after *states.State) []*stateDiffObject {
+	type objKey struct {
+		addr    addrs.UniqueKey
+		deposed states.DeposedKey
+	}
+	var ret []*stateDiffObject
+	objs := make(map[objKey]*stateDiffObject)
+
+	collect := func(state *states.State, isBefore bool) {
+		if state == nil {
+			return
+		}
+		for _, ms := range state.Modules {
+			for _, rs := range ms.Resources {
+				for key, is := range rs.Instances {
+					addr := rs.Addr.Instance(key)
+					record := func(deposed states.DeposedKey, src *states.ResourceInstanceObjectSrc) {
+						k := objKey{addr.UniqueKey(), deposed}
+						obj, ok := objs[k]
+						if !ok {
+							obj = &stateDiffObject{addr: addr, deposed: deposed}
+							objs[k] = obj
+							ret = append(ret, obj)
+						}
+						if isBefore {
+							obj.beforeProvider, obj.before = rs.ProviderConfig, src
+						} else {
+							obj.afterProvider, obj.after = rs.ProviderConfig, src
+						}
+					}
+					if is.Current != nil {
+						record(states.NotDeposed, is.Current)
+					}
+					for dk, src := range is.Deposed {
+						record(dk, src)
+					}
+				}
+			}
+		}
+	}
+	collect(before, true)
+	collect(after, false)
+
+	return ret
+}
+
+func stateDiffObjectValue(src *states.ResourceInstanceObjectSrc, ty cty.Type) (cty.Value, error) {
+	if src == nil {
+		return cty.NullVal(ty), nil
+	}
+	obj, err := src.Decode(ty)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	return obj.Value, nil
+}
+
+// StateDiffSchemas returns the given schemas, extended with a schema for each
+// resource type in the two states whose provider schema is unavailable, for
+// example because the provider isn't installed or no longer supports the
+// resource type.
+//
+// The schema of such a resource type is inferred from the objects in the
+// states, and declares each of their top-level attributes as an optional
+// attribute of dynamic type. This is enough to compare and render the
+// objects, but any attribute the provider would mark as sensitive is only
+// hidden if it was recorded as sensitive in the state.
+//
+// The result is meant for rendering the differences returned by
+// MarshalStateDiffForRenderer, which must be given the original schemas.
+func StateDiffSchemas(before, after *states.State, schemas *tofu.Schemas) *tofu.Schemas {
+	ret, _ := stateDiffSchemas(before, after, schemas)
+	return ret
+}
+
+// stateDiffSchemas implements StateDiffSchemas, additionally returning the
+// schema blocks that were inferred from the states.
+func stateDiffSchemas(before, after *states.State, schemas *tofu.Schemas) (*tofu.Schemas, map[*configschema.Block]bool) {
+	ret := &tofu.Schemas{
+		Providers: make(map[addrs.Provider]providers.ProviderSchema),
+	}
+	if schemas != nil {
+		ret.Provisioners = schemas.Provisioners
+		for provider, schema := range schemas.Providers {
+			ret.Providers[provider] = schema
+		}
+	}
+	inferred := make(map[*configschema.Block]bool)
+
+	for _, obj := range stateDiffObjects(before, after) {
+		for _, side := range []struct {
+			provider addrs.AbsProviderConfig
+			src      *states.ResourceInstanceObjectSrc
+		}{
+			{obj.beforeProvider, obj.before},
+			{obj.afterProvider, obj.after},
+		} {
+			if side.src == nil {
+				continue
+			}
+			provider := side.provider.Provider
+			mode, typeName := obj.addr.Resource.Resource.Mode, obj.addr.Resource.Resource.Type
+			schema, _ := ret.ResourceTypeConfig(provider, mode, typeName)
+			if schema != nil && !inferred[schema.Block] {
+				continue
+			}
+
+			block := &configschema.Block{
+				Attributes: make(map[string]*configschema.Attribute),
+			}
+			if schema != nil {
+				block = schema.Block
+			}
+			for _, name := range stateDiffAttributeNames(side.src) {
+				block.Attributes[name] = &configschema.Attribute{
+					Type:     cty.DynamicPseudoType,
+					Optional: true,
+				}
+			}
+			if schema != nil {
+				continue
+			}
+			inferred[block] = true
+
+			// The provider schema is copied before adding to it, so that the
+			// given schemas are left unchanged.
+			ps := ret.Providers[provider]
+			switch mode {
+			case addrs.ManagedResourceMode:
+				ps.ResourceTypes = maps.Clone(ps.ResourceTypes)
+				if ps.ResourceTypes == nil {
+					ps.ResourceTypes = make(map[string]providers.Schema)
+				}
+				ps.ResourceTypes[typeName] = providers.Schema{Block: block}
+			case addrs.DataResourceMode:
+				ps.DataSources = maps.Clone(ps.DataSources)
+				if ps.DataSources == nil {
+					ps.DataSources = make(map[string]providers.Schema)
+				}
+				ps.DataSources[typeName] = providers.Schema{Block: block}
+			}
+			ret.Providers[provider] = ps
+		}
+	}
+
+	return ret, inferred
+}
+
+// stateDiffAttributeNames returns the names of the top-level attributes of the
+// given object.
+func stateDiffAttributeNames(src *states.ResourceInstanceObjectSrc) []string {
+	if src == nil {
+		return nil
+	}
+	var names []string
+	if src.AttrsFlat != nil {
+		// Objects in the legacy flatmap format are compared key by key.
+		for key := range src.AttrsFlat {
+			names = append(names, key)
+		}
+		return names
+	}
+	var attrs map[string]json.RawMessage
+	if err := json.Unmarshal(src.AttrsJSON, &attrs); err != nil {
+		// The error is reported when the object is decoded.
+		return nil
+	}
+	for name := range attrs {
+		names = append(names, name)
+	}
+	return names
+}
+
+// stateDiffInferredObjectValue decodes an object whose schema was inferred by
+// stateDiffSchemas, using the types implied by its JSON representation.
+func stateDiffInferredObjectValue(src *states.ResourceInstanceObjectSrc, ty cty.Type) (cty.Value, error) {
+	if src == nil {
+		return cty.NullVal(ty), nil
+	}
+
+	var val cty.Value
+	if src.AttrsFlat != nil {
+		flat := make(map[string]cty.Value, len(src.AttrsFlat))
+		for key, v := range src.AttrsFlat {
+			flat[key] = cty.StringVal(v)
+		}
+		val = cty.ObjectVal(flat)
+	} else {
+		implied, err := ctyjson.ImpliedType(src.AttrsJSON)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		val, err = ctyjson.Unmarshal(src.AttrsJSON, implied)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		if !val.Type().IsObjectType() {
+			return cty.NilVal, fmt.Errorf("object is not a JSON object")
+		}
+	}
+
+	attrs := make(map[string]cty.Value, len(ty.AttributeTypes()))
+	for name := range ty.AttributeTypes() {
+		if val.Type().HasAttribute(name) {
+			attrs[name] = val.GetAttr(name)
+		} else {
+			attrs[name] = cty.NullVal(cty.DynamicPseudoType)
+		}
+	}
+	ret := cty.ObjectVal(attrs)
+	if src.AttrSensitivePaths != nil {
+		ret = ret.MarkWithPaths(src.AttrSensitivePaths)
+	}
+	return ret, nil
+}
+
+func stateDiffAction(haveBefore, haveAfter bool, before, after cty.Value) plans.Action {
+	switch {
+	case !haveBefore:
+		return plans.Create
+	case !haveAfter:
+		return plans.Delete
+	case before.RawEquals(after):
+		// RawEquals also compares marks, so a value that only became
+		// sensitive or non-sensitive is reported as an update.
+		return plans.NoOp
+	default:
+		return plans.Update
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonplan
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func TestMarshalStateDiffForRenderer(t *testing.T) {
+	provider := addrs.AbsProviderConfig{
+		Provider: addrs.NewDefaultProvider("test"),
+		Module:   addrs.RootModule,
+	}
+	thing := func(s *states.SyncState, addr string, woozles string) {
+		s.SetResourceInstanceCurrent(
+			mustAddr(addr),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"woozles":"` + woozles + `","foozles":null}`),
+				Status:    states.ObjectReady,
+			},
+			provider,
+			addrs.NoKey,
+		)
+	}
+	output := func(s *states.SyncState, name string, value cty.Value, sensitive bool) {
+		s.SetOutputValue(addrs.OutputValue{Name: name}.Absolute(addrs.RootModuleInstance), value, sensitive, "")
+	}
+
+	before := states.BuildState(func(s *states.SyncState) {
+		thing(s, "test_thing.changed", "old")
+		thing(s, "test_thing.removed", "gone")
+		thing(s, "test_thing.same", "same")
+		output(s, "changed", cty.StringVal("old"), false)
+		output(s, "removed", cty.StringVal("gone"), false)
+	})
+	after := states.BuildState(func(s *states.SyncState) {
+		thing(s, "test_thing.added", "new")
+		thing(s, "test_thing.changed", "new")
+		thing(s, "test_thing.same", "same")
+		output(s, "added", cty.StringVal("new"), true)
+		output(s, "changed", cty.StringVal("new"), false)
+	})
+
+	outputs, resources, err := MarshalStateDiffForRenderer(before, after, testSchemas())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gotResources := make(map[string][]string)
+	for _, rc := range resources {
+		gotResources[rc.Address] = rc.Change.Actions
+	}
+	wantResources := map[string][]string{
+		"test_thing.added":   {"create"},
+		"test_thing.changed": {"update"},
+		"test_thing.removed": {"delete"},
+		"test_thing.same":    {"no-op"},
+	}
+	if diff := cmp.Diff(wantResources, gotResources); diff != "" {
+		t.Errorf("wrong resource changes\n%s", diff)
+	}
+
+	gotOutputs := make(map[string][]string)
+	for name, oc := range outputs {
+		gotOutputs[name] = oc.Actions
+	}
+	wantOutputs := map[string][]string{
+		"added":   {"create"},
+		"changed": {"update"},
+		"removed": {"delete"},
+	}
+	if diff := cmp.Diff(wantOutputs, gotOutputs); diff != "" {
+		t.Errorf("wrong output changes\n%s", diff)
+	}
+	if got, want := string(outputs["added"].AfterSensitive), "true"; got != want {
+		t.Errorf("wrong sensitivity for the added output %s; want %s", got, want)
+	}
+
+	var changedAfter map[string]interface{}
+	for _, rc := range resources {
+		if rc.Address != "test_thing.changed" {
+			continue
+		}
+		if err := json.Unmarshal(rc.Change.After, &changedAfter); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := changedAfter["woozles"], "new"; got != want {
+		t.Errorf("wrong new value for test_thing.changed %q; want %q", got, want)
+	}
+}
+
+func TestMarshalStateDiffForRenderer_noSchema(t *testing.T) {
+	provider := addrs.AbsProviderConfig{
+		Provider: addrs.NewDefaultProvider("other"),
+		Module:   addrs.RootModule,
+	}
+	thing := func(s *states.SyncState, addr string, attrs string) {
+		s.SetResourceInstanceCurrent(
+			mustAddr(addr),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(attrs),
+				Status:    states.ObjectReady,
+			},
+			provider,
+			addrs.NoKey,
+		)
+	}
+
+	before := states.BuildState(func(s *states.SyncState) {
+		thing(s, "other_thing.changed", `{"id":"a","tags":["x"]}`)
+		thing(s, "other_thing.same", `{"id":"b"}`)
+	})
+	after := states.BuildState(func(s *states.SyncState) {
+		thing(s, "other_thing.added", `{"id":"c","size":2}`)
+		thing(s, "other_thing.changed", `{"id":"a","tags":["x","y"],"size":1}`)
+		thing(s, "other_thing.same", `{"id":"b"}`)
+	})
+
+	schemas := testSchemas()
+	_, resources, err := MarshalStateDiffForRenderer(before, after, schemas)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gotResources := make(map[string][]string)
+	gotAfter := make(map[string]string)
+	for _, rc := range resources {
+		gotResources[rc.Address] = rc.Change.Actions
+		gotAfter[rc.Address] = string(rc.Change.After)
+	}
+	wantResources := map[string][]string{
+		"other_thing.added":   {"create"},
+		"other_thing.changed": {"update"},
+		"other_thing.same":    {"no-op"},
+	}
+	if diff := cmp.Diff(wantResources, gotResources); diff != "" {
+		t.Errorf("wrong resource changes\n%s", diff)
+	}
+	wantAfter := map[string]string{
+		"other_thing.added":   `{"id":"c","size":2,"tags":null}`,
+		"other_thing.changed": `{"id":"a","size":1,"tags":["x","y"]}`,
+		"other_thing.same":    `{"id":"b","size":null,"tags":null}`,
+	}
+	if diff := cmp.Diff(wantAfter, gotAfter); diff != "" {
+		t.Errorf("wrong values after\n%s", diff)
+	}
+
+	// The given schemas are left unchanged, while the schemas for rendering
+	// the differences include the inferred schema.
+	if schema, _ := schemas.ResourceTypeConfig(provider.Provider, addrs.ManagedResourceMode, "other_thing"); schema != nil {
+		t.Error("the given schemas were modified")
+	}
+	schema, _ := StateDiffSchemas(before, after, schemas).ResourceTypeConfig(provider.Provider, addrs.ManagedResourceMode, "other_thing")
+	if schema == nil {
+		t.Fatal("no schema inferred for other_thing")
+	}
+	if got, want := len(schema.Block.Attributes), 3; got != want {
+		t.Errorf("inferred schema has %d attributes; want %d", got, want)
+	}
+}
+
+func TestMarshalStateDiffForRenderer_providerChanged(t *testing.T) {
+	thing := func(s *states.SyncState, provider addrs.Provider, attrs string) {
+		s.SetResourceInstanceCurrent(
+			mustAddr("test_thing.moved"),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(attrs),
+				Status:    states.ObjectReady,
+			},
+			addrs.AbsProviderConfig{
+				Provider: provider,
+				Module:   addrs.RootModule,
+			},
+			addrs.NoKey,
+		)
+	}
+
+	// The object is managed by a provider without a schema in the second
+	// state, whose inferred schema has a different type from the schema of
+	// the first state's provider, so the object is reported as deleted and
+	// created instead of changed.
+	before := states.BuildState(func(s *states.SyncState) {
+		thing(s, addrs.NewDefaultProvider("test"), `{"woozles":"old","foozles":null}`)
+	})
+	after := states.BuildState(func(s *states.SyncState) {
+		thing(s, addrs.NewDefaultProvider("other"), `{"id":"new"}`)
+	})
+
+	_, resources, err := MarshalStateDiffForRenderer(before, after, testSchemas())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type change struct {
+		Provider      string
+		Actions       []string
+		Before, After string
+	}
+	var got []change
+	for _, rc := range resources {
+		got = append(got, change{
+			Provider: rc.ProviderName,
+			Actions:  rc.Change.Actions,
+			Before:   string(rc.Change.Before),
+			After:    string(rc.Change.After),
+		})
+	}
+	want := []change{
+		{
+			Provider: "registry.opentofu.org/hashicorp/test",
+			Actions:  []string{"delete"},
+			Before:   `{"foozles":null,"woozles":"old"}`,
+			After:    "null",
+		},
+		{
+			Provider: "registry.opentofu.org/hashicorp/other",
+			Actions:  []string{"create"},
+			Before:   "null",
+			After:    `{"id":"new"}`,
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong resource changes\n%s", diff)
+	}
+}
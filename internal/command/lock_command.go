@@ -0,0 +1,39 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+// LockCommand is a Command implementation that just shows help for
+// the subcommands nested below it.
+type LockCommand struct {
+	Meta
+}
+
+func (c *LockCommand) Run(_ []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *LockCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] lock <subcommand> [options]
+
+  This command has subcommands for inspecting the lock on the state of the
+  current workspace.
+
+  To release a lock that is no longer in use, use "tofu force-unlock".
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *LockCommand) Synopsis() string {
+	return "State lock introspection"
+}
@@ -0,0 +1,174 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+	"github.com/opentofu/opentofu/internal/tracing"
+)
+
+// LockStatusCommand is a cli.Command implementation that reports on the lock
+// currently held on the state, without acquiring it.
+type LockStatusCommand struct {
+	Meta
+}
+
+func (c *LockStatusCommand) Run(rawArgs []string) int {
+	ctx := c.CommandContext()
+	ctx, span := tracing.Tracer().Start(ctx, "Lock status")
+	defer span.End()
+
+	common, rawArgs := arguments.ParseView(rawArgs)
+	c.View.Configure(common)
+
+	// Parse and validate flags
+	args, closer, diags := arguments.ParseLockStatus(rawArgs)
+	defer closer()
+
+	// Instantiate the view, even if there are flag errors, so that we render
+	// diagnostics according to the desired view
+	view := views.NewLockStatus(args.ViewOptions, c.View)
+
+	if diags.HasErrors() {
+		view.Diagnostics(diags)
+		if args.ViewOptions.ViewType == arguments.ViewJSON {
+			return 1 // in case it's json, do not print the help of the command
+		}
+		return cli.RunResultHelp
+	}
+	c.Meta.variableArgs = args.Vars.All()
+
+	// This gets the current directory as full path.
+	configPath := c.WorkingDir.NormalizePath(c.WorkingDir.RootModuleDir())
+
+	// Load the encryption configuration
+	enc, encDiags := c.EncryptionFromPath(ctx, configPath)
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	backendConfig, backendDiags := c.loadBackendConfig(ctx, configPath)
+	diags = diags.Append(backendDiags)
+	if diags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// Load the backend
+	b, backendDiags := c.Backend(ctx, &BackendOpts{
+		Config: backendConfig,
+		View:   view.Backend(),
+	}, enc.State())
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// inspecting the lock doesn't read or write any state data
+	c.ignoreRemoteVersionConflict(b)
+
+	workspace, err := c.Workspace(ctx)
+	if err != nil {
+		view.Diagnostics(diags.Append(fmt.Errorf("Error selecting workspace: %s", err)))
+		return 1
+	}
+	stateMgr, err := b.StateMgr(ctx, workspace)
+	if err != nil {
+		view.Diagnostics(diags.Append(fmt.Errorf("Failed to load state: %s", err)))
+		return 1
+	}
+
+	if optionalLocker, ok := stateMgr.(statemgr.OptionalLocker); ok && !optionalLocker.IsLockingEnabled() {
+		view.Diagnostics(diags)
+		view.LockingDisabledForBackend()
+		return 1
+	}
+
+	inspector, ok := statemgr.LockInspectorFor(stateMgr)
+	if !ok {
+		view.Diagnostics(diags)
+		if _, isLocal := stateMgr.(*statemgr.Filesystem); isLocal {
+			view.LocalStateLockInspectionUnsupported()
+		} else {
+			view.LockInspectionUnsupported()
+		}
+		return 1
+	}
+
+	info, err := inspector.CurrentLock(ctx)
+	if err != nil {
+		view.Diagnostics(diags.Append(fmt.Errorf("Failed to inspect the state lock: %s", err)))
+		return 1
+	}
+
+	view.Diagnostics(diags)
+	if info == nil {
+		view.NotLocked(workspace)
+		return 0
+	}
+	view.Locked(workspace, info)
+	if args.DetailedExitCode {
+		return 2
+	}
+	return 0
+}
+
+func (c *LockStatusCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] lock status [options]
+
+  Show who is holding the lock on the state for the current workspace, and
+  since when, without acquiring the lock.
+
+  This will not modify your infrastructure or your state. Use it before
+  running "tofu force-unlock" to check that the lock really is stale.
+
+  Not all backends are able to report on the current lock. Locks on local
+  state files are released automatically when the process holding them
+  exits, so there is nothing to report on for them.
+
+Options:
+
+  -detailed-exitcode     Return detailed exit codes when the command exits.
+                         This will change the meaning of exit codes to:
+                         0 - Succeeded, the state is not locked
+                         1 - Errored
+                         2 - Succeeded, the state is locked
+
+  -var 'foo=bar'         Set a value for one of the input variables in the root
+                         module of the configuration. Use this option more than
+                         once to set more than one variable.
+
+  -var-file=filename     Load variable values from the given file, in addition
+                         to the default files terraform.tfvars and *.auto.tfvars.
+                         Use this option more than once to include more than one
+                         variables file.
+
+  -json                  Produce output in a machine-readable JSON format,
+                         suitable for use in text editor integrations and other
+                         automated systems. Always disables color.
+
+  -json-into=out.json    Produce the same output as -json, but sent directly
+                         to the given file. This allows automation to preserve
+                         the original human-readable output streams, while
+                         capturing more detailed logs for machine analysis.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *LockStatusCommand) Synopsis() string {
+	return "Show who holds the lock on the current workspace"
+}
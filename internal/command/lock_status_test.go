@@ -0,0 +1,122 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/backend/remote-state/inmem"
+	"github.com/opentofu/opentofu/internal/command/workdir"
+)
+
+func TestLockStatus_inmemBackend(t *testing.T) {
+	// Create a temporary working directory that is empty
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("backend-inmem-locked"), td)
+	t.Chdir(td)
+	defer inmem.Reset()
+
+	// init backend
+	initView, initDone := testView(t)
+	ci := &InitCommand{
+		Meta: Meta{
+			WorkingDir: workdir.NewDir("."),
+			View:       initView,
+		},
+	}
+	code := ci.Run(nil)
+	initOutput := initDone(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n%s", code, initOutput.Stderr())
+	}
+
+	// lockID set in the test fixture
+	const lockID = "2b6a6738-5dd5-50d6-c0ae-f6352977666b"
+
+	view, done := testView(t)
+	c := &LockStatusCommand{
+		Meta: Meta{
+			WorkingDir: workdir.NewDir("."),
+			View:       view,
+		},
+	}
+	code = c.Run([]string{"-no-color", "-detailed-exitcode"})
+	output := done(t)
+	if code != 2 {
+		t.Fatalf("bad: %d\n%s", code, output.All())
+	}
+	for _, want := range []string{
+		`The state for workspace "default" is locked.`,
+		"ID:        " + lockID,
+		"Info:      test config",
+		"tofu force-unlock " + lockID,
+	} {
+		if !strings.Contains(output.Stdout(), want) {
+			t.Errorf("output should contain %q\ngot:\n%s", want, output.Stdout())
+		}
+	}
+}
+
+func TestLockStatus_inmemBackendUnlocked(t *testing.T) {
+	// Create a temporary working directory that is empty
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("inmem-backend"), td)
+	t.Chdir(td)
+	defer inmem.Reset()
+
+	// init backend
+	initView, initDone := testView(t)
+	ci := &InitCommand{
+		Meta: Meta{
+			WorkingDir: workdir.NewDir("."),
+			View:       initView,
+		},
+	}
+	code := ci.Run(nil)
+	initOutput := initDone(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n%s", code, initOutput.Stderr())
+	}
+
+	view, done := testView(t)
+	c := &LockStatusCommand{
+		Meta: Meta{
+			WorkingDir: workdir.NewDir("."),
+			View:       view,
+		},
+	}
+	code = c.Run([]string{"-no-color", "-detailed-exitcode"})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n%s", code, output.All())
+	}
+	if got, want := output.Stdout(), `The state for workspace "default" is not locked.`; !strings.Contains(got, want) {
+		t.Errorf("output should contain %q\ngot:\n%s", want, got)
+	}
+}
+
+func TestLockStatus_localState(t *testing.T) {
+	testCwdTemp(t)
+	testStateFileDefault(t, testState())
+
+	view, done := testView(t)
+	c := &LockStatusCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			View:             view,
+		},
+	}
+	code := c.Run(nil)
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("bad: %d\n%s", code, output.All())
+	}
+	if got, want := output.Stderr(), "Lock inspection unsupported"; !strings.Contains(got, want) {
+		t.Errorf("output should contain %q\ngot:\n%s", want, got)
+	}
+}
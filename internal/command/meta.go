@@ -34,8 +34,10 @@ import (
 	"github.com/opentofu/opentofu/internal/command/workdir"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configload"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/getmodules"
 	"github.com/opentofu/opentofu/internal/getproviders"
+	"github.com/opentofu/opentofu/internal/plugin/cassette"
 	"github.com/opentofu/opentofu/internal/plugins"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/provisioners"
@@ -160,6 +162,15 @@ type Meta struct {
 	// Override certain behavior for tests within this package
 	testingOverrides *testingOverrides
 
+	// providerRecording, if set, records the gRPC calls made to the provider
+	// plugins. This is set by the test command's -record option.
+	providerRecording *cassette.Cassette
+
+	// providerReplay, if set, replaces the provider plugins recorded in it
+	// with ones that replay the recorded calls. This is set by the test
+	// command's -replay option.
+	providerReplay *cassette.Cassette
+
 	// ----------------------------------------------------------
 	// Private: do not set these
 	// ----------------------------------------------------------
@@ -179,10 +190,30 @@ type Meta struct {
 	// backendState is the currently active backend state
 	backendState *clistate.BackendState
 
+	// backendConfigEncryption is the encryption of the backend configuration
+	// stored in the data directory, set when the encryption configuration is
+	// loaded. If nil, the backend configuration is stored unencrypted.
+	backendConfigEncryption encryption.BackendConfigEncryption
+
+	// moduleManifestEncryption is the encryption of the module manifest
+	// stored in the data directory, set when the encryption configuration is
+	// loaded. If nil, it is loaded before any modules are loaded or installed.
+	moduleManifestEncryption encryption.ModuleManifestEncryption
+
+	// moduleManifestUnencrypted is set when "tofu init -from-module" wrote
+	// the module manifest before the encryption configuration of the new
+	// working directory could be loaded.
+	moduleManifestUnencrypted bool
+
 	// Variables for the context (private)
 	variableArgs []flags.RawFlag
 	input        bool
 
+	// workspaceOverride, if set, is the name of the workspace to use instead
+	// of the currently-selected one. This is used when running a command in
+	// several workspaces at once, using -workspace-pattern.
+	workspaceOverride string
+
 	// The fields below are expected to be set by the command via
 	// command line flags. See the Apply command for an example.
 	//
@@ -476,8 +507,12 @@ func (m *Meta) Workspace(ctx context.Context) (string, error) {
 
 // WorkspaceOverridden returns the name of the currently configured workspace,
 // corresponding to the desired named state, as well as a bool saying whether
-// this was set via the TF_WORKSPACE environment variable.
+// this was set via the TF_WORKSPACE environment variable or because the
+// command is running in each of the workspaces matching -workspace-pattern.
 func (m *Meta) WorkspaceOverridden(_ context.Context) (string, bool) {
+	if m.workspaceOverride != "" {
+		return m.workspaceOverride, true
+	}
 	if envVar := os.Getenv(WorkspaceNameEnvVar); envVar != "" {
 		return envVar, true
 	}
@@ -595,12 +595,22 @@ func (m *Meta) backendFromConfig(ctx context.Context, opts *BackendOpts, enc enc
 	// if we're using a remote backend. This may not yet exist which means
 	// we haven't used a non-local backend before. That is okay.
 	statePath := filepath.Join(m.WorkingDir.DataDir(), arguments.DefaultStateFilename)
-	sMgr := &clistate.LocalState{Path: statePath, DataDirOverridden: m.WorkingDir.DataDirOverridden()}
+	sMgr := &clistate.LocalState{Path: statePath, DataDirOverridden: m.WorkingDir.DataDirOverridden(), Encryption: m.backendConfigEncryption}
 	if err := sMgr.RefreshState(context.TODO()); err != nil {
 		diags = diags.Append(fmt.Errorf("Failed to load backend configuration from %s: %w", statePath, err))
 		return nil, diags
 	}
 
+	// If the backend configuration was read using the fallback encryption
+	// method, write it again right away so that it is encrypted with the
+	// primary method from now on.
+	if s := sMgr.State(); s != nil && sMgr.StateEncryptionStatus() == encryption.StatusMigration {
+		if err := sMgr.WriteState(s); err != nil {
+			diags = diags.Append(fmt.Errorf("Failed to re-encrypt backend configuration in %s: %w", statePath, err))
+			return nil, diags
+		}
+	}
+
 	// Load the state, it must be non-nil for the tests below but can be empty
 	s := sMgr.State()
 	if s == nil {
@@ -812,7 +822,7 @@ func (m *Meta) backendFromState(ctx context.Context, enc encryption.StateEncrypt
 	// if we're using a remote backend. This may not yet exist which means
 	// we haven't used a non-local backend before. That is okay.
 	statePath := filepath.Join(m.WorkingDir.DataDir(), arguments.DefaultStateFilename)
-	sMgr := &clistate.LocalState{Path: statePath, DataDirOverridden: m.WorkingDir.DataDirOverridden()}
+	sMgr := &clistate.LocalState{Path: statePath, DataDirOverridden: m.WorkingDir.DataDirOverridden(), Encryption: m.backendConfigEncryption}
 	if err := sMgr.RefreshState(context.TODO()); err != nil {
 		diags = diags.Append(fmt.Errorf("Failed to load backend configuration from %s: %w", statePath, err))
 		return nil, diags
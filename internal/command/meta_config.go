@@ -44,6 +44,11 @@ func (m *Meta) loadConfig(ctx context.Context, rootDir string) (*configs.Config,
 		return nil, diags
 	}
 
+	diags = diags.Append(m.initModuleManifestEncryption(ctx, rootDir))
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
 	config, hclDiags := m.configLoader().LoadConfig(ctx, rootDir, call)
 	diags = diags.Append(hclDiags)
 	return config, diags
@@ -61,6 +66,11 @@ func (m *Meta) loadConfigWithTests(ctx context.Context, rootDir, testDir string)
 		return nil, diags
 	}
 
+	diags = diags.Append(m.initModuleManifestEncryption(ctx, rootDir))
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
 	config, hclDiags := m.configLoader().LoadConfigWithTests(ctx, rootDir, testDir, call)
 	diags = diags.Append(hclDiags)
 	return config, diags
@@ -246,7 +256,13 @@ func (m *Meta) installModules(ctx context.Context, rootDir, testsDir string, upg
 		return true, diags
 	}
 
+	diags = diags.Append(m.initModuleManifestEncryption(ctx, rootDir))
+	if diags.HasErrors() {
+		return true, diags
+	}
+
 	inst := initwd.NewModuleInstaller(m.WorkingDir.ModulesDir(), loader, m.registryClient(ctx), m.ModulePackageFetcher)
+	inst.ManifestEncryption = m.moduleManifestEncryption
 	if m.NewRuntimeEnabled() {
 		// Tell the module installer it should use
 		// the configuration for the new runtime instead
@@ -302,6 +318,9 @@ func (m *Meta) initDirFromModule(ctx context.Context, targetDir string, addr str
 		))
 		return true, diags
 	}
+	// The module manifest is encrypted once the encryption configuration of
+	// the new working directory is loaded. See setModuleManifestEncryption.
+	m.moduleManifestUnencrypted = !moreDiags.HasErrors()
 	return false, diags
 }
 
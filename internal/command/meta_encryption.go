@@ -14,6 +14,7 @@ import (
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/encryption/config"
+	"github.com/opentofu/opentofu/internal/modsdir"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -41,6 +42,26 @@ func (m *Meta) EncryptionFromPath(ctx context.Context, path string) (encryption.
 }
 
 func (m *Meta) EncryptionFromModule(ctx context.Context, module *configs.Module) (encryption.Encryption, tfdiags.Diagnostics) {
+	cfg, diags := m.encryptionConfig(module)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	enc, encDiags := encryption.New(ctx, encryption.DefaultRegistry, cfg, module.StaticEvaluator)
+	diags = diags.Append(encDiags)
+	if enc != nil {
+		// The backend configuration is read and written deep inside the
+		// backend initialization, so we keep its encryption around for then.
+		m.backendConfigEncryption = enc.BackendConfig()
+		diags = diags.Append(m.setModuleManifestEncryption(enc.ModuleManifest()))
+	}
+
+	return enc, diags
+}
+
+// encryptionConfig returns the encryption configuration of the given module,
+// merged with the one given in the TF_ENCRYPTION environment variable.
+func (m *Meta) encryptionConfig(module *configs.Module) (*config.EncryptionConfig, tfdiags.Diagnostics) {
 	cfg := module.Encryption
 	var diags tfdiags.Diagnostics
 
@@ -53,9 +74,71 @@ func (m *Meta) EncryptionFromModule(ctx context.Context, module *configs.Module)
 		}
 		cfg = cfg.Merge(envCfg)
 	}
+	return cfg, diags
+}
 
-	enc, encDiags := encryption.New(ctx, encryption.DefaultRegistry, cfg, module.StaticEvaluator)
-	diags = diags.Append(encDiags)
+// initModuleManifestEncryption makes sure that the module manifest encryption
+// is set before any modules are loaded or installed from the given root
+// module directory.
+//
+// Most commands load the encryption configuration before loading modules. For
+// the ones that don't, the encryption is only set up here if the
+// module_manifest target is configured, so that they don't call any key
+// providers otherwise.
+func (m *Meta) initModuleManifestEncryption(ctx context.Context, rootDir string) tfdiags.Diagnostics {
+	if m.moduleManifestEncryption != nil {
+		return nil
+	}
 
-	return enc, diags
+	// Any problems with the root module are reported again when the caller
+	// loads it in full, so we don't report them here.
+	module, diags := m.loadSingleModule(ctx, rootDir, configs.SelectiveLoadEncryption)
+	if diags.HasErrors() {
+		return nil
+	}
+	cfg, diags := m.encryptionConfig(module)
+	if diags.HasErrors() {
+		return diags
+	}
+	if cfg == nil || cfg.ModuleManifest == nil {
+		m.moduleManifestEncryption = encryption.ModuleManifestEncryptionDisabled()
+		return nil
+	}
+
+	_, encDiags := m.EncryptionFromModule(ctx, module)
+	return encDiags
+}
+
+// setModuleManifestEncryption sets the encryption used by the config loader
+// and the module installer for the module manifest.
+func (m *Meta) setModuleManifestEncryption(enc encryption.ModuleManifestEncryption) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	m.moduleManifestEncryption = enc
+	modulesDir := m.WorkingDir.ModulesDir()
+
+	if m.moduleManifestUnencrypted {
+		// The module manifest was written by "tofu init -from-module" before
+		// the encryption configuration was available, so we encrypt it now.
+		manifest, err := modsdir.ReadManifestSnapshotForDir(modulesDir, nil)
+		if err == nil {
+			err = manifest.WriteSnapshotToDir(modulesDir, enc)
+		}
+		if err != nil {
+			return diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to encrypt module manifest",
+				fmt.Sprintf("Could not encrypt the manifest of installed modules in %s: %s.", modulesDir, err),
+			))
+		}
+		m.moduleManifestUnencrypted = false
+	}
+
+	if err := m.configLoader().SetModuleManifestEncryption(enc); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read module manifest",
+			fmt.Sprintf("Could not read the manifest of installed modules in %s: %s.", modulesDir, err),
+		))
+	}
+	return diags
 }
@@ -17,6 +17,7 @@ import (
 	"sync"
 
 	plugin "github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	terraformProvider "github.com/opentofu/opentofu/internal/builtin/providers/tf"
@@ -310,7 +311,7 @@ func (m *Meta) providerFactories() (map[addrs.Provider]providers.Factory, error)
 		// This should only ever be called once per provider type.
 		// It creates the schema cache to be re-used in all of the subsequent
 		// provider instances.
-		factory := providerFactory(cached)
+		factory := providerFactory(cached, m.providerDialOptions(provider))
 
 		factories[provider] = func() (providers.Interface, error) {
 			checkLock.Lock()
@@ -328,10 +329,18 @@ func (m *Meta) providerFactories() (map[addrs.Provider]providers.Factory, error)
 		}
 	}
 	for provider, localDir := range devOverrideProviders {
-		factories[provider] = devOverrideProviderFactory(provider, localDir)
+		factories[provider] = devOverrideProviderFactory(provider, localDir, m.providerDialOptions(provider))
 	}
 	for provider, reattach := range unmanagedProviders {
-		factories[provider] = unmanagedProviderFactory(provider, reattach)
+		factories[provider] = unmanagedProviderFactory(provider, reattach, m.providerDialOptions(provider))
+	}
+	if m.providerReplay != nil {
+		// Replayed providers don't need to be installed, so we also discard
+		// any errors about them not being available.
+		for provider, factory := range m.providerReplay.Factories() {
+			factories[provider] = factory
+			delete(errs, provider)
+		}
 	}
 
 	var err error
@@ -341,6 +350,17 @@ func (m *Meta) providerFactories() (map[addrs.Provider]providers.Factory, error)
 	return factories, err
 }
 
+// providerDialOptions returns the options for the gRPC connection to the
+// plugin of the given provider.
+func (m *Meta) providerDialOptions(provider addrs.Provider) []grpc.DialOption {
+	if m.providerRecording == nil {
+		return nil
+	}
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(m.providerRecording.Interceptor(provider)),
+	}
+}
+
 func (m *Meta) internalProviders() map[string]providers.Factory {
 	return map[string]providers.Factory{
 		"terraform": func() (providers.Interface, error) {
@@ -351,8 +371,8 @@ func (m *Meta) internalProviders() map[string]providers.Factory {
 
 // providerFactory produces a provider factory that runs up the executable
 // file in the given cache package and uses go-plugin to implement
-// providers.Interface against it.
-func providerFactory(meta *providercache.CachedProvider) providers.Factory {
+// providers.Interface against it, connecting with the given gRPC options.
+func providerFactory(meta *providercache.CachedProvider, dialOptions []grpc.DialOption) providers.Factory {
 	schemaCache := providers.NewSchemaCache()
 
 	return func() (providers.Interface, error) {
@@ -371,6 +391,7 @@ func providerFactory(meta *providercache.CachedProvider) providers.Factory {
 			VersionedPlugins: tfplugin.VersionedPlugins,
 			SyncStdout:       logging.PluginOutputMonitor(fmt.Sprintf("%s:stdout", meta.Provider)),
 			SyncStderr:       logging.PluginOutputMonitor(fmt.Sprintf("%s:stderr", meta.Provider)),
+			GRPCDialOptions:  dialOptions,
 		}
 
 		client := plugin.NewClient(config)
@@ -414,7 +435,7 @@ func initializeProviderInstance(plugin any, protoVer int, pluginClient *plugin.C
 	}
 }
 
-func devOverrideProviderFactory(provider addrs.Provider, localDir getproviders.PackageLocalDir) providers.Factory {
+func devOverrideProviderFactory(provider addrs.Provider, localDir getproviders.PackageLocalDir, dialOptions []grpc.DialOption) providers.Factory {
 	// A dev override is essentially a synthetic cache entry for our purposes
 	// here, so that's how we'll construct it. The providerFactory function
 	// doesn't actually care about the version, so we can leave it
@@ -424,13 +445,13 @@ func devOverrideProviderFactory(provider addrs.Provider, localDir getproviders.P
 		Provider:   provider,
 		Version:    getproviders.UnspecifiedVersion,
 		PackageDir: string(localDir),
-	})
+	}, dialOptions)
 }
 
 // unmanagedProviderFactory produces a provider factory that uses the passed
 // reattach information to connect to go-plugin processes that are already
 // running, and implements providers.Interface against it.
-func unmanagedProviderFactory(provider addrs.Provider, reattach *plugin.ReattachConfig) providers.Factory {
+func unmanagedProviderFactory(provider addrs.Provider, reattach *plugin.ReattachConfig, dialOptions []grpc.DialOption) providers.Factory {
 	schemaCache := providers.NewSchemaCache()
 
 	return func() (providers.Interface, error) {
@@ -442,6 +463,7 @@ func unmanagedProviderFactory(provider addrs.Provider, reattach *plugin.Reattach
 			Reattach:         reattach,
 			SyncStdout:       logging.PluginOutputMonitor(fmt.Sprintf("%s:stdout", provider)),
 			SyncStderr:       logging.PluginOutputMonitor(fmt.Sprintf("%s:stderr", provider)),
+			GRPCDialOptions:  dialOptions,
 		}
 
 		if reattach.ProtocolVersion == 0 {
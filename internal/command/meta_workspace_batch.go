@@ -0,0 +1,283 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// runInWorkspaces runs a command in each of the workspaces whose names match
+// the given pattern, instead of only in the currently-selected workspace.
+//
+// The run callback is called once for each matching workspace, with a copy
+// of the receiver that selects that workspace and whose view captures the
+// output of the command, and must return the exit status of the command for
+// that workspace. Up to pattern.Parallelism workspaces are processed
+// concurrently. The output for each workspace is rendered once the command
+// completes for that workspace, followed by a summary of all of the results.
+//
+// The currently-selected workspace is never changed, so a failure in one of
+// the workspaces leaves the working directory as it was.
+func (m *Meta) runInWorkspaces(ctx context.Context, pattern *arguments.WorkspacePattern, viewType arguments.ViewType, run func(ctx context.Context, m *Meta) int) int {
+	var diags tfdiags.Diagnostics
+	view := views.NewWorkspaceBatch(viewType, m.View)
+
+	workspaces, moreDiags := m.matchingWorkspaces(ctx, pattern)
+	diags = diags.Append(moreDiags)
+	if diags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+	view.Diagnostics(diags)
+
+	// Each workspace gets its own shutdown channel, fed from ours, because
+	// each running operation only takes one value from its channel for each
+	// interrupt, and so a shared channel would only stop one of them.
+	shutdown := newShutdownBroadcast(m.ShutdownCh)
+	defer shutdown.close()
+
+	results := make([]*views.WorkspaceBatchResult, len(workspaces))
+	var lock sync.Mutex // held while rendering results
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, pattern.Parallelism)
+	for i, workspace := range workspaces {
+		// Acquiring the semaphore before starting each goroutine means
+		// that the workspaces start in order. Once interrupted, the
+		// command isn't started in any of the remaining workspaces.
+		select {
+		case sem <- struct{}{}:
+		case <-shutdown.interrupted:
+		}
+		shutdownCh, ok := shutdown.subscribe()
+		if !ok {
+			for j := i; j < len(workspaces); j++ {
+				results[j] = &views.WorkspaceBatchResult{
+					Workspace: workspaces[j],
+					ExitCode:  1,
+					Skipped:   true,
+				}
+			}
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result := m.runInWorkspace(ctx, workspace, shutdownCh, run)
+
+			lock.Lock()
+			defer lock.Unlock()
+			results[i] = result
+			view.WorkspaceResult(result)
+		}()
+	}
+	wg.Wait()
+
+	view.Summary(pattern.Pattern, results)
+
+	// The combined exit status is an error if the command failed for any
+	// of the workspaces, or otherwise the "succeeded with changes" status
+	// of "tofu plan -detailed-exitcode" if that was returned for any of
+	// them.
+	ret := 0
+	for _, result := range results {
+		switch result.ExitCode {
+		case 0:
+		case 2:
+			if ret == 0 {
+				ret = 2
+			}
+		default:
+			ret = 1
+		}
+	}
+	return ret
+}
+
+// matchingWorkspaces returns the sorted names of the workspaces of the
+// configured backend whose names match the given pattern.
+func (m *Meta) matchingWorkspaces(ctx context.Context, pattern *arguments.WorkspacePattern) ([]string, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	enc, encDiags := m.Encryption(ctx)
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		return nil, diags
+	}
+
+	backendConfig, backendDiags := m.loadBackendConfig(ctx, ".")
+	diags = diags.Append(backendDiags)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	b, backendDiags := m.Backend(ctx, &BackendOpts{
+		Config: backendConfig,
+	}, enc.State())
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		return nil, diags
+	}
+
+	// Listing the workspaces doesn't write any state, and each of the
+	// workspaces will check the remote version again when the command
+	// runs in it.
+	m.ignoreRemoteVersionConflict(b)
+
+	all, err := b.Workspaces(ctx)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Error loading workspaces",
+			fmt.Sprintf("Listing workspaces failed: %s", err),
+		))
+		return nil, diags
+	}
+
+	var ret []string
+	for _, workspace := range all {
+		if pattern.Match(workspace) {
+			ret = append(ret, workspace)
+		}
+	}
+	if len(ret) == 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"No matching workspaces",
+			fmt.Sprintf("None of the workspaces of the configured backend match the pattern %q given in -workspace-pattern.", pattern.Pattern),
+		))
+		return nil, diags
+	}
+	slices.Sort(ret)
+	return ret, diags
+}
+
+// runInWorkspace runs a command for one of the workspaces selected by
+// runInWorkspaces, capturing its output.
+func (m *Meta) runInWorkspace(ctx context.Context, workspace string, shutdownCh <-chan struct{}, run func(ctx context.Context, m *Meta) int) *views.WorkspaceBatchResult {
+	result := &views.WorkspaceBatchResult{
+		Workspace: workspace,
+	}
+
+	view, done, err := m.View.Buffered()
+	if err != nil {
+		result.ExitCode = 1
+		result.Stderr = []byte(fmt.Sprintf("Failed to capture the output for workspace %q: %s\n", workspace, err))
+		return result
+	}
+
+	// The copy shares the configuration of the receiver, but must not share
+	// any of the state that is cached while a command runs, because that
+	// state depends on the workspace and the copies run concurrently.
+	child := *m
+	child.View = view
+	child.ShutdownCh = shutdownCh
+	child.workspaceOverride = workspace
+	child.input = false
+	child.cfgLoader = nil
+	child.backendState = nil
+	child.rootModuleCallCache = nil
+	child.inputVariableCache = nil
+
+	result.ExitCode = run(ctx, &child)
+
+	result.Stdout, result.Stderr, err = done()
+	if err != nil {
+		log.Printf("[ERROR] failed to capture the output for workspace %q: %s", workspace, err)
+	}
+	return result
+}
+
+// shutdownBroadcast forwards each interrupt received on a shutdown channel to
+// every one of the channels subscribed to it, so that all of the workspaces
+// being processed concurrently by runInWorkspaces are interrupted.
+type shutdownBroadcast struct {
+	// interrupted is closed when the first interrupt is received.
+	interrupted chan struct{}
+	done        chan struct{}
+
+	mu      sync.Mutex
+	signals int
+	subs    []chan struct{}
+}
+
+func newShutdownBroadcast(shutdownCh <-chan struct{}) *shutdownBroadcast {
+	b := &shutdownBroadcast{
+		interrupted: make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	if shutdownCh == nil {
+		// Running in a unit testing context without a shutdown channel,
+		// so there will never be an interrupt.
+		return b
+	}
+	go func() {
+		for {
+			select {
+			case _, ok := <-shutdownCh:
+				b.broadcast(!ok)
+				if !ok {
+					return
+				}
+			case <-b.done:
+				return
+			}
+		}
+	}()
+	return b
+}
+
+// subscribe returns a new channel that receives all of the interrupts from
+// now on, or false if an interrupt has already been received.
+func (b *shutdownBroadcast) subscribe() (<-chan struct{}, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.signals > 0 {
+		return nil, false
+	}
+	// RunOperation waits for at most two interrupts: the first stops the
+	// operation gracefully and the second cancels it.
+	ch := make(chan struct{}, 2)
+	b.subs = append(b.subs, ch)
+	return ch, true
+}
+
+// broadcast forwards an interrupt to all of the subscribers, or closes all
+// of their channels if the shutdown channel itself was closed.
+func (b *shutdownBroadcast) broadcast(closed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.signals++
+	if b.signals == 1 {
+		close(b.interrupted)
+	}
+	for _, ch := range b.subs {
+		if closed {
+			close(ch)
+			continue
+		}
+		select {
+		case ch <- struct{}{}:
+		default:
+			// The subscriber has already received as many interrupts as
+			// it can handle, or has finished.
+		}
+	}
+}
+
+// close stops forwarding interrupts.
+func (b *shutdownBroadcast) close() {
+	close(b.done)
+}
@@ -45,6 +45,20 @@ func (c *OutputCommand) Run(rawArgs []string) int {
 	// Inject variables from args into meta for static evaluation
 	c.Meta.variableArgs = args.Vars.All()
 
+	if args.WorkspacePattern.Enabled() {
+		view.Diagnostics(diags)
+		return c.runInWorkspaces(ctx, args.WorkspacePattern, args.ViewOptions.ViewType, func(ctx context.Context, meta *Meta) int {
+			cmd := &OutputCommand{Meta: *meta}
+			return cmd.output(ctx, args, views.NewOutput(args.ViewOptions, cmd.View), nil)
+		})
+	}
+
+	return c.output(ctx, args, view, diags)
+}
+
+// output renders the outputs of the currently-selected workspace, appending
+// to the given diagnostics any further diagnostics generated along the way.
+func (c *OutputCommand) output(ctx context.Context, args *arguments.Output, view views.Output, diags tfdiags.Diagnostics) int {
 	// Load the encryption configuration
 	enc, encDiags := c.Encryption(ctx)
 	diags = diags.Append(encDiags)
@@ -155,6 +169,16 @@ Options:
                        to the default files terraform.tfvars and *.auto.tfvars.
                        Use this option more than once to include more than one
                        variables file.
+
+  -workspace-pattern=pattern
+                       Show the outputs of each of the workspaces whose names
+                       match the given glob pattern, such as "prod-*", instead
+                       of only those of the selected workspace. Cannot be used
+                       with -raw or -state.
+
+  -workspace-parallelism=n
+                       Limit the number of workspaces read concurrently with
+                       -workspace-pattern. Defaults to 4.
 `
 	return strings.TrimSpace(helpText)
 }
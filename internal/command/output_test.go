@@ -11,6 +11,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/workdir"
 	"github.com/zclconf/go-cty/cty"
@@ -409,3 +410,82 @@ func stateWithSensitiveValueForOutput() *states.State {
 	})
 	return state
 }
+
+func TestOutput_workspacePattern(t *testing.T) {
+	testCwdTemp(t)
+
+	outputState := func(value string) *states.State {
+		return states.BuildState(func(s *states.SyncState) {
+			s.SetOutputValue(
+				addrs.OutputValue{Name: "foo"}.Absolute(addrs.RootModuleInstance),
+				cty.StringVal(value),
+				false,
+				"",
+			)
+		})
+	}
+	testStateFileDefault(t, outputState("default value"))
+	testStateFileWorkspaceDefault(t, "dev", outputState("dev value"))
+	testStateFileWorkspaceDefault(t, "prod", outputState("prod value"))
+
+	view, done := testView(t)
+	c := &OutputCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-no-color",
+		"-workspace-pattern", "d*",
+		"-workspace-parallelism", "1",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: \n%s", output.Stderr())
+	}
+
+	want := `Workspace "default":
+foo = "default value"
+
+Workspace "dev":
+foo = "dev value"
+
+Completed in 2 workspace(s) matching "d*":
+  default  succeeded
+  dev      succeeded
+`
+	if diff := cmp.Diff(want, output.Stdout()); diff != "" {
+		t.Errorf("wrong output\n%s", diff)
+	}
+
+	// The selected workspace must not change
+	if got, _ := c.WorkspaceOverridden(t.Context()); got != "default" {
+		t.Errorf("wrong selected workspace %q; want \"default\"", got)
+	}
+}
+
+func TestOutput_workspacePatternNoMatch(t *testing.T) {
+	testCwdTemp(t)
+
+	view, done := testView(t)
+	c := &OutputCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-workspace-pattern", "prod-*"})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit status %d; want 1\nstdout: %s", code, output.Stdout())
+	}
+	if got, want := output.Stderr(), "No matching workspaces"; !strings.Contains(got, want) {
+		t.Errorf("wrong error output\ngot:  %s\nwant: %s", got, want)
+	}
+}
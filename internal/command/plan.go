@@ -73,6 +73,24 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 	// Inject variables from args into meta for static evaluation
 	c.Meta.variableArgs = args.Vars.All()
 
+	if args.WorkspacePattern.Enabled() {
+		view.Diagnostics(diags)
+
+		// We can't prompt for input in each of several concurrent plans.
+		args.ViewOptions.InputEnabled = false
+		c.Meta.input = false
+		return c.runInWorkspaces(ctx, args.WorkspacePattern, args.ViewOptions.ViewType, func(ctx context.Context, meta *Meta) int {
+			cmd := &PlanCommand{Meta: *meta}
+			return cmd.plan(ctx, args, views.NewPlan(args.ViewOptions, cmd.View), nil)
+		})
+	}
+
+	return c.plan(ctx, args, view, diags)
+}
+
+// plan creates a plan for the currently-selected workspace, appending to the
+// given diagnostics any further diagnostics generated along the way.
+func (c *PlanCommand) plan(ctx context.Context, args *arguments.Plan, view views.Plan, diags tfdiags.Diagnostics) int {
 	// Load the encryption configuration
 	enc, encDiags := c.Encryption(ctx)
 	diags = diags.Append(encDiags)
@@ -300,6 +318,16 @@ Other Options:
   -show-sensitive              If specified, sensitive values will not be
                                redacted in te UI output.
 
+  -workspace-pattern=pattern   Create a plan for each of the workspaces whose
+                               names match the given glob pattern, such as
+                               "prod-*", instead of only for the selected
+                               workspace, and show a summary of the results.
+                               Cannot be used with -out or -state.
+
+  -workspace-parallelism=n     Limit the number of workspaces planned
+                               concurrently with -workspace-pattern.
+                               Defaults to 4.
+
   -json                        Produce output in a machine-readable JSON
                                format, suitable for use in text editor
                                integrations and other automated systems.
@@ -2260,3 +2260,46 @@ foo = "bar"
 variable "nope" {
 }
 `
+
+func TestPlan_workspacePattern(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	t.Chdir(td)
+
+	testStateFileWorkspaceDefault(t, "dev", states.NewState())
+	testStateFileWorkspaceDefault(t, "prod", states.NewState())
+
+	p := planFixtureProvider()
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-no-color", "-detailed-exitcode", "-workspace-pattern=*"})
+	output := done(t)
+	if code != 2 {
+		t.Fatalf("wrong exit status %d; want 2\nstderr: %s", code, output.Stderr())
+	}
+
+	got := output.Stdout()
+	for _, workspace := range []string{"default", "dev", "prod"} {
+		if want := fmt.Sprintf("Workspace %q:\n", workspace); !strings.Contains(got, want) {
+			t.Errorf("output is missing %q\ngot:\n%s", want, got)
+		}
+	}
+	if got, want := strings.Count(got, "Plan: 1 to add, 0 to change, 0 to destroy."), 3; got != want {
+		t.Errorf("wrong number of plans %d; want %d", got, want)
+	}
+	wantSummary := `Completed in 3 workspace(s) matching "*":
+  default  succeeded with changes
+  dev      succeeded with changes
+  prod     succeeded with changes
+`
+	if !strings.HasSuffix(got, wantSummary) {
+		t.Errorf("wrong summary\ngot:\n%s\nwant suffix:\n%s", got, wantSummary)
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statefile"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// StateDiffCommand is a Command implementation that compares two states.
+type StateDiffCommand struct {
+	Meta
+	StateMeta
+}
+
+func (c *StateDiffCommand) Run(rawArgs []string) int {
+	ctx := c.CommandContext()
+
+	common, rawArgs := arguments.ParseView(rawArgs)
+	c.View.Configure(common)
+	// Because the legacy UI was using println to show diagnostics and the new view is using, by default, print,
+	// in order to keep functional parity, we setup the view to add a new line after each diagnostic.
+	c.View.DiagsWithNewline()
+
+	// Parse and validate flags
+	args, closer, diags := arguments.ParseStateDiff(rawArgs)
+	defer closer()
+
+	// Instantiate the view, even if there are flag errors, so that we render
+	// diagnostics according to the desired view
+	view := views.NewState(args.ViewOptions, c.View)
+	if diags.HasErrors() {
+		view.Diagnostics(diags)
+		if args.ViewOptions.ViewType == arguments.ViewJSON {
+			return 1 // in case it's json, do not print the help of the command
+		}
+		return cli.RunResultHelp
+	}
+	c.View.SetShowSensitive(args.ShowSensitive)
+	c.Meta.variableArgs = args.Vars.All()
+
+	if diags := c.Meta.checkRequiredVersion(ctx); diags != nil {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// Load the encryption configuration
+	enc, encDiags := c.Encryption(ctx)
+	if encDiags.HasErrors() {
+		view.Diagnostics(encDiags)
+		return 1
+	}
+
+	loader := &stateDiffLoader{meta: &c.Meta, enc: enc}
+	from, err := loader.load(ctx, args.From)
+	if err != nil {
+		view.Diagnostics(diags.Append(stateDiffLoadError(args.From, err)))
+		return 1
+	}
+	to, err := loader.load(ctx, args.To)
+	if err != nil {
+		view.Diagnostics(diags.Append(stateDiffLoadError(args.To, err)))
+		return 1
+	}
+
+	// We need the schemas of the providers used in either state. A resource
+	// can use a different provider in each of them, so we load the schemas
+	// for each state separately and combine them. The configuration is left
+	// out, since only the providers of the states are needed.
+	//
+	// Resource instances whose provider schema is unavailable are compared
+	// without it, so failing to load the schemas is only a warning.
+	schemas := &tofu.Schemas{
+		Providers: make(map[addrs.Provider]providers.ProviderSchema),
+	}
+	var schemaErrs []string
+	for _, state := range []*states.State{from, to} {
+		stateSchemas, schemaDiags := c.MaybeGetSchemas(ctx, state, configs.NewEmptyConfig())
+		if schemaDiags.HasErrors() {
+			if err := schemaDiags.Err().Error(); !slices.Contains(schemaErrs, err) {
+				schemaErrs = append(schemaErrs, err)
+			}
+			continue
+		}
+		diags = diags.Append(schemaDiags)
+		if stateSchemas == nil {
+			continue
+		}
+		for provider, schema := range stateSchemas.Providers {
+			schemas.Providers[provider] = schema
+		}
+	}
+	if len(schemaErrs) > 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Failed to load provider schemas",
+			fmt.Sprintf("The resource instances whose provider schema is unavailable are compared using only the attributes stored in the states, so attributes that the provider marks as sensitive may be shown.\n\n%s", strings.Join(schemaErrs, "\n\n")),
+		))
+	}
+
+	if len(diags) > 0 {
+		view.Diagnostics(diags)
+	}
+	return view.StateDiff(from, to, schemas)
+}
+
+func (c *StateDiffCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] state diff [options] FROM TO
+
+  Compare two states and show the differences between their resource
+  instances and root module output values.
+
+  FROM and TO can each be one of the following:
+
+    PATH                 A local state file, such as one saved using
+                         "tofu state pull".
+
+    workspace:NAME       The latest state of the given workspace in the
+                         configured backend.
+
+    history:VERSION-ID   An earlier snapshot of the state of the current
+                         workspace, as listed by "tofu state history".
+
+  Differences are shown as the changes that would turn the FROM state into
+  the TO state. Rendering the differences uses the schemas of the providers
+  used in both states, so this command should run in an initialized working
+  directory. Resource instances whose provider schema is unavailable are
+  compared using only the attributes stored in the states.
+
+Options:
+
+  -show-sensitive         If specified, sensitive values will be displayed.
+
+  -var 'foo=bar'          Set a value for one of the input variables in the root
+                          module of the configuration. Use this option more than
+                          once to set more than one variable.
+
+  -var-file=filename      Load variable values from the given file, in addition
+                          to the default files terraform.tfvars and *.auto.tfvars.
+                          Use this option more than once to include more than one
+                          variables file.
+
+  -json                   Produce output in a machine-readable JSON format,
+                          suitable for use in text editor integrations and other
+                          automated systems. Always disables color.
+
+  -json-into=out.json     Produce the same output as -json, but sent directly
+                          to the given file. This allows automation to preserve
+                          the original human-readable output streams, while
+                          capturing more detailed logs for machine analysis.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StateDiffCommand) Synopsis() string {
+	return "Compare two states"
+}
+
+// stateDiffLoader reads the states compared by "tofu state diff", loading the
+// backend only if one of the sources needs it.
+type stateDiffLoader struct {
+	meta *Meta
+	enc  encryption.Encryption
+
+	b backend.Backend
+}
+
+func (l *stateDiffLoader) load(ctx context.Context, src arguments.StateDiffSource) (*states.State, error) {
+	if src.Path != "" {
+		stateFile, err := getStateFromPath(src.Path, l.enc)
+		if err != nil {
+			return nil, err
+		}
+		return stateDiffState(stateFile), nil
+	}
+
+	b, err := l.backend(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if src.Workspace != "" {
+		stateFile, err := getStateFromBackend(ctx, b, src.Workspace)
+		if err != nil {
+			return nil, err
+		}
+		return stateDiffState(stateFile), nil
+	}
+
+	workspace, err := l.meta.Workspace(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting workspace: %w", err)
+	}
+	stateMgr, err := b.StateMgr(ctx, workspace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state manager: %w", err)
+	}
+	history, ok := statemgr.HistoryFor(stateMgr)
+	if !ok {
+		return nil, fmt.Errorf("the state storage for workspace %q does not retain earlier snapshots of the state", workspace)
+	}
+	stateFile, err := history.StateSnapshot(ctx, src.VersionID)
+	if err != nil {
+		return nil, err
+	}
+	return stateDiffState(stateFile), nil
+}
+
+func (l *stateDiffLoader) backend(ctx context.Context) (backend.Backend, error) {
+	if l.b != nil {
+		return l.b, nil
+	}
+	b, backendDiags := l.meta.Backend(ctx, nil, l.enc.State())
+	if backendDiags.HasErrors() {
+		return nil, backendDiags.Err()
+	}
+	// This is a read-only command
+	l.meta.ignoreRemoteVersionConflict(b)
+	l.b = b
+	return b, nil
+}
+
+// stateDiffState returns the state in the given file, treating a missing
+// state as an empty one.
+func stateDiffState(stateFile *statefile.File) *states.State {
+	if stateFile == nil || stateFile.State == nil {
+		return states.NewState()
+	}
+	return stateFile.State
+}
+
+func stateDiffLoadError(src arguments.StateDiffSource, err error) tfdiags.Diagnostic {
+	return tfdiags.Sourceless(
+		tfdiags.Error,
+		fmt.Sprintf("Failed to read state %q", src.String()),
+		err.Error(),
+	)
+}
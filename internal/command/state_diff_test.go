@@ -0,0 +1,228 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/command/workdir"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// stateDiffFixtureProvider returns a mock provider with the schema of the
+// resource type used in testState.
+func stateDiffFixtureProvider() *tofu.MockProvider {
+	p := testProvider()
+	p.GetProviderSchemaResponse = &providers.GetProviderSchemaResponse{
+		ResourceTypes: map[string]providers.Schema{
+			"test_instance": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"id": {Type: cty.String, Optional: true, Computed: true},
+					},
+				},
+			},
+		},
+	}
+	return p
+}
+
+func TestStateDiff(t *testing.T) {
+	td := t.TempDir()
+	fromPath := filepath.Join(td, "from.tfstate")
+	testStateHistoryFile(t, fromPath, "fake-for-testing", 1, states.NewState())
+	toPath := testStateFile(t, testState())
+
+	p := stateDiffFixtureProvider()
+	view, done := testView(t)
+	c := &StateDiffCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-no-color", fromPath, toPath})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	for _, want := range []string{
+		"# test_instance.foo was added",
+		`+ id = "bar"`,
+		"Differences: 1 added, 0 changed, 0 removed.",
+	} {
+		if got := output.Stdout(); !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q\ngot: %s", want, got)
+		}
+	}
+}
+
+func TestStateDiff_same(t *testing.T) {
+	statePath := testStateFile(t, testState())
+
+	p := stateDiffFixtureProvider()
+	view, done := testView(t)
+	c := &StateDiffCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-no-color", statePath, statePath})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+	if got, want := output.Stdout(), "No differences."; !strings.Contains(got, want) {
+		t.Fatalf("expected output to contain %q\ngot: %s", want, got)
+	}
+}
+
+func TestStateDiff_providerChanged(t *testing.T) {
+	// The first state uses another provider for the same resource type, so
+	// the schemas of the providers of both states are needed.
+	other := addrs.AbsProviderConfig{
+		Provider: addrs.NewProvider(addrs.DefaultProviderRegistryHost, "hashicorp2", "test"),
+		Module:   addrs.RootModule,
+	}
+	from := states.BuildState(func(s *states.SyncState) {
+		for name, id := range map[string]string{"foo": "old", "gone": "gone"} {
+			s.SetResourceInstanceCurrent(
+				addrs.Resource{
+					Mode: addrs.ManagedResourceMode,
+					Type: "test_instance",
+					Name: name,
+				}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+				&states.ResourceInstanceObjectSrc{
+					AttrsJSON: []byte(`{"id":"` + id + `"}`),
+					Status:    states.ObjectReady,
+				},
+				other,
+				addrs.NoKey,
+			)
+		}
+	})
+	fromPath := filepath.Join(t.TempDir(), "from.tfstate")
+	testStateHistoryFile(t, fromPath, "fake-for-testing", 1, from)
+	toPath := testStateFile(t, testState())
+
+	p := stateDiffFixtureProvider()
+	view, done := testView(t)
+	c := &StateDiffCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-no-color", fromPath, toPath})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	for _, want := range []string{
+		"# test_instance.foo has changed",
+		`~ id = "old" -> "bar"`,
+		"# test_instance.gone was removed",
+		"Differences: 0 added, 1 changed, 1 removed.",
+	} {
+		if got := output.Stdout(); !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q\ngot: %s", want, got)
+		}
+	}
+}
+
+func TestStateDiff_noSchema(t *testing.T) {
+	// The provider of the second state isn't available, so its resource
+	// instances are compared without the provider schema.
+	to := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "other_instance",
+				Name: "foo",
+			}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"id":"baz"}`),
+				Status:    states.ObjectReady,
+			},
+			addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("other"),
+				Module:   addrs.RootModule,
+			},
+			addrs.NoKey,
+		)
+	})
+	toPath := filepath.Join(t.TempDir(), "to.tfstate")
+	testStateHistoryFile(t, toPath, "fake-for-testing", 1, to)
+	fromPath := testStateFile(t, testState())
+
+	p := stateDiffFixtureProvider()
+	view, done := testView(t)
+	c := &StateDiffCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-no-color", fromPath, toPath})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	for _, want := range []string{
+		"Warning: Failed to load provider schemas",
+		"# other_instance.foo was added",
+		`+ id = "baz"`,
+		"# test_instance.foo was removed",
+		`- id = "bar"`,
+	} {
+		if got := output.Stdout(); !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q\ngot: %s", want, got)
+		}
+	}
+}
+
+func TestStateDiff_missingFile(t *testing.T) {
+	statePath := testStateFile(t, testState())
+
+	p := stateDiffFixtureProvider()
+	view, done := testView(t)
+	c := &StateDiffCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{statePath, filepath.Join(t.TempDir(), "missing.tfstate")})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stdout())
+	}
+	if got, want := output.Stderr(), "Failed to read state"; !strings.Contains(got, want) {
+		t.Fatalf("expected error output to contain %q\ngot: %s", want, got)
+	}
+}
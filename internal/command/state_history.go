@@ -0,0 +1,210 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// StateHistoryCommand is a Command implementation that lists the earlier
+// state snapshots retained by the state storage.
+type StateHistoryCommand struct {
+	Meta
+	StateMeta
+}
+
+func (c *StateHistoryCommand) Run(rawArgs []string) int {
+	ctx := c.CommandContext()
+
+	common, rawArgs := arguments.ParseView(rawArgs)
+	c.View.Configure(common)
+	// Because the legacy UI was using println to show diagnostics and the new view is using, by default, print,
+	// in order to keep functional parity, we setup the view to add a new line after each diagnostic.
+	c.View.DiagsWithNewline()
+
+	// Parse and validate flags
+	args, closer, diags := arguments.ParseStateHistory(rawArgs)
+	defer closer()
+
+	// Instantiate the view, even if there are flag errors, so that we render
+	// diagnostics according to the desired view
+	view := views.NewState(args.ViewOptions, c.View)
+	if diags.HasErrors() {
+		view.Diagnostics(diags)
+		if args.ViewOptions.ViewType == arguments.ViewJSON {
+			return 1 // in case it's json, do not print the help of the command
+		}
+		return cli.RunResultHelp
+	}
+	c.Meta.variableArgs = args.Vars.All()
+
+	if args.State.StatePath != "" {
+		c.Meta.stateArgs.StatePath = args.State.StatePath
+	}
+
+	if diags := c.Meta.checkRequiredVersion(ctx); diags != nil {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// Load the encryption configuration
+	enc, encDiags := c.Encryption(ctx)
+	if encDiags.HasErrors() {
+		view.Diagnostics(encDiags)
+		return 1
+	}
+
+	// Load the backend
+	b, backendDiags := c.Backend(ctx, nil, enc.State())
+	if backendDiags.HasErrors() {
+		view.Diagnostics(backendDiags)
+		return 1
+	}
+
+	// This is a read-only command
+	c.ignoreRemoteVersionConflict(b)
+
+	// Get the state manager for the current workspace
+	env, err := c.Workspace(ctx)
+	if err != nil {
+		view.Diagnostics(diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Error selecting workspace",
+			err.Error(),
+		)))
+		return 1
+	}
+	stateMgr, err := b.StateMgr(ctx, env)
+	if err != nil {
+		view.StateLoadingFailure(err.Error())
+		return 1
+	}
+
+	history, ok := statemgr.HistoryFor(stateMgr)
+	if !ok {
+		view.StateHistoryUnsupported()
+		return 1
+	}
+
+	versions, err := history.StateHistory(ctx)
+	if err != nil {
+		view.Diagnostics(diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to list state snapshots",
+			err.Error(),
+		)))
+		return 1
+	}
+
+	if args.VersionID != "" {
+		version, moreDiags := c.readStateVersion(ctx, history, versions, args.VersionID)
+		diags = diags.Append(moreDiags)
+		if diags.HasErrors() {
+			view.Diagnostics(diags)
+			return 1
+		}
+		versions = []*statemgr.SnapshotVersion{version}
+	}
+
+	view.StateHistory(versions)
+	return 0
+}
+
+// readStateVersion reads the snapshot with the given version ID to complete
+// its entry in the given list with the lineage and serial recorded in it.
+// The list only holds the metadata kept by the storage, so that listing the
+// history doesn't need to read and decrypt every snapshot.
+func (c *StateHistoryCommand) readStateVersion(ctx context.Context, history statemgr.History, versions []*statemgr.SnapshotVersion, versionID string) (*statemgr.SnapshotVersion, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	var version *statemgr.SnapshotVersion
+	for _, v := range versions {
+		if v.ID == versionID {
+			version = v
+			break
+		}
+	}
+	if version == nil {
+		return nil, diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"State snapshot not found",
+			fmt.Sprintf("There is no retained state snapshot with version ID %q. Run \"tofu state history\" to list the available snapshots.", versionID),
+		))
+	}
+
+	f, err := history.StateSnapshot(ctx, versionID)
+	if err != nil {
+		return nil, diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read state snapshot",
+			fmt.Sprintf("Failed to read the state snapshot with version ID %q: %s.", versionID, err),
+		))
+	}
+	version.Lineage = f.Lineage
+	version.Serial = f.Serial
+	return version, diags
+}
+
+func (c *StateHistoryCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] state history [options] [VERSION_ID]
+
+  List the earlier snapshots of the state retained by the state storage.
+
+  Each snapshot is listed with a version ID that can be passed to
+  "tofu state rollback" to restore it, along with when it was written and,
+  if the storage records it, who wrote it. Snapshots are listed from newest
+  to oldest.
+
+  The list only uses the metadata kept by the storage. To also show the
+  serial and lineage recorded in a snapshot, pass its version ID as an
+  argument. OpenTofu then reads, and if needed decrypts, only that snapshot.
+
+  Which snapshots are available depends on the storage: local state keeps
+  the backup files written alongside the state file, while remote state
+  backends list the object versions or snapshots retained by their
+  storage, if it's configured to keep them.
+
+Options:
+
+  -state=statefile    Path to a local OpenTofu state file whose history
+                      should be listed. By default, OpenTofu will consult
+                      the state of the currently-selected workspace.
+
+  -var 'foo=bar'      Set a value for one of the input variables in the root
+                      module of the configuration. Use this option more than
+                      once to set more than one variable.
+
+  -var-file=filename  Load variable values from the given file, in addition
+                      to the default files terraform.tfvars and *.auto.tfvars.
+                      Use this option more than once to include more than one
+                      variables file.
+
+  -json               Produce output in a machine-readable JSON format, 
+                      suitable for use in text editor integrations and other 
+                      automated systems. Always disables color.
+
+  -json-into=out.json Produce the same output as -json, but sent directly
+                      to the given file. This allows automation to preserve
+                      the original human-readable output streams, while
+                      capturing more detailed logs for machine analysis.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StateHistoryCommand) Synopsis() string {
+	return "List earlier snapshots of the state"
+}
@@ -0,0 +1,164 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/command/workdir"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statefile"
+)
+
+func TestStateHistory(t *testing.T) {
+	statePath := testStateFile(t, testState())
+	backupPath := statePath + ".1700000000.backup"
+	testStateHistoryFile(t, backupPath, "fake-for-testing", 1, states.NewState())
+
+	p := testProvider()
+	view, done := testView(t)
+	c := &StateHistoryCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.Stdout()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("wrong number of snapshots listed; got:\n%s", output.Stdout())
+	}
+	if !strings.HasPrefix(lines[0], filepath.Base(statePath)) || !strings.Contains(lines[0], "(latest)") {
+		t.Errorf("first line should describe the latest snapshot\ngot: %s", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], filepath.Base(backupPath)) {
+		t.Errorf("second line should describe the backup snapshot\ngot: %s", lines[1])
+	}
+	// The snapshots are not read to list them
+	if strings.Contains(output.Stdout(), "serial=") {
+		t.Errorf("the list should only show the metadata of the snapshots\ngot:\n%s", output.Stdout())
+	}
+}
+
+func TestStateHistory_versionID(t *testing.T) {
+	statePath := testStateFile(t, testState())
+	backupPath := statePath + ".1700000000.backup"
+	testStateHistoryFile(t, backupPath, "fake-for-testing", 1, states.NewState())
+
+	p := testProvider()
+	view, done := testView(t)
+	c := &StateHistoryCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		filepath.Base(backupPath),
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.Stdout()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("wrong number of snapshots listed; got:\n%s", output.Stdout())
+	}
+	if !strings.HasPrefix(lines[0], filepath.Base(backupPath)) || !strings.Contains(lines[0], "serial=1  lineage=fake-for-testing") {
+		t.Errorf("wrong description of the backup snapshot\ngot: %s", lines[0])
+	}
+}
+
+func TestStateHistory_unknownVersionID(t *testing.T) {
+	statePath := testStateFile(t, testState())
+
+	p := testProvider()
+	view, done := testView(t)
+	c := &StateHistoryCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"nonexistent.backup",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit code %d; want 1\n\n%s", code, output.Stdout())
+	}
+	if got, want := output.Stderr(), "State snapshot not found"; !strings.Contains(got, want) {
+		t.Fatalf("expected error to contain %q\ngot: %s", want, got)
+	}
+}
+
+func TestStateHistory_noState(t *testing.T) {
+	testCwdTemp(t)
+
+	p := testProvider()
+	view, done := testView(t)
+	c := &StateHistoryCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	code := c.Run(nil)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+	if got, want := output.Stdout(), "No state snapshots found."; !strings.Contains(got, want) {
+		t.Fatalf("expected output to contain %q\ngot: %s", want, got)
+	}
+}
+
+// testStateHistoryFile writes a state snapshot with the given lineage and
+// serial to the given path, for use as an earlier snapshot in tests of the
+// state history commands.
+func testStateHistoryFile(t *testing.T, path string, lineage string, serial uint64, state *states.State) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create state snapshot %s: %s", path, err)
+	}
+	defer f.Close()
+
+	sf := &statefile.File{
+		Lineage: lineage,
+		Serial:  serial,
+		State:   state,
+	}
+	if err := statefile.Write(sf, f, encryption.StateEncryptionDisabled()); err != nil {
+		t.Fatalf("failed to write state snapshot %s: %s", path, err)
+	}
+}
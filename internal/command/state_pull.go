@@ -14,7 +14,6 @@ import (
 	"github.com/mitchellh/cli"
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/views"
-	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -96,7 +95,8 @@ func (c *StatePullCommand) Run(rawArgs []string) int {
 
 	if stateFile != nil { // we produce no output if the statefile is nil
 		var buf bytes.Buffer
-		err = statefile.Write(stateFile, &buf, encryption.StateEncryptionDisabled()) // Don't encrypt to stdout
+		// The output is only encrypted if the state_pull target is configured
+		err = statefile.Write(stateFile, &buf, enc.StatePull())
 		if err != nil {
 			view.Diagnostics(diags.Append(fmt.Errorf("Failed to write state: %s", err)))
 			return 1
@@ -122,6 +122,9 @@ Usage: tofu [global options] state pull [options]
   The primary use of this is for state stored remotely. This command
   will still work with local state but is less useful for this.
 
+  If the state_pull encryption target is configured, the output is
+  encrypted with it.
+
 Options:
 
   -var 'foo=bar'     Set a value for one of the input variables in the root
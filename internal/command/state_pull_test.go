@@ -8,10 +8,13 @@ package command
 import (
 	"bytes"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/workdir"
+	"github.com/opentofu/opentofu/internal/encryption"
 )
 
 func TestStatePull(t *testing.T) {
@@ -106,3 +109,68 @@ func TestStatePull_checkRequiredVersion(t *testing.T) {
 		t.Fatalf("output should not point to met version constraint, but is:\n\n%s", errStr)
 	}
 }
+
+// statePullEncryptionConfig encrypts the output of "tofu state pull" and the
+// backend configuration in the data directory, reading an unencrypted backend
+// configuration with the fallback method.
+const statePullEncryptionConfig = `
+key_provider "pbkdf2" "main" {
+  passphrase = "passphrase-for-testing"
+}
+method "aes_gcm" "main" {
+  keys = key_provider.pbkdf2.main
+}
+method "unencrypted" "migrate" {}
+
+state_pull {
+  method = method.aes_gcm.main
+}
+backend_config {
+  method = method.aes_gcm.main
+  fallback {
+    method = method.unencrypted.migrate
+  }
+}
+`
+
+// writeStatePullEncryptionConfig writes statePullEncryptionConfig into the
+// configuration in the current working directory.
+func writeStatePullEncryptionConfig(t *testing.T) {
+	t.Helper()
+	cfg := "terraform {\n  encryption {\n" + statePullEncryptionConfig + "\n  }\n}\n"
+	if err := os.WriteFile("encryption.tf", []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStatePull_encrypted(t *testing.T) {
+	// Create a temporary working directory that is empty
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("state-pull-backend"), td)
+	t.Chdir(td)
+	writeStatePullEncryptionConfig(t)
+
+	p := testProvider()
+	view, done := testView(t)
+	c := &StatePullCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	code := c.Run(nil)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.All())
+	}
+
+	if ok, err := encryption.IsEncryptionPayload([]byte(output.Stdout())); err != nil || !ok {
+		t.Errorf("state pull output is not encrypted:\n%s", output.Stdout())
+	}
+
+	// The unencrypted backend configuration was read with the fallback
+	// method, and so is written again encrypted with the primary method.
+	assertStateEncrypted(t, filepath.Join(".terraform", arguments.DefaultStateFilename))
+}
@@ -6,6 +6,7 @@
 package command
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -88,11 +89,21 @@ func (c *StatePushCommand) Run(rawArgs []string) int {
 	}
 
 	// Read the state
-	srcStateFile, err := statefile.Read(r, encryption.StateEncryptionDisabled()) // Assume the given statefile is not encrypted
+	srcStateData, err := io.ReadAll(r)
 	if c, ok := r.(io.Closer); ok {
 		// Close the reader if possible right now since we're done with it.
 		c.Close()
 	}
+	var srcStateFile *statefile.File
+	if err == nil {
+		// The given state is usually unencrypted, unless it was written by
+		// "tofu state pull" with the state_pull encryption target configured.
+		srcEnc := encryption.StateEncryptionDisabled()
+		if isEncrypted, _ := encryption.IsEncryptionPayload(srcStateData); isEncrypted {
+			srcEnc = enc.StatePull()
+		}
+		srcStateFile, err = statefile.Read(bytes.NewReader(srcStateData), srcEnc)
+	}
 	if err != nil {
 		view.Diagnostics(diags.Append(tfdiags.Sourceless(
 			tfdiags.Error,
@@ -220,6 +231,9 @@ Usage: tofu [global options] state push [options] PATH
   Data from stdin is not streamed to the backend: it is loaded completely
   (until pipe close), verified, and then pushed.
 
+  A state that was encrypted by "tofu state pull" is decrypted with the
+  state_pull encryption target before it is pushed.
+
 Options:
 
   -force              Write the state even if lineages don't match or the
@@ -7,14 +7,18 @@ package command
 
 import (
 	"bytes"
+	"os"
 	"strings"
 	"testing"
 
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/backend/remote-state/inmem"
 	"github.com/opentofu/opentofu/internal/command/workdir"
+	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/encryption/config"
 	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statefile"
 )
 
 func TestStatePush_empty(t *testing.T) {
@@ -343,3 +347,52 @@ func TestStatePush_checkRequiredVersion(t *testing.T) {
 		t.Fatalf("output should not point to met version constraint, but is:\n\n%s", errStr)
 	}
 }
+
+func TestStatePush_encrypted(t *testing.T) {
+	// Create a temporary working directory that is empty
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("state-push-replace-match"), td)
+	t.Chdir(td)
+	writeStatePullEncryptionConfig(t)
+
+	expected := testStateRead(t, "replace.tfstate")
+
+	// Encrypt the replacement as "tofu state pull" would
+	cfg, diags := config.LoadConfigFromString("test", statePullEncryptionConfig)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	enc, diags := encryption.New(t.Context(), encryption.DefaultRegistry, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	var buf bytes.Buffer
+	if err := statefile.Write(statefile.New(expected, "", 0), &buf, enc.StatePull()); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := os.WriteFile("replace.tfstate", buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := testProvider()
+	view, done := testView(t)
+	c := &StatePushCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{"-force", "replace.tfstate"}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	actual := testStateRead(t, "local-state.tfstate")
+	if !actual.Equal(expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
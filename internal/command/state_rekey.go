@@ -0,0 +1,236 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/clistate"
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// StateRekeyCommand is a Command implementation that re-encrypts the state
+// with the primary encryption method, after it was read using the fallback
+// method.
+type StateRekeyCommand struct {
+	StateMeta
+}
+
+func (c *StateRekeyCommand) Run(rawArgs []string) int {
+	ctx := c.CommandContext()
+
+	common, rawArgs := arguments.ParseView(rawArgs)
+	c.View.Configure(common)
+	// Because the legacy UI was using println to show diagnostics and the new view is using, by default, print,
+	// in order to keep functional parity, we setup the view to add a new line after each diagnostic.
+	c.View.DiagsWithNewline()
+
+	// Parse and validate flags
+	args, closer, diags := arguments.ParseStateRekey(rawArgs)
+	defer closer()
+
+	// Instantiate the view, even if there are flag errors, so that we render
+	// diagnostics according to the desired view
+	view := views.NewState(args.ViewOptions, c.View)
+	if diags.HasErrors() {
+		view.Diagnostics(diags)
+		if args.ViewOptions.ViewType == arguments.ViewJSON {
+			return 1 // We don't want to print the help of the command in JSON view
+		}
+		return cli.RunResultHelp
+	}
+	c.Meta.variableArgs = args.Vars.All()
+	c.Meta.stateArgs = *args.State
+	c.Meta.backendArgs = *args.Backend
+
+	if diags := c.Meta.checkRequiredVersion(ctx); diags != nil {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	if args.WorkspacePattern.Enabled() {
+		return c.runInWorkspaces(ctx, args.WorkspacePattern, args.ViewOptions.ViewType, func(ctx context.Context, meta *Meta) int {
+			meta.View.DiagsWithNewline()
+			cmd := &StateRekeyCommand{StateMeta{Meta: *meta}}
+			return cmd.rekey(ctx, views.NewState(args.ViewOptions, cmd.View))
+		})
+	}
+
+	return c.rekey(ctx, view)
+}
+
+// rekey re-encrypts the state of the currently-selected workspace, if it was
+// read using the fallback encryption method.
+func (c *StateRekeyCommand) rekey(ctx context.Context, view views.State) int {
+	var diags tfdiags.Diagnostics
+
+	// Load the encryption configuration
+	enc, encDiags := c.Encryption(ctx)
+	if encDiags.HasErrors() {
+		view.Diagnostics(encDiags)
+		return 1
+	}
+
+	// Get the state
+	stateMgr, err := c.State(ctx, enc, view)
+	if err != nil {
+		view.StateLoadingFailure(err.Error())
+		return 1
+	}
+
+	reporter, ok := stateMgr.(statemgr.EncryptionStatusReporter)
+	if !ok {
+		view.Diagnostics(diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"State encryption unsupported",
+			"The configured backend does not support state encryption, so there is no state to re-encrypt.",
+		)))
+		return 1
+	}
+
+	if c.stateArgs.Lock {
+		stateLocker := clistate.NewLocker(c.stateArgs.LockTimeout, view.Backend().StateLocker())
+		if diags := stateLocker.Lock(stateMgr, "state-rekey"); diags.HasErrors() {
+			view.Diagnostics(diags)
+			return 1
+		}
+		defer func() {
+			if diags := stateLocker.Unlock(); diags.HasErrors() {
+				view.Diagnostics(diags)
+			}
+		}()
+	}
+
+	// Reading the state decrypts it with whichever of the primary and the
+	// fallback methods can decrypt it.
+	if err := stateMgr.RefreshState(ctx); err != nil {
+		view.Diagnostics(diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to refresh state",
+			err.Error(),
+		)))
+		return 1
+	}
+
+	state := stateMgr.State()
+	if state == nil {
+		view.StateRekeyEmpty()
+		return 0
+	}
+	if reporter.StateEncryptionStatus() != encryption.StatusMigration {
+		view.StateRekeyed(false)
+		return 0
+	}
+
+	b, backendDiags := c.Backend(ctx, nil, enc.State())
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// Get schemas, if possible, before writing state
+	var schemas *tofu.Schemas
+	if isCloudMode(b) {
+		var schemaDiags tfdiags.Diagnostics
+		schemas, schemaDiags = c.MaybeGetSchemas(ctx, state, nil)
+		diags = diags.Append(schemaDiags)
+	}
+
+	// Writing the state back unchanged is enough to have it encrypted with
+	// the primary method, because the state managers always persist a state
+	// that was read using the fallback method.
+	if err := stateMgr.WriteState(state); err != nil {
+		view.StateSavingError(err.Error())
+		return 1
+	}
+	if err := stateMgr.PersistState(ctx, schemas); err != nil {
+		view.StateSavingError(err.Error())
+		return 1
+	}
+
+	if len(diags) > 0 {
+		view.Diagnostics(diags)
+	}
+	view.StateRekeyed(true)
+	return 0
+}
+
+func (c *StateRekeyCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] state rekey [options]
+
+  Re-encrypt the state with the primary encryption method.
+
+  When the encryption configuration of the state is changed, for example to
+  rotate a key, OpenTofu can still read a state that was encrypted with the
+  earlier configuration if it is given as the fallback method, but only
+  encrypts the state with the new configuration the next time the state is
+  written. This command reads the state using the fallback method and
+  writes it again immediately, encrypted with the primary method, without
+  changing its content.
+
+  A state that is already encrypted with the primary method is left as it
+  is.
+
+Options:
+
+  -lock=false                 Don't hold a state lock during the operation.
+                              This is dangerous if others might concurrently
+                              run commands against the same workspace.
+
+  -lock-timeout=0s            Duration to retry a state lock.
+
+  -ignore-remote-version      A rare option used for the remote backend only.
+                              See the remote backend documentation for more
+                              information.
+
+  -var 'foo=bar'              Set a value for one of the input variables in
+                              the root module of the configuration. Use this
+                              option more than once to set more than one
+                              variable.
+
+  -var-file=filename          Load variable values from the given file, in
+                              addition to the default files terraform.tfvars
+                              and *.auto.tfvars. Use this option more than
+                              once to include more than one variables file.
+
+  -workspace-pattern=pattern  Re-encrypt the state of each of the workspaces
+                              whose names match the given glob pattern, such
+                              as "*" for all workspaces, instead of only the
+                              selected workspace, and show a summary of the
+                              results.
+
+  -workspace-parallelism=n    Limit the number of workspaces processed
+                              concurrently with -workspace-pattern.
+                              Defaults to 4.
+
+  -json                       Produce output in a machine-readable JSON
+                              format, suitable for use in text editor
+                              integrations and other automated systems.
+                              Always disables color.
+
+  -json-into=out.json         Produce the same output as -json, but sent
+                              directly to the given file. This allows
+                              automation to preserve the original
+                              human-readable output streams, while capturing
+                              more detailed logs for machine analysis.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StateRekeyCommand) Synopsis() string {
+	return "Re-encrypt the state with the primary encryption method"
+}
@@ -0,0 +1,168 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/workdir"
+	"github.com/opentofu/opentofu/internal/encryption"
+)
+
+// stateRekeyConfig returns a configuration which encrypts the state with the
+// given primary method, and reads it with the given fallback method if it
+// isn't empty.
+func stateRekeyConfig(primary, fallback string) string {
+	var fallbackBlock string
+	if fallback != "" {
+		fallbackBlock = "fallback {\n      method = " + fallback + "\n    }"
+	}
+	return `
+terraform {
+  encryption {
+    key_provider "pbkdf2" "old" {
+      passphrase = "old-passphrase-for-testing"
+    }
+    key_provider "pbkdf2" "new" {
+      passphrase = "new-passphrase-for-testing"
+    }
+    method "aes_gcm" "old" {
+      keys = key_provider.pbkdf2.old
+    }
+    method "aes_gcm" "new" {
+      keys = key_provider.pbkdf2.new
+    }
+    method "unencrypted" "migrate" {}
+
+    state {
+      method = ` + primary + `
+    ` + fallbackBlock + `
+    }
+  }
+}
+`
+}
+
+func testStateRekey(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+
+	view, done := testView(t)
+	c := &StateRekeyCommand{
+		StateMeta{
+			Meta: Meta{
+				WorkingDir:       workdir.NewDir("."),
+				testingOverrides: metaOverridesForProvider(testProvider()),
+				View:             view,
+			},
+		},
+	}
+	code := c.Run(args)
+	output := done(t)
+	return code, output.Stdout(), output.Stderr()
+}
+
+func TestStateRekey(t *testing.T) {
+	testCwdTemp(t)
+	testStateFileDefault(t, testState())
+
+	writeConfig := func(primary, fallback string) {
+		t.Helper()
+		if err := os.WriteFile("main.tf", []byte(stateRekeyConfig(primary, fallback)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// First encrypt the unencrypted state with the old key, and then rotate
+	// it to the new key.
+	for _, step := range []struct{ primary, fallback string }{
+		{"method.aes_gcm.old", "method.unencrypted.migrate"},
+		{"method.aes_gcm.new", "method.aes_gcm.old"},
+	} {
+		writeConfig(step.primary, step.fallback)
+		code, stdout, stderr := testStateRekey(t)
+		if code != 0 {
+			t.Fatalf("bad: %d\n\n%s", code, stderr)
+		}
+		if want := "Re-encrypted the state with the primary encryption method."; !strings.Contains(stdout, want) {
+			t.Fatalf("expected output to contain %q\ngot: %s", want, stdout)
+		}
+	}
+
+	// The state must now be readable with the new key alone.
+	writeConfig("method.aes_gcm.new", "")
+	code, stdout, stderr := testStateRekey(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, stderr)
+	}
+	if want := "The state is already encrypted with the primary encryption method."; !strings.Contains(stdout, want) {
+		t.Fatalf("expected output to contain %q\ngot: %s", want, stdout)
+	}
+
+	assertStateEncrypted(t, arguments.DefaultStateFilename)
+}
+
+func TestStateRekey_noFallback(t *testing.T) {
+	testCwdTemp(t)
+	testStateFileDefault(t, testState())
+	if err := os.WriteFile("main.tf", []byte(stateRekeyConfig("method.aes_gcm.new", "")), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without the fallback, the unencrypted state can't be read at all.
+	code, _, stderr := testStateRekey(t, "-no-color")
+	if code != 1 {
+		t.Fatalf("bad: %d; want 1", code)
+	}
+	if want := "unencrypted payload"; !strings.Contains(stderr, want) {
+		t.Fatalf("expected error to contain %q\ngot: %s", want, stderr)
+	}
+}
+
+func TestStateRekey_workspacePattern(t *testing.T) {
+	testCwdTemp(t)
+	if err := os.WriteFile("main.tf", []byte(stateRekeyConfig("method.aes_gcm.new", "method.unencrypted.migrate")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, workspace := range []string{"one", "two"} {
+		paths = append(paths, testStateFileWorkspaceDefault(t, workspace, testState()))
+	}
+
+	code, stdout, stderr := testStateRekey(t, "-workspace-pattern=*")
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, stderr)
+	}
+	for _, want := range []string{
+		`Workspace "one":`,
+		`Workspace "two":`,
+		"Re-encrypted the state with the primary encryption method.",
+		// The default workspace has no state yet.
+		`Workspace "default":`,
+		"There is no state to re-encrypt.",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected output to contain %q\ngot: %s", want, stdout)
+		}
+	}
+	for _, path := range paths {
+		assertStateEncrypted(t, path)
+	}
+}
+
+func assertStateEncrypted(t *testing.T, path string) {
+	t.Helper()
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := encryption.IsEncryptionPayload(raw); err != nil || !ok {
+		t.Errorf("state at %s is not encrypted:\n%s", path, raw)
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/clistate"
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// StateRollbackCommand is a Command implementation that restores one of the
+// earlier state snapshots retained by the state storage.
+type StateRollbackCommand struct {
+	StateMeta
+}
+
+func (c *StateRollbackCommand) Run(rawArgs []string) int {
+	ctx := c.CommandContext()
+
+	common, rawArgs := arguments.ParseView(rawArgs)
+	c.View.Configure(common)
+	// Because the legacy UI was using println to show diagnostics and the new view is using, by default, print,
+	// in order to keep functional parity, we setup the view to add a new line after each diagnostic.
+	c.View.DiagsWithNewline()
+
+	// Parse and validate flags
+	args, closer, diags := arguments.ParseStateRollback(rawArgs)
+	defer closer()
+
+	// Instantiate the view, even if there are flag errors, so that we render
+	// diagnostics according to the desired view
+	view := views.NewState(args.ViewOptions, c.View)
+	if diags.HasErrors() {
+		view.Diagnostics(diags)
+		if args.ViewOptions.ViewType == arguments.ViewJSON {
+			return 1 // We don't want to print the help of the command in JSON view
+		}
+		return cli.RunResultHelp
+	}
+	c.Meta.variableArgs = args.Vars.All()
+	c.Meta.stateArgs = *args.State
+	c.Meta.backendArgs = *args.Backend
+
+	if diags := c.Meta.checkRequiredVersion(ctx); diags != nil {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// Load the encryption configuration
+	enc, encDiags := c.Encryption(ctx)
+	if encDiags.HasErrors() {
+		view.Diagnostics(encDiags)
+		return 1
+	}
+
+	// Get the state
+	stateMgr, err := c.State(ctx, enc, view)
+	if err != nil {
+		view.StateLoadingFailure(err.Error())
+		return 1
+	}
+
+	history, ok := statemgr.HistoryFor(stateMgr)
+	if !ok {
+		view.StateHistoryUnsupported()
+		return 1
+	}
+
+	if c.stateArgs.Lock {
+		stateLocker := clistate.NewLocker(c.stateArgs.LockTimeout, view.Backend().StateLocker())
+		if diags := stateLocker.Lock(stateMgr, "state-rollback"); diags.HasErrors() {
+			view.Diagnostics(diags)
+			return 1
+		}
+		defer func() {
+			if diags := stateLocker.Unlock(); diags.HasErrors() {
+				view.Diagnostics(diags)
+			}
+		}()
+	}
+
+	if err := stateMgr.RefreshState(ctx); err != nil {
+		view.Diagnostics(diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to refresh state",
+			err.Error(),
+		)))
+		return 1
+	}
+
+	snapshot, err := history.StateSnapshot(ctx, args.VersionID)
+	if err != nil {
+		view.Diagnostics(diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			fmt.Sprintf("Failed to read state snapshot %q", args.VersionID),
+			fmt.Sprintf("%s\n\nUse \"tofu state history\" to list the available snapshots.", err),
+		)))
+		return 1
+	}
+
+	// Restoring a snapshot from an unrelated lineage would most likely mean
+	// that the storage has somehow been shared between different
+	// configurations, so we require the same explicit override as
+	// "tofu state push" does before we'll allow it.
+	current := statemgr.Export(stateMgr)
+	if !args.Force && current != nil && current.Lineage != "" && snapshot.Lineage != current.Lineage {
+		view.Diagnostics(diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"State snapshot has a different lineage",
+			fmt.Sprintf(
+				"The selected snapshot has lineage %q, but the current state has lineage %q, so the snapshot does not seem to be an earlier version of the current state. Use the -force option to restore it anyway.",
+				snapshot.Lineage, current.Lineage,
+			),
+		)))
+		return 1
+	}
+
+	state := snapshot.State
+	if state == nil {
+		state = states.NewState()
+	}
+
+	b, backendDiags := c.Backend(ctx, nil, enc.State())
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// Get schemas, if possible, before writing state
+	var schemas *tofu.Schemas
+	if isCloudMode(b) {
+		var schemaDiags tfdiags.Diagnostics
+		schemas, schemaDiags = c.MaybeGetSchemas(ctx, state, nil)
+		diags = diags.Append(schemaDiags)
+	}
+
+	// We write the snapshot as a new snapshot of the current state, rather
+	// than importing it as-is, so that the serial continues to increase and
+	// other OpenTofu processes will recognize the result as the newest state.
+	if err := stateMgr.WriteState(state); err != nil {
+		view.StateSavingError(err.Error())
+		return 1
+	}
+	if err := stateMgr.PersistState(ctx, schemas); err != nil {
+		view.StateSavingError(err.Error())
+		return 1
+	}
+
+	var newSerial uint64
+	if f := statemgr.Export(stateMgr); f != nil {
+		newSerial = f.Serial
+	}
+
+	if len(diags) > 0 {
+		view.Diagnostics(diags)
+	}
+	view.StateRolledBack(args.VersionID, snapshot.Serial, newSerial)
+	return 0
+}
+
+func (c *StateRollbackCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] state rollback [options] VERSION-ID
+
+  Restore an earlier snapshot of the state retained by the state storage.
+
+  VERSION-ID is the ID of one of the snapshots listed by
+  "tofu state history". The selected snapshot is written as the newest
+  snapshot of the current state, with a serial higher than any written
+  so far, so the snapshots it replaces remain in the history.
+
+  The command will refuse to restore a snapshot whose lineage differs from
+  the lineage of the current state unless you specify the "-force" flag.
+
+Options:
+
+  -force                  Restore the snapshot even if its lineage differs
+                          from the lineage of the current state.
+
+  -backup=PATH            Path where OpenTofu should write the backup
+                          state.
+
+  -lock=false             Don't hold a state lock during the operation. This is
+                          dangerous if others might concurrently run commands
+                          against the same workspace.
+
+  -lock-timeout=0s        Duration to retry a state lock.
+
+  -state=PATH             Path to the local state file to restore a snapshot
+                          of. By default, OpenTofu uses the state of the
+                          currently-selected workspace.
+
+  -ignore-remote-version  A rare option used for the remote backend only. See
+                          the remote backend documentation for more information.
+
+  -var 'foo=bar'          Set a value for one of the input variables in the root
+                          module of the configuration. Use this option more than
+                          once to set more than one variable.
+
+  -var-file=filename      Load variable values from the given file, in addition
+                          to the default files terraform.tfvars and *.auto.tfvars.
+                          Use this option more than once to include more than one
+                          variables file.
+
+  -json                   Produce output in a machine-readable JSON format, 
+                          suitable for use in text editor integrations and other 
+                          automated systems. Always disables color.
+
+  -json-into=out.json     Produce the same output as -json, but sent directly
+                          to the given file. This allows automation to preserve
+                          the original human-readable output streams, while
+                          capturing more detailed logs for machine analysis.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StateRollbackCommand) Synopsis() string {
+	return "Restore an earlier snapshot of the state"
+}
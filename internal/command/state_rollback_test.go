@@ -0,0 +1,168 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/command/workdir"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statefile"
+)
+
+func TestStateRollback(t *testing.T) {
+	statePath := testStateFile(t, testState())
+
+	old := states.NewState()
+	mark := markStateForMatching(old, "old")
+	backupPath := statePath + ".1700000000.backup"
+	testStateHistoryFile(t, backupPath, "fake-for-testing", 0, old)
+
+	p := testProvider()
+	view, done := testView(t)
+	c := &StateRollbackCommand{
+		StateMeta{
+			Meta: Meta{
+				WorkingDir:       workdir.NewDir("."),
+				testingOverrides: metaOverridesForProvider(p),
+				View:             view,
+			},
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		filepath.Base(backupPath),
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	sf := testStateRollbackRead(t, statePath)
+	assertStateHasMarker(t, sf.State, mark)
+	if got, want := sf.Lineage, "fake-for-testing"; got != want {
+		t.Errorf("wrong lineage %q; want %q", got, want)
+	}
+	if got, want := sf.Serial, uint64(1); got != want {
+		t.Errorf("wrong serial %d; want %d", got, want)
+	}
+	if got, want := output.Stdout(), `Restored state snapshot "`+filepath.Base(backupPath)+`" (serial 0) as serial 1.`; !strings.Contains(got, want) {
+		t.Errorf("expected output to contain %q\ngot: %s", want, got)
+	}
+
+	// The state we replaced must have been backed up alongside the others
+	backups, err := filepath.Glob(statePath + ".*.backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("expected a new backup next to %s; got %v", backupPath, backups)
+	}
+}
+
+func TestStateRollback_lineageMismatch(t *testing.T) {
+	statePath := testStateFile(t, testState())
+	backupPath := statePath + ".1700000000.backup"
+	testStateHistoryFile(t, backupPath, "other-lineage", 5, states.NewState())
+
+	p := testProvider()
+	view, done := testView(t)
+	c := &StateRollbackCommand{
+		StateMeta{
+			Meta: Meta{
+				WorkingDir:       workdir.NewDir("."),
+				testingOverrides: metaOverridesForProvider(p),
+				View:             view,
+			},
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		filepath.Base(backupPath),
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("expected failure; got %d\n\n%s", code, output.Stdout())
+	}
+	if got, want := output.Stderr(), "State snapshot has a different lineage"; !strings.Contains(got, want) {
+		t.Errorf("expected error to contain %q\ngot: %s", want, got)
+	}
+
+	// The state must be unchanged
+	if got := testStateRead(t, statePath); !got.Equal(testState()) {
+		t.Errorf("state should not have been modified; got:\n%s", got.String())
+	}
+
+	// Trying again with -force should succeed
+	view, done = testView(t)
+	c.View = view
+	args = []string{
+		"-state", statePath,
+		"-force",
+		filepath.Base(backupPath),
+	}
+	code = c.Run(args)
+	output = done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+	if got := testStateRead(t, statePath); !got.Empty() {
+		t.Errorf("expected the empty snapshot to be restored; got:\n%s", got.String())
+	}
+}
+
+func TestStateRollback_unknownVersion(t *testing.T) {
+	statePath := testStateFile(t, testState())
+
+	p := testProvider()
+	view, done := testView(t)
+	c := &StateRollbackCommand{
+		StateMeta{
+			Meta: Meta{
+				WorkingDir:       workdir.NewDir("."),
+				testingOverrides: metaOverridesForProvider(p),
+				View:             view,
+			},
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"../../etc/passwd",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("expected failure; got %d\n\n%s", code, output.Stdout())
+	}
+	if got, want := output.Stderr(), "Failed to read state snapshot"; !strings.Contains(got, want) {
+		t.Errorf("expected error to contain %q\ngot: %s", want, got)
+	}
+}
+
+func testStateRollbackRead(t *testing.T, path string) *statefile.File {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer f.Close()
+
+	sf, err := statefile.Read(f, encryption.StateEncryptionDisabled())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return sf
+}
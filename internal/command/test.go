@@ -8,29 +8,37 @@ package command
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"maps"
+	"os"
 	"path"
+	"path/filepath"
 	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/opentofu/opentofu/internal/lang"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/checks"
 	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/logging"
 	"github.com/opentofu/opentofu/internal/moduletest"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/plugin/cassette"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -75,6 +83,13 @@ Options:
                         will be performed. All locations, for all errors
                         will be listed. Disabled by default
 
+  -coverage             Report which resources, output values, check blocks and
+                        conditions of the module under test were exercised by
+                        the tests.
+
+  -coverage-out=path    Write the coverage of the module under test to the
+                        given file in the lcov format. Implies -coverage.
+
   -filter=testfile      If specified, OpenTofu will only execute the test files
                         specified by this flag. You can use this option multiple
                         times to execute more than one test file. The path should
@@ -89,12 +104,39 @@ Options:
                         the original human-readable output streams, while
                         capturing more detailed logs for machine analysis.
 
+  -junit-xml=path       Write a JUnit XML report of the test results to the
+                        given file, in addition to the regular output.
+
   -no-color             If specified, output won't contain any color.
 
+  -parallelism=n        Execute up to n run blocks at the same time, defaults
+                        to 1. Test files are executed concurrently, and so
+                        are the run blocks within a file that use different
+                        state files, if at least one of them only uses mock
+                        providers.
+
+  -record=path          Record the calls made to the provider plugins into the
+                        given cassette file, so that they can be replayed
+                        with -replay. The provider configurations and
+                        sensitive values are not recorded.
+
+  -replay=path          Replay the calls made to the provider plugins from the
+                        given cassette file, instead of launching the plugins
+                        recorded in it. The tests must make the same calls
+                        as when the cassette was recorded.
+
+  -tap                  If specified, the test results will be printed in the
+                        Test Anything Protocol (TAP) format instead of the
+                        human-readable format.
+
   -test-directory=path  Set the OpenTofu test directory, defaults to "tests". When set, the
                         test command will search for test files in the current directory and
                         in the one specified by the flag.
 
+  -update-snapshots     Write the plans of the run blocks that set
+                        expect_plan_snapshot to their snapshot files, instead
+                        of comparing the plans against them.
+
   -var 'foo=bar'        Set a value for one of the input variables in the root
                         module of the configuration. Use this option more than
                         once to set more than one variable.
@@ -138,7 +180,18 @@ func (c *TestCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
-	view := views.NewTest(args.ViewOptions, c.View)
+	var view views.Test
+	if args.TAP {
+		view = views.NewTestTAP(c.View)
+	} else {
+		view = views.NewTest(args.ViewOptions, c.View)
+	}
+
+	var junit *views.TestJUnitXMLFile
+	if args.JUnitXMLPath != "" {
+		junit = views.NewTestJUnitXMLFile(args.JUnitXMLPath, c.View)
+		view = views.TestMulti{view, junit}
+	}
 
 	// Users can also specify variables via the command line, so we'll parse
 	// all that here.
@@ -228,6 +281,10 @@ func (c *TestCommand) Run(rawArgs []string) int {
 
 	log.Printf("[DEBUG] TestCommand: found %d files with %d run blocks", fileCount, runCount)
 
+	if args.Coverage {
+		suite.Coverage = moduletest.NewCoverage(config)
+	}
+
 	if len(args.Filter) > 0 && len(suite.Files) == 0 {
 		diags = diags.Append(tfdiags.Sourceless(
 			tfdiags.Warning,
@@ -242,6 +299,23 @@ func (c *TestCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
+	if args.ReplayPath != "" {
+		replay, err := cassette.Load(args.ReplayPath)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to read provider cassette",
+				err.Error(),
+			))
+			view.Diagnostics(nil, nil, diags)
+			return 1
+		}
+		c.providerReplay = replay
+	}
+	if args.RecordPath != "" {
+		c.providerRecording = cassette.New()
+	}
+
 	opts, err := c.contextOpts(ctx)
 	if err != nil {
 		diags = diags.Append(err)
@@ -252,6 +326,20 @@ func (c *TestCommand) Run(rawArgs []string) int {
 	// Don't use encryption during testing
 	opts.Encryption = encryption.Disabled()
 
+	// Each run block modifies the configuration it is executed against, so
+	// every test file that is executed concurrently needs a separate copy of
+	// the main configuration.
+	configCopies := []*configs.Config{config}
+	for len(configCopies) < min(args.Parallelism, fileCount) {
+		configCopy, configDiags := c.loadConfig(ctx, ".")
+		diags = diags.Append(configDiags)
+		if configDiags.HasErrors() {
+			view.Diagnostics(nil, nil, diags)
+			return 1
+		}
+		configCopies = append(configCopies, configCopy)
+	}
+
 	// Print out all the diagnostics we have from the setup. These will just be
 	// warnings, and we want them out of the way before we start the actual
 	// testing.
@@ -272,9 +360,10 @@ func (c *TestCommand) Run(rawArgs []string) int {
 	runner := &TestSuiteRunner{
 		command: c,
 
-		Suite:  &suite,
-		Config: config,
-		View:   view,
+		Suite:   &suite,
+		Config:  config,
+		Configs: configCopies,
+		View:    view,
 
 		GlobalVariables: variables,
 		Opts:            opts,
@@ -287,7 +376,9 @@ func (c *TestCommand) Run(rawArgs []string) int {
 		Cancelled: false,
 		Stopped:   false,
 
-		Verbose: args.Verbose,
+		Verbose:         args.Verbose,
+		Parallelism:     args.Parallelism,
+		UpdateSnapshots: args.UpdateSnapshots,
 	}
 
 	view.Abstract(&suite)
@@ -334,6 +425,17 @@ func (c *TestCommand) Run(rawArgs []string) int {
 		// tests finished normally with no interrupts.
 	}
 
+	if c.providerRecording != nil {
+		if err := c.providerRecording.Save(args.RecordPath); err != nil {
+			view.Diagnostics(nil, nil, tfdiags.Diagnostics{tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to write provider cassette",
+				err.Error(),
+			)})
+			return 1
+		}
+	}
+
 	if runner.Cancelled {
 		// Don't print out the conclusion if the test was cancelled.
 		return 1
@@ -341,6 +443,26 @@ func (c *TestCommand) Run(rawArgs []string) int {
 
 	view.Conclusion(&suite)
 
+	if junit != nil && junit.Err() != nil {
+		view.Diagnostics(nil, nil, tfdiags.Diagnostics{tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to write JUnit XML report",
+			junit.Err().Error(),
+		)})
+		return 1
+	}
+
+	if args.CoveragePath != "" {
+		if err := os.WriteFile(args.CoveragePath, suite.Coverage.LCOV(), 0644); err != nil {
+			view.Diagnostics(nil, nil, tfdiags.Diagnostics{tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to write coverage report",
+				err.Error(),
+			)})
+			return 1
+		}
+	}
+
 	if suite.Status != moduletest.Pass {
 		return 1
 	}
@@ -355,6 +477,12 @@ type TestSuiteRunner struct {
 	Suite  *moduletest.Suite
 	Config *configs.Config
 
+	// Configs holds the copies of Config that the test files executed
+	// concurrently use, one for each test file that can be executed at the
+	// same time. If empty, Config is used and the test files are executed
+	// one at a time.
+	Configs []*configs.Config
+
 	GlobalVariables map[string]backend.UnparsedVariableValue
 	Opts            *tofu.ContextOpts
 
@@ -378,6 +506,18 @@ type TestSuiteRunner struct {
 
 	// Verbose tells the runner to print out plan files during each test run.
 	Verbose bool
+
+	// Parallelism is the number of run blocks, across all the test files,
+	// that the runner executes at the same time.
+	Parallelism int
+
+	// UpdateSnapshots tells the runner to write the plans of the run blocks
+	// to their plan snapshots, instead of comparing the plans against them.
+	UpdateSnapshots bool
+
+	// lock guards the status of the suite and the view, which are shared by
+	// the test files executed concurrently.
+	lock sync.Mutex
 }
 
 func (runner *TestSuiteRunner) Start(ctx context.Context) {
@@ -387,136 +527,393 @@ func (runner *TestSuiteRunner) Start(ctx context.Context) {
 	}
 	sort.Strings(files) // execute the files in alphabetical order
 
+	runner.Suite.SetParallelism(runner.Parallelism)
+
+	// Test files take a copy of the configuration while they execute, so
+	// there are never more test files executing than there are copies.
+	available := make(chan *configs.Config, max(len(runner.Configs), 1))
+	for _, config := range runner.Configs {
+		available <- config
+	}
+	if len(runner.Configs) == 0 {
+		available <- runner.Config
+	}
+
 	runner.Suite.Status = moduletest.Pass
+
+	// The output of each test file is printed once the output of the test
+	// files before it has been, so it is in the same order however many test
+	// files are executed concurrently.
+	printed := make(chan struct{})
+	close(printed)
+
+	var wg sync.WaitGroup
 	for _, name := range files {
+		config := <-available
 		if runner.Cancelled {
-			return
+			break
 		}
 
 		file := runner.Suite.Files[name]
 
 		fileRunner := &TestFileRunner{
-			Suite: runner,
+			Suite:  runner,
+			Config: config,
 			States: map[string]*TestFileState{
 				MainStateIdentifier: {
 					Run:   nil,
 					State: states.NewState(),
 				},
 			},
+			previous: printed,
+			printed:  make(chan struct{}),
 		}
+		printed = fileRunner.printed
+
+		wg.Add(1)
+		panicHandler := logging.PanicHandlerWithTraceFn()
+		go func() {
+			defer panicHandler()
+			defer wg.Done()
+			defer fileRunner.flush()
+			defer func() { available <- config }()
 
-		fileRunner.ExecuteTestFile(ctx, file)
-		fileRunner.Cleanup(ctx, file)
-		runner.Suite.Status = runner.Suite.Status.Merge(file.Status)
+			fileRunner.ExecuteTestFile(ctx, file)
+			fileRunner.Cleanup(ctx, file)
+
+			runner.lock.Lock()
+			defer runner.lock.Unlock()
+			runner.Suite.Status = runner.Suite.Status.Merge(file.Status)
+		}()
 	}
+	wg.Wait()
 }
 
 type TestFileRunner struct {
 	Suite *TestSuiteRunner
 
+	// Config is the copy of the main configuration that the run blocks in
+	// the file are executed against.
+	Config *configs.Config
+
 	States map[string]*TestFileState
+
+	// lock guards the states and the status of the file, which are shared by
+	// the run blocks executed concurrently.
+	lock sync.Mutex
+
+	// aborted is set when a run block fails in a way that means none of the
+	// remaining run blocks can be executed.
+	aborted bool
+
+	// previous is closed once the output of the earlier test files has been
+	// printed, and printed once the output of this file has been as well.
+	// Until then, the output of this file is kept in output.
+	previous chan struct{}
+	printed  chan struct{}
+	output   []func(view views.Test)
 }
 
 type TestFileState struct {
 	Run   *moduletest.Run
 	State *states.State
+
+	// Seeded holds the addresses of the resource instances loaded by the
+	// setup block of the file, rather than created by a run block. These are
+	// never destroyed when the state is cleaned up.
+	Seeded addrs.Set[addrs.AbsResourceInstance]
 }
 
 func (runner *TestFileRunner) ExecuteTestFile(ctx context.Context, file *moduletest.File) {
 	log.Printf("[TRACE] TestFileRunner: executing test file %s", file.Name)
 
+	start := time.Now()
 	file.Status = file.Status.Merge(moduletest.Pass)
-	for _, run := range file.Runs {
-		if runner.Suite.Cancelled {
-			// This means a hard stop has been requested, in this case we don't
-			// even stop to mark future tests as having been skipped. They'll
-			// just show up as pending in the printed summary.
-			return
+
+	runner.seedStates(file)
+
+	runner.Suite.Suite.ExecuteRuns(file, runner.Config, func() bool {
+		runner.lock.Lock()
+		defer runner.lock.Unlock()
+
+		// This means a hard stop has been requested, in this case we don't
+		// even stop to mark future tests as having been skipped. They'll
+		// just show up as pending in the printed summary.
+		return runner.Suite.Cancelled || runner.aborted
+	}, func(run *moduletest.Run) {
+		runner.executeRun(ctx, run, file)
+	})
+
+	if runner.Suite.Cancelled || runner.aborted {
+		return
+	}
+	file.Duration = time.Since(start)
+
+	runner.print(func(view views.Test) {
+		view.File(file)
+		for _, run := range file.Runs {
+			view.Run(run, file)
 		}
+	})
+}
+
+// seedStates loads the starting states set by the setup block of the file.
+// If any of them can't be loaded, the file errors and none of its run blocks
+// are executed.
+func (runner *TestFileRunner) seedStates(file *moduletest.File) {
+	if file.Config.Setup == nil {
+		return
+	}
 
-		if runner.Suite.Stopped {
-			// Then the test was requested to be stopped, so we just mark each
-			// following test as skipped and move on.
-			run.Status = moduletest.Skip
+	for _, setup := range file.Config.Setup.States {
+		key := MainStateIdentifier
+		if setup.Module != nil {
+			key = setup.Module.String()
+		}
+
+		state, diags := loadTestStartingState(setup)
+		file.Diagnostics = file.Diagnostics.Append(diags)
+		if diags.HasErrors() {
+			file.Status = moduletest.Error
 			continue
 		}
 
-		if file.Status == moduletest.Error {
-			// If the overall test file has errored, we don't keep trying to
-			// execute tests. Instead, we mark all remaining run blocks as
-			// skipped.
-			run.Status = moduletest.Skip
+		seeded := addrs.MakeSet[addrs.AbsResourceInstance]()
+		for _, obj := range state.AllResourceInstanceObjectAddrs() {
+			seeded.Add(obj.Instance)
+		}
+
+		runner.States[key] = &TestFileState{
+			Run:    nil,
+			State:  state,
+			Seeded: seeded,
+		}
+	}
+}
+
+// moveSeeded updates the addresses of the seeded resource instances that
+// were moved to a new address by the given plan, so they are still excluded
+// from the cleanup.
+func (runner *TestFileRunner) moveSeeded(run *moduletest.Run, plan *plans.Plan) {
+	key := MainStateIdentifier
+	if run.Config.ConfigUnderTest != nil {
+		key = run.Config.Module.Source.String()
+	}
+
+	runner.lock.Lock()
+	defer runner.lock.Unlock()
+
+	state, exists := runner.States[key]
+	if !exists || len(state.Seeded) == 0 {
+		return
+	}
+
+	var moved []*plans.ResourceInstanceChangeSrc
+	for _, change := range plan.Changes.Resources {
+		if change.DeposedKey != states.NotDeposed || change.PrevRunAddr.Equal(change.Addr) {
 			continue
 		}
+		if state.Seeded.Has(change.PrevRunAddr) {
+			moved = append(moved, change)
+		}
+	}
 
-		key := MainStateIdentifier
-		config := runner.Suite.Config
-		if run.Config.ConfigUnderTest != nil {
-			config = run.Config.ConfigUnderTest
-			// Then we need to load an alternate state and not the main one.
+	// Objects can swap addresses, so we remove all the old addresses before
+	// adding any of the new ones.
+	for _, change := range moved {
+		state.Seeded.Remove(change.PrevRunAddr)
+	}
+	for _, change := range moved {
+		state.Seeded.Add(change.Addr)
+	}
+}
 
-			key = run.Config.Module.Source.String()
-			if key == MainStateIdentifier {
-				// This is bad. It means somehow the module we're loading has
-				// the same key as main state and we're about to corrupt things.
-
-				run.Diagnostics = run.Diagnostics.Append(&hcl.Diagnostic{
-					Severity: hcl.DiagError,
-					Summary:  "Invalid module source",
-					Detail:   fmt.Sprintf("The source for the selected module evaluated to %s which should not be possible. This is a bug in OpenTofu - please report it!", key),
-					Subject:  run.Config.Module.DeclRange.Ptr(),
-				})
-
-				run.Status = moduletest.Error
-				file.Status = moduletest.Error
-				continue // Abort!
-			}
+// loadTestStartingState reads the state file of the given setup state.
+func loadTestStartingState(setup *configs.TestSetupState) (*states.State, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
 
-			if _, exists := runner.States[key]; !exists {
-				runner.States[key] = &TestFileState{
-					Run:   nil,
-					State: states.NewState(),
-				}
-			}
+	f, err := os.Open(setup.File)
+	if err == nil {
+		defer f.Close()
+
+		var sf *statefile.File
+		sf, err = statefile.Read(f, encryption.StateEncryptionDisabled())
+		if err == nil {
+			return sf.State, diags
 		}
+	}
 
-		state, updatedState := runner.ExecuteTestRun(ctx, run, file, runner.States[key].State, config)
-		if updatedState {
-			var err error
-
-			// We need to simulate state serialization between multiple runs
-			// due to its side effects. One of such side effects is removal
-			// of destroyed non-root module outputs. This is not handled
-			// during graph walk since those values are not stored in the
-			// state file. This is more of a weird workaround instead of a
-			// proper fix, unfortunately.
-			state, err = simulateStateSerialization(state)
-			if err != nil {
-				run.Diagnostics = run.Diagnostics.Append(&hcl.Diagnostic{
-					Severity: hcl.DiagError,
-					Summary:  "Failure during state serialization",
-					Detail:   err.Error(),
-				})
-
-				// We cannot reuse state later so that's a hard stop.
-				return
-			}
+	diags = diags.Append(&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Failed to load starting state",
+		Detail:   fmt.Sprintf("OpenTofu could not load the state file %s: %s.", setup.File, err),
+		Subject:  setup.DeclRange.Ptr(),
+	})
+	return nil, diags
+}
 
-			// Only update the most recent run and state if the state was
-			// actually updated by this change. We want to use the run that
-			// most recently updated the tracked state as the cleanup
-			// configuration.
-			runner.States[key].State = state
-			runner.States[key].Run = run
+// executeRun executes a single run block from the file, and records the
+// updated state and status afterwards.
+func (runner *TestFileRunner) executeRun(ctx context.Context, run *moduletest.Run, file *moduletest.File) {
+	if runner.Suite.Stopped {
+		// Then the test was requested to be stopped, so we just mark each
+		// following test as skipped and move on.
+		run.Status = moduletest.Skip
+		return
+	}
+
+	runner.lock.Lock()
+	fileStatus := file.Status
+	runner.lock.Unlock()
+
+	if fileStatus == moduletest.Error {
+		// If the overall test file has errored, we don't keep trying to
+		// execute tests. Instead, we mark all remaining run blocks as
+		// skipped.
+		run.Status = moduletest.Skip
+		return
+	}
+
+	key := MainStateIdentifier
+	config := runner.Config
+	if run.Config.ConfigUnderTest != nil {
+		config = run.Config.ConfigUnderTest
+		// Then we need to load an alternate state and not the main one.
+
+		key = run.Config.Module.Source.String()
+		if key == MainStateIdentifier {
+			// This is bad. It means somehow the module we're loading has
+			// the same key as main state and we're about to corrupt things.
+
+			run.Diagnostics = run.Diagnostics.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid module source",
+				Detail:   fmt.Sprintf("The source for the selected module evaluated to %s which should not be possible. This is a bug in OpenTofu - please report it!", key),
+				Subject:  run.Config.Module.DeclRange.Ptr(),
+			})
+
+			run.Status = moduletest.Error
+
+			runner.lock.Lock()
+			defer runner.lock.Unlock()
+			file.Status = moduletest.Error
+			return // Abort!
 		}
+	}
 
-		file.Status = file.Status.Merge(run.Status)
+	runner.lock.Lock()
+	if _, exists := runner.States[key]; !exists {
+		runner.States[key] = &TestFileState{
+			Run:   nil,
+			State: states.NewState(),
+		}
+	}
+	state := runner.States[key].State
+	runner.lock.Unlock()
+
+	runStart := time.Now()
+	state, updatedState := runner.ExecuteTestRun(ctx, run, file, state, config)
+	run.Duration = time.Since(runStart)
+	if updatedState {
+		var err error
+
+		// We need to simulate state serialization between multiple runs
+		// due to its side effects. One of such side effects is removal
+		// of destroyed non-root module outputs. This is not handled
+		// during graph walk since those values are not stored in the
+		// state file. This is more of a weird workaround instead of a
+		// proper fix, unfortunately.
+		state, err = simulateStateSerialization(state)
+		if err != nil {
+			run.Diagnostics = run.Diagnostics.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Failure during state serialization",
+				Detail:   err.Error(),
+			})
+
+			// We cannot reuse state later so that's a hard stop.
+			runner.lock.Lock()
+			defer runner.lock.Unlock()
+			runner.aborted = true
+			return
+		}
 	}
 
-	runner.Suite.View.File(file)
-	for _, run := range file.Runs {
-		runner.Suite.View.Run(run, file)
+	runner.lock.Lock()
+	defer runner.lock.Unlock()
+
+	if updatedState {
+		// Only update the most recent run and state if the state was
+		// actually updated by this change. We want to use the run that
+		// most recently updated the tracked state as the cleanup
+		// configuration.
+		runner.States[key].State = state
+		runner.States[key].Run = run
+	}
+
+	file.Status = file.Status.Merge(run.Status)
+}
+
+// print prints output for the file using the given function, as soon as the
+// output of the earlier test files has been printed.
+func (runner *TestFileRunner) print(output func(view views.Test)) {
+	runner.lock.Lock()
+	defer runner.lock.Unlock()
+
+	select {
+	case <-runner.previous:
+		if len(runner.output) == 0 {
+			runner.Suite.lock.Lock()
+			defer runner.Suite.lock.Unlock()
+			output(runner.Suite.View)
+			return
+		}
+	default:
+	}
+	runner.output = append(runner.output, output)
+}
+
+// flush waits for the output of the earlier test files to be printed, and
+// then prints the output of the file that is still waiting.
+func (runner *TestFileRunner) flush() {
+	<-runner.previous
+
+	runner.lock.Lock()
+	defer runner.lock.Unlock()
+
+	runner.Suite.lock.Lock()
+	defer runner.Suite.lock.Unlock()
+	for _, output := range runner.output {
+		output(runner.Suite.View)
+	}
+	runner.output = nil
+	close(runner.printed)
+}
+
+// states returns a copy of the states tracked for the file, which is safe to
+// read while other run blocks are executing.
+func (runner *TestFileRunner) states() map[string]*TestFileState {
+	runner.lock.Lock()
+	defer runner.lock.Unlock()
+
+	states := make(map[string]*TestFileState, len(runner.States))
+	for key, state := range runner.States {
+		states[key] = &TestFileState{
+			Run:   state.Run,
+			State: state.State,
+		}
+	}
+	return states
+}
+
+// coverage returns the coverage that the given run block contributes to, or
+// nil if coverage wasn't requested or the run block doesn't execute against
+// the main configuration.
+func (runner *TestFileRunner) coverage(run *moduletest.Run) *moduletest.Coverage {
+	if run.Config.ConfigUnderTest != nil {
+		return nil
 	}
+	return runner.Suite.Suite.Coverage
 }
 
 func (runner *TestFileRunner) ExecuteTestRun(ctx context.Context, run *moduletest.Run, file *moduletest.File, state *states.State, config *configs.Config) (*states.State, bool) {
@@ -547,7 +944,7 @@ func (runner *TestFileRunner) ExecuteTestRun(ctx context.Context, run *moduletes
 		return state, false
 	}
 
-	evalCtx, evalDiags := buildEvalContextForProviderConfigTransform(runner.States, run, file, config, runner.Suite.GlobalVariables)
+	evalCtx, evalDiags := buildEvalContextForProviderConfigTransform(runner.states(), run, file, config, runner.Suite.GlobalVariables)
 	run.Diagnostics = run.Diagnostics.Append(evalDiags)
 	if evalDiags.HasErrors() {
 		run.Status = moduletest.Error
@@ -570,7 +967,10 @@ func (runner *TestFileRunner) ExecuteTestRun(ctx context.Context, run *moduletes
 		return state, false
 	}
 
-	planCtx, plan, planDiags := runner.plan(ctx, config, state, run, file)
+	planCtx, plan, planChecks, planDiags := runner.plan(ctx, config, state, run, file)
+	if coverage := runner.coverage(run); coverage != nil {
+		coverage.RecordPlan(plan, planChecks)
+	}
 	if run.Config.Command == configs.PlanTestCommand {
 		expectedFailures, sourceRanges := run.BuildExpectedFailuresAndSourceMaps()
 		// Then we want to assess our conditions and diagnostics differently.
@@ -616,6 +1016,32 @@ func (runner *TestFileRunner) ExecuteTestRun(ctx context.Context, run *moduletes
 		}
 
 		planCtx.TestContext(config, plan.PlannedState, plan, variables).EvaluateAgainstPlan(run)
+		runner.checkPlanSnapshot(ctx, planCtx, config, plan, run)
+		return state, false
+	}
+
+	if run.Config.Command == configs.DestroyTestCommand && planDiags.HasErrors() && plan != nil {
+		// A destroy plan fails when it reaches a resource protected by
+		// prevent_destroy, which the test can expect. In that case nothing is
+		// destroyed, so we make the assertions against the unchanged state.
+		expectedFailures, sourceRanges := run.BuildExpectedFailuresAndSourceMaps()
+		planDiags = run.ValidateExpectedFailures(expectedFailures, sourceRanges, planDiags)
+		run.Diagnostics = run.Diagnostics.Append(planDiags)
+		if planDiags.HasErrors() {
+			run.Status = moduletest.Error
+			return state, false
+		}
+
+		variables, resetVariables, variableDiags := runner.prepareInputVariablesForAssertions(config, run, file, runner.Suite.GlobalVariables)
+		defer resetVariables()
+
+		run.Diagnostics = run.Diagnostics.Append(variableDiags)
+		if variableDiags.HasErrors() {
+			run.Status = moduletest.Error
+			return state, false
+		}
+
+		planCtx.TestContext(config, state, plan, variables).EvaluateAgainstState(run)
 		return state, false
 	}
 
@@ -645,7 +1071,13 @@ func (runner *TestFileRunner) ExecuteTestRun(ctx context.Context, run *moduletes
 	}
 	run.Diagnostics = filteredDiags
 
-	applyCtx, updated, applyDiags := runner.apply(ctx, plan, state, config, run, file)
+	runner.checkPlanSnapshot(ctx, planCtx, config, plan, run)
+
+	applyCtx, updated, applyChecks, applyDiags := runner.apply(ctx, plan, state, config, run, file)
+	runner.moveSeeded(run, plan)
+	if coverage := runner.coverage(run); coverage != nil {
+		coverage.RecordApply(updated, applyChecks)
+	}
 
 	// Remove expected diagnostics, and add diagnostics in case anything that should have failed didn't.
 	applyDiags = run.ValidateExpectedFailures(expectedFailures, sourceRanges, applyDiags)
@@ -696,6 +1128,126 @@ func (runner *TestFileRunner) ExecuteTestRun(ctx context.Context, run *moduletes
 	return updated, true
 }
 
+// checkPlanSnapshot compares the JSON representation of the plan for the
+// given run block against the snapshot in its expect_plan_snapshot file, or
+// writes the plan to the file if the runner is updating the snapshots.
+func (runner *TestFileRunner) checkPlanSnapshot(ctx context.Context, tfCtx *tofu.Context, config *configs.Config, plan *plans.Plan, run *moduletest.Run) {
+	filename := run.Config.ExpectPlanSnapshot
+	if filename == "" {
+		return
+	}
+	subject := run.Config.ExpectPlanSnapshotDeclRange.Ptr()
+
+	got, diags := renderPlanSnapshot(ctx, tfCtx, config, plan)
+	if diags.HasErrors() {
+		run.Diagnostics = run.Diagnostics.Append(diags)
+		run.Status = run.Status.Merge(moduletest.Error)
+		return
+	}
+
+	if runner.Suite.UpdateSnapshots {
+		err := os.MkdirAll(filepath.Dir(filename), 0755)
+		if err == nil {
+			err = os.WriteFile(filename, got, 0644)
+		}
+		if err != nil {
+			run.Diagnostics = run.Diagnostics.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Failed to write plan snapshot",
+				Detail:   fmt.Sprintf("OpenTofu could not write the plan snapshot to %s: %s.", filename, err),
+				Subject:  subject,
+			})
+			run.Status = run.Status.Merge(moduletest.Error)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(filename)
+	if err != nil {
+		detail := fmt.Sprintf("OpenTofu could not read the plan snapshot from %s: %s.", filename, err)
+		if os.IsNotExist(err) {
+			detail = fmt.Sprintf("The plan snapshot %s does not exist. Run tofu test with the -update-snapshots option to create it.", filename)
+		}
+		run.Diagnostics = run.Diagnostics.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Failed to read plan snapshot",
+			Detail:   detail,
+			Subject:  subject,
+		})
+		run.Status = run.Status.Merge(moduletest.Error)
+		return
+	}
+
+	if bytes.Equal(want, got) {
+		return
+	}
+
+	diff := cmp.Diff(string(want), string(got))
+
+	// We indent the diff so that the diagnostic renderer doesn't wrap it.
+	var lines []string
+	for line := range strings.SplitSeq(strings.TrimSuffix(diff, "\n"), "\n") {
+		lines = append(lines, "  "+line)
+	}
+	run.Diagnostics = run.Diagnostics.Append(&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Plan does not match snapshot",
+		Detail:   fmt.Sprintf("The plan for this run block differs from the plan snapshot in %s (-snapshot +plan):\n\n%s\n\nIf the changes are expected, run tofu test with the -update-snapshots option to update the snapshot.", filename, strings.Join(lines, "\n")),
+		Subject:  subject,
+	})
+	run.Status = run.Status.Merge(moduletest.Fail)
+}
+
+// renderPlanSnapshot returns the JSON representation of the plan that is
+// compared against the plan snapshots.
+func renderPlanSnapshot(ctx context.Context, tfCtx *tofu.Context, config *configs.Config, plan *plans.Plan) ([]byte, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	schemas, schemaDiags := tfCtx.Schemas(ctx, config, plan.PlannedState)
+	diags = diags.Append(schemaDiags)
+	if schemaDiags.HasErrors() {
+		return nil, diags
+	}
+
+	output, err := jsonplan.MarshalForLog(config, plan, nil, schemas)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to render plan snapshot",
+			fmt.Sprintf("OpenTofu could not render the plan as JSON: %s.", err)))
+		return nil, diags
+	}
+
+	// The version, the timestamp and the configuration change independently
+	// of the planned changes, and the checks are covered by the assertions,
+	// so we leave them out to keep the snapshots stable.
+	output.TerraformVersion = ""
+	output.Timestamp = ""
+	output.Config = nil
+	output.RelevantAttributes = nil
+	output.Checks = nil
+
+	// The snapshots are usually committed, and the diagnostics for a
+	// mismatch include their contents, so they must not hold secrets.
+	if err := jsonplan.MaskSensitiveValues(output, config); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to render plan snapshot",
+			fmt.Sprintf("OpenTofu could not hide the sensitive values in the plan: %s.", err)))
+		return nil, diags
+	}
+
+	src, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to render plan snapshot",
+			fmt.Sprintf("OpenTofu could not render the plan as JSON: %s.", err)))
+		return nil, diags
+	}
+	return append(src, '\n'), diags
+}
+
 func (runner *TestFileRunner) validate(ctx context.Context, config *configs.Config, run *moduletest.Run, file *moduletest.File) tfdiags.Diagnostics {
 	log.Printf("[TRACE] TestFileRunner: called validate for %s/%s", file.Name, run.Name)
 
@@ -791,12 +1343,14 @@ func (runner *TestFileRunner) destroy(ctx context.Context, config *configs.Confi
 		return state, diags
 	}
 
-	_, updated, applyDiags := runner.apply(ctx, plan, state, config, run, file)
+	_, updated, _, applyDiags := runner.apply(ctx, plan, state, config, run, file)
 	diags = diags.Append(applyDiags)
 	return updated, diags
 }
 
-func (runner *TestFileRunner) plan(ctx context.Context, config *configs.Config, state *states.State, run *moduletest.Run, file *moduletest.File) (*tofu.Context, *plans.Plan, tfdiags.Diagnostics) {
+// plan creates the plan for the given run block, returning it along with the
+// state of the checks evaluated while planning.
+func (runner *TestFileRunner) plan(ctx context.Context, config *configs.Config, state *states.State, run *moduletest.Run, file *moduletest.File) (*tofu.Context, *plans.Plan, *checks.State, tfdiags.Diagnostics) {
 	log.Printf("[TRACE] TestFileRunner: called plan for %s/%s", file.Name, run.Name)
 
 	var diags tfdiags.Diagnostics
@@ -804,24 +1358,33 @@ func (runner *TestFileRunner) plan(ctx context.Context, config *configs.Config,
 	targets, targetDiags := run.GetTargets()
 	diags = diags.Append(targetDiags)
 
+	if run.Config.Options.Mode == configs.ImportOnlyTestMode {
+		importTargets, importDiags := getImportTargetsForTest(config, run)
+		diags = diags.Append(importDiags)
+		targets = importTargets
+	}
+
 	replaces, replaceDiags := run.GetReplaces()
 	diags = diags.Append(replaceDiags)
 
 	references, referenceDiags := run.GetReferences()
 	diags = diags.Append(referenceDiags)
 
-	evalCtx, ctxDiags := getEvalContextForTest(runner.States, config, runner.Suite.GlobalVariables)
+	evalCtx, ctxDiags := getEvalContextForTest(runner.states(), config, runner.Suite.GlobalVariables)
 	diags = diags.Append(ctxDiags)
 
 	variables, variableDiags := buildInputVariablesForTest(run, file, config, runner.Suite.GlobalVariables, evalCtx)
 	diags = diags.Append(variableDiags)
 
 	if diags.HasErrors() {
-		return nil, nil, diags
+		return nil, nil, nil, diags
 	}
 
 	planOpts := &tofu.PlanOpts{
 		Mode: func() plans.Mode {
+			if run.Config.Command == configs.DestroyTestCommand {
+				return plans.DestroyMode
+			}
 			switch run.Config.Options.Mode {
 			case configs.RefreshOnlyTestMode:
 				return plans.RefreshOnlyMode
@@ -836,10 +1399,15 @@ func (runner *TestFileRunner) plan(ctx context.Context, config *configs.Config,
 		ExternalReferences: references,
 	}
 
+	var checkState *checks.State
+	planOpts.RecordChecks = func(state *checks.State) {
+		checkState = state
+	}
+
 	tfCtx, ctxDiags := tofu.NewContext(runner.Suite.Opts)
 	diags = diags.Append(ctxDiags)
 	if ctxDiags.HasErrors() {
-		return nil, nil, diags
+		return nil, nil, nil, diags
 	}
 
 	runningCtx, done := context.WithCancel(context.WithoutCancel(ctx))
@@ -864,10 +1432,12 @@ func (runner *TestFileRunner) plan(ctx context.Context, config *configs.Config,
 	diags = diags.Append(waitDiags)
 	diags = diags.Append(planDiags)
 
-	return tfCtx, plan, diags
+	return tfCtx, plan, checkState, diags
 }
 
-func (runner *TestFileRunner) apply(ctx context.Context, plan *plans.Plan, state *states.State, config *configs.Config, run *moduletest.Run, file *moduletest.File) (*tofu.Context, *states.State, tfdiags.Diagnostics) {
+// apply applies the given plan for the given run block, returning the updated
+// state along with the state of the checks evaluated while applying.
+func (runner *TestFileRunner) apply(ctx context.Context, plan *plans.Plan, state *states.State, config *configs.Config, run *moduletest.Run, file *moduletest.File) (*tofu.Context, *states.State, *checks.State, tfdiags.Diagnostics) {
 	log.Printf("[TRACE] TestFileRunner: called apply for %s/%s", file.Name, run.Name)
 
 	var diags tfdiags.Diagnostics
@@ -894,20 +1464,26 @@ func (runner *TestFileRunner) apply(ctx context.Context, plan *plans.Plan, state
 	tfCtx, ctxDiags := tofu.NewContext(runner.Suite.Opts)
 	diags = diags.Append(ctxDiags)
 	if ctxDiags.HasErrors() {
-		return nil, state, diags
+		return nil, state, nil, diags
 	}
 
 	runningCtx, done := context.WithCancel(context.WithoutCancel(ctx))
 
 	var updated *states.State
+	var checkState *checks.State
 	var applyDiags tfdiags.Diagnostics
+	applyOpts := &tofu.ApplyOpts{
+		RecordChecks: func(state *checks.State) {
+			checkState = state
+		},
+	}
 
 	panicHandler := logging.PanicHandlerWithTraceFn()
 	go func() {
 		defer panicHandler()
 		defer done()
 		log.Printf("[DEBUG] TestFileRunner: starting apply for %s/%s", file.Name, run.Name)
-		updated, applyDiags = tfCtx.Apply(ctx, plan, config, nil)
+		updated, applyDiags = tfCtx.Apply(ctx, plan, config, applyOpts)
 		log.Printf("[DEBUG] TestFileRunner: completed apply for %s/%s", file.Name, run.Name)
 	}()
 	waitDiags, cancelled := runner.wait(tfCtx, runningCtx, run, file, created)
@@ -919,7 +1495,7 @@ func (runner *TestFileRunner) apply(ctx context.Context, plan *plans.Plan, state
 	diags = diags.Append(waitDiags)
 	diags = diags.Append(applyDiags)
 
-	return tfCtx, updated, diags
+	return tfCtx, updated, checkState, diags
 }
 
 func (runner *TestFileRunner) wait(ctx *tofu.Context, runningCtx context.Context, run *moduletest.Run, file *moduletest.File, created []*plans.ResourceInstanceChangeSrc) (diags tfdiags.Diagnostics, cancelled bool) {
@@ -941,15 +1517,18 @@ func (runner *TestFileRunner) wait(ctx *tofu.Context, runningCtx context.Context
 	handleCancelled := func() {
 		log.Printf("[DEBUG] TestFileRunner: test execution cancelled during %s", identifier)
 
+		fileStates := runner.states()
 		states := make(map[*moduletest.Run]*states.State)
-		states[nil] = runner.States[MainStateIdentifier].State
-		for key, module := range runner.States {
+		states[nil] = fileStates[MainStateIdentifier].State
+		for key, module := range fileStates {
 			if key == MainStateIdentifier {
 				continue
 			}
 			states[module.Run] = module.State
 		}
+		runner.Suite.lock.Lock()
 		runner.Suite.View.FatalInterruptSummary(run, file, states, created)
+		runner.Suite.lock.Unlock()
 
 		cancelled = true
 		go ctx.Stop()
@@ -1002,11 +1581,12 @@ func (runner *TestFileRunner) Cleanup(ctx context.Context, file *moduletest.File
 	var states []*TestFileState
 	for key, state := range runner.States {
 		if state.Run == nil {
-			if state.State.Empty() {
+			if state.State.Empty() || state.Seeded != nil {
 				// We can see a run block being empty when the state is empty if
 				// a module was only used to execute plan commands. So this is
 				// okay, and means we have nothing to cleanup so we'll just
-				// skip it.
+				// skip it. The same goes for starting states loaded by the
+				// setup block, as no run block created their resources.
 				continue
 			}
 
@@ -1022,7 +1602,9 @@ func (runner *TestFileRunner) Cleanup(ctx context.Context, file *moduletest.File
 
 			var diags tfdiags.Diagnostics
 			diags = diags.Append(tfdiags.Sourceless(tfdiags.Error, "Inconsistent state", fmt.Sprintf("Found inconsistent state while cleaning up %s. This is a bug in OpenTofu - please report it", file.Name)))
-			runner.Suite.View.DestroySummary(diags, nil, file, state.State)
+			runner.print(func(view views.Test) {
+				view.DestroySummary(diags, nil, file, state.State)
+			})
 			continue
 		}
 
@@ -1051,7 +1633,7 @@ func (runner *TestFileRunner) Cleanup(ctx context.Context, file *moduletest.File
 
 		isMainState := state.Run.Config.Module == nil
 		if isMainState {
-			runConfig = runner.Suite.Config
+			runConfig = runner.Config
 		} else {
 			runConfig = state.Run.Config.ConfigUnderTest
 		}
@@ -1064,17 +1646,35 @@ func (runner *TestFileRunner) Cleanup(ctx context.Context, file *moduletest.File
 		reset, configDiags := runConfig.TransformForTest(state.Run.Config, file.Config, evalCtx)
 		diags = diags.Append(configDiags)
 
+		// The resources loaded by the setup block aren't owned by the test,
+		// so we only destroy the ones created by the run blocks.
 		updated := state.State
+		if len(state.Seeded) > 0 {
+			updated = updated.DeepCopy()
+			sync := updated.SyncWrapper()
+			for addr := range state.Seeded.All() {
+				sync.ForgetResourceInstanceAll(addr)
+			}
+			updated = sync.Close()
+		}
+
 		if !diags.HasErrors() {
+			// Destroying a state counts towards the parallelism just like
+			// executing a run block, as the other test files may still be
+			// executing their run blocks.
 			var destroyDiags tfdiags.Diagnostics
-			updated, destroyDiags = runner.destroy(ctx, runConfig, state.State, state.Run, file)
+			runner.Suite.Suite.Acquire()
+			updated, destroyDiags = runner.destroy(ctx, runConfig, updated, state.Run, file)
+			runner.Suite.Suite.Release()
 			diags = diags.Append(destroyDiags)
 		}
-		runner.Suite.View.DestroySummary(diags, state.Run, file, updated)
+		runner.print(func(view views.Test) {
+			view.DestroySummary(diags, state.Run, file, updated)
 
-		if updated.HasManagedResourceInstanceObjects() {
-			views.SaveErroredTestStateFile(updated, state.Run, file, runner.Suite.View)
-		}
+			if updated.HasManagedResourceInstanceObjects() {
+				views.SaveErroredTestStateFile(updated, state.Run, file, view)
+			}
+		})
 		reset()
 	}
 }
@@ -1284,7 +1884,7 @@ func parseAndApplyDefaultValues(unparsedVariables map[string]backend.UnparsedVar
 // the config which must be called so the config can be reused going forward.
 func (runner *TestFileRunner) prepareInputVariablesForAssertions(config *configs.Config, run *moduletest.Run, file *moduletest.File, globals map[string]backend.UnparsedVariableValue) (tofu.InputValues, func(), tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
-	ctx, ctxDiags := getEvalContextForTest(runner.States, config, globals)
+	ctx, ctxDiags := getEvalContextForTest(runner.states(), config, globals)
 	diags = diags.Append(ctxDiags)
 
 	variables := make(map[string]backend.UnparsedVariableValue)
@@ -1364,6 +1964,35 @@ func (runner *TestFileRunner) prepareInputVariablesForAssertions(config *configs
 	}, diags
 }
 
+// getImportTargetsForTest returns the resources named by the import blocks in
+// the configuration under test, which are the only targets of a run block in
+// import-only mode.
+func getImportTargetsForTest(config *configs.Config, run *moduletest.Run) ([]addrs.Targetable, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	if len(config.Module.Import) == 0 {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "No import blocks",
+			Detail:   "The run block is in \"import-only\" mode, but the configuration under test contains no import blocks.",
+			Subject:  run.Config.Options.DeclRange.Ptr(),
+		})
+		return nil, diags
+	}
+
+	var targets []addrs.Targetable
+	for _, imp := range config.Module.Import {
+		if imp.ResolvedTo != nil {
+			targets = append(targets, *imp.ResolvedTo)
+			continue
+		}
+		// The instance keys of this address are dynamic, so we target every
+		// instance of the resource.
+		targets = append(targets, imp.StaticTo)
+	}
+	return targets, diags
+}
+
 // checkProblematicPlanErrors checks for plan errors that are also "expected" by the tests. In some cases we expect an error, however,
 // what causes the error might not be what we expected. So we try to warn about that here.
 func checkProblematicPlanErrors(expectedFailures addrs.Map[addrs.Referenceable, bool], planDiags tfdiags.Diagnostics) tfdiags.Diagnostics {
@@ -6,13 +6,23 @@
 package command
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"os"
 	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
 	"github.com/opentofu/opentofu/internal/command/workdir"
 	"github.com/zclconf/go-cty/cty"
 
@@ -20,8 +30,12 @@ import (
 	testing_command "github.com/opentofu/opentofu/internal/command/testing"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/grpcwrap"
+	tfplugin "github.com/opentofu/opentofu/internal/plugin"
+	"github.com/opentofu/opentofu/internal/plugin/cassette"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/terminal"
+	"github.com/opentofu/opentofu/internal/tfplugin5"
 )
 
 func TestTest(t *testing.T) {
@@ -1567,6 +1581,67 @@ func TestTest_MockProviderValidation(t *testing.T) {
 	}
 }
 
+// TestTest_MockProviderFunctions checks that the provider-defined functions mocked in a
+// mock_provider block return the mocked values, whether the provider declares them or not.
+func TestTest_MockProviderFunctions(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("test/mock_provider_functions"), td)
+	t.Chdir(td)
+
+	provider := testing_command.NewProvider(nil)
+	providerSource, closePS := newMockProviderSource(t, map[string][]string{
+		"test": {"1.0.0"},
+	})
+	defer closePS()
+
+	provider.Provider.GetProviderSchemaResponse = &providers.GetProviderSchemaResponse{
+		ResourceTypes: map[string]providers.Schema{
+			"test_resource": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"value": {
+							Type:     cty.String,
+							Optional: true,
+						},
+					},
+				},
+			},
+		},
+		Functions: map[string]providers.FunctionSpec{
+			"greet": {
+				Parameters: []providers.FunctionParameterSpec{
+					{
+						Name: "name",
+						Type: cty.String,
+					},
+				},
+				Return: cty.String,
+			},
+		},
+	}
+
+	view, done := testView(t)
+	meta := Meta{
+		WorkingDir:       workdir.NewDir("."),
+		testingOverrides: metaOverridesForProvider(provider.Provider),
+		View:             view,
+		ProviderSource:   providerSource,
+	}
+
+	testCmd := &TestCommand{
+		Meta: meta,
+	}
+
+	code := testCmd.Run([]string{"-no-color"})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("expected status code 0 but got %d: %s", code, output.All())
+	}
+	if provider.Provider.CallFunctionCalled {
+		t.Errorf("expected the mocked functions not to call the provider")
+	}
+}
+
 // TestTest_MockProviderValidationForEach checks if tofu test runs proper validation for
 // mock_provider with for_each. Even if provider schema has required fields, tofu test should
 // ignore it completely, because the provider is mocked.
@@ -1654,3 +1729,861 @@ func TestTest_DeprecatedOutputs(t *testing.T) {
 		t.Fatalf("expected status code 0 but got %d: %s", code, output.All())
 	}
 }
+
+func TestTest_TAP(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "reporters")), td)
+	t.Chdir(td)
+
+	provider := testing_command.NewProvider(nil)
+	view, done := testView(t)
+
+	c := &TestCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(provider.Provider),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-tap", "-no-color"})
+	output := done(t)
+
+	if code != 1 {
+		t.Errorf("expected status code 1 but got %d", code)
+	}
+
+	expected := `TAP version 13
+1..2
+# main.tftest.hcl... fail
+ok 1 - main.tftest.hcl run "passes"
+  ---
+  status: pass
+  duration_ms: 0
+  ...
+not ok 2 - main.tftest.hcl run "fails"
+  ---
+  status: fail
+  duration_ms: 0
+  diagnostics: |
+    Error: Test assertion failed
+    
+      on main.tftest.hcl line 10, in run "fails":
+      10:     condition     = test_resource.foo.value == "zap"
+        ├────────────────
+        │ test_resource.foo.value is "bar"
+        ├────────────────
+        │ Diff: 
+        │     "bar" -> "zap"
+    
+    invalid value
+  ...
+# pass 1
+# fail 1
+# skip 0
+`
+
+	// Durations vary between test runs.
+	actual := regexp.MustCompile(`duration_ms: \d+`).ReplaceAllString(output.Stdout(), "duration_ms: 0")
+	if diff := cmp.Diff(expected, actual); len(diff) > 0 {
+		t.Errorf("output didn't match expected:\nexpected:\n%s\nactual:\n%s\ndiff:\n%s", expected, actual, diff)
+	}
+
+	if output.Stderr() != "" {
+		t.Errorf("unexpected stderr output:\n%s", output.Stderr())
+	}
+
+	if provider.ResourceCount() > 0 {
+		t.Errorf("should have deleted all resources on completion but left %v", provider.ResourceString())
+	}
+}
+
+func TestTest_JUnitXML(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "reporters")), td)
+	t.Chdir(td)
+
+	provider := testing_command.NewProvider(nil)
+	view, done := testView(t)
+
+	c := &TestCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(provider.Provider),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-no-color", "-junit-xml=report.xml"})
+	output := done(t)
+
+	if code != 1 {
+		t.Errorf("expected status code 1 but got %d", code)
+	}
+
+	// The human-readable output is still printed alongside the report.
+	if !strings.Contains(output.Stdout(), "main.tftest.hcl... fail") {
+		t.Errorf("expected human-readable output but got:\n%s", output.Stdout())
+	}
+
+	report, err := os.ReadFile("report.xml")
+	if err != nil {
+		t.Fatalf("failed to read the JUnit XML report: %s", err)
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="2" failures="1" errors="0" skipped="0" time="0.000">
+  <testsuite name="main.tftest.hcl" tests="2" failures="1" errors="0" skipped="0" time="0.000">
+    <testcase name="passes" classname="main.tftest.hcl" time="0.000"></testcase>
+    <testcase name="fails" classname="main.tftest.hcl" time="0.000">
+      <failure message="Test assertion failed"><![CDATA[Error: Test assertion failed
+
+  on main.tftest.hcl line 10, in run "fails":
+  10:     condition     = test_resource.foo.value == "zap"
+    ├────────────────
+    │ test_resource.foo.value is "bar"
+    ├────────────────
+    │ Diff: 
+    │     "bar" -> "zap"
+
+invalid value]]></failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+
+	// Durations vary between test runs.
+	actual := regexp.MustCompile(`time="[0-9.]+"`).ReplaceAllString(string(report), `time="0.000"`)
+	if diff := cmp.Diff(expected, actual); len(diff) > 0 {
+		t.Errorf("report didn't match expected:\nexpected:\n%s\nactual:\n%s\ndiff:\n%s", expected, actual, diff)
+	}
+}
+
+func TestTest_Coverage(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "coverage")), td)
+	t.Chdir(td)
+
+	provider := testing_command.NewProvider(nil)
+	view, done := testView(t)
+
+	c := &TestCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(provider.Provider),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-coverage-out=coverage.lcov", "-no-color"})
+	output := done(t)
+
+	if code != 0 {
+		t.Errorf("expected status code 0 but got %d: %s", code, output.All())
+	}
+
+	expected := `main.tftest.hcl... pass
+  run "valid"... pass
+  run "invalid"... pass
+
+Coverage of the module under test:
+  resources:  1/2 planned, 1/2 applied
+  outputs:    1/1 planned, 1/1 applied
+  checks:     1/1 evaluated
+  conditions: 3/3 evaluated to true, 1/3 evaluated to false
+
+Not covered:
+  - test_resource.foo.precondition[0] (main.tf:14) never evaluated to false
+  - test_resource.bar (main.tf:21) was never planned
+  - check.value.assert[0] (main.tf:31) never evaluated to false
+
+Success! 2 passed, 0 failed.
+`
+	actual := output.Stdout()
+	if diff := cmp.Diff(expected, actual); len(diff) > 0 {
+		t.Errorf("output didn't match expected:\nexpected:\n%s\nactual:\n%s\ndiff:\n%s", expected, actual, diff)
+	}
+
+	report, err := os.ReadFile("coverage.lcov")
+	if err != nil {
+		t.Fatalf("failed to read the coverage report: %s", err)
+	}
+
+	expected = `TN:
+SF:main.tf
+FN:10,test_resource.foo
+FNDA:1,test_resource.foo
+FN:21,test_resource.bar
+FNDA:0,test_resource.bar
+FN:26,output.value
+FNDA:1,output.value
+FN:30,check.value
+FNDA:1,check.value
+FNF:4
+FNH:3
+BRDA:4,0,0,2
+BRDA:4,0,1,1
+BRDA:14,1,0,2
+BRDA:14,1,1,0
+BRDA:31,2,0,2
+BRDA:31,2,1,0
+BRF:6
+BRH:4
+DA:4,3
+DA:10,1
+DA:14,2
+DA:21,0
+DA:26,1
+DA:30,1
+DA:31,2
+LF:7
+LH:6
+end_of_record
+`
+	if diff := cmp.Diff(expected, string(report)); len(diff) > 0 {
+		t.Errorf("report didn't match expected:\nexpected:\n%s\nactual:\n%s\ndiff:\n%s", expected, report, diff)
+	}
+}
+
+func TestTest_PlanSnapshot(t *testing.T) {
+	tcs := map[string]struct {
+		args     []string
+		code     int
+		expected string
+		errors   []string
+	}{
+		"match": {
+			code: 0,
+			expected: `tests/main.tftest.hcl... pass
+  run "plan"... pass
+  run "apply"... pass
+
+Success! 2 passed, 0 failed.
+`,
+		},
+		"mismatch": {
+			args: []string{"-var=suffix=-changed"},
+			code: 1,
+			expected: `tests/main.tftest.hcl... fail
+  run "plan"... fail
+  run "apply"... fail
+
+Failure! 0 passed, 2 failed.
+`,
+			errors: []string{
+				"Error: Plan does not match snapshot",
+				`"value": "plan-changed"`,
+				"run tofu test with the -update-snapshots option",
+			},
+		},
+		"sensitive": {
+			// Sensitive values are hidden in the snapshots, so changing one
+			// doesn't change the plans.
+			args: []string{"-var=secret=correct horse"},
+			code: 0,
+			expected: `tests/main.tftest.hcl... pass
+  run "plan"... pass
+  run "apply"... pass
+
+Success! 2 passed, 0 failed.
+`,
+		},
+		"update": {
+			args: []string{"-var=suffix=-changed", "-update-snapshots"},
+			code: 0,
+			expected: `tests/main.tftest.hcl... pass
+  run "plan"... pass
+  run "apply"... pass
+
+Success! 2 passed, 0 failed.
+`,
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			td := t.TempDir()
+			testCopyDir(t, testFixturePath(path.Join("test", "plan_snapshot")), td)
+			t.Chdir(td)
+
+			run := func(args ...string) (int, *terminal.TestOutput) {
+				provider := testing_command.NewProvider(nil)
+				view, done := testView(t)
+
+				c := &TestCommand{
+					Meta: Meta{
+						WorkingDir:       workdir.NewDir("."),
+						testingOverrides: metaOverridesForProvider(provider.Provider),
+						View:             view,
+					},
+				}
+
+				code := c.Run(append(args, "-no-color"))
+				return code, done(t)
+			}
+
+			code, output := run(tc.args...)
+			if code != tc.code {
+				t.Errorf("expected status code %d but got %d: %s", tc.code, code, output.All())
+			}
+
+			if diff := cmp.Diff(tc.expected, output.Stdout()); len(diff) > 0 {
+				t.Errorf("output didn't match expected:\nexpected:\n%s\nactual:\n%s\ndiff:\n%s", tc.expected, output.Stdout(), diff)
+			}
+
+			stderr := output.Stderr()
+			for _, want := range tc.errors {
+				if !strings.Contains(stderr, want) {
+					t.Errorf("expected the errors to contain %q, but got:\n%s", want, stderr)
+				}
+			}
+
+			for _, snapshot := range []string{"plan.json", "apply.json"} {
+				src, err := os.ReadFile(filepath.Join("tests", "snapshots", snapshot))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if strings.Contains(string(src), "hunter2") || !strings.Contains(string(src), `"(sensitive value)"`) {
+					t.Errorf("the sensitive values are not hidden in %s:\n%s", snapshot, src)
+				}
+			}
+			if strings.Contains(output.All(), "hunter2") {
+				t.Errorf("the output includes a sensitive value:\n%s", output.All())
+			}
+
+			if slices.Contains(tc.args, "-update-snapshots") {
+				// The updated snapshots must match the same plans.
+				if code, output := run(tc.args[0]); code != 0 {
+					t.Errorf("expected status code 0 with the updated snapshots but got %d: %s", code, output.All())
+				}
+			}
+		})
+	}
+}
+
+func TestTest_Parallelism(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "parallel")), td)
+	t.Chdir(td)
+
+	provider := testing_command.NewProvider(nil)
+	providerSource, close := newMockProviderSource(t, map[string][]string{
+		"test": {"1.0.0"},
+	})
+	defer close()
+
+	streams, done := terminal.StreamsForTesting(t)
+	view := views.NewView(streams)
+	meta := Meta{
+		WorkingDir:       workdir.NewDir("."),
+		testingOverrides: metaOverridesForProvider(provider.Provider),
+		View:             view,
+		ProviderSource:   providerSource,
+	}
+
+	init := &InitCommand{
+		Meta: meta,
+	}
+
+	if code := init.Run(nil); code != 0 {
+		output := done(t)
+		t.Fatalf("expected status code 0 but got %d: %s", code, output.All())
+	}
+	done(t)
+
+	// The output is the same as when executing everything in order.
+	expected := `modules.tftest.hcl... pass
+  run "main"... pass
+  run "setup"... pass
+  run "uses_setup"... pass
+real.tftest.hcl... pass
+  run "first"... pass
+  run "second"... pass
+
+Success! 5 passed, 0 failed.
+`
+
+	for _, parallelism := range []string{"1", "4"} {
+		t.Run(parallelism, func(t *testing.T) {
+			streams, done := terminal.StreamsForTesting(t)
+			meta.View = views.NewView(streams)
+			c := &TestCommand{
+				Meta: meta,
+			}
+
+			code := c.Run([]string{"-no-color", "-parallelism=" + parallelism})
+			output := done(t)
+
+			if code != 0 {
+				t.Errorf("expected status code 0 but got %d", code)
+			}
+
+			actual := output.All()
+			if diff := cmp.Diff(expected, actual); len(diff) > 0 {
+				t.Errorf("output didn't match expected:\nexpected:\n%s\nactual:\n%s\ndiff:\n%s", expected, actual, diff)
+			}
+
+			if provider.ResourceCount() > 0 {
+				t.Errorf("should have deleted all resources on completion but left %v", provider.ResourceString())
+			}
+		})
+	}
+}
+
+func TestTest_ParallelismMocked(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "parallel_mocked")), td)
+	t.Chdir(td)
+
+	// The run blocks "first" and "second" use mocked providers and their
+	// own states, so they can only complete when they are executed at the
+	// same time.
+	testProvider := testing_command.NewProvider(nil)
+	testProvider.Provider.ConfigureProviderCalled = true
+	provider := &rendezvousProvider{
+		Interface: testProvider.Provider,
+		values:    []string{"first", "second"},
+		timeout:   10 * time.Second,
+		arrived:   make(map[string]bool),
+		all:       make(chan struct{}),
+	}
+	providerSource, close := newMockProviderSource(t, map[string][]string{
+		"test": {"1.0.0"},
+	})
+	defer close()
+
+	streams, done := terminal.StreamsForTesting(t)
+	view := views.NewView(streams)
+	meta := Meta{
+		WorkingDir:       workdir.NewDir("."),
+		testingOverrides: metaOverridesForProvider(provider),
+		View:             view,
+		ProviderSource:   providerSource,
+	}
+
+	init := &InitCommand{
+		Meta: meta,
+	}
+
+	if code := init.Run(nil); code != 0 {
+		output := done(t)
+		t.Fatalf("expected status code 0 but got %d: %s", code, output.All())
+	}
+	done(t)
+
+	streams, done = terminal.StreamsForTesting(t)
+	meta.View = views.NewView(streams)
+	c := &TestCommand{
+		Meta: meta,
+	}
+
+	code := c.Run([]string{"-no-color", "-parallelism=4"})
+	output := done(t)
+
+	if code != 0 {
+		t.Errorf("expected status code 0 but got %d", code)
+	}
+
+	expected := `main.tftest.hcl... pass
+  run "main"... pass
+  run "first"... pass
+  run "second"... pass
+
+Success! 3 passed, 0 failed.
+`
+	actual := output.All()
+	if diff := cmp.Diff(expected, actual); len(diff) > 0 {
+		t.Errorf("output didn't match expected:\nexpected:\n%s\nactual:\n%s\ndiff:\n%s", expected, actual, diff)
+	}
+
+	if provider.timedOut {
+		t.Errorf("the run blocks were not executed concurrently")
+	}
+	if testProvider.ResourceCount() > 0 {
+		t.Errorf("should not have created any resources with the mocked provider but created %v", testProvider.ResourceString())
+	}
+}
+
+// rendezvousProvider wraps a provider so that validating a resource whose
+// value is one of the given values blocks until resources with all of them
+// are being validated, or until the timeout expires. This proves that the
+// run blocks validating them are executed concurrently.
+type rendezvousProvider struct {
+	providers.Interface
+
+	values  []string
+	timeout time.Duration
+
+	mu       sync.Mutex
+	arrived  map[string]bool
+	all      chan struct{}
+	timedOut bool
+}
+
+func (p *rendezvousProvider) ValidateResourceConfig(ctx context.Context, req providers.ValidateResourceConfigRequest) providers.ValidateResourceConfigResponse {
+	if value := req.Config.GetAttr("value"); value.IsKnown() && !value.IsNull() && slices.Contains(p.values, value.AsString()) {
+		p.mu.Lock()
+		if !p.arrived[value.AsString()] {
+			p.arrived[value.AsString()] = true
+			if len(p.arrived) == len(p.values) {
+				close(p.all)
+			}
+		}
+		p.mu.Unlock()
+
+		select {
+		case <-p.all:
+		case <-time.After(p.timeout):
+			p.mu.Lock()
+			p.timedOut = true
+			p.mu.Unlock()
+		}
+	}
+	return p.Interface.ValidateResourceConfig(ctx, req)
+}
+
+func TestTest_DestroyCommand(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "destroy_command")), td)
+	t.Chdir(td)
+
+	provider := testing_command.NewProvider(nil)
+
+	// Keep track of the destroyed resources, so we can check the "destroy"
+	// run destroyed everything in the right order.
+	type destroyedResource struct {
+		id, value string
+	}
+	var destroyed []destroyedResource
+	apply := provider.Provider.ApplyResourceChangeFn
+	provider.Provider.ApplyResourceChangeFn = func(request providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+		if request.PlannedState.IsNull() {
+			destroyed = append(destroyed, destroyedResource{
+				id:    request.PriorState.GetAttr("id").AsString(),
+				value: request.PriorState.GetAttr("value").AsString(),
+			})
+		}
+		return apply(request)
+	}
+
+	view, done := testView(t)
+
+	c := &TestCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(provider.Provider),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-no-color"})
+	output := done(t)
+
+	if code != 0 {
+		t.Errorf("expected status code 0 but got %d: %s", code, output.All())
+	}
+
+	expected := `main.tftest.hcl... pass
+  run "setup"... pass
+  run "protected"... pass
+  run "destroy"... pass
+  run "recreate"... pass
+
+Success! 4 passed, 0 failed.
+`
+	actual := output.All()
+	if diff := cmp.Diff(expected, actual); len(diff) > 0 {
+		t.Errorf("output didn't match expected:\nexpected:\n%s\nactual:\n%s\ndiff:\n%s", expected, actual, diff)
+	}
+
+	// The "destroy" run and the cleanup each destroy all three resources.
+	if len(destroyed) != 6 {
+		t.Fatalf("expected 6 destroyed resources but got %v", destroyed)
+	}
+
+	// test_resource.secondary depends on test_resource.primary, so must be
+	// destroyed first.
+	primary := slices.IndexFunc(destroyed[:3], func(r destroyedResource) bool {
+		return r.value == "primary"
+	})
+	secondary := slices.IndexFunc(destroyed[:3], func(r destroyedResource) bool {
+		return primary >= 0 && r.value == destroyed[primary].id
+	})
+	if primary < 0 || secondary < 0 || secondary > primary {
+		t.Errorf("destroyed resources in the wrong order: %v", destroyed)
+	}
+
+	if provider.ResourceCount() > 0 {
+		t.Errorf("should have deleted all resources on completion but left %v", provider.ResourceString())
+	}
+}
+
+func TestTest_ImportOnlyMode(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "import_only")), td)
+	t.Chdir(td)
+
+	provider := testing_command.NewProvider(nil)
+	provider.Store.Put(provider.GetResourceKey("existing"), cty.ObjectVal(map[string]cty.Value{
+		"id":              cty.StringVal("existing"),
+		"value":           cty.StringVal("existing"),
+		"interrupt_count": cty.NullVal(cty.Number),
+	}))
+	view, done := testView(t)
+
+	c := &TestCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(provider.Provider),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-no-color"})
+	output := done(t)
+
+	if code != 0 {
+		t.Errorf("expected status code 0 but got %d: %s", code, output.All())
+	}
+
+	// The precondition on test_resource.other would fail if the import-only
+	// runs planned anything other than the imported resource. The runs are
+	// targeted at the imported resource, so OpenTofu warns about targeting.
+	expected := `main.tftest.hcl... pass
+  run "plan_import"... pass
+
+Warning: Resource targeting is in effect
+
+You are creating a plan with either the -target option or the -exclude
+option, which means that the result of this plan may not represent all of the
+changes requested by the current configuration.
+
+The -target and -exclude options are not for routine use, and are provided
+only for exceptional situations such as recovering from errors or mistakes,
+or when OpenTofu specifically suggests to use it as part of an error message.
+  run "import"... pass
+
+Warning: Resource targeting is in effect
+
+You are creating a plan with either the -target option or the -exclude
+option, which means that the result of this plan may not represent all of the
+changes requested by the current configuration.
+
+The -target and -exclude options are not for routine use, and are provided
+only for exceptional situations such as recovering from errors or mistakes,
+or when OpenTofu specifically suggests to use it as part of an error message.
+
+Warning: Applied changes may be incomplete
+
+The plan was created with the -target or the -exclude option in effect, so
+some changes requested in the configuration may have been ignored and the
+output values may not be fully updated. Run the following command to verify
+that no other changes are pending:
+    tofu plan
+	
+Note that the -target and -exclude options are not suitable for routine use,
+and are provided only for exceptional situations such as recovering from
+errors or mistakes, or when OpenTofu specifically suggests to use it as part
+of an error message.
+  run "apply"... pass
+
+Success! 3 passed, 0 failed.
+`
+	actual := output.All()
+	if diff := cmp.Diff(expected, actual); len(diff) > 0 {
+		t.Errorf("output didn't match expected:\nexpected:\n%s\nactual:\n%s\ndiff:\n%s", expected, actual, diff)
+	}
+
+	if provider.ResourceCount() > 0 {
+		t.Errorf("should have deleted all resources on completion but left %v", provider.ResourceString())
+	}
+}
+
+func TestTest_SetupState(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "setup_state")), td)
+	t.Chdir(td)
+
+	// The resource in the starting state already exists.
+	provider := testing_command.NewProvider(nil)
+	provider.Store.Put(provider.GetResourceKey("existing"), cty.ObjectVal(map[string]cty.Value{
+		"id":              cty.StringVal("existing"),
+		"value":           cty.StringVal("existing"),
+		"interrupt_count": cty.NullVal(cty.Number),
+	}))
+
+	var created int
+	apply := provider.Provider.ApplyResourceChangeFn
+	provider.Provider.ApplyResourceChangeFn = func(request providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+		if request.PriorState.IsNull() {
+			created++
+		}
+		return apply(request)
+	}
+
+	view, done := testView(t)
+
+	c := &TestCommand{
+		Meta: Meta{
+			WorkingDir:       workdir.NewDir("."),
+			testingOverrides: metaOverridesForProvider(provider.Provider),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-no-color"})
+	output := done(t)
+
+	if code != 0 {
+		t.Errorf("expected status code 0 but got %d: %s", code, output.All())
+	}
+
+	expected := `tests/main.tftest.hcl... pass
+  run "upgrade"... pass
+tests/plan.tftest.hcl... pass
+  run "plan"... pass
+
+Success! 2 passed, 0 failed.
+`
+	actual := output.All()
+	if diff := cmp.Diff(expected, actual); len(diff) > 0 {
+		t.Errorf("output didn't match expected:\nexpected:\n%s\nactual:\n%s\ndiff:\n%s", expected, actual, diff)
+	}
+
+	// The moved block should have moved the resource in the starting state,
+	// instead of creating a new one.
+	if created != 1 {
+		t.Errorf("expected only test_resource.created to be created but %d resources were", created)
+	}
+
+	// The resource loaded from the starting state isn't owned by the test and
+	// must survive the cleanup, while the one the test created is destroyed.
+	if provider.ResourceCount() != 1 || provider.Store.Get(provider.GetResourceKey("existing")).IsNull() {
+		t.Errorf("should have kept the existing resource on completion but left %v", provider.ResourceString())
+	}
+}
+
+func TestTest_RecordReplay(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "record_replay")), td)
+	t.Chdir(td)
+
+	// The cassette is recorded from a real gRPC connection to the provider,
+	// which runs in-process as an unmanaged provider.
+	provider := testing_command.NewProvider(nil)
+	reattach, stop := testServeProvider(t, provider.Provider)
+
+	expected := `main.tftest.hcl... pass
+  run "validate_test_resource"... pass
+
+Success! 1 passed, 0 failed.
+`
+
+	view, done := testView(t)
+	c := &TestCommand{
+		Meta: Meta{
+			WorkingDir: workdir.NewDir("."),
+			UnmanagedProviders: map[addrs.Provider]*plugin.ReattachConfig{
+				addrs.NewDefaultProvider("test"): reattach,
+			},
+			View: view,
+		},
+	}
+	code := c.Run([]string{"-no-color", "-record=cassette.json"})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("expected status code 0 while recording but got %d: %s", code, output.All())
+	}
+	if diff := cmp.Diff(expected, output.All()); len(diff) > 0 {
+		t.Errorf("output didn't match expected while recording:\n%s", diff)
+	}
+	if provider.ResourceCount() > 0 {
+		t.Errorf("should have deleted all resources on completion but left %v", provider.ResourceString())
+	}
+
+	recorded, err := cassette.Load("cassette.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	interactions := recorded.Providers["registry.opentofu.org/hashicorp/test"].Interactions
+	methods := make(map[string]bool)
+	for _, interaction := range interactions {
+		methods[interaction.Method] = true
+		if bytes.Contains(interaction.Request, []byte("hunter2")) || bytes.Contains(interaction.Response, []byte("hunter2")) {
+			t.Errorf("the recorded %s call includes the provider's password", interaction.Method)
+		}
+	}
+	for _, method := range []string{tfplugin5.Provider_Configure_FullMethodName, tfplugin5.Provider_ApplyResourceChange_FullMethodName} {
+		if !methods[method] {
+			t.Errorf("the cassette has no %s call", method)
+		}
+	}
+
+	// The provider is no longer running, and isn't installed, so the tests
+	// can only pass if all of the calls are replayed from the cassette.
+	stop()
+
+	view, done = testView(t)
+	c = &TestCommand{
+		Meta: Meta{
+			WorkingDir: workdir.NewDir("."),
+			View:       view,
+		},
+	}
+	code = c.Run([]string{"-no-color", "-replay=cassette.json"})
+	output = done(t)
+	if code != 0 {
+		t.Fatalf("expected status code 0 while replaying but got %d: %s", code, output.All())
+	}
+	if diff := cmp.Diff(expected, output.All()); len(diff) > 0 {
+		t.Errorf("output didn't match expected while replaying:\n%s", diff)
+	}
+}
+
+// testServeProvider serves the given provider over gRPC in-process, returning
+// the configuration to attach to it as an unmanaged provider and a function
+// that stops it.
+func testServeProvider(t *testing.T, p providers.Interface) (*plugin.ReattachConfig, func()) {
+	t.Helper()
+
+	reattachCh := make(chan *plugin.ReattachConfig)
+	closeCh := make(chan struct{})
+	ctx, cancel := context.WithCancel(t.Context())
+	go plugin.Serve(&plugin.ServeConfig{
+		Logger: hclog.New(&hclog.LoggerOptions{
+			Name:   "plugintest",
+			Level:  hclog.Trace,
+			Output: io.Discard,
+		}),
+		Test: &plugin.ServeTestConfig{
+			Context:          ctx,
+			ReattachConfigCh: reattachCh,
+			CloseCh:          closeCh,
+		},
+		GRPCServer: plugin.DefaultGRPCServer,
+		VersionedPlugins: map[int]plugin.PluginSet{
+			5: {
+				"provider": &tfplugin.GRPCProviderPlugin{
+					GRPCProvider: func() tfplugin5.ProviderServer {
+						return grpcwrap.Provider(p)
+					},
+				},
+			},
+		},
+	})
+	reattach := <-reattachCh
+	if reattach == nil {
+		t.Fatal("no reattach config received")
+	}
+
+	stopped := false
+	stop := func() {
+		if !stopped {
+			stopped = true
+			cancel()
+			<-closeCh
+		}
+	}
+	t.Cleanup(stop)
+	return reattach, stop
+}
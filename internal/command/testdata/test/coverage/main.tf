@@ -0,0 +1,35 @@
+variable "size" {
+  type = number
+
+  validation {
+    condition     = var.size > 0
+    error_message = "The size must be positive."
+  }
+}
+
+resource "test_resource" "foo" {
+  value = var.size
+
+  lifecycle {
+    precondition {
+      condition     = var.size < 10
+      error_message = "The size is too large."
+    }
+  }
+}
+
+resource "test_resource" "bar" {
+  count = var.size > 5 ? 1 : 0
+  value = "bar"
+}
+
+output "value" {
+  value = test_resource.foo.value
+}
+
+check "value" {
+  assert {
+    condition     = test_resource.foo.value != "0"
+    error_message = "The value must not be zero."
+  }
+}
@@ -0,0 +1,17 @@
+run "valid" {
+  variables {
+    size = 1
+  }
+}
+
+run "invalid" {
+  command = plan
+
+  variables {
+    size = 0
+  }
+
+  expect_failures = [
+    var.size,
+  ]
+}
@@ -0,0 +1,20 @@
+variable "protect" {
+  type    = bool
+  default = false
+}
+
+resource "test_resource" "primary" {
+  value = "primary"
+}
+
+resource "test_resource" "secondary" {
+  value = test_resource.primary.id
+}
+
+resource "test_resource" "protected" {
+  value = "protected"
+
+  lifecycle {
+    prevent_destroy = var.protect
+  }
+}
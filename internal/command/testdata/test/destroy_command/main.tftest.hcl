@@ -0,0 +1,42 @@
+variables {
+  protect = true
+}
+
+run "setup" {
+  assert {
+    condition     = test_resource.secondary.value == test_resource.primary.id
+    error_message = "invalid value"
+  }
+}
+
+run "protected" {
+  command = destroy
+
+  expect_failures = [
+    test_resource.protected,
+  ]
+
+  assert {
+    condition     = test_resource.protected.value == "protected"
+    error_message = "protected resource was destroyed"
+  }
+}
+
+run "destroy" {
+  command = destroy
+
+  variables {
+    protect = false
+  }
+}
+
+run "recreate" {
+  variables {
+    protect = false
+  }
+
+  assert {
+    condition     = test_resource.secondary.value == test_resource.primary.id
+    error_message = "invalid value"
+  }
+}
@@ -0,0 +1,25 @@
+variable "allow_other" {
+  type    = bool
+  default = false
+}
+
+import {
+  to = test_resource.imported
+  id = "existing"
+}
+
+resource "test_resource" "imported" {
+  id    = "existing"
+  value = "existing"
+}
+
+resource "test_resource" "other" {
+  value = "other"
+
+  lifecycle {
+    precondition {
+      condition     = var.allow_other
+      error_message = "test_resource.other should not be planned"
+    }
+  }
+}
@@ -0,0 +1,34 @@
+run "plan_import" {
+  command = plan
+
+  plan_options {
+    mode = import-only
+  }
+
+  assert {
+    condition     = test_resource.imported.value == "existing"
+    error_message = "invalid value"
+  }
+}
+
+run "import" {
+  plan_options {
+    mode = import-only
+  }
+
+  assert {
+    condition     = test_resource.imported.value == "existing"
+    error_message = "invalid value"
+  }
+}
+
+run "apply" {
+  variables {
+    allow_other = true
+  }
+
+  assert {
+    condition     = test_resource.other.value == "other"
+    error_message = "invalid value"
+  }
+}
@@ -0,0 +1,17 @@
+terraform {
+  required_providers {
+    test = {
+      source = "hashicorp/test"
+    }
+  }
+}
+
+provider "test" {}
+
+resource "test_resource" "primary" {
+  value = provider::test::greet("world")
+}
+
+output "id" {
+  value = provider::test::id()
+}
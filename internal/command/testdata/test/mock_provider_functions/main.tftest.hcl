@@ -0,0 +1,21 @@
+mock_provider "test" {
+  mock_function "greet" {
+    returns = "hello, world"
+  }
+
+  mock_function "id" {
+    returns = 42
+  }
+}
+
+run "test" {
+  assert {
+    condition     = test_resource.primary.value == "hello, world"
+    error_message = "Unexpected value"
+  }
+
+  assert {
+    condition     = output.id == 42
+    error_message = "Unexpected id"
+  }
+}
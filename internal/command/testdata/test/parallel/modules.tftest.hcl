@@ -0,0 +1,27 @@
+run "main" {
+  variables {
+    value = "main"
+  }
+
+  assert {
+    condition     = test_resource.foo.value == "main"
+    error_message = "invalid value"
+  }
+}
+
+run "setup" {
+  module {
+    source = "./setup"
+  }
+}
+
+run "uses_setup" {
+  variables {
+    value = run.setup.value
+  }
+
+  assert {
+    condition     = test_resource.foo.value == "setup"
+    error_message = "invalid value"
+  }
+}
@@ -0,0 +1,21 @@
+run "first" {
+  variables {
+    value = "first"
+  }
+
+  assert {
+    condition     = test_resource.foo.value == "first"
+    error_message = "invalid value"
+  }
+}
+
+run "second" {
+  variables {
+    value = "second"
+  }
+
+  assert {
+    condition     = test_resource.foo.value == "second"
+    error_message = "invalid value"
+  }
+}
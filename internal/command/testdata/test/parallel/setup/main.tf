@@ -0,0 +1,7 @@
+resource "test_resource" "setup" {
+  value = "setup"
+}
+
+output "value" {
+  value = test_resource.setup.value
+}
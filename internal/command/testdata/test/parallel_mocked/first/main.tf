@@ -0,0 +1,3 @@
+resource "test_resource" "first" {
+  value = "first"
+}
@@ -0,0 +1,30 @@
+mock_provider "test" {}
+
+run "main" {
+  assert {
+    condition     = test_resource.main.value == "main"
+    error_message = "invalid value"
+  }
+}
+
+run "first" {
+  module {
+    source = "./first"
+  }
+
+  assert {
+    condition     = test_resource.first.value == "first"
+    error_message = "invalid value"
+  }
+}
+
+run "second" {
+  module {
+    source = "./second"
+  }
+
+  assert {
+    condition     = test_resource.second.value == "second"
+    error_message = "invalid value"
+  }
+}
@@ -0,0 +1,31 @@
+variable "value" {
+  type = string
+}
+
+variable "suffix" {
+  type    = string
+  default = ""
+}
+
+variable "secret" {
+  type      = string
+  default   = "hunter2"
+  sensitive = true
+}
+
+resource "test_resource" "foo" {
+  value = "${var.value}${var.suffix}"
+}
+
+resource "test_resource" "secret" {
+  value = var.secret
+}
+
+output "value" {
+  value = test_resource.foo.value
+}
+
+output "secret" {
+  value     = test_resource.secret.value
+  sensitive = true
+}
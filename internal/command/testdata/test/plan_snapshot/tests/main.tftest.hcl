@@ -0,0 +1,17 @@
+run "plan" {
+  command = plan
+
+  variables {
+    value = "plan"
+  }
+
+  expect_plan_snapshot = "snapshots/plan.json"
+}
+
+run "apply" {
+  variables {
+    value = "apply"
+  }
+
+  expect_plan_snapshot = "snapshots/apply.json"
+}
@@ -0,0 +1,12 @@
+variable "password" {
+  type = string
+}
+
+provider "test" {
+  username = "admin"
+  password = var.password
+}
+
+resource "test_resource" "foo" {
+  value = "bar"
+}
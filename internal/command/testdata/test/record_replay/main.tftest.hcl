@@ -0,0 +1,10 @@
+variables {
+  password = "hunter2"
+}
+
+run "validate_test_resource" {
+  assert {
+    condition = test_resource.foo.value == "bar"
+    error_message = "invalid value"
+  }
+}
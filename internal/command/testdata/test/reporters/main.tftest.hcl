@@ -0,0 +1,13 @@
+run "passes" {
+  assert {
+    condition     = test_resource.foo.value == "bar"
+    error_message = "invalid value"
+  }
+}
+
+run "fails" {
+  assert {
+    condition     = test_resource.foo.value == "zap"
+    error_message = "invalid value"
+  }
+}
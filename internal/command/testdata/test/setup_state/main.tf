@@ -0,0 +1,13 @@
+resource "test_resource" "renamed" {
+  id    = "existing"
+  value = "existing"
+}
+
+moved {
+  from = test_resource.original
+  to   = test_resource.renamed
+}
+
+resource "test_resource" "created" {
+  value = "created"
+}
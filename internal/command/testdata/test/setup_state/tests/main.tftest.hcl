@@ -0,0 +1,16 @@
+fixture "v1" {
+  state = "states/v1.tfstate"
+}
+
+setup {
+  state {
+    fixture = "v1"
+  }
+}
+
+run "upgrade" {
+  assert {
+    condition     = test_resource.renamed.value == "existing"
+    error_message = "invalid value"
+  }
+}
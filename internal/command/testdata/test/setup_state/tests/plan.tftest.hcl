@@ -0,0 +1,19 @@
+# The fixture is declared in main.tftest.hcl.
+setup {
+  state {
+    fixture = "v1"
+  }
+}
+
+run "plan" {
+  command = plan
+
+  plan_options {
+    refresh = false
+  }
+
+  assert {
+    condition     = test_resource.renamed.id == "existing"
+    error_message = "invalid id"
+  }
+}
@@ -98,6 +98,7 @@ func NewProvider(store *ResourceStore) *TestProvider {
 	provider.Provider.PlanResourceChangeFn = provider.PlanResourceChange
 	provider.Provider.ApplyResourceChangeFn = provider.ApplyResourceChange
 	provider.Provider.ReadResourceFn = provider.ReadResource
+	provider.Provider.ImportResourceStateFn = provider.ImportResourceState
 	provider.Provider.ReadDataSourceFn = provider.ReadDataSource
 
 	return provider
@@ -271,6 +272,28 @@ func (provider *TestProvider) ReadResource(request providers.ReadResourceRequest
 	}
 }
 
+func (provider *TestProvider) ImportResourceState(request providers.ImportResourceStateRequest) providers.ImportResourceStateResponse {
+	var diags tfdiags.Diagnostics
+
+	resource := provider.Store.Get(provider.GetResourceKey(request.Target.ID))
+	if resource == cty.NilVal {
+		diags = diags.Append(tfdiags.Sourceless(tfdiags.Error, "not found", fmt.Sprintf("%s does not exist", request.Target.ID)))
+		return providers.ImportResourceStateResponse{
+			Diagnostics: diags,
+		}
+	}
+
+	return providers.ImportResourceStateResponse{
+		ImportedResources: []providers.ImportedResource{
+			{
+				TypeName: request.TypeName,
+				State:    resource,
+			},
+		},
+		Diagnostics: diags,
+	}
+}
+
 func (provider *TestProvider) ReadDataSource(request providers.ReadDataSourceRequest) providers.ReadDataSourceResponse {
 	var diags tfdiags.Diagnostics
 
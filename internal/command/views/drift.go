@@ -0,0 +1,216 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// The Drift view is used for the drift command.
+type Drift interface {
+	Operation() Operation
+	Hooks() []tofu.Hook
+
+	// DriftReport renders the drift detected by the refresh-only plan.
+	DriftReport(report *jsonplan.DriftReport)
+
+	Diagnostics(diags tfdiags.Diagnostics)
+	HelpPrompt()
+
+	// Backend returns the non-command view that contains methods to provide
+	// progress output for the backend operations.
+	Backend() Backend
+}
+
+// NewDrift returns an initialized Drift implementation for the given ViewType.
+func NewDrift(args arguments.ViewOptions, view *View) Drift {
+	var ret Drift
+	switch args.ViewType {
+	case arguments.ViewJSON:
+		ret = &DriftJSON{view: NewJSONView(view, nil)}
+	case arguments.ViewHuman:
+		ret = &DriftHuman{view: view}
+	default:
+		panic(fmt.Sprintf("unknown view type %v", args.ViewType))
+	}
+
+	if args.JSONInto != nil {
+		ret = DriftMulti{ret, &DriftJSON{view: NewJSONView(view, args.JSONInto)}}
+	}
+	return ret
+}
+
+type DriftMulti []Drift
+
+var _ Drift = (DriftMulti)(nil)
+
+func (m DriftMulti) Operation() Operation {
+	var operation OperationMulti
+	for _, drift := range m {
+		operation = append(operation, drift.Operation())
+	}
+	return operation
+}
+
+func (m DriftMulti) Hooks() []tofu.Hook {
+	var hooks []tofu.Hook
+	for _, drift := range m {
+		hooks = append(hooks, drift.Hooks()...)
+	}
+	return hooks
+}
+
+func (m DriftMulti) DriftReport(report *jsonplan.DriftReport) {
+	for _, drift := range m {
+		drift.DriftReport(report)
+	}
+}
+
+func (m DriftMulti) Diagnostics(diags tfdiags.Diagnostics) {
+	for _, drift := range m {
+		drift.Diagnostics(diags)
+	}
+}
+
+func (m DriftMulti) HelpPrompt() {
+	for _, drift := range m {
+		drift.HelpPrompt()
+	}
+}
+
+func (m DriftMulti) Backend() Backend {
+	ret := make([]Backend, len(m))
+	for i, v := range m {
+		ret[i] = v.Backend()
+	}
+	return BackendMulti(ret)
+}
+
+// The DriftHuman implementation renders human-readable text logs, suitable
+// for a scrolling terminal.
+type DriftHuman struct {
+	view *View
+}
+
+var _ Drift = (*DriftHuman)(nil)
+
+func (v *DriftHuman) Operation() Operation {
+	return NewOperation(arguments.ViewHuman, v.view)
+}
+
+func (v *DriftHuman) Hooks() []tofu.Hook {
+	return []tofu.Hook{NewUIOptionalHook(v.view)}
+}
+
+func (v *DriftHuman) DriftReport(report *jsonplan.DriftReport) {
+	if report.Summary.Total() == 0 {
+		msg := "\n[reset][bold][green]No drift detected.[reset] The remote objects match the OpenTofu state.\n"
+		_, _ = v.view.streams.Print(v.view.colorize.Color(msg))
+		return
+	}
+
+	_, _ = v.view.streams.Print(v.view.colorize.Color("\n[reset][bold][yellow]Drift detected:[reset]\n"))
+	for _, r := range report.Resources {
+		switch r.Drift {
+		case jsonplan.DriftDeleted:
+			_, _ = v.view.streams.Println(v.view.colorize.Color(fmt.Sprintf("\n  [bold]# %s[reset] has been deleted outside of OpenTofu", r.Address)))
+		case jsonplan.DriftMoved:
+			_, _ = v.view.streams.Println(v.view.colorize.Color(fmt.Sprintf("\n  [bold]# %s[reset] has moved to [bold]%s[reset]", r.PreviousAddress, r.Address)))
+		default:
+			msg := fmt.Sprintf("\n  [bold]# %s[reset] has changed outside of OpenTofu", r.Address)
+			if r.PreviousAddress != "" {
+				msg = fmt.Sprintf("\n  [bold]# %s[reset] (moved from %s) has changed outside of OpenTofu", r.Address, r.PreviousAddress)
+			}
+			_, _ = v.view.streams.Println(v.view.colorize.Color(msg))
+			for _, attr := range r.Attributes {
+				if attr.Sensitive {
+					_, _ = v.view.streams.Printf("      %s: (sensitive value)\n", attr.Path)
+					continue
+				}
+				_, _ = v.view.streams.Printf("      %s: %s -> %s\n", attr.Path, attr.Before, attr.After)
+			}
+		}
+	}
+
+	var counts []string
+	for _, count := range []struct {
+		n    int
+		verb string
+	}{
+		{report.Summary.Changed, "changed"},
+		{report.Summary.Deleted, "deleted"},
+		{report.Summary.Moved, "moved"},
+	} {
+		if count.n > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", count.n, count.verb))
+		}
+	}
+	msg := fmt.Sprintf("\n[reset][bold]Drift summary:[reset] %s.\n", strings.Join(counts, ", "))
+	_, _ = v.view.streams.Print(v.view.colorize.Color(msg))
+}
+
+func (v *DriftHuman) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+func (v *DriftHuman) HelpPrompt() {
+	v.view.HelpPrompt("drift")
+}
+
+func (v *DriftHuman) Backend() Backend {
+	return &BackendHuman{
+		view: v.view,
+	}
+}
+
+// The DriftJSON implementation renders streaming JSON logs, suitable for
+// integrating with other software.
+type DriftJSON struct {
+	view *JSONView
+}
+
+var _ Drift = (*DriftJSON)(nil)
+
+func (v *DriftJSON) Operation() Operation {
+	return &OperationJSON{view: v.view}
+}
+
+func (v *DriftJSON) Hooks() []tofu.Hook {
+	return []tofu.Hook{
+		newJSONHook(v.view),
+	}
+}
+
+func (v *DriftJSON) DriftReport(report *jsonplan.DriftReport) {
+	msg := "No drift detected"
+	if report.Summary.Total() > 0 {
+		msg = fmt.Sprintf("Drift detected: %d changed, %d deleted, %d moved", report.Summary.Changed, report.Summary.Deleted, report.Summary.Moved)
+	}
+	v.view.log.Info(
+		msg,
+		"type", "drift_report",
+		"drift_report", report,
+	)
+}
+
+func (v *DriftJSON) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+func (v *DriftJSON) HelpPrompt() {
+}
+
+func (v *DriftJSON) Backend() Backend {
+	return &BackendJSON{
+		view: v.view,
+	}
+}
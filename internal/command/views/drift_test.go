@@ -0,0 +1,139 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
+)
+
+func TestDriftReport(t *testing.T) {
+	tests := map[string]struct {
+		report     *jsonplan.DriftReport
+		wantStdout string
+		wantJson   []map[string]any
+	}{
+		"no drift": {
+			report: &jsonplan.DriftReport{
+				FormatVersion: jsonplan.DriftFormatVersion,
+				Resources:     []jsonplan.DriftedResource{},
+			},
+			wantStdout: "\nNo drift detected. The remote objects match the OpenTofu state.\n",
+			wantJson: []map[string]any{
+				{
+					"@level":   "info",
+					"@message": "No drift detected",
+					"@module":  "tofu.ui",
+					"type":     "drift_report",
+					"drift_report": map[string]any{
+						"format_version": "1.0",
+						"summary": map[string]any{
+							"changed": float64(0),
+							"deleted": float64(0),
+							"moved":   float64(0),
+						},
+						"resources": []any{},
+					},
+				},
+			},
+		},
+		"drift": {
+			report: &jsonplan.DriftReport{
+				FormatVersion: jsonplan.DriftFormatVersion,
+				Summary:       jsonplan.DriftSummary{Changed: 1, Deleted: 1},
+				Resources: []jsonplan.DriftedResource{
+					{
+						Address:      "test_instance.foo",
+						Type:         "test_instance",
+						ProviderName: "registry.opentofu.org/hashicorp/test",
+						Drift:        jsonplan.DriftChanged,
+						Attributes: []jsonplan.DriftedAttribute{
+							{Path: "ami", Before: json.RawMessage(`"bar"`), After: json.RawMessage(`"baz"`)},
+							{Path: "password", Before: json.RawMessage(`null`), After: json.RawMessage(`null`), Sensitive: true},
+						},
+					},
+					{
+						Address:      "test_instance.bar",
+						Type:         "test_instance",
+						ProviderName: "registry.opentofu.org/hashicorp/test",
+						Drift:        jsonplan.DriftDeleted,
+					},
+				},
+			},
+			wantStdout: `
+Drift detected:
+
+  # test_instance.foo has changed outside of OpenTofu
+      ami: "bar" -> "baz"
+      password: (sensitive value)
+
+  # test_instance.bar has been deleted outside of OpenTofu
+
+Drift summary: 1 changed, 1 deleted.
+`,
+			wantJson: []map[string]any{
+				{
+					"@level":   "info",
+					"@message": "Drift detected: 1 changed, 1 deleted, 0 moved",
+					"@module":  "tofu.ui",
+					"type":     "drift_report",
+					"drift_report": map[string]any{
+						"format_version": "1.0",
+						"summary": map[string]any{
+							"changed": float64(1),
+							"deleted": float64(1),
+							"moved":   float64(0),
+						},
+						"resources": []any{
+							map[string]any{
+								"address":       "test_instance.foo",
+								"type":          "test_instance",
+								"provider_name": "registry.opentofu.org/hashicorp/test",
+								"drift":         "changed",
+								"attributes": []any{
+									map[string]any{"path": "ami", "before": "bar", "after": "baz"},
+									map[string]any{"path": "password", "before": nil, "after": nil, "sensitive": true},
+								},
+							},
+							map[string]any{
+								"address":       "test_instance.bar",
+								"type":          "test_instance",
+								"provider_name": "registry.opentofu.org/hashicorp/test",
+								"drift":         "deleted",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Run("human", func(t *testing.T) {
+				view, done := testView(t)
+				NewDrift(arguments.ViewOptions{ViewType: arguments.ViewHuman}, view).DriftReport(tc.report)
+				output := done(t)
+				if diff := cmp.Diff(tc.wantStdout, output.Stdout()); diff != "" {
+					t.Errorf("invalid stdout (-want, +got):\n%s", diff)
+				}
+			})
+			t.Run("json", func(t *testing.T) {
+				view, done := testView(t)
+				NewDrift(arguments.ViewOptions{ViewType: arguments.ViewJSON}, view).DriftReport(tc.report)
+				output := done(t)
+				if output.Stderr() != "" {
+					t.Errorf("expected no stderr but got:\n%s", output.Stderr())
+				}
+				testJSONViewOutputEquals(t, output.Stdout(), tc.wantJson)
+			})
+		})
+	}
+}
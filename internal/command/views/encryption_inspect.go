@@ -0,0 +1,155 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+type EncryptionInspect interface {
+	Diagnostics(diags tfdiags.Diagnostics)
+
+	// Inspection reports how the state or plan file at the given path is
+	// encrypted. The kind is either "state" or "plan".
+	Inspection(path string, kind string, inspection *encryption.Inspection)
+}
+
+// NewEncryptionInspect returns an initialized EncryptionInspect implementation for the given ViewType.
+func NewEncryptionInspect(args arguments.ViewOptions, view *View) EncryptionInspect {
+	var ret EncryptionInspect
+	switch args.ViewType {
+	case arguments.ViewJSON:
+		ret = &EncryptionInspectJSON{view: NewJSONView(view, nil)}
+	case arguments.ViewHuman:
+		ret = &EncryptionInspectHuman{view: view}
+	default:
+		panic(fmt.Sprintf("unknown view type %v", args.ViewType))
+	}
+
+	if args.JSONInto != nil {
+		ret = &EncryptionInspectMulti{ret, &EncryptionInspectJSON{view: NewJSONView(view, args.JSONInto)}}
+	}
+	return ret
+}
+
+type EncryptionInspectMulti []EncryptionInspect
+
+var _ EncryptionInspect = (EncryptionInspectMulti)(nil)
+
+func (m EncryptionInspectMulti) Diagnostics(diags tfdiags.Diagnostics) {
+	for _, o := range m {
+		o.Diagnostics(diags)
+	}
+}
+
+func (m EncryptionInspectMulti) Inspection(path string, kind string, inspection *encryption.Inspection) {
+	for _, o := range m {
+		o.Inspection(path, kind, inspection)
+	}
+}
+
+type EncryptionInspectHuman struct {
+	view *View
+}
+
+var _ EncryptionInspect = (*EncryptionInspectHuman)(nil)
+
+func (v *EncryptionInspectHuman) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+func (v *EncryptionInspectHuman) Inspection(path string, kind string, inspection *encryption.Inspection) {
+	if !inspection.Encrypted {
+		_, _ = v.view.streams.Printf("The %s file %q is not encrypted.\n", kind, path)
+	} else {
+		_, _ = v.view.streams.Printf("The %s file %q is encrypted.\n\n", kind, path)
+		_, _ = v.view.streams.Printf("Encryption version: %s\n", inspection.Version)
+		if len(inspection.KeyProviders) == 0 {
+			_, _ = v.view.streams.Println("Key provider metadata: none")
+		} else {
+			_, _ = v.view.streams.Println("Key provider metadata:")
+			for _, kp := range inspection.KeyProviders {
+				_, _ = v.view.streams.Printf("  %s: %s\n", kp.Key, kp.Metadata)
+			}
+		}
+	}
+	_, _ = v.view.streams.Println()
+
+	var msg string
+	switch inspection.Status {
+	case encryption.StatusSatisfied:
+		if inspection.Method == "" {
+			msg = fmt.Sprintf("[reset][green]Encryption is not configured for %s files.[reset]\n", kind)
+		} else {
+			msg = fmt.Sprintf("[reset][green]It can be read with the primary encryption method, %s.[reset]\n", inspection.Method)
+		}
+	case encryption.StatusMigration:
+		msg = fmt.Sprintf("[reset][bold][yellow]It can only be read with the fallback encryption method, %s.[reset]\n", inspection.Method)
+		if kind == "state" {
+			msg += `Run "tofu state rekey" to re-encrypt the state with the primary method.` + "\n"
+		} else {
+			msg += "Create a new plan to have it encrypted with the primary method.\n"
+		}
+	default:
+		msg = "[reset][bold][red]It cannot be read with the current encryption configuration.[reset]\n"
+	}
+	_, _ = v.view.streams.Print(v.view.colorize.Color(msg))
+}
+
+type EncryptionInspectJSON struct {
+	view *JSONView
+}
+
+var _ EncryptionInspect = (*EncryptionInspectJSON)(nil)
+
+func (v *EncryptionInspectJSON) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+func (v *EncryptionInspectJSON) Inspection(path string, kind string, inspection *encryption.Inspection) {
+	type keyProvider struct {
+		Key      string          `json:"key"`
+		Metadata json.RawMessage `json:"metadata,omitempty"`
+	}
+	keyProviders := make([]keyProvider, 0, len(inspection.KeyProviders))
+	for _, kp := range inspection.KeyProviders {
+		keyProviders = append(keyProviders, keyProvider{Key: kp.Key, Metadata: kp.Metadata})
+	}
+
+	msg := fmt.Sprintf("The %s file %q is not encrypted", kind, path)
+	if inspection.Encrypted {
+		msg = fmt.Sprintf("The %s file %q is encrypted", kind, path)
+	}
+	v.view.log.Info(
+		msg,
+		"type", "encryption_inspection",
+		"path", path,
+		"file_type", kind,
+		"encrypted", inspection.Encrypted,
+		"encryption_version", inspection.Version,
+		"key_providers", keyProviders,
+		"method", inspection.Method,
+		"status", encryptionStatusString(inspection.Status),
+	)
+}
+
+// encryptionStatusString returns the name of the given status, as used in the
+// machine-readable output.
+func encryptionStatusString(status encryption.EncryptionStatus) string {
+	switch status {
+	case encryption.StatusSatisfied:
+		return "satisfied"
+	case encryption.StatusMigration:
+		return "migration"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/encryption"
+)
+
+func TestEncryptionInspectViews(t *testing.T) {
+	tests := map[string]struct {
+		viewCall   func(v EncryptionInspect)
+		wantJson   []map[string]any
+		wantStdout string
+		wantStderr string
+	}{
+		"encrypted with the fallback method": {
+			viewCall: func(v EncryptionInspect) {
+				v.Inspection("terraform.tfstate", "state", &encryption.Inspection{
+					Encrypted: true,
+					Version:   "v0",
+					KeyProviders: []encryption.InspectedKeyProvider{
+						{Key: "key_provider.pbkdf2.old", Metadata: []byte(`{"iterations":600000}`)},
+					},
+					Method: "method.aes_gcm.old",
+					Status: encryption.StatusMigration,
+				})
+			},
+			wantStdout: `The state file "terraform.tfstate" is encrypted.
+
+Encryption version: v0
+Key provider metadata:
+  key_provider.pbkdf2.old: {"iterations":600000}
+
+It can only be read with the fallback encryption method, method.aes_gcm.old.
+Run "tofu state rekey" to re-encrypt the state with the primary method.
+`,
+			wantJson: []map[string]any{
+				{
+					"@level":             "info",
+					"@message":           `The state file "terraform.tfstate" is encrypted`,
+					"@module":            "tofu.ui",
+					"type":               "encryption_inspection",
+					"path":               "terraform.tfstate",
+					"file_type":          "state",
+					"encrypted":          true,
+					"encryption_version": "v0",
+					"key_providers": []any{
+						map[string]any{
+							"key":      "key_provider.pbkdf2.old",
+							"metadata": map[string]any{"iterations": float64(600000)},
+						},
+					},
+					"method": "method.aes_gcm.old",
+					"status": "migration",
+				},
+			},
+		},
+		"not encrypted": {
+			viewCall: func(v EncryptionInspect) {
+				v.Inspection("tfplan", "plan", &encryption.Inspection{
+					Status: encryption.StatusSatisfied,
+				})
+			},
+			wantStdout: `The plan file "tfplan" is not encrypted.
+
+Encryption is not configured for plan files.
+`,
+			wantJson: []map[string]any{
+				{
+					"@level":             "info",
+					"@message":           `The plan file "tfplan" is not encrypted`,
+					"@module":            "tofu.ui",
+					"type":               "encryption_inspection",
+					"path":               "tfplan",
+					"file_type":          "plan",
+					"encrypted":          false,
+					"encryption_version": "",
+					"key_providers":      []any{},
+					"method":             "",
+					"status":             "satisfied",
+				},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEncryptionInspectHuman(t, tc.viewCall, tc.wantStdout, tc.wantStderr)
+			testEncryptionInspectJson(t, tc.viewCall, tc.wantJson)
+			testEncryptionInspectMulti(t, tc.viewCall, tc.wantStdout, tc.wantStderr, tc.wantJson)
+		})
+	}
+}
+
+func testEncryptionInspectHuman(t *testing.T, call func(v EncryptionInspect), wantStdout, wantStderr string) {
+	view, done := testView(t)
+	getView := NewEncryptionInspect(arguments.ViewOptions{ViewType: arguments.ViewHuman}, view)
+	call(getView)
+	output := done(t)
+	if diff := cmp.Diff(wantStderr, output.Stderr()); diff != "" {
+		t.Errorf("invalid stderr (-want, +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(wantStdout, output.Stdout()); diff != "" {
+		t.Errorf("invalid stdout (-want, +got):\n%s", diff)
+	}
+}
+
+func testEncryptionInspectJson(t *testing.T, call func(v EncryptionInspect), want []map[string]interface{}) {
+	view, done := testView(t)
+	getView := NewEncryptionInspect(arguments.ViewOptions{ViewType: arguments.ViewJSON}, view)
+	call(getView)
+	output := done(t)
+	if output.Stderr() != "" {
+		t.Errorf("expected no stderr but got:\n%s", output.Stderr())
+	}
+
+	testJSONViewOutputEquals(t, output.Stdout(), want)
+}
+
+func testEncryptionInspectMulti(t *testing.T, call func(v EncryptionInspect), wantStdout string, wantStderr string, want []map[string]interface{}) {
+	jsonInto, err := os.CreateTemp(t.TempDir(), "json-into-*")
+	if err != nil {
+		t.Fatalf("failed to create the file to write json content into: %s", err)
+	}
+	view, done := testView(t)
+	getView := NewEncryptionInspect(arguments.ViewOptions{ViewType: arguments.ViewHuman, JSONInto: jsonInto}, view)
+	call(getView)
+	{
+		if err := jsonInto.Close(); err != nil {
+			t.Fatalf("failed to close the jsonInto file: %s", err)
+		}
+		// check the fileInto content
+		fileContent, err := os.ReadFile(jsonInto.Name())
+		if err != nil {
+			t.Fatalf("failed to read the file content with the json output: %s", err)
+		}
+		testJSONViewOutputEquals(t, string(fileContent), want)
+	}
+	{
+		output := done(t)
+		if diff := cmp.Diff(wantStderr, output.Stderr()); diff != "" {
+			t.Errorf("invalid stderr (-want, +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(wantStdout, output.Stdout()); diff != "" {
+			t.Errorf("invalid stdout (-want, +got):\n%s", diff)
+		}
+	}
+}
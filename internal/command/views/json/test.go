@@ -49,6 +49,56 @@ type TestFatalInterrupt struct {
 	Planned []string                        `json:"planned,omitempty"`
 }
 
+type TestCoverage struct {
+	Totals map[string]TestCoverageTotals `json:"totals"`
+	Items  []TestCoverageItem            `json:"items"`
+}
+
+type TestCoverageTotals struct {
+	Total   int `json:"total"`
+	Covered int `json:"covered"`
+}
+
+type TestCoverageItem struct {
+	Kind    string `json:"kind"`
+	Address string `json:"address"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Covered bool   `json:"covered"`
+	Planned int    `json:"planned"`
+	Applied int    `json:"applied"`
+	Passed  int    `json:"passed"`
+	Failed  int    `json:"failed"`
+}
+
+func ToTestCoverage(coverage *moduletest.Coverage) TestCoverage {
+	ret := TestCoverage{
+		Totals: make(map[string]TestCoverageTotals),
+		Items:  make([]TestCoverageItem, 0, len(coverage.Items)),
+	}
+	for _, kind := range moduletest.CoverageKinds {
+		totals := coverage.Totals(kind)
+		ret.Totals[string(kind)] = TestCoverageTotals{
+			Total:   totals.Total,
+			Covered: totals.Covered,
+		}
+	}
+	for _, item := range coverage.Items {
+		ret.Items = append(ret.Items, TestCoverageItem{
+			Kind:    string(item.Kind),
+			Address: item.Address,
+			File:    item.Range.Filename,
+			Line:    item.Range.Start.Line,
+			Covered: item.Covered(),
+			Planned: item.Planned,
+			Applied: item.Applied,
+			Passed:  item.Passed,
+			Failed:  item.Failed,
+		})
+	}
+	return ret
+}
+
 func ToTestStatus(status moduletest.Status) TestStatus {
 	return TestStatus(strings.ToLower(status.String()))
 }
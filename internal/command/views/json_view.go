@@ -32,6 +32,15 @@ const JSON_UI_VERSION = "1.2"
 // multiple version log entries, which might disrupt any other external parsing tool that
 // might rely on the machine readable output.
 func NewJSONView(view *View, out *os.File) *JSONView {
+	jv := newJSONViewWithoutVersion(view, out)
+	jv.Version()
+	return jv
+}
+
+// newJSONViewWithoutVersion creates a new JSONView like NewJSONView, but
+// without logging the version entry. This is for the rare views that are
+// created alongside a root level view that has already logged it.
+func newJSONViewWithoutVersion(view *View, out *os.File) *JSONView {
 	if out == nil {
 		out = view.streams.Stdout.File
 	}
@@ -41,12 +50,10 @@ func NewJSONView(view *View, out *os.File) *JSONView {
 		JSONFormat:         true,
 		JSONEscapeDisabled: true,
 	})
-	jv := &JSONView{
+	return &JSONView{
 		log:  log,
 		view: view,
 	}
-	jv.Version()
-	return jv
 }
 
 type JSONView struct {
@@ -0,0 +1,204 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+type LockStatus interface {
+	Diagnostics(diags tfdiags.Diagnostics)
+	LockingDisabledForBackend()
+	LockInspectionUnsupported()
+	LocalStateLockInspectionUnsupported()
+
+	// NotLocked reports that nobody currently holds the lock on the state of
+	// the given workspace.
+	NotLocked(workspace string)
+
+	// Locked reports the lock currently held on the state of the given
+	// workspace.
+	Locked(workspace string, info *statemgr.LockInfo)
+
+	// Backend returns the non-command view that contains methods to provide
+	// progress output for the backend operations.
+	Backend() Backend
+}
+
+// NewLockStatus returns an initialized LockStatus implementation for the given ViewType.
+func NewLockStatus(args arguments.ViewOptions, view *View) LockStatus {
+	var ret LockStatus
+	switch args.ViewType {
+	case arguments.ViewJSON:
+		ret = &LockStatusJSON{view: NewJSONView(view, nil)}
+	case arguments.ViewHuman:
+		ret = &LockStatusHuman{view: view}
+	default:
+		panic(fmt.Sprintf("unknown view type %v", args.ViewType))
+	}
+
+	if args.JSONInto != nil {
+		ret = &LockStatusMulti{ret, &LockStatusJSON{view: NewJSONView(view, args.JSONInto)}}
+	}
+	return ret
+}
+
+type LockStatusMulti []LockStatus
+
+var _ LockStatus = (LockStatusMulti)(nil)
+
+func (m LockStatusMulti) Diagnostics(diags tfdiags.Diagnostics) {
+	for _, o := range m {
+		o.Diagnostics(diags)
+	}
+}
+
+func (m LockStatusMulti) LockingDisabledForBackend() {
+	for _, o := range m {
+		o.LockingDisabledForBackend()
+	}
+}
+
+func (m LockStatusMulti) LockInspectionUnsupported() {
+	for _, o := range m {
+		o.LockInspectionUnsupported()
+	}
+}
+
+func (m LockStatusMulti) LocalStateLockInspectionUnsupported() {
+	for _, o := range m {
+		o.LocalStateLockInspectionUnsupported()
+	}
+}
+
+func (m LockStatusMulti) NotLocked(workspace string) {
+	for _, o := range m {
+		o.NotLocked(workspace)
+	}
+}
+
+func (m LockStatusMulti) Locked(workspace string, info *statemgr.LockInfo) {
+	for _, o := range m {
+		o.Locked(workspace, info)
+	}
+}
+
+func (m LockStatusMulti) Backend() Backend {
+	ret := make([]Backend, len(m))
+	for i, v := range m {
+		ret[i] = v.Backend()
+	}
+	return BackendMulti(ret)
+}
+
+type LockStatusHuman struct {
+	view *View
+}
+
+var _ LockStatus = (*LockStatusHuman)(nil)
+
+func (v *LockStatusHuman) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+func (v *LockStatusHuman) LockingDisabledForBackend() {
+	v.Diagnostics(tfdiags.Diagnostics{diagLockingDisabledBackend})
+}
+
+func (v *LockStatusHuman) LockInspectionUnsupported() {
+	v.Diagnostics(tfdiags.Diagnostics{diagLockInspectionUnsupported})
+}
+
+func (v *LockStatusHuman) LocalStateLockInspectionUnsupported() {
+	v.Diagnostics(tfdiags.Diagnostics{diagLocalStateLockInspectionUnsupported})
+}
+
+func (v *LockStatusHuman) NotLocked(workspace string) {
+	_, _ = v.view.streams.Printf("The state for workspace %q is not locked.\n", workspace)
+}
+
+func (v *LockStatusHuman) Locked(workspace string, info *statemgr.LockInfo) {
+	msg := fmt.Sprintf("[reset][bold][yellow]The state for workspace %q is locked.[reset]\n\n", workspace)
+	_, _ = v.view.streams.Print(v.view.colorize.Color(msg))
+	_, _ = v.view.streams.Print(info.String())
+	if info.ID != "" {
+		_, _ = v.view.streams.Printf(`
+If you are sure that the lock is no longer in use, for example because the
+process holding it was interrupted, you can release it by running:
+  tofu force-unlock %s
+`, info.ID)
+	}
+}
+
+func (v *LockStatusHuman) Backend() Backend {
+	return &BackendHuman{
+		view: v.view,
+	}
+}
+
+type LockStatusJSON struct {
+	view *JSONView
+}
+
+var _ LockStatus = (*LockStatusJSON)(nil)
+
+func (v *LockStatusJSON) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+func (v *LockStatusJSON) LockingDisabledForBackend() {
+	v.Diagnostics(tfdiags.Diagnostics{diagLockingDisabledBackend})
+}
+
+func (v *LockStatusJSON) LockInspectionUnsupported() {
+	v.Diagnostics(tfdiags.Diagnostics{diagLockInspectionUnsupported})
+}
+
+func (v *LockStatusJSON) LocalStateLockInspectionUnsupported() {
+	v.Diagnostics(tfdiags.Diagnostics{diagLocalStateLockInspectionUnsupported})
+}
+
+func (v *LockStatusJSON) NotLocked(workspace string) {
+	v.view.log.Info(
+		fmt.Sprintf("The state for workspace %q is not locked", workspace),
+		"type", "lock_status",
+		"workspace", workspace,
+		"locked", false,
+	)
+}
+
+func (v *LockStatusJSON) Locked(workspace string, info *statemgr.LockInfo) {
+	v.view.log.Info(
+		fmt.Sprintf("The state for workspace %q is locked", workspace),
+		"type", "lock_status",
+		"workspace", workspace,
+		"locked", true,
+		"lock", info,
+	)
+}
+
+func (v *LockStatusJSON) Backend() Backend {
+	return &BackendJSON{
+		view: v.view,
+	}
+}
+
+var (
+	diagLockInspectionUnsupported = tfdiags.Sourceless(
+		tfdiags.Error,
+		"Lock inspection unsupported",
+		"The configured backend cannot report on the current lock without acquiring it.",
+	)
+	diagLocalStateLockInspectionUnsupported = tfdiags.Sourceless(
+		tfdiags.Error,
+		"Lock inspection unsupported",
+		"Local state files are locked by the operating system, which releases the lock automatically when the process holding it exits, so there is no lock information to report on.",
+	)
+)
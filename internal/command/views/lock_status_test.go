@@ -0,0 +1,176 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+func TestLockStatusViews(t *testing.T) {
+	info := &statemgr.LockInfo{
+		ID:        "fake-lock-id",
+		Operation: "OperationTypeApply",
+		Who:       "ci@runner-1",
+		Version:   "1.10.0",
+		Created:   time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC),
+		Path:      "bucket/terraform.tfstate",
+	}
+
+	tests := map[string]struct {
+		viewCall   func(v LockStatus)
+		wantJson   []map[string]any
+		wantStdout string
+		wantStderr string
+	}{
+		"not locked": {
+			viewCall: func(v LockStatus) {
+				v.NotLocked("default")
+			},
+			wantStdout: withNewline(`The state for workspace "default" is not locked.`),
+			wantJson: []map[string]any{
+				{
+					"@level":    "info",
+					"@message":  `The state for workspace "default" is not locked`,
+					"@module":   "tofu.ui",
+					"type":      "lock_status",
+					"workspace": "default",
+					"locked":    false,
+				},
+			},
+		},
+		"locked": {
+			viewCall: func(v LockStatus) {
+				v.Locked("default", info)
+			},
+			wantStdout: `The state for workspace "default" is locked.
+
+Lock Info:
+  ID:        fake-lock-id
+  Path:      bucket/terraform.tfstate
+  Operation: OperationTypeApply
+  Who:       ci@runner-1
+  Version:   1.10.0
+  Created:   2026-10-16 09:30:00 +0000 UTC
+  Info:      
+
+If you are sure that the lock is no longer in use, for example because the
+process holding it was interrupted, you can release it by running:
+  tofu force-unlock fake-lock-id
+`,
+			wantJson: []map[string]any{
+				{
+					"@level":    "info",
+					"@message":  `The state for workspace "default" is locked`,
+					"@module":   "tofu.ui",
+					"type":      "lock_status",
+					"workspace": "default",
+					"locked":    true,
+					"lock": map[string]any{
+						"ID":        "fake-lock-id",
+						"Operation": "OperationTypeApply",
+						"Info":      "",
+						"Who":       "ci@runner-1",
+						"Version":   "1.10.0",
+						"Created":   "2026-10-16T09:30:00Z",
+						"Path":      "bucket/terraform.tfstate",
+					},
+				},
+			},
+		},
+		"lock inspection unsupported": {
+			viewCall: func(v LockStatus) {
+				v.LockInspectionUnsupported()
+			},
+			wantStderr: `
+Error: Lock inspection unsupported
+
+The configured backend cannot report on the current lock without acquiring
+it.
+`,
+			wantJson: []map[string]any{
+				{
+					"@level":   "error",
+					"@message": "Error: Lock inspection unsupported",
+					"@module":  "tofu.ui",
+					"diagnostic": map[string]any{
+						"detail":   "The configured backend cannot report on the current lock without acquiring it.",
+						"severity": "error",
+						"summary":  "Lock inspection unsupported",
+					},
+					"type": "diagnostic",
+				},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			testLockStatusHuman(t, tc.viewCall, tc.wantStdout, tc.wantStderr)
+			testLockStatusJson(t, tc.viewCall, tc.wantJson)
+			testLockStatusMulti(t, tc.viewCall, tc.wantStdout, tc.wantStderr, tc.wantJson)
+		})
+	}
+}
+
+func testLockStatusHuman(t *testing.T, call func(v LockStatus), wantStdout, wantStderr string) {
+	view, done := testView(t)
+	getView := NewLockStatus(arguments.ViewOptions{ViewType: arguments.ViewHuman}, view)
+	call(getView)
+	output := done(t)
+	if diff := cmp.Diff(wantStderr, output.Stderr()); diff != "" {
+		t.Errorf("invalid stderr (-want, +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(wantStdout, output.Stdout()); diff != "" {
+		t.Errorf("invalid stdout (-want, +got):\n%s", diff)
+	}
+}
+
+func testLockStatusJson(t *testing.T, call func(v LockStatus), want []map[string]interface{}) {
+	view, done := testView(t)
+	getView := NewLockStatus(arguments.ViewOptions{ViewType: arguments.ViewJSON}, view)
+	call(getView)
+	output := done(t)
+	if output.Stderr() != "" {
+		t.Errorf("expected no stderr but got:\n%s", output.Stderr())
+	}
+
+	testJSONViewOutputEquals(t, output.Stdout(), want)
+}
+
+func testLockStatusMulti(t *testing.T, call func(v LockStatus), wantStdout string, wantStderr string, want []map[string]interface{}) {
+	jsonInto, err := os.CreateTemp(t.TempDir(), "json-into-*")
+	if err != nil {
+		t.Fatalf("failed to create the file to write json content into: %s", err)
+	}
+	view, done := testView(t)
+	getView := NewLockStatus(arguments.ViewOptions{ViewType: arguments.ViewHuman, JSONInto: jsonInto}, view)
+	call(getView)
+	{
+		if err := jsonInto.Close(); err != nil {
+			t.Fatalf("failed to close the jsonInto file: %s", err)
+		}
+		// check the fileInto content
+		fileContent, err := os.ReadFile(jsonInto.Name())
+		if err != nil {
+			t.Fatalf("failed to read the file content with the json output: %s", err)
+		}
+		testJSONViewOutputEquals(t, string(fileContent), want)
+	}
+	{
+		output := done(t)
+		if diff := cmp.Diff(wantStderr, output.Stderr()); diff != "" {
+			t.Errorf("invalid stderr (-want, +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(wantStdout, output.Stdout()); diff != "" {
+			t.Errorf("invalid stdout (-want, +got):\n%s", diff)
+		}
+	}
+}
@@ -7,16 +7,20 @@ package views
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/jsonformat"
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
 	"github.com/opentofu/opentofu/internal/command/jsonprovider"
 	"github.com/opentofu/opentofu/internal/command/jsonstate"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tofu"
 )
@@ -29,6 +33,9 @@ type State interface {
 	StateLoadingFailure(baseError string)
 	StateSavingError(baseError string)
 
+	// `tofu state diff` specific
+	StateDiff(from, to *states.State, schemas *tofu.Schemas) int
+
 	// `tofu state list` specific
 	StateListAddr(resAddr addrs.AbsResourceInstance)
 
@@ -38,6 +45,10 @@ type State interface {
 	DryRunMovedStatus(moved int)
 	MoveFinalStatus(moved int)
 
+	// `tofu state history` specific
+	StateHistoryUnsupported()
+	StateHistory(versions []*statemgr.SnapshotVersion)
+
 	// `tofu state pull` specific
 	PrintPulledState(state string)
 
@@ -47,6 +58,13 @@ type State interface {
 	ReplaceProviderCancelled()
 	ProviderReplaced(forResources int)
 
+	// `tofu state rollback` specific
+	StateRolledBack(versionID string, fromSerial, toSerial uint64)
+
+	// `tofu state rekey` specific
+	StateRekeyed(rekeyed bool)
+	StateRekeyEmpty()
+
 	// `tofu state rm` specific
 	ResourceRemoveStatus(dryRun bool, target string)
 	DryRunRemovedStatus(removed int)
@@ -109,6 +127,14 @@ func (m StateMulti) StateSavingError(baseError string) {
 	}
 }
 
+func (m StateMulti) StateDiff(from, to *states.State, schemas *tofu.Schemas) int {
+	var ret int
+	for _, o := range m {
+		ret = max(ret, o.StateDiff(from, to, schemas))
+	}
+	return ret
+}
+
 func (m StateMulti) StateListAddr(resAddr addrs.AbsResourceInstance) {
 	for _, o := range m {
 		o.StateListAddr(resAddr)
@@ -139,6 +165,18 @@ func (m StateMulti) MoveFinalStatus(moved int) {
 	}
 }
 
+func (m StateMulti) StateHistoryUnsupported() {
+	for _, o := range m {
+		o.StateHistoryUnsupported()
+	}
+}
+
+func (m StateMulti) StateHistory(versions []*statemgr.SnapshotVersion) {
+	for _, o := range m {
+		o.StateHistory(versions)
+	}
+}
+
 func (m StateMulti) PrintPulledState(state string) {
 	for _, o := range m {
 		o.PrintPulledState(state)
@@ -169,6 +207,24 @@ func (m StateMulti) ProviderReplaced(forResources int) {
 	}
 }
 
+func (m StateMulti) StateRolledBack(versionID string, fromSerial, toSerial uint64) {
+	for _, o := range m {
+		o.StateRolledBack(versionID, fromSerial, toSerial)
+	}
+}
+
+func (m StateMulti) StateRekeyed(rekeyed bool) {
+	for _, o := range m {
+		o.StateRekeyed(rekeyed)
+	}
+}
+
+func (m StateMulti) StateRekeyEmpty() {
+	for _, o := range m {
+		o.StateRekeyEmpty()
+	}
+}
+
 func (m StateMulti) ResourceRemoveStatus(dryRun bool, target string) {
 	for _, o := range m {
 		o.ResourceRemoveStatus(dryRun, target)
@@ -251,6 +307,32 @@ func (v *StateHuman) StateSavingError(baseError string) {
 	)})
 }
 
+func (v *StateHuman) StateDiff(from, to *states.State, schemas *tofu.Schemas) int {
+	renderer := jsonformat.Renderer{
+		Colorize:            v.view.colorize,
+		Streams:             v.view.streams,
+		RunningInAutomation: v.view.runningInAutomation,
+		ShowSensitive:       v.view.showSensitive,
+	}
+
+	outputs, changes, err := jsonplan.MarshalStateDiffForRenderer(from, to, schemas)
+	if err != nil {
+		v.Diagnostics(tfdiags.Diagnostics{diagErrStateDiff(err)})
+		return 1
+	}
+
+	renderer.RenderHumanStateDiff(jsonformat.StateDiff{
+		PlanFormatVersion:     jsonplan.FormatVersion,
+		OutputChanges:         outputs,
+		ResourceChanges:       changes,
+		ProviderFormatVersion: jsonprovider.FormatVersion,
+		// The renderer needs a schema for every resource type, including
+		// those whose provider schema is unavailable.
+		ProviderSchemas: jsonprovider.MarshalForRenderer(jsonplan.StateDiffSchemas(from, to, schemas)),
+	})
+	return 0
+}
+
 func (v *StateHuman) StateListAddr(resAddr addrs.AbsResourceInstance) {
 	_, _ = v.view.streams.Println(resAddr.String())
 }
@@ -281,6 +363,38 @@ func (v *StateHuman) MoveFinalStatus(moved int) {
 	_, _ = v.view.streams.Println(fmt.Sprintf("Successfully moved %d object(s).", moved))
 }
 
+func (v *StateHuman) StateHistoryUnsupported() {
+	v.Diagnostics(tfdiags.Diagnostics{diagErrStateHistoryUnsupported})
+}
+
+func (v *StateHuman) StateHistory(versions []*statemgr.SnapshotVersion) {
+	if len(versions) == 0 {
+		_, _ = v.view.streams.Println("No state snapshots found.")
+		return
+	}
+
+	idWidth := 0
+	for _, version := range versions {
+		idWidth = max(idWidth, len(version.ID))
+	}
+	for _, version := range versions {
+		line := fmt.Sprintf("%-*s  ", idWidth, version.ID)
+		if version.Lineage != "" {
+			// Only set when the snapshot itself was read
+			line += fmt.Sprintf("serial=%d  lineage=%s  ", version.Serial, version.Lineage)
+		}
+		line += fmt.Sprintf(
+			"created=%s  who=%s",
+			stateHistoryTime(version.Created),
+			stateHistoryValue(version.Who),
+		)
+		if version.Latest {
+			line += v.view.colorize.Color("  [bold](latest)[reset]")
+		}
+		_, _ = v.view.streams.Println(line)
+	}
+}
+
 func (v *StateHuman) PrintPulledState(state string) {
 	_, _ = v.view.streams.Println(state)
 }
@@ -311,6 +425,22 @@ func (v *StateHuman) ProviderReplaced(forResources int) {
 	_, _ = v.view.streams.Println(fmt.Sprintf("Successfully replaced provider for %d resources.", forResources))
 }
 
+func (v *StateHuman) StateRolledBack(versionID string, fromSerial, toSerial uint64) {
+	_, _ = v.view.streams.Println(fmt.Sprintf("Restored state snapshot %q (serial %d) as serial %d.", versionID, fromSerial, toSerial))
+}
+
+func (v *StateHuman) StateRekeyed(rekeyed bool) {
+	if !rekeyed {
+		_, _ = v.view.streams.Println("The state is already encrypted with the primary encryption method.")
+		return
+	}
+	_, _ = v.view.streams.Println("Re-encrypted the state with the primary encryption method.")
+}
+
+func (v *StateHuman) StateRekeyEmpty() {
+	_, _ = v.view.streams.Println("There is no state to re-encrypt.")
+}
+
 func (v *StateHuman) ResourceRemoveStatus(dryRun bool, target string) {
 	if dryRun {
 		_, _ = v.view.streams.Println(fmt.Sprintf("Would remove %s", target))
@@ -421,6 +551,30 @@ func (v *StateJSON) StateSavingError(baseError string) {
 	)})
 }
 
+func (v *StateJSON) StateDiff(from, to *states.State, schemas *tofu.Schemas) int {
+	outputs, changes, err := jsonplan.MarshalStateDiffForRenderer(from, to, schemas)
+	if err != nil {
+		v.Diagnostics(tfdiags.Diagnostics{diagErrStateDiff(err)})
+		return 1
+	}
+
+	if changes == nil {
+		// Consumers can rely on this always being an array
+		changes = []jsonplan.ResourceChange{}
+	}
+	rawDiff, err := json.Marshal(stateDiffJSON{
+		FormatVersion:   jsonplan.FormatVersion,
+		ResourceChanges: changes,
+		OutputChanges:   outputs,
+	})
+	if err != nil {
+		v.Diagnostics(tfdiags.Diagnostics{diagErrStateDiff(err)})
+		return 1
+	}
+	_, _ = fmt.Fprintln(v.output, string(rawDiff))
+	return 0
+}
+
 func (v *StateJSON) StateListAddr(resAddr addrs.AbsResourceInstance) {
 	v.view.log.Info(resAddr.String(), "type", "resource_address")
 }
@@ -451,6 +605,33 @@ func (v *StateJSON) MoveFinalStatus(moved int) {
 	v.view.Info(fmt.Sprintf("Successfully moved %d object(s)", moved))
 }
 
+func (v *StateJSON) StateHistoryUnsupported() {
+	v.Diagnostics(tfdiags.Diagnostics{diagErrStateHistoryUnsupported})
+}
+
+func (v *StateJSON) StateHistory(versions []*statemgr.SnapshotVersion) {
+	if len(versions) == 0 {
+		v.view.Info("No state snapshots found")
+		return
+	}
+	for _, version := range versions {
+		args := []any{
+			"type", "state_version",
+			"version_id", version.ID,
+		}
+		if version.Lineage != "" {
+			// Only set when the snapshot itself was read
+			args = append(args, "serial", version.Serial, "lineage", version.Lineage)
+		}
+		args = append(args,
+			"created", stateHistoryTime(version.Created),
+			"who", version.Who,
+			"latest", version.Latest,
+		)
+		v.view.log.Info(fmt.Sprintf("State snapshot %s", version.ID), args...)
+	}
+}
+
 func (v *StateJSON) PrintPulledState(_ string) {
 	v.view.Error("printing the pulled state is not available in the JSON view. The `tofu state pull` should not be configured with the `-json` flag")
 }
@@ -476,6 +657,22 @@ func (v *StateJSON) ProviderReplaced(forResources int) {
 	v.view.Info(fmt.Sprintf("Successfully replaced provider for %d resources", forResources))
 }
 
+func (v *StateJSON) StateRolledBack(versionID string, fromSerial, toSerial uint64) {
+	v.view.Info(fmt.Sprintf("Restored state snapshot %q (serial %d) as serial %d", versionID, fromSerial, toSerial))
+}
+
+func (v *StateJSON) StateRekeyed(rekeyed bool) {
+	if !rekeyed {
+		v.view.Info("The state is already encrypted with the primary encryption method")
+		return
+	}
+	v.view.Info("Re-encrypted the state with the primary encryption method")
+}
+
+func (v *StateJSON) StateRekeyEmpty() {
+	v.view.Info("There is no state to re-encrypt")
+}
+
 func (v *StateJSON) ResourceRemoveStatus(dryRun bool, target string) {
 	if dryRun {
 		v.view.Info(fmt.Sprintf("Would remove %s", target))
@@ -550,6 +747,11 @@ var (
 		"Destination module already exists",
 		`Please ensure your addresses and state paths are valid. No state was persisted. Your existing states are untouched.`,
 	)
+	diagErrStateHistoryUnsupported = tfdiags.Sourceless(
+		tfdiags.Error,
+		"State history is not available",
+		`The current state storage does not retain earlier snapshots of the state. To restore an earlier snapshot, retrieve it from wherever your storage keeps old versions and use "tofu state push" instead.`,
+	)
 	diagErrNoInstanceFound = tfdiags.Sourceless(
 		tfdiags.Error,
 		"No instance found for the given address",
@@ -571,6 +773,41 @@ const (
 Cause: %s`
 )
 
+// stateDiffJSON is the machine-readable representation of the differences
+// between two states, which reuses the change representations of the JSON
+// plan format.
+type stateDiffJSON struct {
+	FormatVersion   string                     `json:"format_version"`
+	ResourceChanges []jsonplan.ResourceChange  `json:"resource_changes"`
+	OutputChanges   map[string]jsonplan.Change `json:"output_changes"`
+}
+
+func diagErrStateDiff(err error) tfdiags.Diagnostic {
+	return tfdiags.Sourceless(
+		tfdiags.Error,
+		"Failed to compare states",
+		fmt.Sprintf("Error while comparing the states: %s.", err),
+	)
+}
+
+// stateHistoryValue returns the given value, or a placeholder if the storage
+// didn't record it.
+func stateHistoryValue(v string) string {
+	if v == "" {
+		return "-"
+	}
+	return v
+}
+
+// stateHistoryTime formats the given snapshot creation time, or returns a
+// placeholder if the storage didn't record it.
+func stateHistoryTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
 const (
 	errParsingAddressHeader      = `Error parsing instance address %q`
 	errParsingAddressDescription = `This command requires that the address references one specific instance. To view the available instances, use "tofu state list". Please modify the address to reference a specific instance.`
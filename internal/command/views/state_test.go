@@ -9,6 +9,7 @@ import (
 	"context"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	regaddr "github.com/opentofu/registry-address/v2"
@@ -20,6 +21,7 @@ import (
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tofu"
 	"github.com/opentofu/opentofu/version"
@@ -228,6 +230,213 @@ persisted. Your existing states are untouched.
 			},
 			wantStdout: withNewline("Successfully moved 1 object(s)."),
 		},
+		"stateHistoryUnsupported": {
+			viewCall: func(state State) {
+				state.StateHistoryUnsupported()
+			},
+			wantJson: []map[string]any{
+				{
+					"@level":   "error",
+					"@message": "Error: State history is not available",
+					"@module":  "tofu.ui",
+					"diagnostic": map[string]any{
+						"detail":   "The current state storage does not retain earlier snapshots of the state. To restore an earlier snapshot, retrieve it from wherever your storage keeps old versions and use \"tofu state push\" instead.",
+						"severity": "error",
+						"summary":  "State history is not available",
+					},
+					"type": "diagnostic",
+				},
+			},
+			wantStderr: `
+Error: State history is not available
+
+The current state storage does not retain earlier snapshots of the state. To
+restore an earlier snapshot, retrieve it from wherever your storage keeps old
+versions and use "tofu state push" instead.
+`,
+		},
+		"stateHistory": {
+			viewCall: func(state State) {
+				state.StateHistory([]*statemgr.SnapshotVersion{
+					{
+						ID:      "10",
+						Lineage: "9ba8c556-ae6c-20ee-f6ed-b57c7cc04dcd",
+						Serial:  10,
+						Created: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+						Who:     "user@host",
+						Latest:  true,
+					},
+					{
+						// Only the metadata kept by the storage
+						ID: "9",
+					},
+				})
+			},
+			wantJson: []map[string]any{
+				{
+					"@level":     "info",
+					"@message":   "State snapshot 10",
+					"@module":    "tofu.ui",
+					"type":       "state_version",
+					"version_id": "10",
+					"serial":     float64(10),
+					"lineage":    "9ba8c556-ae6c-20ee-f6ed-b57c7cc04dcd",
+					"created":    "2026-01-02T03:04:05Z",
+					"who":        "user@host",
+					"latest":     true,
+				},
+				{
+					"@level":     "info",
+					"@message":   "State snapshot 9",
+					"@module":    "tofu.ui",
+					"type":       "state_version",
+					"version_id": "9",
+					"created":    "-",
+					"who":        "",
+					"latest":     false,
+				},
+			},
+			wantStdout: `10  serial=10  lineage=9ba8c556-ae6c-20ee-f6ed-b57c7cc04dcd  created=2026-01-02T03:04:05Z  who=user@host  (latest)
+9   created=-  who=-
+`,
+		},
+		"stateDiff with no differences": {
+			ignoreTimestamp: true,
+			viewCall: func(state State) {
+				state.StateDiff(states.NewState(), states.NewState(), &tofu.Schemas{})
+			},
+			wantStdout: "\nNo differences. The two states contain the same resource instances and output values.\n",
+			wantJson: []map[string]any{
+				{
+					"format_version":   "1.2",
+					"resource_changes": []any{},
+					"output_changes":   map[string]any{},
+				},
+			},
+		},
+		"stateDiff without schemas": {
+			ignoreTimestamp: true,
+			viewCall: func(state State) {
+				withResource := states.BuildState(func(s *states.SyncState) {
+					s.SetResourceInstanceCurrent(
+						addrs.Resource{
+							Mode: addrs.ManagedResourceMode,
+							Type: "test_resource",
+							Name: "foo",
+						}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+						&states.ResourceInstanceObjectSrc{
+							AttrsJSON: []byte(`{"id":"bar"}`),
+							Status:    states.ObjectReady,
+						},
+						addrs.AbsProviderConfig{
+							Provider: addrs.NewDefaultProvider("test"),
+							Module:   addrs.RootModule,
+						},
+						addrs.NoKey,
+					)
+				})
+				state.StateDiff(states.NewState(), withResource, &tofu.Schemas{})
+			},
+			wantStdout: `
+Resource instances are compared using the following symbols:
+  + only in the second state
+
+  # test_resource.foo was added
+  + resource "test_resource" "foo" {
+      + id = "bar"
+    }
+
+Differences: 1 added, 0 changed, 0 removed.
+`,
+			wantJson: []map[string]any{
+				{
+					"format_version": "1.2",
+					"resource_changes": []any{
+						map[string]any{
+							"address":       "test_resource.foo",
+							"mode":          "managed",
+							"type":          "test_resource",
+							"name":          "foo",
+							"provider_name": "registry.opentofu.org/hashicorp/test",
+							"change": map[string]any{
+								"actions":          []any{"create"},
+								"before":           nil,
+								"after":            map[string]any{"id": "bar"},
+								"after_unknown":    map[string]any{},
+								"before_sensitive": false,
+								"after_sensitive":  map[string]any{},
+							},
+						},
+					},
+					"output_changes": map[string]any{},
+				},
+			},
+		},
+		"stateHistoryEmpty": {
+			viewCall: func(state State) {
+				state.StateHistory(nil)
+			},
+			wantJson: []map[string]any{
+				{
+					"@level":   "info",
+					"@message": "No state snapshots found",
+					"@module":  "tofu.ui",
+				},
+			},
+			wantStdout: withNewline("No state snapshots found."),
+		},
+		"stateRolledBack": {
+			viewCall: func(state State) {
+				state.StateRolledBack("terraform.tfstate.backup", 3, 5)
+			},
+			wantJson: []map[string]any{
+				{
+					"@level":   "info",
+					"@message": `Restored state snapshot "terraform.tfstate.backup" (serial 3) as serial 5`,
+					"@module":  "tofu.ui",
+				},
+			},
+			wantStdout: withNewline(`Restored state snapshot "terraform.tfstate.backup" (serial 3) as serial 5.`),
+		},
+		"stateRekeyed": {
+			viewCall: func(state State) {
+				state.StateRekeyed(true)
+			},
+			wantJson: []map[string]any{
+				{
+					"@level":   "info",
+					"@message": "Re-encrypted the state with the primary encryption method",
+					"@module":  "tofu.ui",
+				},
+			},
+			wantStdout: withNewline("Re-encrypted the state with the primary encryption method."),
+		},
+		"stateRekeyedUnchanged": {
+			viewCall: func(state State) {
+				state.StateRekeyed(false)
+			},
+			wantJson: []map[string]any{
+				{
+					"@level":   "info",
+					"@message": "The state is already encrypted with the primary encryption method",
+					"@module":  "tofu.ui",
+				},
+			},
+			wantStdout: withNewline("The state is already encrypted with the primary encryption method."),
+		},
+		"stateRekeyEmpty": {
+			viewCall: func(state State) {
+				state.StateRekeyEmpty()
+			},
+			wantJson: []map[string]any{
+				{
+					"@level":   "info",
+					"@message": "There is no state to re-encrypt",
+					"@module":  "tofu.ui",
+				},
+			},
+			wantStdout: withNewline("There is no state to re-encrypt."),
+		},
 		"printPulledState": {
 			viewCall: func(state State) {
 				state.PrintPulledState(`{"version":4,"terraform_version":"1.11.5","serial":9,"lineage":"9ba8c556-ae6c-20ee-f6ed-b57c7cc04dcd","outputs":{},"resources":[]}`)
@@ -8,6 +8,7 @@ package views
 import (
 	"bytes"
 	"fmt"
+	"strings"
 
 	"github.com/mitchellh/colorstring"
 
@@ -167,6 +168,13 @@ func (t *TestHuman) Abstract(_ *moduletest.Suite) {
 func (t *TestHuman) Conclusion(suite *moduletest.Suite) {
 	t.view.streams.Println()
 
+	if suite.Coverage != nil {
+		for _, line := range coverageSummary(suite.Coverage) {
+			t.view.streams.Println(line)
+		}
+		t.view.streams.Println()
+	}
+
 	counts := make(map[moduletest.Status]int)
 	for _, file := range suite.Files {
 		for _, run := range file.Runs {
@@ -407,6 +415,13 @@ func (t *TestJSON) Abstract(suite *moduletest.Suite) {
 }
 
 func (t *TestJSON) Conclusion(suite *moduletest.Suite) {
+	if suite.Coverage != nil {
+		t.view.log.Info(
+			coverageMessage(suite.Coverage),
+			"type", json.MessageTestCoverage,
+			json.MessageTestCoverage, json.ToTestCoverage(suite.Coverage))
+	}
+
 	summary := json.TestSuiteSummary{
 		Status: json.ToTestStatus(suite.Status),
 	}
@@ -636,6 +651,19 @@ func testStatus(status moduletest.Status) string {
 	}
 }
 
+// plainTestDiagnostics returns the given diagnostics as plain text, without
+// any leading or trailing blank lines, for embedding in the machine-readable
+// test reports.
+func plainTestDiagnostics(view *View, diags tfdiags.Diagnostics) string {
+	diags.Sort()
+
+	msgs := make([]string, 0, len(diags))
+	for _, diag := range diags {
+		msgs = append(msgs, strings.TrimSpace(format.DiagnosticPlain(diag, view.configSources(), 0)))
+	}
+	return strings.Join(msgs, "\n\n")
+}
+
 // SaveErroredTestStateFile is a helper function to invoked in DestroySummary
 // to store the state to errored_test.tfstate and handle associated diagnostics and errors with this operation
 func SaveErroredTestStateFile(state *states.State, run *moduletest.Run, file *moduletest.File, view Test) {
@@ -655,6 +683,14 @@ func SaveErroredTestStateFile(state *states.State, run *moduletest.Run, file *mo
 			view: v.view,
 		}
 		v.view.log.Info("Writing state to file: errored_test.tfstate")
+	case *TestTAP:
+		op = NewOperation(arguments.ViewHuman, v.view)
+		v.view.streams.Eprint(format.WordWrap("\nWriting state to file: errored_test.tfstate\n", v.view.errorColumns()))
+	case TestMulti:
+		// The first view is the one chosen by the user, the others only
+		// produce additional reports.
+		SaveErroredTestStateFile(state, run, file, v[0])
+		return
 	default:
 	}
 
@@ -0,0 +1,68 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/moduletest"
+)
+
+// coverageSummary returns the lines of the human-readable summary of the
+// coverage of the module under test, which lists the objects that were not
+// covered by the tests.
+func coverageSummary(coverage *moduletest.Coverage) []string {
+	lines := []string{"Coverage of the module under test:"}
+
+	resources := coverage.Totals(moduletest.CoverageResource)
+	lines = append(lines, fmt.Sprintf("  resources:  %d/%d planned, %d/%d applied", resources.Planned, resources.Total, resources.Applied, resources.Total))
+	outputs := coverage.Totals(moduletest.CoverageOutput)
+	lines = append(lines, fmt.Sprintf("  outputs:    %d/%d planned, %d/%d applied", outputs.Planned, outputs.Total, outputs.Applied, outputs.Total))
+	checks := coverage.Totals(moduletest.CoverageCheck)
+	lines = append(lines, fmt.Sprintf("  checks:     %d/%d evaluated", checks.Planned, checks.Total))
+	conditions := coverage.Totals(moduletest.CoverageCondition)
+	lines = append(lines, fmt.Sprintf("  conditions: %d/%d evaluated to true, %d/%d evaluated to false", conditions.Passed, conditions.Total, conditions.Failed, conditions.Total))
+
+	var uncovered []string
+	for _, item := range coverage.Items {
+		if item.Covered() {
+			continue
+		}
+
+		var reason string
+		switch {
+		case item.Kind == moduletest.CoverageCheck:
+			reason = "was never evaluated"
+		case item.Kind != moduletest.CoverageCondition:
+			reason = "was never planned"
+		case item.Passed == 0 && item.Failed == 0:
+			reason = "was never evaluated"
+		case item.Failed == 0:
+			reason = "never evaluated to false"
+		default:
+			reason = "never evaluated to true"
+		}
+		uncovered = append(uncovered, fmt.Sprintf("  - %s (%s:%d) %s", item.Address, item.Range.Filename, item.Range.Start.Line, reason))
+	}
+	if len(uncovered) > 0 {
+		lines = append(lines, "", "Not covered:")
+		lines = append(lines, uncovered...)
+	}
+
+	return lines
+}
+
+// coverageMessage returns a single line describing the number of objects
+// of each kind that were covered by the tests.
+func coverageMessage(coverage *moduletest.Coverage) string {
+	var parts []string
+	for _, kind := range moduletest.CoverageKinds {
+		totals := coverage.Totals(kind)
+		parts = append(parts, fmt.Sprintf("%d/%d %ss", totals.Covered, totals.Total, kind))
+	}
+	return fmt.Sprintf("Coverage: %s.", strings.Join(parts, ", "))
+}
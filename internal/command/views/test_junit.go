@@ -0,0 +1,261 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/moduletest"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// TestJUnitXMLFile writes the results of the test command to a file as a
+// JUnit XML report, which most CI systems can display natively.
+//
+// Each test file is reported as a test suite, and each run block as a test
+// case within it. The report is only written once testing has concluded, so
+// it is used alongside one of the other views rather than on its own.
+type TestJUnitXMLFile struct {
+	view *View
+	path string
+
+	// cleanup collects the messages about problems destroying the resources
+	// created by each test file, which are reported alongside the file.
+	cleanup map[string][]string
+
+	err error
+}
+
+var _ Test = (*TestJUnitXMLFile)(nil)
+
+// NewTestJUnitXMLFile returns a view that writes a JUnit XML report to the
+// given path. The given view is only used for rendering the source code
+// snippets of diagnostics in the report.
+func NewTestJUnitXMLFile(path string, view *View) *TestJUnitXMLFile {
+	return &TestJUnitXMLFile{
+		view:    view,
+		path:    path,
+		cleanup: make(map[string][]string),
+	}
+}
+
+// Err returns the error that prevented the report from being written, if
+// any.
+func (t *TestJUnitXMLFile) Err() error {
+	return t.err
+}
+
+func (t *TestJUnitXMLFile) Abstract(_ *moduletest.Suite) {
+	// Do nothing, the report is written all at once in the conclusion.
+}
+
+func (t *TestJUnitXMLFile) Conclusion(suite *moduletest.Suite) {
+	src, err := xml.MarshalIndent(t.report(suite), "", "  ")
+	if err != nil {
+		t.err = fmt.Errorf("failed to encode the JUnit XML report: %w", err)
+		return
+	}
+
+	src = append([]byte(xml.Header), src...)
+	src = append(src, '\n')
+	if err := os.WriteFile(t.path, src, 0644); err != nil {
+		t.err = fmt.Errorf("failed to write the JUnit XML report: %w", err)
+	}
+}
+
+func (t *TestJUnitXMLFile) File(_ *moduletest.File) {
+	// Do nothing, the report is written all at once in the conclusion.
+}
+
+func (t *TestJUnitXMLFile) Run(_ *moduletest.Run, _ *moduletest.File) {
+	// Do nothing, the report is written all at once in the conclusion.
+}
+
+func (t *TestJUnitXMLFile) DestroySummary(diags tfdiags.Diagnostics, run *moduletest.Run, file *moduletest.File, state *states.State) {
+	identifier := file.Name
+	if run != nil {
+		identifier = fmt.Sprintf("%s/%s", identifier, run.Name)
+	}
+
+	var msgs []string
+	if diags.HasErrors() {
+		msgs = append(msgs, fmt.Sprintf("OpenTofu encountered an error destroying resources created while executing %s.", identifier))
+	}
+	if len(diags) > 0 {
+		msgs = append(msgs, plainTestDiagnostics(t.view, diags))
+	}
+	if state.HasManagedResourceInstanceObjects() {
+		var resources strings.Builder
+		fmt.Fprintf(&resources, "OpenTofu left the following resources in state after executing %s, and they need to be cleaned up manually:\n", identifier)
+		for _, resource := range state.AllResourceInstanceObjectAddrs() {
+			if resource.DeposedKey != states.NotDeposed {
+				fmt.Fprintf(&resources, "  - %s (%s)\n", resource.Instance, resource.DeposedKey)
+				continue
+			}
+			fmt.Fprintf(&resources, "  - %s\n", resource.Instance)
+		}
+		msgs = append(msgs, resources.String())
+	}
+
+	t.cleanup[file.Name] = append(t.cleanup[file.Name], msgs...)
+}
+
+func (t *TestJUnitXMLFile) Diagnostics(_ *moduletest.Run, _ *moduletest.File, _ tfdiags.Diagnostics) {
+	// Do nothing, the diagnostics of files and run blocks are taken from the
+	// suite in the conclusion, and the others are not related to any test.
+}
+
+func (t *TestJUnitXMLFile) Interrupted() {
+	// Do nothing, the runs that were skipped are reported as such.
+}
+
+func (t *TestJUnitXMLFile) FatalInterrupt() {
+	// Do nothing, the report isn't written after a fatal interrupt.
+}
+
+func (t *TestJUnitXMLFile) FatalInterruptSummary(_ *moduletest.Run, _ *moduletest.File, _ map[*moduletest.Run]*states.State, _ []*plans.ResourceInstanceChangeSrc) {
+	// Do nothing, the report isn't written after a fatal interrupt.
+}
+
+func (t *TestJUnitXMLFile) report(suite *moduletest.Suite) junitTestSuites {
+	var names []string
+	for name := range suite.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var report junitTestSuites
+	var total time.Duration
+	for _, name := range names {
+		file := suite.Files[name]
+		ts := junitTestSuite{
+			Name: file.Name,
+			Time: junitTime(file.Duration),
+		}
+
+		for _, run := range file.Runs {
+			tc := junitTestCase{
+				Name:      run.Name,
+				Classname: file.Name,
+				Time:      junitTime(run.Duration),
+			}
+
+			switch run.Status {
+			case moduletest.Pass:
+				if len(run.Diagnostics) > 0 {
+					tc.SystemOut = &junitText{Body: plainTestDiagnostics(t.view, run.Diagnostics)}
+				}
+			case moduletest.Skip:
+				tc.Skipped = &junitSkipped{}
+				ts.Skipped++
+			case moduletest.Pending:
+				tc.Skipped = &junitSkipped{Message: "Not executed"}
+				ts.Skipped++
+			case moduletest.Fail:
+				tc.Failure = t.message(run.Diagnostics, "Test failed")
+				ts.Failures++
+			case moduletest.Error:
+				tc.Error = t.message(run.Diagnostics, "Test errored")
+				ts.Errors++
+			}
+
+			ts.Tests++
+			ts.TestCases = append(ts.TestCases, tc)
+		}
+
+		var systemErr []string
+		if len(file.Diagnostics) > 0 {
+			systemErr = append(systemErr, plainTestDiagnostics(t.view, file.Diagnostics))
+		}
+		systemErr = append(systemErr, t.cleanup[file.Name]...)
+		if len(systemErr) > 0 {
+			ts.SystemErr = &junitText{Body: strings.Join(systemErr, "\n\n")}
+		}
+
+		report.Tests += ts.Tests
+		report.Failures += ts.Failures
+		report.Errors += ts.Errors
+		report.Skipped += ts.Skipped
+		report.Suites = append(report.Suites, ts)
+		total += file.Duration
+	}
+	report.Time = junitTime(total)
+
+	return report
+}
+
+// message returns the failure or error element for a run block with the
+// given diagnostics, using the summary of the first error as the message.
+func (t *TestJUnitXMLFile) message(diags tfdiags.Diagnostics, fallback string) *junitMessage {
+	msg := &junitMessage{
+		Message: fallback,
+		Body:    plainTestDiagnostics(t.view, diags),
+	}
+	for _, diag := range diags {
+		if diag.Severity() == tfdiags.Error {
+			msg.Message = diag.Description().Summary
+			break
+		}
+	}
+	return msg
+}
+
+// junitTime formats a duration as the number of seconds, as used by the time
+// attributes of JUnit XML reports.
+func junitTime(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+	SystemErr *junitText      `xml:"system-err,omitempty"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	SystemOut *junitText    `xml:"system-out,omitempty"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",cdata"`
+}
+
+type junitText struct {
+	Body string `xml:",cdata"`
+}
@@ -0,0 +1,158 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/moduletest"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// TestTAP renders the results of the test command in the Test Anything
+// Protocol (TAP) version 13 format, with one test point for each run block.
+//
+// Everything that is not a test point, such as diagnostics that don't belong
+// to a run block, is printed as a TAP comment so that the output remains
+// parseable. Messages about interrupts are printed to stderr, like in the
+// human-readable view.
+type TestTAP struct {
+	view *View
+
+	// count is the number of the last test point that was printed.
+	count int
+}
+
+var _ Test = (*TestTAP)(nil)
+
+func NewTestTAP(view *View) *TestTAP {
+	return &TestTAP{view: view}
+}
+
+func (t *TestTAP) Abstract(suite *moduletest.Suite) {
+	runs := 0
+	for _, file := range suite.Files {
+		runs += len(file.Runs)
+	}
+
+	t.view.streams.Println("TAP version 13")
+	if runs == 0 {
+		t.view.streams.Println("1..0 # SKIP no run blocks found")
+		return
+	}
+	t.view.streams.Printf("1..%d\n", runs)
+}
+
+func (t *TestTAP) Conclusion(suite *moduletest.Suite) {
+	counts := make(map[moduletest.Status]int)
+	for _, file := range suite.Files {
+		for _, run := range file.Runs {
+			counts[run.Status]++
+		}
+	}
+
+	if suite.Coverage != nil {
+		t.comment(strings.Join(coverageSummary(suite.Coverage), "\n"))
+	}
+
+	t.view.streams.Printf("# pass %d\n", counts[moduletest.Pass])
+	t.view.streams.Printf("# fail %d\n", counts[moduletest.Fail]+counts[moduletest.Error])
+	t.view.streams.Printf("# skip %d\n", counts[moduletest.Skip]+counts[moduletest.Pending])
+}
+
+func (t *TestTAP) File(file *moduletest.File) {
+	t.view.streams.Printf("# %s... %s\n", file.Name, testStatus(file.Status))
+	t.Diagnostics(nil, file, file.Diagnostics)
+}
+
+func (t *TestTAP) Run(run *moduletest.Run, file *moduletest.File) {
+	t.count++
+
+	description := fmt.Sprintf("%s run %q", file.Name, run.Name)
+	switch run.Status {
+	case moduletest.Pass:
+		t.view.streams.Printf("ok %d - %s\n", t.count, description)
+	case moduletest.Skip, moduletest.Pending:
+		t.view.streams.Printf("ok %d - %s # SKIP\n", t.count, description)
+	default:
+		t.view.streams.Printf("not ok %d - %s\n", t.count, description)
+	}
+
+	// The details of the run block are given in a YAML block following the
+	// test point.
+	t.view.streams.Println("  ---")
+	t.view.streams.Printf("  status: %s\n", testStatus(run.Status))
+	t.view.streams.Printf("  duration_ms: %d\n", run.Duration.Milliseconds())
+	if len(run.Diagnostics) > 0 {
+		t.view.streams.Println("  diagnostics: |")
+		for _, line := range strings.Split(plainTestDiagnostics(t.view, run.Diagnostics), "\n") {
+			t.view.streams.Printf("    %s\n", line)
+		}
+	}
+	t.view.streams.Println("  ...")
+}
+
+func (t *TestTAP) DestroySummary(diags tfdiags.Diagnostics, run *moduletest.Run, file *moduletest.File, state *states.State) {
+	identifier := file.Name
+	if run != nil {
+		identifier = fmt.Sprintf("%s/%s", identifier, run.Name)
+	}
+
+	if diags.HasErrors() {
+		t.comment(fmt.Sprintf("OpenTofu encountered an error destroying resources created while executing %s.", identifier))
+	}
+	t.Diagnostics(run, file, diags)
+
+	if state.HasManagedResourceInstanceObjects() {
+		t.comment(fmt.Sprintf("OpenTofu left the following resources in state after executing %s, these left-over resources can be viewed by reading the statefile written to disk(errored_test.tfstate) and they need to be cleaned up manually:", identifier))
+		for _, resource := range state.AllResourceInstanceObjectAddrs() {
+			if resource.DeposedKey != states.NotDeposed {
+				t.comment(fmt.Sprintf("  - %s (%s)", resource.Instance, resource.DeposedKey))
+				continue
+			}
+			t.comment(fmt.Sprintf("  - %s", resource.Instance))
+		}
+	}
+}
+
+func (t *TestTAP) Diagnostics(_ *moduletest.Run, _ *moduletest.File, diags tfdiags.Diagnostics) {
+	if len(diags) == 0 {
+		return
+	}
+	t.comment(plainTestDiagnostics(t.view, diags))
+}
+
+func (t *TestTAP) Interrupted() {
+	t.human().Interrupted()
+}
+
+func (t *TestTAP) FatalInterrupt() {
+	t.human().FatalInterrupt()
+}
+
+func (t *TestTAP) FatalInterruptSummary(run *moduletest.Run, file *moduletest.File, existingStates map[*moduletest.Run]*states.State, created []*plans.ResourceInstanceChangeSrc) {
+	t.human().FatalInterruptSummary(run, file, existingStates, created)
+}
+
+// human returns the human-readable view, which prints everything related to
+// interrupts to stderr, and therefore doesn't interfere with the TAP output.
+func (t *TestTAP) human() *TestHuman {
+	return &TestHuman{view: t.view}
+}
+
+// comment prints the given text as TAP comments, one for each line.
+func (t *TestTAP) comment(text string) {
+	for _, line := range strings.Split(text, "\n") {
+		if line == "" {
+			t.view.streams.Println("#")
+			continue
+		}
+		t.view.streams.Printf("# %s\n", line)
+	}
+}
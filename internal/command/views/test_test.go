@@ -13,6 +13,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
@@ -1904,6 +1905,93 @@ func TestTestJSON_Conclusion(t *testing.T) {
 				},
 			},
 		},
+		"coverage": {
+			suite: &moduletest.Suite{
+				Status: moduletest.Pass,
+				Files: map[string]*moduletest.File{
+					"main.tftest.hcl": {
+						Name:   "main.tftest.hcl",
+						Status: moduletest.Pass,
+						Runs: []*moduletest.Run{
+							{
+								Name:   "test_one",
+								Status: moduletest.Pass,
+							},
+						},
+					},
+				},
+				Coverage: &moduletest.Coverage{
+					Items: []*moduletest.CoverageItem{
+						{
+							Kind:    moduletest.CoverageResource,
+							Address: "test_resource.foo",
+							Range:   hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: 1}},
+							Planned: 1,
+							Applied: 1,
+						},
+						{
+							Kind:    moduletest.CoverageCondition,
+							Address: "test_resource.foo.precondition[0]",
+							Range:   hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: 3}},
+							Passed:  2,
+						},
+					},
+				},
+			},
+			want: []map[string]any{
+				{
+					"@level":   "info",
+					"@message": "Coverage: 1/1 resources, 0/0 outputs, 0/0 checks, 0/1 conditions.",
+					"@module":  "tofu.ui",
+					"test_coverage": map[string]any{
+						"totals": map[string]any{
+							"resource":  map[string]any{"total": 1.0, "covered": 1.0},
+							"output":    map[string]any{"total": 0.0, "covered": 0.0},
+							"check":     map[string]any{"total": 0.0, "covered": 0.0},
+							"condition": map[string]any{"total": 1.0, "covered": 0.0},
+						},
+						"items": []any{
+							map[string]any{
+								"kind":    "resource",
+								"address": "test_resource.foo",
+								"file":    "main.tf",
+								"line":    1.0,
+								"covered": true,
+								"planned": 1.0,
+								"applied": 1.0,
+								"passed":  0.0,
+								"failed":  0.0,
+							},
+							map[string]any{
+								"kind":    "condition",
+								"address": "test_resource.foo.precondition[0]",
+								"file":    "main.tf",
+								"line":    3.0,
+								"covered": false,
+								"planned": 0.0,
+								"applied": 0.0,
+								"passed":  2.0,
+								"failed":  0.0,
+							},
+						},
+					},
+					"type": "test_coverage",
+				},
+				{
+					"@level":   "info",
+					"@message": "Success! 1 passed, 0 failed.",
+					"@module":  "tofu.ui",
+					"test_summary": map[string]any{
+						"status":  "pass",
+						"errored": 0.0,
+						"failed":  0.0,
+						"passed":  1.0,
+						"skipped": 0.0,
+					},
+					"type": "test_summary",
+				},
+			},
+		},
 	}
 	for name, tc := range tcs {
 		t.Run(name, func(t *testing.T) {
@@ -114,6 +114,32 @@ func (v *View) Configure(view *arguments.View) {
 	v.ModuleDeprecationWarnLvl = view.ModuleDeprecationWarnLvl
 }
 
+// Buffered returns a new view that is configured in the same way as the
+// receiver, but whose output is captured in memory instead of being written
+// to the receiver's streams. The returned function must be called once the
+// new view is no longer needed, and returns the captured output.
+//
+// This allows running several operations concurrently without interleaving
+// their output.
+func (v *View) Buffered() (*View, func() (stdout, stderr []byte, err error), error) {
+	streams, done, err := terminal.BufferedStreams(v.outputColumns())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	colorize := *v.colorize
+	ret := NewView(streams)
+	ret.colorize = &colorize
+	ret.compactWarnings = v.compactWarnings
+	ret.consolidateWarnings = v.consolidateWarnings
+	ret.consolidateErrors = v.consolidateErrors
+	ret.runningInAutomation = v.runningInAutomation
+	ret.concise = v.concise
+	ret.ModuleDeprecationWarnLvl = v.ModuleDeprecationWarnLvl
+	ret.showSensitive = v.showSensitive
+	return ret, done, nil
+}
+
 func (v *View) DiagsWithNewline() {
 	v.diagsPrinter = func(severity tfdiags.Severity, msg string) {
 		if severity == tfdiags.Error {
@@ -0,0 +1,187 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// WorkspaceBatchResult describes the outcome of running a command in one of
+// the workspaces selected using the -workspace-pattern option.
+type WorkspaceBatchResult struct {
+	Workspace string
+
+	// ExitCode is the exit status that the command would have returned if
+	// it were run for this workspace alone.
+	ExitCode int
+
+	// Stdout and Stderr are the output the command produced for this
+	// workspace, rendered according to the view type of the command.
+	Stdout, Stderr []byte
+
+	// Skipped is set if the command was interrupted before it started in
+	// this workspace, in which case there is no output.
+	Skipped bool
+}
+
+// Status returns a short description of the outcome of the command.
+func (r *WorkspaceBatchResult) Status() string {
+	if r.Skipped {
+		return "skipped"
+	}
+	switch r.ExitCode {
+	case 0:
+		return "succeeded"
+	case 2:
+		// Only returned by "tofu plan -detailed-exitcode"
+		return "succeeded with changes"
+	default:
+		return "failed"
+	}
+}
+
+// The WorkspaceBatch view renders the results of running a command in each
+// of the workspaces selected using the -workspace-pattern option.
+type WorkspaceBatch interface {
+	Diagnostics(diags tfdiags.Diagnostics)
+
+	// WorkspaceResult renders the output of the command for a single
+	// workspace, once the command has completed for that workspace.
+	WorkspaceResult(result *WorkspaceBatchResult)
+
+	// Summary renders the combined outcome of the command once it has
+	// completed for all of the workspaces matching the given pattern.
+	Summary(pattern string, results []*WorkspaceBatchResult)
+}
+
+// NewWorkspaceBatch returns an initialized WorkspaceBatch implementation for
+// the given ViewType.
+func NewWorkspaceBatch(vt arguments.ViewType, view *View) WorkspaceBatch {
+	switch vt {
+	case arguments.ViewJSON:
+		// The view of the command itself has already logged the version.
+		return &WorkspaceBatchJSON{view: newJSONViewWithoutVersion(view, nil)}
+	case arguments.ViewHuman:
+		return &WorkspaceBatchHuman{view: view}
+	default:
+		panic(fmt.Sprintf("unknown view type %v", vt))
+	}
+}
+
+// The WorkspaceBatchHuman implementation writes the output of each workspace
+// as-is below a heading naming the workspace.
+type WorkspaceBatchHuman struct {
+	view *View
+}
+
+var _ WorkspaceBatch = (*WorkspaceBatchHuman)(nil)
+
+func (v *WorkspaceBatchHuman) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+func (v *WorkspaceBatchHuman) WorkspaceResult(result *WorkspaceBatchResult) {
+	heading := v.view.colorize.Color(fmt.Sprintf("[reset][bold]Workspace %q:[reset]", result.Workspace))
+	_, _ = v.view.streams.Println(heading)
+	if len(result.Stdout) > 0 {
+		_, _ = v.view.streams.Stdout.File.Write(result.Stdout)
+	}
+	if len(result.Stderr) > 0 {
+		_, _ = v.view.streams.Eprintln(heading)
+		_, _ = v.view.streams.Stderr.File.Write(result.Stderr)
+	}
+	_, _ = v.view.streams.Println()
+}
+
+func (v *WorkspaceBatchHuman) Summary(pattern string, results []*WorkspaceBatchResult) {
+	nameLen := 0
+	for _, result := range results {
+		nameLen = max(nameLen, len(result.Workspace))
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "[reset][bold]Completed in %d workspace(s) matching %q:[reset]\n", len(results), pattern)
+	for _, result := range results {
+		color := "green"
+		switch result.Status() {
+		case "failed":
+			color = "red"
+		case "skipped":
+			color = "yellow"
+		}
+		fmt.Fprintf(&buf, "  %-*s  [%s]%s[reset]\n", nameLen, result.Workspace, color, result.Status())
+	}
+	_, _ = v.view.streams.Print(v.view.colorize.Color(buf.String()))
+}
+
+// The WorkspaceBatchJSON implementation logs one message per workspace,
+// embedding the JSON values the command produced for that workspace, and
+// then a final summary message.
+type WorkspaceBatchJSON struct {
+	view *JSONView
+}
+
+var _ WorkspaceBatch = (*WorkspaceBatchJSON)(nil)
+
+func (v *WorkspaceBatchJSON) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+func (v *WorkspaceBatchJSON) WorkspaceResult(result *WorkspaceBatchResult) {
+	// The output of a command in JSON mode is a sequence of JSON values:
+	// either one value per line for the streaming UI, or a single
+	// indented value for commands like "tofu output -json".
+	messages := []json.RawMessage{}
+	dec := json.NewDecoder(bytes.NewReader(result.Stdout))
+	for dec.More() {
+		var msg json.RawMessage
+		if err := dec.Decode(&msg); err != nil {
+			v.view.Warn(fmt.Sprintf("Failed to decode the output for workspace %q: %s", result.Workspace, err))
+			break
+		}
+		messages = append(messages, msg)
+	}
+
+	args := []any{
+		"type", "workspace_result",
+		"workspace", result.Workspace,
+		"status", result.Status(),
+		"exit_code", result.ExitCode,
+		"messages", messages,
+	}
+	if len(result.Stderr) > 0 {
+		args = append(args, "stderr", string(result.Stderr))
+	}
+	v.view.log.Info(fmt.Sprintf("Workspace %q: %s", result.Workspace, result.Status()), args...)
+}
+
+type workspaceBatchSummaryJSON struct {
+	Workspace string `json:"workspace"`
+	Status    string `json:"status"`
+	ExitCode  int    `json:"exit_code"`
+}
+
+func (v *WorkspaceBatchJSON) Summary(pattern string, results []*WorkspaceBatchResult) {
+	workspaces := make([]workspaceBatchSummaryJSON, len(results))
+	for i, result := range results {
+		workspaces[i] = workspaceBatchSummaryJSON{
+			Workspace: result.Workspace,
+			Status:    result.Status(),
+			ExitCode:  result.ExitCode,
+		}
+	}
+	v.view.log.Info(
+		fmt.Sprintf("Completed in %d workspace(s) matching %q", len(results), pattern),
+		"type", "workspace_summary",
+		"pattern", pattern,
+		"workspaces", workspaces,
+	)
+}
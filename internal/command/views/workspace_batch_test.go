@@ -0,0 +1,142 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+)
+
+func TestWorkspaceBatchViews(t *testing.T) {
+	dev := &WorkspaceBatchResult{
+		Workspace: "dev",
+		ExitCode:  0,
+		Stdout:    []byte(`{"@message":"dev output"}` + "\n"),
+	}
+	prod := &WorkspaceBatchResult{
+		Workspace: "prod",
+		ExitCode:  1,
+		Stderr:    []byte("Error: prod failed\n"),
+	}
+
+	tests := map[string]struct {
+		viewCall   func(view WorkspaceBatch)
+		wantJson   []map[string]any
+		wantStdout string
+		wantStderr string
+	}{
+		"workspace result": {
+			viewCall: func(view WorkspaceBatch) {
+				view.WorkspaceResult(dev)
+			},
+			wantStdout: "Workspace \"dev\":\n{\"@message\":\"dev output\"}\n\n",
+			wantJson: []map[string]any{
+				{
+					"@level":    "info",
+					"@message":  `Workspace "dev": succeeded`,
+					"@module":   "tofu.ui",
+					"type":      "workspace_result",
+					"workspace": "dev",
+					"status":    "succeeded",
+					"exit_code": float64(0),
+					"messages": []any{
+						map[string]any{"@message": "dev output"},
+					},
+				},
+			},
+		},
+		"failed workspace result": {
+			viewCall: func(view WorkspaceBatch) {
+				view.WorkspaceResult(prod)
+			},
+			wantStdout: "Workspace \"prod\":\n\n",
+			wantStderr: "Workspace \"prod\":\nError: prod failed\n",
+			wantJson: []map[string]any{
+				{
+					"@level":    "info",
+					"@message":  `Workspace "prod": failed`,
+					"@module":   "tofu.ui",
+					"type":      "workspace_result",
+					"workspace": "prod",
+					"status":    "failed",
+					"exit_code": float64(1),
+					"messages":  []any{},
+					"stderr":    "Error: prod failed\n",
+				},
+			},
+		},
+		"summary": {
+			viewCall: func(view WorkspaceBatch) {
+				view.Summary("*", []*WorkspaceBatchResult{dev, prod})
+			},
+			wantStdout: `Completed in 2 workspace(s) matching "*":
+  dev   succeeded
+  prod  failed
+`,
+			wantJson: []map[string]any{
+				{
+					"@level":   "info",
+					"@message": `Completed in 2 workspace(s) matching "*"`,
+					"@module":  "tofu.ui",
+					"type":     "workspace_summary",
+					"pattern":  "*",
+					"workspaces": []any{
+						map[string]any{"workspace": "dev", "status": "succeeded", "exit_code": float64(0)},
+						map[string]any{"workspace": "prod", "status": "failed", "exit_code": float64(1)},
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			{
+				view, done := testView(t)
+				tc.viewCall(NewWorkspaceBatch(arguments.ViewHuman, view))
+				output := done(t)
+				if diff := cmp.Diff(tc.wantStderr, output.Stderr()); diff != "" {
+					t.Errorf("invalid stderr (-want, +got):\n%s", diff)
+				}
+				if diff := cmp.Diff(tc.wantStdout, output.Stdout()); diff != "" {
+					t.Errorf("invalid stdout (-want, +got):\n%s", diff)
+				}
+			}
+			{
+				view, done := testView(t)
+				tc.viewCall(NewWorkspaceBatch(arguments.ViewJSON, view))
+				output := done(t)
+				if output.Stderr() != "" {
+					t.Errorf("expected no stderr but got:\n%s", output.Stderr())
+				}
+				testJSONViewOutputEqualsFull(t, output.Stdout(), tc.wantJson)
+			}
+		})
+	}
+}
+
+func TestWorkspaceBatchResult_Status(t *testing.T) {
+	tests := map[int]string{
+		0: "succeeded",
+		1: "failed",
+		2: "succeeded with changes",
+	}
+	for code, want := range tests {
+		result := &WorkspaceBatchResult{ExitCode: code}
+		if got := result.Status(); got != want {
+			t.Errorf("wrong status for exit code %d: got %q, want %q", code, got, want)
+		}
+	}
+}
+
+func TestWorkspaceBatchResult_StatusSkipped(t *testing.T) {
+	result := &WorkspaceBatchResult{ExitCode: 1, Skipped: true}
+	if got, want := result.Status(), "skipped"; got != want {
+		t.Errorf("wrong status: got %q, want %q", got, want)
+	}
+}
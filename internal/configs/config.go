@@ -980,6 +980,7 @@ func (c *Config) getProviderConfigTransformForTest(evalCtx *hcl.EvalContext) tes
 					Instances:         testProvider.Instances,
 					IsMocked:          testProvider.IsMocked,
 					MockResources:     testProvider.MockResources,
+					MockFunctions:     testProvider.MockFunctions,
 					OverrideResources: testProvider.OverrideResources,
 				}
 
@@ -1008,6 +1009,7 @@ func (c *Config) getProviderConfigTransformForTest(evalCtx *hcl.EvalContext) tes
 					Instances:         mp.Instances,
 					IsMocked:          true,
 					MockResources:     mp.MockResources,
+					MockFunctions:     mp.MockFunctions,
 					OverrideResources: mp.OverrideResources,
 				}
 			}
@@ -106,6 +106,15 @@ func (c *lazyLoader) RefreshModules() error {
 	return l.RefreshModules()
 }
 
+// SetModuleManifestEncryption implements Loader
+func (c *lazyLoader) SetModuleManifestEncryption(enc modsdir.ManifestEncryption) error {
+	l, err := c.init()
+	if err != nil {
+		return err
+	}
+	return l.SetModuleManifestEncryption(enc)
+}
+
 // Sources implements Loader
 func (c *lazyLoader) Sources() map[string]*hcl.File {
 	l, err := c.init()
@@ -30,6 +30,7 @@ type Loader interface {
 	IsConfigDir(path string) bool
 	ModulesDir() string
 	RefreshModules() error
+	SetModuleManifestEncryption(enc modsdir.ManifestEncryption) error
 	Sources() map[string]*hcl.File
 	LoadConfig(ctx context.Context, rootDir string, call configs.StaticModuleCall) (*configs.Config, hcl.Diagnostics)
 	LoadConfigWithTests(ctx context.Context, rootDir string, testDir string, call configs.StaticModuleCall) (*configs.Config, hcl.Diagnostics)
@@ -141,6 +142,19 @@ func (l *loader) RefreshModules() error {
 	return l.modules.readModuleManifestSnapshot()
 }
 
+// SetModuleManifestEncryption sets the encryption of the module manifest and
+// reads the manifest from disk again using it.
+//
+// The encryption is configured in the root module, which can only be loaded
+// once the loader exists, so the manifest is read unencrypted at first. An
+// encrypted manifest is treated as empty until this is called.
+//
+// An error is returned if the manifest file cannot be read.
+func (l *loader) SetModuleManifestEncryption(enc modsdir.ManifestEncryption) error {
+	l.modules.Encryption = enc
+	return l.modules.readModuleManifestSnapshot()
+}
+
 // Sources returns the source code cache for the underlying parser of this
 // loader. This is a shorthand for l.Parser().Sources().
 func (l *loader) Sources() map[string]*hcl.File {
@@ -68,6 +68,16 @@ func (l *loader) ModuleLocalPath(_ context.Context, req *configs.ModuleRequest)
 	key := l.modules.manifest.ModuleKey(req.Path)
 	record, exists := l.modules.manifest[key]
 
+	if !exists && l.modules.manifestErr != nil {
+		return nil, hcl.Diagnostics{
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Failed to read module manifest",
+				Detail:   fmt.Sprintf("Cannot load this module: %s. Make sure that the module_manifest target is configured in the encryption block of the root module.", l.modules.manifestErr),
+				Subject:  &req.CallRange,
+			},
+		}
+	}
 	if !exists {
 		return nil, hcl.Diagnostics{
 			{
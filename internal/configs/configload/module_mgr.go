@@ -6,6 +6,7 @@
 package configload
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 
@@ -34,6 +35,15 @@ type moduleMgr struct {
 	// writeModuleManifestSnapshot to persist a snapshot of the manifest
 	// to disk for use on subsequent runs.
 	manifest modsdir.Manifest
+
+	// Encryption decrypts the manifest snapshot. If nil, the manifest
+	// snapshot is expected to be unencrypted.
+	Encryption modsdir.ManifestEncryption
+
+	// manifestErr is set when the manifest snapshot is encrypted but
+	// Encryption is not set yet. The manifest is then treated as empty,
+	// and modules that are missing from it are reported with this error.
+	manifestErr error
 }
 
 func (m *moduleMgr) manifestSnapshotPath() string {
@@ -53,6 +63,14 @@ func (m *moduleMgr) readModuleManifestSnapshot() error {
 	}
 	defer r.Close()
 
-	m.manifest, err = modsdir.ReadManifestSnapshot(r)
+	m.manifest, err = modsdir.ReadManifestSnapshot(r, m.Encryption)
+	m.manifestErr = nil
+	if errors.Is(err, modsdir.ErrManifestEncrypted) {
+		// The encryption configuration is loaded from the root module, which
+		// happens after the loader is created, so we don't fail here.
+		m.manifest = make(modsdir.Manifest)
+		m.manifestErr = err
+		return nil
+	}
 	return err
 }
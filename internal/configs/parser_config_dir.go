@@ -329,6 +329,7 @@ func (p *Parser) loadTestFiles(basePath string, paths []string) (map[string]*Tes
 		}
 	}
 
+	diags = append(diags, resolveTestFixtures(tfs)...)
 	return tfs, diags
 }
 
@@ -7,6 +7,8 @@ package configs
 
 import (
 	"fmt"
+	"path/filepath"
+	"sort"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
@@ -19,12 +21,12 @@ import (
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
-// TestCommand represents the OpenTofu a given run block will execute, plan
-// or apply. Defaults to apply.
+// TestCommand represents the OpenTofu a given run block will execute, plan,
+// apply or destroy. Defaults to apply.
 type TestCommand rune
 
 // TestMode represents the plan mode that OpenTofu will use for a given run
-// block, normal or refresh-only. Defaults to normal.
+// block, normal, refresh-only or import-only. Defaults to normal.
 type TestMode rune
 
 const (
@@ -36,12 +38,21 @@ const (
 	// operation.
 	PlanTestCommand TestCommand = 'P'
 
+	// DestroyTestCommand causes the run block to execute a OpenTofu destroy
+	// operation.
+	DestroyTestCommand TestCommand = 'D'
+
 	// NormalTestMode causes the run block to execute in plans.NormalMode.
 	NormalTestMode TestMode = 0
 
 	// RefreshOnlyTestMode causes the run block to execute in
 	// plans.RefreshOnlyMode.
 	RefreshOnlyTestMode TestMode = 'R'
+
+	// ImportOnlyTestMode causes the run block to execute in plans.NormalMode,
+	// targeting only the resources named by the import blocks within the
+	// configuration under test.
+	ImportOnlyTestMode TestMode = 'I'
 )
 
 // TestFile represents a single test file within a `tofu test` execution.
@@ -76,6 +87,14 @@ type TestFile struct {
 	// with Providers map to use later when instantiating provider instance.
 	MockProviders map[string]*MockProvider
 
+	// Fixtures defines the starting states declared in this test file. The
+	// setup blocks of every test file within the module can load them.
+	Fixtures map[string]*TestFixture
+
+	// Setup sets the starting states of the run blocks within the test file,
+	// instead of the empty state.
+	Setup *TestSetup
+
 	VariablesDeclRange hcl.Range
 }
 
@@ -110,6 +129,7 @@ func (file *TestFile) getTestProviderOrMock(addr string) (*Provider, bool) {
 			Instances:         mockProvider.Instances,
 			IsMocked:          true,
 			MockResources:     mockProvider.MockResources,
+			MockFunctions:     mockProvider.MockFunctions,
 			OverrideResources: mockProvider.OverrideResources,
 		}
 
@@ -187,9 +207,16 @@ type TestRun struct {
 	// Underlying modules shouldn't be called.
 	OverrideModules []*OverrideModule
 
-	NameDeclRange      hcl.Range
-	VariablesDeclRange hcl.Range
-	DeclRange          hcl.Range
+	// ExpectPlanSnapshot is the path of a golden file that the JSON
+	// representation of the plan for this run block should match. Relative
+	// paths are resolved from the directory of the test file that declares
+	// them. If empty, the plan is not compared against a snapshot.
+	ExpectPlanSnapshot string
+
+	NameDeclRange               hcl.Range
+	VariablesDeclRange          hcl.Range
+	ExpectPlanSnapshotDeclRange hcl.Range
+	DeclRange                   hcl.Range
 }
 
 // Validate does a very simple and cursory check across the run block to look
@@ -245,7 +272,8 @@ type TestRunModuleCall struct {
 
 // TestRunOptions contains the plan options for a given run block.
 type TestRunOptions struct {
-	// Mode is the planning mode to run in. One of ['normal', 'refresh-only'].
+	// Mode is the planning mode to run in. One of ['normal', 'refresh-only',
+	// 'import-only'].
 	Mode TestMode
 
 	// Refresh is analogous to the -refresh=false OpenTofu plan option.
@@ -260,6 +288,60 @@ type TestRunOptions struct {
 	DeclRange hcl.Range
 }
 
+const (
+	blockNameFixture = "fixture"
+	blockNameSetup   = "setup"
+)
+
+// TestFixture is a named starting state for run blocks, which the setup blocks
+// of every test file within the module can load.
+type TestFixture struct {
+	Name string
+
+	// Module is the source of the alternate module whose state the fixture
+	// holds, or nil if it holds the state of the main configuration.
+	Module addrs.ModuleSource
+
+	// State is the path of the state file that holds the starting state.
+	State string
+
+	DeclRange hcl.Range
+}
+
+// TestSetup represents the setup block of a test file.
+type TestSetup struct {
+	States []*TestSetupState
+
+	DeclRange hcl.Range
+}
+
+// TestSetupState sets the starting state of the run blocks that operate on
+// either the main configuration or an alternate module.
+type TestSetupState struct {
+	// Fixture is the name of the fixture the starting state comes from. Once
+	// all the test files are loaded, Module and File are copied from the
+	// fixture.
+	Fixture string
+
+	// Module is the source of the alternate module whose state is set, or nil
+	// to set the state of the main configuration.
+	Module addrs.ModuleSource
+
+	// File is the path of the state file that holds the starting state.
+	File string
+
+	DeclRange hcl.Range
+}
+
+// moduleKey returns the source of the module whose state is set, which is
+// empty for the main configuration.
+func (s *TestSetupState) moduleKey() string {
+	if s.Module == nil {
+		return ""
+	}
+	return s.Module.String()
+}
+
 const (
 	blockNameOverrideResource = "override_resource"
 	blockNameOverrideData     = "override_data"
@@ -328,6 +410,7 @@ type MockProvider struct {
 	// Fields below are specific to configs.MockProvider:
 
 	MockResources     []*MockResource
+	MockFunctions     []*MockFunction
 	OverrideResources []*OverrideResource
 }
 
@@ -367,6 +450,28 @@ func (mp *MockProvider) validateMockResources() hcl.Diagnostics {
 	return diags
 }
 
+func (mp *MockProvider) validateMockFunctions() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	functions := make(map[string]struct{})
+
+	for _, fn := range mp.MockFunctions {
+		if _, ok := functions[fn.Name]; ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("Duplicated `%v` block", blockNameMockFunction),
+				Detail:   fmt.Sprintf("`%v.%v` is already defined in `mock_provider` block.", blockNameMockFunction, fn.Name),
+				Subject:  fn.DeclRange.Ptr(),
+			})
+			continue
+		}
+
+		functions[fn.Name] = struct{}{}
+	}
+
+	return diags
+}
+
 func (mp *MockProvider) validateOverrideResources() hcl.Diagnostics {
 	var diags hcl.Diagnostics
 
@@ -417,6 +522,17 @@ func (r MockResource) getBlockName() string {
 	}
 }
 
+const blockNameMockFunction = "mock_function"
+
+// MockFunction represents a mocked provider-defined function. Every call
+// to the function returns the same value, regardless of the arguments.
+type MockFunction struct {
+	Name    string
+	Returns cty.Value
+
+	DeclRange hcl.Range
+}
+
 func loadTestFile(body hcl.Body) (*TestFile, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 
@@ -426,6 +542,7 @@ func loadTestFile(body hcl.Body) (*TestFile, hcl.Diagnostics) {
 	tf := TestFile{
 		Providers:     make(map[string]*Provider),
 		MockProviders: make(map[string]*MockProvider),
+		Fixtures:      make(map[string]*TestFixture),
 	}
 
 	for _, block := range content.Blocks {
@@ -496,12 +613,256 @@ func loadTestFile(body hcl.Body) (*TestFile, hcl.Diagnostics) {
 					tf.MockProviders[k] = mockProvider
 				}
 			}
+
+		case blockNameFixture:
+			fixture, fixtureDiags := decodeTestFixtureBlock(block)
+			diags = append(diags, fixtureDiags...)
+
+			if !fixtureDiags.HasErrors() {
+				if existing, ok := tf.Fixtures[fixture.Name]; ok {
+					diags = append(diags, &hcl.Diagnostic{
+						Severity: hcl.DiagError,
+						Summary:  "Duplicated `fixture` block",
+						Detail:   fmt.Sprintf("A fixture named %q was already declared at %s.", fixture.Name, existing.DeclRange),
+						Subject:  fixture.DeclRange.Ptr(),
+					})
+				} else {
+					tf.Fixtures[fixture.Name] = fixture
+				}
+			}
+
+		case blockNameSetup:
+			if tf.Setup != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Multiple \"setup\" blocks",
+					Detail:   fmt.Sprintf("This test file already has a setup block defined at %s.", tf.Setup.DeclRange),
+					Subject:  block.DefRange.Ptr(),
+				})
+				continue
+			}
+
+			setup, setupDiags := decodeTestSetupBlock(block)
+			diags = append(diags, setupDiags...)
+			tf.Setup = setup
 		}
 	}
 
 	return &tf, diags
 }
 
+// resolveTestFixtures copies the fixtures loaded by the setup blocks of the
+// given test files into them, as the fixtures can be declared in any of the
+// test files of the module.
+func resolveTestFixtures(files map[string]*TestFile) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fixtures := make(map[string]*TestFixture)
+	for _, name := range names {
+		for _, fixture := range files[name].Fixtures {
+			if existing, ok := fixtures[fixture.Name]; ok {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Duplicated `fixture` block",
+					Detail:   fmt.Sprintf("A fixture named %q was already declared at %s. Fixtures are shared by all the test files, so their names must be unique across them.", fixture.Name, existing.DeclRange),
+					Subject:  fixture.DeclRange.Ptr(),
+				})
+				continue
+			}
+			fixtures[fixture.Name] = fixture
+		}
+	}
+
+	for _, name := range names {
+		setup := files[name].Setup
+		if setup == nil {
+			continue
+		}
+
+		keys := make(map[string]*TestSetupState)
+		for _, state := range setup.States {
+			if state.Fixture != "" {
+				fixture, ok := fixtures[state.Fixture]
+				if !ok {
+					diags = append(diags, &hcl.Diagnostic{
+						Severity: hcl.DiagError,
+						Summary:  "Unknown fixture",
+						Detail:   fmt.Sprintf("There is no fixture named %q in the test files of this module.", state.Fixture),
+						Subject:  state.DeclRange.Ptr(),
+					})
+					continue
+				}
+				state.Module = fixture.Module
+				state.File = fixture.State
+			}
+
+			if existing, ok := keys[state.moduleKey()]; ok {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Duplicated starting state",
+					Detail:   fmt.Sprintf("The starting state for the same module was already set at %s.", existing.DeclRange),
+					Subject:  state.DeclRange.Ptr(),
+				})
+				continue
+			}
+			keys[state.moduleKey()] = state
+		}
+	}
+
+	return diags
+}
+
+func decodeTestFixtureBlock(block *hcl.Block) (*TestFixture, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	fixture := &TestFixture{
+		Name:      block.Labels[0],
+		DeclRange: block.DefRange,
+	}
+
+	if !hclsyntax.ValidIdentifier(fixture.Name) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid fixture name",
+			Detail:   badIdentifierDetail,
+			Subject:  &block.LabelRanges[0],
+		})
+	}
+
+	content, contentDiags := block.Body.Content(testFixtureBlockSchema)
+	diags = append(diags, contentDiags...)
+
+	if attr, exists := content.Attributes["state"]; exists {
+		diags = append(diags, decodeTestStateFile(attr, &fixture.State)...)
+	}
+
+	if attr, exists := content.Attributes["module"]; exists {
+		source, sourceDiags := decodeTestStateModule(attr)
+		diags = append(diags, sourceDiags...)
+		fixture.Module = source
+	}
+
+	return fixture, diags
+}
+
+func decodeTestSetupBlock(block *hcl.Block) (*TestSetup, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	content, contentDiags := block.Body.Content(testSetupBlockSchema)
+	diags = append(diags, contentDiags...)
+
+	setup := &TestSetup{
+		DeclRange: block.DefRange,
+	}
+
+	for _, stateBlock := range content.Blocks {
+		state, stateDiags := decodeTestSetupStateBlock(stateBlock)
+		diags = append(diags, stateDiags...)
+		if !stateDiags.HasErrors() {
+			setup.States = append(setup.States, state)
+		}
+	}
+
+	return setup, diags
+}
+
+func decodeTestSetupStateBlock(block *hcl.Block) (*TestSetupState, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	content, contentDiags := block.Body.Content(testSetupStateBlockSchema)
+	diags = append(diags, contentDiags...)
+
+	state := &TestSetupState{
+		DeclRange: block.DefRange,
+	}
+
+	fixture, hasFixture := content.Attributes["fixture"]
+	file, hasFile := content.Attributes["file"]
+	module, hasModule := content.Attributes["module"]
+
+	switch {
+	case hasFixture && (hasFile || hasModule):
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Conflicting starting state",
+			Detail:   "The \"fixture\" argument cannot be used together with the \"file\" and \"module\" arguments, as the fixture already sets them.",
+			Subject:  fixture.Range.Ptr(),
+		})
+	case hasFixture:
+		diags = append(diags, gohcl.DecodeExpression(fixture.Expr, nil, &state.Fixture)...)
+	case hasFile:
+		diags = append(diags, decodeTestStateFile(file, &state.File)...)
+		if hasModule {
+			source, sourceDiags := decodeTestStateModule(module)
+			diags = append(diags, sourceDiags...)
+			state.Module = source
+		}
+	default:
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Missing starting state",
+			Detail:   "A state block requires either a \"fixture\" or a \"file\" argument.",
+			Subject:  block.DefRange.Ptr(),
+		})
+	}
+
+	return state, diags
+}
+
+// decodeTestStateFile decodes the path of a state file used as a starting
+// state. Relative paths are resolved from the directory of the test file
+// that declares them.
+func decodeTestStateFile(attr *hcl.Attribute, path *string) hcl.Diagnostics {
+	diags := gohcl.DecodeExpression(attr.Expr, nil, path)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	if *path == "" {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid state file path",
+			Detail:   fmt.Sprintf("The %q argument requires the path of a state file.", attr.Name),
+			Subject:  attr.Expr.Range().Ptr(),
+		})
+		return diags
+	}
+
+	if !filepath.IsAbs(*path) {
+		*path = filepath.Join(filepath.Dir(attr.Expr.Range().Filename), *path)
+	}
+	return diags
+}
+
+// decodeTestStateModule decodes the source of the alternate module whose
+// state is set, which must match the source in the module block of the run
+// blocks that use the state.
+func decodeTestStateModule(attr *hcl.Attribute) (addrs.ModuleSource, hcl.Diagnostics) {
+	var raw string
+	diags := gohcl.DecodeExpression(attr.Expr, nil, &raw)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	source, err := addrs.ParseModuleSource(raw)
+	if err != nil {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid module source address",
+			Detail:   fmt.Sprintf("Failed to parse module source address: %s.", err),
+			Subject:  attr.Expr.Range().Ptr(),
+		})
+		return nil, diags
+	}
+	return source, diags
+}
+
 func decodeTestRunBlock(block *hcl.Block) (*TestRun, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 
@@ -620,14 +981,27 @@ func decodeTestRunBlock(block *hcl.Block) (*TestRun, hcl.Diagnostics) {
 			r.Command = ApplyTestCommand
 		case "plan":
 			r.Command = PlanTestCommand
+		case "destroy":
+			r.Command = DestroyTestCommand
 		default:
 			diags = append(diags, &hcl.Diagnostic{
 				Severity: hcl.DiagError,
 				Summary:  "Invalid \"command\" keyword",
-				Detail:   "The \"command\" argument requires one of the following keywords without quotes: apply or plan.",
+				Detail:   "The \"command\" argument requires one of the following keywords without quotes: apply, plan or destroy.",
 				Subject:  attr.Expr.Range().Ptr(),
 			})
 		}
+
+		if r.Command == DestroyTestCommand && r.Options.Mode != NormalTestMode {
+			// A destroy plan has its own planning mode, so it can't be
+			// combined with any of the other modes.
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Incompatible plan options",
+				Detail:   "The \"mode\" option cannot be set when running a test with the \"destroy\" command.",
+				Subject:  r.Options.DeclRange.Ptr(),
+			})
+		}
 	} else {
 		r.Command = ApplyTestCommand // Default to apply
 	}
@@ -644,6 +1018,25 @@ func decodeTestRunBlock(block *hcl.Block) (*TestRun, hcl.Diagnostics) {
 		r.ExpectFailures = failures
 	}
 
+	if attr, exists := content.Attributes["expect_plan_snapshot"]; exists {
+		r.ExpectPlanSnapshotDeclRange = attr.Range
+
+		rawDiags := gohcl.DecodeExpression(attr.Expr, nil, &r.ExpectPlanSnapshot)
+		diags = append(diags, rawDiags...)
+		switch {
+		case rawDiags.HasErrors():
+		case r.ExpectPlanSnapshot == "":
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid \"expect_plan_snapshot\" path",
+				Detail:   "The \"expect_plan_snapshot\" argument requires the path of the file that holds the expected plan.",
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+		case !filepath.IsAbs(r.ExpectPlanSnapshot):
+			r.ExpectPlanSnapshot = filepath.Join(filepath.Dir(attr.Expr.Range().Filename), r.ExpectPlanSnapshot)
+		}
+	}
+
 	return &r, diags
 }
 
@@ -752,13 +1145,15 @@ func decodeTestRunOptionsBlock(block *hcl.Block) (*TestRunOptions, hcl.Diagnosti
 		switch hcl.ExprAsKeyword(attr.Expr) {
 		case "refresh-only":
 			opts.Mode = RefreshOnlyTestMode
+		case "import-only":
+			opts.Mode = ImportOnlyTestMode
 		case "normal":
 			opts.Mode = NormalTestMode
 		default:
 			diags = append(diags, &hcl.Diagnostic{
 				Severity: hcl.DiagError,
 				Summary:  "Invalid \"mode\" keyword",
-				Detail:   "The \"mode\" argument requires one of the following keywords without quotes: normal or refresh-only",
+				Detail:   "The \"mode\" argument requires one of the following keywords without quotes: normal, refresh-only or import-only",
 				Subject:  attr.Expr.Range().Ptr(),
 			})
 		}
@@ -795,6 +1190,16 @@ func decodeTestRunOptionsBlock(block *hcl.Block) (*TestRunOptions, hcl.Diagnosti
 		})
 	}
 
+	if len(opts.Target) > 0 && opts.Mode == ImportOnlyTestMode {
+		// The import blocks already decide the targets in this mode.
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Incompatible plan options",
+			Detail:   "The \"target\" option cannot be set when running a test in \"import-only\" mode.",
+			Subject:  content.Attributes["target"].Range.Ptr(),
+		})
+	}
+
 	return &opts, diags
 }
 
@@ -938,6 +1343,12 @@ func decodeMockProviderBlock(block *hcl.Block) (*MockProvider, hcl.Diagnostics)
 			if !resDiags.HasErrors() {
 				provider.MockResources = append(provider.MockResources, res)
 			}
+		case blockNameMockFunction:
+			fn, fnDiags := decodeMockFunctionBlock(block)
+			diags = append(diags, fnDiags...)
+			if !fnDiags.HasErrors() {
+				provider.MockFunctions = append(provider.MockFunctions, fn)
+			}
 		case blockNameOverrideData, blockNameOverrideResource:
 			res, resDiags := decodeOverrideResourceBlock(block)
 			diags = append(diags, resDiags...)
@@ -948,6 +1359,7 @@ func decodeMockProviderBlock(block *hcl.Block) (*MockProvider, hcl.Diagnostics)
 	}
 
 	diags = append(diags, provider.validateMockResources()...)
+	diags = append(diags, provider.validateMockFunctions()...)
 	diags = append(diags, provider.validateOverrideResources()...)
 
 	return provider, diags
@@ -980,6 +1392,31 @@ func decodeMockResourceBlock(block *hcl.Block) (*MockResource, hcl.Diagnostics)
 	return res, diags
 }
 
+func decodeMockFunctionBlock(block *hcl.Block) (*MockFunction, hcl.Diagnostics) {
+	fn := &MockFunction{
+		Name:      block.Labels[0],
+		DeclRange: block.DefRange,
+	}
+
+	content, diags := block.Body.Content(mockFunctionBlockSchema)
+
+	if !hclsyntax.ValidIdentifier(fn.Name) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid mock function name",
+			Detail:   badIdentifierDetail,
+			Subject:  &block.LabelRanges[0],
+		})
+	}
+
+	if attr, exists := content.Attributes["returns"]; exists {
+		v, valDiags := attr.Expr.Value(nil)
+		fn.Returns, diags = v, append(diags, valDiags...)
+	}
+
+	return fn, diags
+}
+
 func parseObjectAttrWithNoVariables(attr *hcl.Attribute) (map[string]cty.Value, hcl.Diagnostics) {
 	attrVal, valDiags := attr.Expr.Value(nil)
 	diags := valDiags
@@ -1075,6 +1512,44 @@ var testFileSchema = &hcl.BodySchema{
 			Type:       blockNameMockProvider,
 			LabelNames: []string{"name"},
 		},
+		{
+			// fixture block defines a starting state shared by the test files.
+			Type:       blockNameFixture,
+			LabelNames: []string{"name"},
+		},
+		{
+			// setup block sets the starting states of the run blocks.
+			Type: blockNameSetup,
+		},
+	},
+}
+
+var testFixtureBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name:     "state",
+			Required: true,
+		},
+		{
+			Name:     "module",
+			Required: false,
+		},
+	},
+}
+
+var testSetupBlockSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type: "state",
+		},
+	},
+}
+
+var testSetupStateBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "fixture"},
+		{Name: "file"},
+		{Name: "module"},
 	},
 }
 
@@ -1088,6 +1563,8 @@ var testRunBlockSchema = &hcl.BodySchema{
 		{Name: "providers"},
 		// expect_failures indicates whether test failures are expected.
 		{Name: "expect_failures"},
+		// expect_plan_snapshot is the path of a file holding the expected plan.
+		{Name: "expect_plan_snapshot"},
 	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{
@@ -1190,6 +1667,10 @@ var mockProviderBlockSchema = &hcl.BodySchema{
 			Type:       blockNameMockData,
 			LabelNames: []string{"type"},
 		},
+		{
+			Type:       blockNameMockFunction,
+			LabelNames: []string{"name"},
+		},
 		{
 			Type: blockNameOverrideResource,
 		},
@@ -1206,3 +1687,12 @@ var mockResourceBlockSchema = &hcl.BodySchema{
 		},
 	},
 }
+
+var mockFunctionBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name:     "returns",
+			Required: true,
+		},
+	},
+}
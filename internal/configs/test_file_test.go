@@ -7,12 +7,14 @@ package configs
 
 import (
 	"fmt"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hcltest"
+	"github.com/zclconf/go-cty/cty"
 )
 
 func TestTestRun_Validate(t *testing.T) {
@@ -178,3 +180,416 @@ func TestDecodeTestRunModuleBlock(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeTestRunBlock_expectPlanSnapshot(t *testing.T) {
+	tcs := map[string]struct {
+		input         string
+		want          string
+		expectedDiags hcl.Diagnostics
+	}{
+		"path": {
+			input: `"snapshots/plan.json"`,
+			want:  filepath.Join("tests", "snapshots", "plan.json"),
+		},
+		"parent directory": {
+			input: `"../snapshots/plan.json"`,
+			want:  filepath.Join("snapshots", "plan.json"),
+		},
+		"empty": {
+			input: `""`,
+			expectedDiags: hcl.Diagnostics{
+				{
+					Summary: "Invalid \"expect_plan_snapshot\" path",
+				},
+			},
+		},
+		"reference": {
+			input: `var.path`,
+			expectedDiags: hcl.Diagnostics{
+				{
+					Summary: "Variables not allowed",
+				},
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			// Relative paths are resolved from the directory of the test file.
+			expr, _ := hclsyntax.ParseExpression([]byte(tc.input), filepath.Join("tests", "main.tftest.hcl"), hcl.Pos{Line: 1, Column: 1})
+
+			block := &hcl.Block{
+				Type:        "run",
+				Labels:      []string{"test"},
+				LabelRanges: []hcl.Range{blockRange},
+				Body: hcltest.MockBody(&hcl.BodyContent{
+					Attributes: hcl.Attributes{
+						"expect_plan_snapshot": {
+							Name: "expect_plan_snapshot",
+							Expr: expr,
+						},
+					},
+				}),
+				DefRange: blockRange,
+			}
+
+			run, diags := decodeTestRunBlock(block)
+			if tc.expectedDiags != nil || diags != nil {
+				if len(diags) != len(tc.expectedDiags) {
+					t.Fatalf("wrong diagnostics: %s", diags.Error())
+				}
+				assertDiagsSummaryMatch(t, tc.expectedDiags, diags)
+				return
+			}
+
+			if run.ExpectPlanSnapshot != tc.want {
+				t.Errorf("got %q; want %q", run.ExpectPlanSnapshot, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadTestFile_mockFunctions(t *testing.T) {
+	tcs := map[string]struct {
+		src           string
+		want          map[string]cty.Value
+		expectedDiags []string
+	}{
+		"valid": {
+			src: `
+mock_provider "test" {
+  mock_function "greet" {
+    returns = "hello"
+  }
+
+  mock_function "ids" {
+    returns = [1, 2]
+  }
+}
+`,
+			want: map[string]cty.Value{
+				"greet": cty.StringVal("hello"),
+				"ids":   cty.TupleVal([]cty.Value{cty.NumberIntVal(1), cty.NumberIntVal(2)}),
+			},
+		},
+		"duplicated": {
+			src: `
+mock_provider "test" {
+  mock_function "greet" {
+    returns = "hello"
+  }
+
+  mock_function "greet" {
+    returns = "bye"
+  }
+}
+`,
+			expectedDiags: []string{"Duplicated `mock_function` block"},
+		},
+		"missing returns": {
+			src: `
+mock_provider "test" {
+  mock_function "greet" {}
+}
+`,
+			expectedDiags: []string{"Missing required argument"},
+		},
+		"variables": {
+			src: `
+mock_provider "test" {
+  mock_function "greet" {
+    returns = var.greeting
+  }
+}
+`,
+			expectedDiags: []string{"Variables not allowed"},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			file, diags := hclsyntax.ParseConfig([]byte(tc.src), "main.tftest.hcl", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+
+			tf, diags := loadTestFile(file.Body)
+			if len(tc.expectedDiags) > 0 || len(diags) > 0 {
+				if len(diags) != len(tc.expectedDiags) {
+					t.Fatalf("wrong diagnostics: %s", diags.Error())
+				}
+				for i, want := range tc.expectedDiags {
+					if diags[i].Summary != want {
+						t.Errorf("wanted %s as summary, got %s instead", want, diags[i].Summary)
+					}
+				}
+				return
+			}
+
+			got := make(map[string]cty.Value)
+			for _, fn := range tf.MockProviders["test"].MockFunctions {
+				got[fn.Name] = fn.Returns
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("wrong mock functions %#v", got)
+			}
+			for name, want := range tc.want {
+				if !got[name].RawEquals(want) {
+					t.Errorf("wrong value for %s: %#v; want %#v", name, got[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadTestFile_runCommandAndMode(t *testing.T) {
+	tcs := map[string]struct {
+		src           string
+		command       TestCommand
+		mode          TestMode
+		expectedDiags []string
+	}{
+		"destroy": {
+			src: `
+run "test" {
+  command = destroy
+}
+`,
+			command: DestroyTestCommand,
+			mode:    NormalTestMode,
+		},
+		"import-only": {
+			src: `
+run "test" {
+  plan_options {
+    mode = import-only
+  }
+}
+`,
+			command: ApplyTestCommand,
+			mode:    ImportOnlyTestMode,
+		},
+		"destroy with mode": {
+			src: `
+run "test" {
+  command = destroy
+
+  plan_options {
+    mode = refresh-only
+  }
+}
+`,
+			expectedDiags: []string{"Incompatible plan options"},
+		},
+		"import-only with target": {
+			src: `
+run "test" {
+  plan_options {
+    mode   = import-only
+    target = [test_resource.foo]
+  }
+}
+`,
+			expectedDiags: []string{"Incompatible plan options"},
+		},
+		"invalid command": {
+			src: `
+run "test" {
+  command = import
+}
+`,
+			expectedDiags: []string{"Invalid \"command\" keyword"},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			file, diags := hclsyntax.ParseConfig([]byte(tc.src), "main.tftest.hcl", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+
+			tf, diags := loadTestFile(file.Body)
+			if len(tc.expectedDiags) > 0 || len(diags) > 0 {
+				if len(diags) != len(tc.expectedDiags) {
+					t.Fatalf("wrong diagnostics: %s", diags.Error())
+				}
+				for i, want := range tc.expectedDiags {
+					if diags[i].Summary != want {
+						t.Errorf("wanted %s as summary, got %s instead", want, diags[i].Summary)
+					}
+				}
+				return
+			}
+
+			run := tf.Runs[0]
+			if run.Command != tc.command {
+				t.Errorf("wrong command %q; want %q", run.Command, tc.command)
+			}
+			if run.Options.Mode != tc.mode {
+				t.Errorf("wrong mode %q; want %q", run.Options.Mode, tc.mode)
+			}
+		})
+	}
+}
+
+func TestResolveTestFixtures(t *testing.T) {
+	tcs := map[string]struct {
+		files         map[string]string
+		want          map[string]string
+		expectedDiags []string
+	}{
+		"shared": {
+			files: map[string]string{
+				"fixtures.tftest.hcl": `
+fixture "v1" {
+  state  = "states/v1.tfstate"
+  module = "./setup"
+}
+`,
+				"main.tftest.hcl": `
+setup {
+  state {
+    fixture = "v1"
+  }
+
+  state {
+    file = "states/main.tfstate"
+  }
+}
+`,
+			},
+			want: map[string]string{
+				"./setup": "states/v1.tfstate",
+				"":        "states/main.tfstate",
+			},
+		},
+		"relative to test file": {
+			files: map[string]string{
+				"tests/fixtures.tftest.hcl": `
+fixture "v1" {
+  state = "states/v1.tfstate"
+}
+`,
+				"main.tftest.hcl": `
+setup {
+  state {
+    fixture = "v1"
+  }
+}
+`,
+			},
+			want: map[string]string{
+				"": filepath.Join("tests", "states", "v1.tfstate"),
+			},
+		},
+		"unknown fixture": {
+			files: map[string]string{
+				"main.tftest.hcl": `
+setup {
+  state {
+    fixture = "missing"
+  }
+}
+`,
+			},
+			expectedDiags: []string{"Unknown fixture"},
+		},
+		"duplicated fixture": {
+			files: map[string]string{
+				"a.tftest.hcl": `
+fixture "v1" {
+  state = "a.tfstate"
+}
+`,
+				"b.tftest.hcl": `
+fixture "v1" {
+  state = "b.tfstate"
+}
+`,
+			},
+			expectedDiags: []string{"Duplicated `fixture` block"},
+		},
+		"duplicated state": {
+			files: map[string]string{
+				"main.tftest.hcl": `
+setup {
+  state {
+    file = "a.tfstate"
+  }
+
+  state {
+    file = "b.tfstate"
+  }
+}
+`,
+			},
+			expectedDiags: []string{"Duplicated starting state"},
+		},
+		"conflicting state": {
+			files: map[string]string{
+				"main.tftest.hcl": `
+fixture "v1" {
+  state = "a.tfstate"
+}
+
+setup {
+  state {
+    fixture = "v1"
+    file    = "b.tfstate"
+  }
+}
+`,
+			},
+			expectedDiags: []string{"Conflicting starting state"},
+		},
+		"missing state": {
+			files: map[string]string{
+				"main.tftest.hcl": `
+setup {
+  state {}
+}
+`,
+			},
+			expectedDiags: []string{"Missing starting state"},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			var diags hcl.Diagnostics
+			files := make(map[string]*TestFile)
+			for filename, src := range tc.files {
+				file, parseDiags := hclsyntax.ParseConfig([]byte(src), filename, hcl.InitialPos)
+				if parseDiags.HasErrors() {
+					t.Fatal(parseDiags.Error())
+				}
+
+				tf, loadDiags := loadTestFile(file.Body)
+				diags = append(diags, loadDiags...)
+				files[filename] = tf
+			}
+			diags = append(diags, resolveTestFixtures(files)...)
+
+			if len(tc.expectedDiags) > 0 || len(diags) > 0 {
+				if len(diags) != len(tc.expectedDiags) {
+					t.Fatalf("wrong diagnostics: %s", diags.Error())
+				}
+				for i, want := range tc.expectedDiags {
+					if diags[i].Summary != want {
+						t.Errorf("wanted %s as summary, got %s instead", want, diags[i].Summary)
+					}
+				}
+				return
+			}
+
+			got := make(map[string]string)
+			for _, state := range files["main.tftest.hcl"].Setup.States {
+				got[state.moduleKey()] = state.File
+			}
+			if diff := cmp.Diff(tc.want, got); len(diff) > 0 {
+				t.Errorf("wrong starting states:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption/config"
+)
+
+// BackendConfigEncryption describes the methods that you can use for encrypting the backend configuration that
+// "tofu init" stores in the data directory (.terraform/terraform.tfstate). This file contains the configuration of the
+// backend, including any values given with -backend-config or derived from variables, which often include
+// credentials.
+type BackendConfigEncryption interface {
+	// EncryptBackendConfig encrypts a JSON-serialized backend configuration file and returns the encrypted form.
+	//
+	// If the user configured no encryption, this function should be a no-op and return the input unchanged.
+	EncryptBackendConfig([]byte) ([]byte, error)
+
+	// DecryptBackendConfig decrypts a potentially encrypted backend configuration file and returns the
+	// JSON-serialized backend configuration file, along with whether it was decrypted with the primary or a fallback
+	// method.
+	//
+	// If the user configured no encryption, return the input as-is regardless if the file is valid.
+	DecryptBackendConfig([]byte) ([]byte, EncryptionStatus, error)
+}
+
+type backendConfigEncryption struct {
+	base *baseEncryption
+}
+
+func newBackendConfigEncryption(ctx context.Context, enc *encryption, target *config.TargetConfig, enforced bool, name string, staticEval *configs.StaticEvaluator) (BackendConfigEncryption, hcl.Diagnostics) {
+	base, diags := newBaseEncryption(ctx, enc, target, enforced, name, staticEval)
+	return &backendConfigEncryption{base}, diags
+}
+
+func (b *backendConfigEncryption) EncryptBackendConfig(data []byte) ([]byte, error) {
+	return b.base.encrypt(data, func(base basedata) interface{} { return base })
+}
+
+func (b *backendConfigEncryption) DecryptBackendConfig(data []byte) ([]byte, EncryptionStatus, error) {
+	return b.base.decrypt(context.TODO(), data, validateBackendConfigPayload)
+}
+
+// validateBackendConfigPayload checks whether the given data is an unencrypted
+// backend configuration file.
+func validateBackendConfigPayload(data []byte) error {
+	tmp := struct {
+		Version int `json:"version"`
+	}{}
+	err := json.Unmarshal(data, &tmp)
+	if err != nil {
+		return err
+	}
+	if tmp.Version == 0 {
+		return fmt.Errorf("Given payload is not a backend configuration file")
+	}
+	return nil
+}
+
+func BackendConfigEncryptionDisabled() BackendConfigEncryption {
+	return &backendConfigDisabled{}
+}
+
+type backendConfigDisabled struct{}
+
+func (b *backendConfigDisabled) EncryptBackendConfig(plainConfig []byte) ([]byte, error) {
+	return plainConfig, nil
+}
+func (b *backendConfigDisabled) DecryptBackendConfig(encryptedConfig []byte) ([]byte, EncryptionStatus, error) {
+	return encryptedConfig, StatusSatisfied, nil
+}
@@ -0,0 +1,152 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption/config"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/pbkdf2"
+	"github.com/opentofu/opentofu/internal/encryption/method/aesgcm"
+	"github.com/opentofu/opentofu/internal/encryption/method/unencrypted"
+	"github.com/opentofu/opentofu/internal/encryption/registry/lockingencryptionregistry"
+)
+
+func TestBackendConfigEncryption(t *testing.T) {
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(pbkdf2.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(unencrypted.New()); err != nil {
+		panic(err)
+	}
+
+	newEncryption := func(t *testing.T, target string) Encryption {
+		t.Helper()
+		cfg, diags := config.LoadConfigFromString("test", `
+			key_provider "pbkdf2" "main" {
+				passphrase = "Correct passphrase 123"
+			}
+			method "aes_gcm" "main" {
+				keys = key_provider.pbkdf2.main
+			}
+			method "unencrypted" "migrate" {}
+			`+target)
+		if diags.HasErrors() {
+			t.Fatalf("%v", diags.Error())
+		}
+		enc, diags := New(t.Context(), reg, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+		if diags.HasErrors() {
+			t.Fatalf("%v", diags.Error())
+		}
+		return enc
+	}
+
+	plainConfig := []byte(`{"version": 3, "backend": {"type": "s3", "config": {"secret_key": "hunter2"}, "hash": 1}}`)
+
+	t.Run("round trip", func(t *testing.T) {
+		enc := newEncryption(t, `backend_config {
+			method = method.aes_gcm.main
+		}`).BackendConfig()
+
+		encrypted, err := enc.EncryptBackendConfig(plainConfig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(encrypted, []byte("hunter2")) {
+			t.Fatalf("encrypted backend config contains the secret: %s", encrypted)
+		}
+		if ok, err := IsEncryptionPayload(encrypted); err != nil || !ok {
+			t.Fatalf("expected an encrypted payload, got %s", encrypted)
+		}
+
+		decrypted, status, err := enc.DecryptBackendConfig(encrypted)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status != StatusSatisfied {
+			t.Errorf("wrong status %d; want %d", status, StatusSatisfied)
+		}
+		if !bytes.Equal(decrypted, plainConfig) {
+			t.Errorf("wrong decrypted backend config\ngot:  %s\nwant: %s", decrypted, plainConfig)
+		}
+	})
+
+	t.Run("migration", func(t *testing.T) {
+		enc := newEncryption(t, `backend_config {
+			method = method.aes_gcm.main
+			fallback {
+				method = method.unencrypted.migrate
+			}
+		}`).BackendConfig()
+
+		decrypted, status, err := enc.DecryptBackendConfig(plainConfig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status != StatusMigration {
+			t.Errorf("wrong status %d; want %d", status, StatusMigration)
+		}
+		if !bytes.Equal(decrypted, plainConfig) {
+			t.Errorf("wrong decrypted backend config\ngot:  %s\nwant: %s", decrypted, plainConfig)
+		}
+	})
+
+	t.Run("unencrypted without fallback", func(t *testing.T) {
+		enc := newEncryption(t, `backend_config {
+			method = method.aes_gcm.main
+		}`).BackendConfig()
+
+		if _, _, err := enc.DecryptBackendConfig(plainConfig); err == nil {
+			t.Fatal("expected an error reading an unencrypted backend config")
+		}
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		enc := newEncryption(t, `state {
+			method = method.aes_gcm.main
+		}`)
+
+		encrypted, err := enc.BackendConfig().EncryptBackendConfig(plainConfig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(encrypted, plainConfig) {
+			t.Errorf("backend config was modified without backend_config encryption configured: %s", encrypted)
+		}
+
+		// The state_pull target is configured separately from the state
+		statePull, err := enc.StatePull().EncryptState([]byte(`{"terraform_version": "1.9.0", "serial": 1, "lineage": "magic"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, _ := IsEncryptionPayload(statePull); ok {
+			t.Errorf("state pull output was encrypted without state_pull encryption configured: %s", statePull)
+		}
+	})
+
+	t.Run("enforced", func(t *testing.T) {
+		cfg, diags := config.LoadConfigFromString("test", `
+			method "unencrypted" "migrate" {}
+			backend_config {
+				enforced = true
+				method = method.unencrypted.migrate
+			}
+		`)
+		if diags.HasErrors() {
+			t.Fatalf("%v", diags.Error())
+		}
+		_, diags = New(t.Context(), reg, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+		if !diags.HasErrors() {
+			t.Fatal("expected an error with the unencrypted method enforced")
+		}
+	})
+}
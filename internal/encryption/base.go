@@ -38,6 +38,10 @@ type keyProviderMetamap map[keyprovider.MetaStorageKey][]byte
 type keyProviderMetadata struct {
 	input  keyProviderMetamap
 	output keyProviderMetamap
+
+	// unavailable collects the errors of the key providers that failed to provide a key, when the method can do
+	// without them. See setupKeyProvider.
+	unavailable map[keyprovider.MetaStorageKey]*hcl.Diagnostic
 }
 
 func newBaseEncryption(ctx context.Context, enc *encryption, target *config.TargetConfig, enforced bool, name string, staticEval *configs.StaticEvaluator) (*baseEncryption, hcl.Diagnostics) {
@@ -77,8 +81,9 @@ func newBaseEncryption(ctx context.Context, enc *encryption, target *config.Targ
 	//
 
 	encMeta := keyProviderMetadata{
-		input:  make(keyProviderMetamap),
-		output: make(keyProviderMetamap),
+		input:       make(keyProviderMetamap),
+		output:      make(keyProviderMetamap),
+		unavailable: make(map[keyprovider.MetaStorageKey]*hcl.Diagnostic),
 	}
 
 	// methodConfigsFromTarget guarantees that there will be at least one encryption method.  They are not optional in the common target
@@ -152,8 +157,26 @@ const (
 	StatusMigration EncryptionStatus = 2
 )
 
-// TODO Find a way to make these errors actionable / clear
 func (base *baseEncryption) decrypt(ctx context.Context, data []byte, validator func([]byte) error) ([]byte, EncryptionStatus, error) {
+	uncd, methodIdx, err := base.decryptWithMethod(ctx, data, validator)
+	if err != nil {
+		return nil, StatusUnknown, err
+	}
+	if methodIdx == 0 {
+		// Decrypted with first method (encryption method), or unencrypted
+		// and no pending migration
+		return uncd, StatusSatisfied, nil
+	}
+	// Used a fallback, or unencrypted and pending migration
+	return uncd, StatusMigration, nil
+}
+
+// decryptWithMethod decrypts the given data like decrypt, but returns the index
+// in base.methods of the method that was used to decrypt it instead of the
+// resulting status.
+//
+// TODO Find a way to make these errors actionable / clear
+func (base *baseEncryption) decryptWithMethod(ctx context.Context, data []byte, validator func([]byte) error) ([]byte, int, error) {
 	inputData := basedata{}
 	err := json.Unmarshal(data, &inputData)
 
@@ -165,30 +188,20 @@ func (base *baseEncryption) decrypt(ctx context.Context, data []byte, validator
 
 			// Return the outer json error if we have one
 			if err != nil {
-				return nil, StatusUnknown, fmt.Errorf("invalid data format for decryption: %w, %w", err, verr)
+				return nil, -1, fmt.Errorf("invalid data format for decryption: %w, %w", err, verr)
 			}
 
 			// Must have been invalid json payload
-			return nil, StatusUnknown, fmt.Errorf("unable to determine data structure during decryption: %w", verr)
+			return nil, -1, fmt.Errorf("unable to determine data structure during decryption: %w", verr)
 		}
 
 		// Yep, it's already decrypted
-		unencryptedSupported := false
-		for _, method := range base.methods {
+		for i, method := range base.methods {
 			if unencrypted.IsConfig(method) {
-				unencryptedSupported = true
-				break
+				return data, i, nil
 			}
 		}
-		if !unencryptedSupported {
-			return nil, StatusUnknown, fmt.Errorf("encountered unencrypted payload without unencrypted method configured")
-		}
-		if unencrypted.IsConfig(base.methods[0]) {
-			// Decrypted and no pending migration
-			return data, StatusSatisfied, nil
-		}
-		// Decrypted and pending migration
-		return data, StatusMigration, nil
+		return nil, -1, fmt.Errorf("encountered unencrypted payload without unencrypted method configured")
 	}
 	// This is not actually used, only the map inside the Meta parameter is. This is because we are passing the map
 	// around.
@@ -197,7 +210,7 @@ func (base *baseEncryption) decrypt(ctx context.Context, data []byte, validator
 	}
 
 	if inputData.Version != encryptionVersion {
-		return nil, StatusUnknown, fmt.Errorf("invalid encrypted payload version: %s != %s", inputData.Version, encryptionVersion)
+		return nil, -1, fmt.Errorf("invalid encrypted payload version: %s != %s", inputData.Version, encryptionVersion)
 	}
 
 	errs := make([]error, 0)
@@ -209,23 +222,19 @@ func (base *baseEncryption) decrypt(ctx context.Context, data []byte, validator
 
 		// TODO Discuss if we should potentially cache this based on a json-encoded version of inputData.Meta and reduce overhead dramatically
 		decMethod, diags := setupMethod(ctx, base.enc.cfg, method, keyProviderMetadata{
-			input:  inputData.Meta,
-			output: outputData.Meta,
+			input:       inputData.Meta,
+			output:      outputData.Meta,
+			unavailable: make(map[keyprovider.MetaStorageKey]*hcl.Diagnostic),
 		}, base.enc.reg, base.staticEval)
 		if diags.HasErrors() {
 			// This cast to error here is safe as we know that at least one error exists
-			return nil, StatusUnknown, diags
+			return nil, -1, diags
 		}
 
 		uncd, err := decMethod.Decrypt(inputData.Data)
 		if err == nil {
 			// Success
-			if i == 0 {
-				// Decrypted with first method (encryption method)
-				return uncd, StatusSatisfied, nil
-			}
-			// Used a fallback
-			return uncd, StatusMigration, nil
+			return uncd, i, nil
 		}
 		// Record the failure
 		errs = append(errs, fmt.Errorf("attempted decryption failed for %s: %w", base.name, err))
@@ -233,5 +242,5 @@ func (base *baseEncryption) decrypt(ctx context.Context, data []byte, validator
 
 	errs = append([]error{fmt.Errorf("decryption failed for all provided methods")}, errs...)
 
-	return nil, StatusUnknown, errors.New(errors.Join(errs...).Error())
+	return nil, -1, errors.New(errors.Join(errs...).Error())
 }
@@ -11,15 +11,20 @@ import (
 	"github.com/opentofu/opentofu/internal/encryption/method"
 )
 
-// EncryptionConfig describes the terraform.encryption HCL block you can use to configure the state and plan encryption.
+// EncryptionConfig describes the terraform.encryption HCL block you can use to configure the state and plan encryption,
+// as well as the encryption of the other files OpenTofu writes, such as the backend configuration and the module manifest
+// in the data directory.
 // The individual fields of this struct match the HCL structure directly.
 type EncryptionConfig struct {
 	KeyProviderConfigs []KeyProviderConfig `hcl:"key_provider,block"`
 	MethodConfigs      []MethodConfig      `hcl:"method,block"`
 
-	State  *EnforceableTargetConfig `hcl:"state,block"`
-	Plan   *EnforceableTargetConfig `hcl:"plan,block"`
-	Remote *RemoteConfig            `hcl:"remote_state_data_sources,block"`
+	State          *EnforceableTargetConfig `hcl:"state,block"`
+	Plan           *EnforceableTargetConfig `hcl:"plan,block"`
+	Remote         *RemoteConfig            `hcl:"remote_state_data_sources,block"`
+	BackendConfig  *EnforceableTargetConfig `hcl:"backend_config,block"`
+	StatePull      *EnforceableTargetConfig `hcl:"state_pull,block"`
+	ModuleManifest *EnforceableTargetConfig `hcl:"module_manifest,block"`
 
 	// Not preserved through merge operations
 	DeclRange hcl.Range
@@ -71,6 +76,12 @@ func (m MethodConfig) Addr() (method.Addr, hcl.Diagnostics) {
 // RemoteConfig describes the terraform.encryption.remote block you can use to declare encryption for remote state data
 // sources.
 type RemoteConfig struct {
+	// Enforced makes all remote state data sources refuse to read unencrypted state, except for the ones listed in
+	// AllowUnencrypted.
+	Enforced bool `hcl:"enforced,optional"`
+	// AllowUnencrypted lists the remote state data sources that may read unencrypted state despite Enforced.
+	AllowUnencrypted []string `hcl:"allow_unencrypted,optional"`
+
 	Default *TargetConfig       `hcl:"default,block"`
 	Targets []NamedTargetConfig `hcl:"remote_state_data_source,block"`
 }
@@ -86,10 +97,27 @@ type TargetConfig struct {
 // Note: This struct is copied because gohcl does not support embedding.
 type EnforceableTargetConfig struct {
 	Enforced bool           `hcl:"enforced,optional"`
+	Mode     TargetMode     `hcl:"mode,optional"`
 	Method   hcl.Expression `hcl:"method,optional"`
 	Fallback *TargetConfig  `hcl:"fallback,block"`
 }
 
+// TargetMode describes which parts of a state file a target encrypts.
+type TargetMode string
+
+const (
+	// TargetModeFull encrypts the entire file. This is the default when no mode is specified.
+	TargetModeFull TargetMode = "full"
+	// TargetModeSensitiveAttributes only encrypts the resource attributes and outputs marked as sensitive, leaving the
+	// rest of the state file readable.
+	TargetModeSensitiveAttributes TargetMode = "sensitive_attributes"
+)
+
+// IsSensitiveAttributes returns true if the target only encrypts sensitive values.
+func (e EnforceableTargetConfig) IsSensitiveAttributes() bool {
+	return e.Mode == TargetModeSensitiveAttributes
+}
+
 // AsTargetConfig converts the struct into its parent TargetConfig.
 func (e EnforceableTargetConfig) AsTargetConfig() *TargetConfig {
 	return &TargetConfig{
@@ -104,6 +132,7 @@ func (e EnforceableTargetConfig) AsTargetConfig() *TargetConfig {
 // Note: This struct is copied because gohcl does not support embedding.
 type NamedTargetConfig struct {
 	Name     string         `hcl:"name,label"`
+	Enforced bool           `hcl:"enforced,optional"`
 	Method   hcl.Expression `hcl:"method,optional"`
 	Fallback *TargetConfig  `hcl:"fallback,block"`
 }
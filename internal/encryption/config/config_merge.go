@@ -22,9 +22,12 @@ func MergeConfigs(cfg *EncryptionConfig, override *EncryptionConfig) *Encryption
 		KeyProviderConfigs: mergeKeyProviderConfigs(cfg.KeyProviderConfigs, override.KeyProviderConfigs),
 		MethodConfigs:      mergeMethodConfigs(cfg.MethodConfigs, override.MethodConfigs),
 
-		State:  mergeEnforceableTargetConfigs(cfg.State, override.State),
-		Plan:   mergeEnforceableTargetConfigs(cfg.Plan, override.Plan),
-		Remote: mergeRemoteConfigs(cfg.Remote, override.Remote),
+		State:          mergeEnforceableTargetConfigs(cfg.State, override.State),
+		Plan:           mergeEnforceableTargetConfigs(cfg.Plan, override.Plan),
+		Remote:         mergeRemoteConfigs(cfg.Remote, override.Remote),
+		BackendConfig:  mergeEnforceableTargetConfigs(cfg.BackendConfig, override.BackendConfig),
+		StatePull:      mergeEnforceableTargetConfigs(cfg.StatePull, override.StatePull),
+		ModuleManifest: mergeEnforceableTargetConfigs(cfg.ModuleManifest, override.ModuleManifest),
 	}
 }
 
@@ -114,8 +117,13 @@ func mergeEnforceableTargetConfigs(cfg *EnforceableTargetConfig, override *Enfor
 	}
 
 	mergeTarget := mergeTargetConfigs(cfg.AsTargetConfig(), override.AsTargetConfig())
+	mode := cfg.Mode
+	if override.Mode != "" {
+		mode = override.Mode
+	}
 	return &EnforceableTargetConfig{
 		Enforced: cfg.Enforced || override.Enforced,
+		Mode:     mode,
 		Method:   mergeTarget.Method,
 		Fallback: mergeTarget.Fallback,
 	}
@@ -130,8 +138,13 @@ func mergeRemoteConfigs(cfg *RemoteConfig, override *RemoteConfig) *RemoteConfig
 	}
 
 	merged := &RemoteConfig{
-		Default: mergeTargetConfigs(cfg.Default, override.Default),
-		Targets: make([]NamedTargetConfig, len(cfg.Targets)),
+		Enforced:         cfg.Enforced || override.Enforced,
+		AllowUnencrypted: cfg.AllowUnencrypted,
+		Default:          mergeTargetConfigs(cfg.Default, override.Default),
+		Targets:          make([]NamedTargetConfig, len(cfg.Targets)),
+	}
+	if override.AllowUnencrypted != nil {
+		merged.AllowUnencrypted = override.AllowUnencrypted
 	}
 
 	copy(merged.Targets, cfg.Targets)
@@ -144,6 +157,7 @@ func mergeRemoteConfigs(cfg *RemoteConfig, override *RemoteConfig) *RemoteConfig
 				mergeTarget := mergeTargetConfigs(t.AsTargetConfig(), overrideTarget.AsTargetConfig())
 				merged.Targets[i] = NamedTargetConfig{
 					Name:     t.Name,
+					Enforced: t.Enforced || overrideTarget.Enforced,
 					Method:   mergeTarget.Method,
 					Fallback: mergeTarget.Fallback,
 				}
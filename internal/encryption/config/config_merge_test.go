@@ -321,6 +321,18 @@ func TestMergeTargetConfigs(t *testing.T) {
 			override: makeEnforceableTargetConfig(true, expressionTwo, makeTargetConfig(true, expressionTwo, nil)),
 			expected: makeEnforceableTargetConfig(true, expressionTwo, makeTargetConfig(true, expressionTwo, nil)),
 		},
+		{
+			name:     "mode - should be kept if not overridden",
+			input:    &EnforceableTargetConfig{Mode: TargetModeSensitiveAttributes, Method: expressionOne},
+			override: &EnforceableTargetConfig{Method: expressionTwo},
+			expected: &EnforceableTargetConfig{Mode: TargetModeSensitiveAttributes, Method: expressionTwo},
+		},
+		{
+			name:     "mode - should be overridden",
+			input:    &EnforceableTargetConfig{Mode: TargetModeSensitiveAttributes, Method: expressionOne},
+			override: &EnforceableTargetConfig{Mode: TargetModeFull, Method: expressionOne},
+			expected: &EnforceableTargetConfig{Mode: TargetModeFull, Method: expressionOne},
+		},
 	}
 
 	for _, test := range tests {
@@ -333,3 +345,55 @@ func TestMergeTargetConfigs(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeRemoteConfigs(t *testing.T) {
+	expression := hcltest.MockExprLiteral(cty.UnknownVal(cty.Set(cty.String)))
+
+	tests := []struct {
+		name     string
+		input    *RemoteConfig
+		override *RemoteConfig
+		expected *RemoteConfig
+	}{
+		{
+			name:     "enforced - should be true if any are true",
+			input:    &RemoteConfig{Enforced: true},
+			override: &RemoteConfig{},
+			expected: &RemoteConfig{Enforced: true, Targets: []NamedTargetConfig{}},
+		},
+		{
+			name:     "allow list is kept without override",
+			input:    &RemoteConfig{AllowUnencrypted: []string{"foo"}},
+			override: &RemoteConfig{Enforced: true},
+			expected: &RemoteConfig{Enforced: true, AllowUnencrypted: []string{"foo"}, Targets: []NamedTargetConfig{}},
+		},
+		{
+			name:     "allow list is replaced by override",
+			input:    &RemoteConfig{AllowUnencrypted: []string{"foo"}},
+			override: &RemoteConfig{AllowUnencrypted: []string{"bar"}},
+			expected: &RemoteConfig{AllowUnencrypted: []string{"bar"}, Targets: []NamedTargetConfig{}},
+		},
+		{
+			name: "target enforced - should be true if any are true",
+			input: &RemoteConfig{Targets: []NamedTargetConfig{
+				{Name: "foo", Enforced: true, Method: expression},
+			}},
+			override: &RemoteConfig{Targets: []NamedTargetConfig{
+				{Name: "foo", Method: expression},
+			}},
+			expected: &RemoteConfig{Targets: []NamedTargetConfig{
+				{Name: "foo", Enforced: true, Method: expression},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := mergeRemoteConfigs(test.input, test.override)
+
+			if !reflect.DeepEqual(output, test.expected) {
+				t.Errorf("expected %v, got %v", spew.Sdump(test.expected), spew.Sdump(output))
+			}
+		})
+	}
+}
@@ -75,9 +75,44 @@ func DecodeConfig(body hcl.Body, rng hcl.Range) (*EncryptionConfig, hcl.Diagnost
 		}
 	}
 
+	diags = append(diags, validateTargetMode(cfg.State, "state", true, rng)...)
+	diags = append(diags, validateTargetMode(cfg.Plan, "plan", false, rng)...)
+	diags = append(diags, validateTargetMode(cfg.BackendConfig, "backend_config", false, rng)...)
+	diags = append(diags, validateTargetMode(cfg.StatePull, "state_pull", true, rng)...)
+	diags = append(diags, validateTargetMode(cfg.ModuleManifest, "module_manifest", false, rng)...)
+
 	if diags.HasErrors() {
 		return nil, diags
 	}
 
 	return cfg, diags
 }
+
+// validateTargetMode checks that the mode of the given target is known and that partial encryption is only used for
+// targets that contain state files.
+func validateTargetMode(target *EnforceableTargetConfig, name string, allowPartial bool, rng hcl.Range) hcl.Diagnostics {
+	if target == nil {
+		return nil
+	}
+	switch target.Mode {
+	case "", TargetModeFull:
+		return nil
+	case TargetModeSensitiveAttributes:
+		if allowPartial {
+			return nil
+		}
+		return hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Unsupported encryption mode",
+			Detail:   fmt.Sprintf("The %q mode is only supported for the state and state_pull targets, not for %s.", target.Mode, name),
+			Subject:  rng.Ptr(),
+		}}
+	default:
+		return hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid encryption mode",
+			Detail:   fmt.Sprintf("Invalid mode %q for %s, expected %q or %q.", target.Mode, name, TargetModeFull, TargetModeSensitiveAttributes),
+			Subject:  rng.Ptr(),
+		}}
+	}
+}
@@ -6,15 +6,20 @@
 package encryption
 
 import (
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/age"
 	"github.com/opentofu/opentofu/internal/encryption/keyprovider/aws_kms"
 	"github.com/opentofu/opentofu/internal/encryption/keyprovider/azure_vault"
 	externalKeyProvider "github.com/opentofu/opentofu/internal/encryption/keyprovider/external"
 	"github.com/opentofu/opentofu/internal/encryption/keyprovider/gcp_kms"
 	"github.com/opentofu/opentofu/internal/encryption/keyprovider/openbao"
 	"github.com/opentofu/opentofu/internal/encryption/keyprovider/pbkdf2"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/pkcs11"
 	"github.com/opentofu/opentofu/internal/encryption/method/aesgcm"
+	"github.com/opentofu/opentofu/internal/encryption/method/aesgcmsiv"
+	"github.com/opentofu/opentofu/internal/encryption/method/envelope"
 	externalMethod "github.com/opentofu/opentofu/internal/encryption/method/external"
 	"github.com/opentofu/opentofu/internal/encryption/method/unencrypted"
+	"github.com/opentofu/opentofu/internal/encryption/method/xchacha20poly1305"
 	"github.com/opentofu/opentofu/internal/encryption/registry/lockingencryptionregistry"
 )
 
@@ -36,12 +41,29 @@ func init() {
 	if err := DefaultRegistry.RegisterKeyProvider(openbao.New()); err != nil {
 		panic(err)
 	}
+	if err := DefaultRegistry.RegisterKeyProvider(age.New()); err != nil {
+		panic(err)
+	}
+	if pkcs11.Supported {
+		if err := DefaultRegistry.RegisterKeyProvider(pkcs11.New()); err != nil {
+			panic(err)
+		}
+	}
 	if err := DefaultRegistry.RegisterKeyProvider(externalKeyProvider.New()); err != nil {
 		panic(err)
 	}
 	if err := DefaultRegistry.RegisterMethod(aesgcm.New()); err != nil {
 		panic(err)
 	}
+	if err := DefaultRegistry.RegisterMethod(aesgcmsiv.New()); err != nil {
+		panic(err)
+	}
+	if err := DefaultRegistry.RegisterMethod(xchacha20poly1305.New()); err != nil {
+		panic(err)
+	}
+	if err := DefaultRegistry.RegisterMethod(envelope.New()); err != nil {
+		panic(err)
+	}
 	if err := DefaultRegistry.RegisterMethod(externalMethod.New()); err != nil {
 		panic(err)
 	}
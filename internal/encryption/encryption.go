@@ -27,13 +27,31 @@ type Encryption interface {
 	// RemoteState produces a StateEncryption for reading remote states using the terraform_remote_state data
 	// source.
 	RemoteState(string) StateEncryption
+
+	// BackendConfig produces a BackendConfigEncryption for encrypting and decrypting the backend configuration
+	// stored in the data directory.
+	BackendConfig() BackendConfigEncryption
+
+	// StatePull produces a StateEncryption for encrypting the state written by "tofu state pull" and decrypting the
+	// state read by "tofu state push".
+	StatePull() StateEncryption
+
+	// ModuleManifest produces a ModuleManifestEncryption for encrypting and decrypting the module manifest stored in
+	// the data directory.
+	ModuleManifest() ModuleManifestEncryption
 }
 
 type encryption struct {
-	state         StateEncryption
-	plan          PlanEncryption
-	remoteDefault StateEncryption
-	remotes       map[string]StateEncryption
+	state          StateEncryption
+	plan           PlanEncryption
+	remoteDefault  StateEncryption
+	remotes        map[string]StateEncryption
+	backendConfig  BackendConfigEncryption
+	statePull      StateEncryption
+	moduleManifest ModuleManifestEncryption
+
+	// remoteEnforced describes the remote state data sources that must refuse to read unencrypted state.
+	remoteEnforced remoteStatePolicy
 
 	// Inputs
 	cfg *config.EncryptionConfig
@@ -69,7 +87,7 @@ func New(ctx context.Context, reg registry.Registry, cfg *config.EncryptionConfi
 	var encDiags hcl.Diagnostics
 
 	if cfg.State != nil {
-		enc.state, encDiags = newStateEncryption(ctx, enc, cfg.State.AsTargetConfig(), cfg.State.Enforced, "state", staticEval)
+		enc.state, encDiags = newStateEncryption(ctx, enc, cfg.State.AsTargetConfig(), cfg.State.Enforced, cfg.State.IsSensitiveAttributes(), "state", staticEval)
 		diags = append(diags, encDiags...)
 	} else {
 		enc.state = StateEncryptionDisabled()
@@ -83,7 +101,7 @@ func New(ctx context.Context, reg registry.Registry, cfg *config.EncryptionConfi
 	}
 
 	if cfg.Remote != nil && cfg.Remote.Default != nil {
-		enc.remoteDefault, encDiags = newStateEncryption(ctx, enc, cfg.Remote.Default, false, "remote.default", staticEval)
+		enc.remoteDefault, encDiags = newStateEncryption(ctx, enc, cfg.Remote.Default, false, false, "remote.default", staticEval)
 		diags = append(diags, encDiags...)
 	} else {
 		enc.remoteDefault = StateEncryptionDisabled()
@@ -93,10 +111,35 @@ func New(ctx context.Context, reg registry.Registry, cfg *config.EncryptionConfi
 		for _, remoteTarget := range cfg.Remote.Targets {
 			// TODO the addr here should be generated in one place.
 			addr := "remote.remote_state_datasource." + remoteTarget.Name
-			enc.remotes[remoteTarget.Name], encDiags = newStateEncryption(ctx, enc, remoteTarget.AsTargetConfig(), false, addr, staticEval)
+			enc.remotes[remoteTarget.Name], encDiags = newStateEncryption(ctx, enc, remoteTarget.AsTargetConfig(), remoteTarget.Enforced, false, addr, staticEval)
 			diags = append(diags, encDiags...)
 		}
+
+		enc.remoteEnforced, encDiags = newRemoteStatePolicy(cfg.Remote)
+		diags = append(diags, encDiags...)
+	}
+
+	if cfg.BackendConfig != nil {
+		enc.backendConfig, encDiags = newBackendConfigEncryption(ctx, enc, cfg.BackendConfig.AsTargetConfig(), cfg.BackendConfig.Enforced, "backend_config", staticEval)
+		diags = append(diags, encDiags...)
+	} else {
+		enc.backendConfig = BackendConfigEncryptionDisabled()
+	}
+
+	if cfg.StatePull != nil {
+		enc.statePull, encDiags = newStateEncryption(ctx, enc, cfg.StatePull.AsTargetConfig(), cfg.StatePull.Enforced, cfg.StatePull.IsSensitiveAttributes(), "state_pull", staticEval)
+		diags = append(diags, encDiags...)
+	} else {
+		enc.statePull = StateEncryptionDisabled()
 	}
+
+	if cfg.ModuleManifest != nil {
+		enc.moduleManifest, encDiags = newModuleManifestEncryption(ctx, enc, cfg.ModuleManifest.AsTargetConfig(), cfg.ModuleManifest.Enforced, "module_manifest", staticEval)
+		diags = append(diags, encDiags...)
+	} else {
+		enc.moduleManifest = ModuleManifestEncryptionDisabled()
+	}
+
 	if diags.HasErrors() {
 		return nil, diags
 	}
@@ -112,10 +155,26 @@ func (e *encryption) Plan() PlanEncryption {
 }
 
 func (e *encryption) RemoteState(name string) StateEncryption {
-	if enc, ok := e.remotes[name]; ok {
-		return enc
+	enc, ok := e.remotes[name]
+	if !ok {
+		enc = e.remoteDefault
+	}
+	if e.remoteEnforced.isEnforced(name) {
+		return &enforcedRemoteStateEncryption{name: name, StateEncryption: enc}
 	}
-	return e.remoteDefault
+	return enc
+}
+
+func (e *encryption) BackendConfig() BackendConfigEncryption {
+	return e.backendConfig
+}
+
+func (e *encryption) StatePull() StateEncryption {
+	return e.statePull
+}
+
+func (e *encryption) ModuleManifest() ModuleManifestEncryption {
+	return e.moduleManifest
 }
 
 // Mostly used in tests
@@ -129,3 +188,10 @@ func (e *encryptionDisabled) Plan() PlanEncryption   { return PlanEncryptionDisa
 func (e *encryptionDisabled) RemoteState(name string) StateEncryption {
 	return StateEncryptionDisabled()
 }
+func (e *encryptionDisabled) BackendConfig() BackendConfigEncryption {
+	return BackendConfigEncryptionDisabled()
+}
+func (e *encryptionDisabled) StatePull() StateEncryption { return StateEncryptionDisabled() }
+func (e *encryptionDisabled) ModuleManifest() ModuleManifestEncryption {
+	return ModuleManifestEncryptionDisabled()
+}
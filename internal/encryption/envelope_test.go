@@ -0,0 +1,219 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption/config"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/static"
+	"github.com/opentofu/opentofu/internal/encryption/method/envelope"
+	"github.com/opentofu/opentofu/internal/encryption/registry/lockingencryptionregistry"
+)
+
+func TestEnvelope(t *testing.T) {
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(static.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(envelope.New()); err != nil {
+		panic(err)
+	}
+
+	const (
+		mainKey       = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+		wrongKey      = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653170"
+		breakGlassKey = "54686520627265616b20676c617373206b657920666f722074657374696e6721"
+	)
+
+	newStateEncryption := func(t *testing.T, mainKey string, keys string) StateEncryption {
+		t.Helper()
+		cfg, diags := config.LoadConfigFromString("test", `
+			key_provider "static" "main" {
+				key = "`+mainKey+`"
+			}
+			key_provider "static" "break_glass" {
+				key = "`+breakGlassKey+`"
+			}
+			method "envelope" "example" {
+				keys = `+keys+`
+			}
+			state {
+				method = method.envelope.example
+			}`)
+		if diags.HasErrors() {
+			t.Fatalf("%v", diags.Error())
+		}
+		enc, diags := New(t.Context(), reg, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+		if diags.HasErrors() {
+			t.Fatalf("%v", diags.Error())
+		}
+		return enc.State()
+	}
+
+	testData := []byte(`{"serial": 42, "lineage": "magic"}`)
+	encryptedState, err := newStateEncryption(t, mainKey, "[key_provider.static.main, key_provider.static.break_glass]").EncryptState(testData)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if string(encryptedState) == string(testData) {
+		t.Fatalf("The state has not been encrypted.")
+	}
+
+	tests := map[string]struct {
+		mainKey string
+		keys    string
+		wantErr bool
+	}{
+		"all keys": {
+			mainKey: mainKey,
+			keys:    "[key_provider.static.main, key_provider.static.break_glass]",
+		},
+		"main key only": {
+			mainKey: mainKey,
+			keys:    "[key_provider.static.main]",
+		},
+		"break glass key only": {
+			mainKey: mainKey,
+			keys:    "[key_provider.static.break_glass]",
+		},
+		"wrong main key": {
+			mainKey: wrongKey,
+			keys:    "[key_provider.static.main, key_provider.static.break_glass]",
+		},
+		"wrong main key only": {
+			mainKey: wrongKey,
+			keys:    "[key_provider.static.main]",
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			decryptedState, _, err := newStateEncryption(t, tc.mainKey, tc.keys).DecryptState(encryptedState)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			if string(decryptedState) != string(testData) {
+				t.Fatalf("Incorrect decrypted state: %s", decryptedState)
+			}
+		})
+	}
+}
+
+// unavailableKeyProvider is a key provider that can be switched to fail, like a KMS that can't be reached.
+type unavailableKeyProvider struct {
+	failing *bool
+}
+
+func (p unavailableKeyProvider) ID() keyprovider.ID {
+	return "unavailable"
+}
+
+func (p unavailableKeyProvider) ConfigStruct() keyprovider.Config {
+	return &unavailableKeyProviderConfig{failing: p.failing}
+}
+
+type unavailableKeyProviderConfig struct {
+	failing *bool
+}
+
+func (c *unavailableKeyProviderConfig) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
+	return c, nil, nil
+}
+
+func (c *unavailableKeyProviderConfig) Provide(_ keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	if *c.failing {
+		return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{Message: "the KMS is unreachable", Cause: errors.New("connection refused")}
+	}
+	key := []byte("the kms key for testing envelope")
+	return keyprovider.Output{EncryptionKey: key, DecryptionKey: key}, nil, nil
+}
+
+func TestEnvelope_unavailableKeyProvider(t *testing.T) {
+	failing := false
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(static.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterKeyProvider(unavailableKeyProvider{failing: &failing}); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(envelope.New()); err != nil {
+		panic(err)
+	}
+
+	newStateEncryption := func(t *testing.T, keys string) (StateEncryption, hcl.Diagnostics) {
+		t.Helper()
+		cfg, diags := config.LoadConfigFromString("test", `
+			key_provider "unavailable" "kms" {
+			}
+			key_provider "static" "break_glass" {
+				key = "54686520627265616b20676c617373206b657920666f722074657374696e6721"
+			}
+			method "envelope" "example" {
+				keys = `+keys+`
+			}
+			state {
+				method = method.envelope.example
+			}`)
+		if diags.HasErrors() {
+			t.Fatalf("%v", diags.Error())
+		}
+		enc, diags := New(t.Context(), reg, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		return enc.State(), diags
+	}
+
+	testData := []byte(`{"serial": 42, "lineage": "magic"}`)
+	enc, diags := newStateEncryption(t, "[key_provider.unavailable.kms, key_provider.static.break_glass]")
+	if len(diags) > 0 {
+		t.Fatalf("%v", diags.Error())
+	}
+	encryptedState, err := enc.EncryptState(testData)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	// With the KMS unreachable, the state is decrypted with the break glass key, and the failure is reported as a
+	// warning only.
+	failing = true
+	enc, diags = newStateEncryption(t, "[key_provider.unavailable.kms, key_provider.static.break_glass]")
+	if len(diags) != 1 || diags[0].Severity != hcl.DiagWarning || !strings.Contains(diags[0].Detail, "the KMS is unreachable") {
+		t.Fatalf("expected a warning about the unavailable key provider, got: %v", diags)
+	}
+	decryptedState, _, err := enc.DecryptState(encryptedState)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if string(decryptedState) != string(testData) {
+		t.Fatalf("Incorrect decrypted state: %s", decryptedState)
+	}
+
+	// The state can't be written in the meantime, as it would not be decryptable with the KMS key.
+	if _, err := enc.EncryptState(testData); err == nil || !strings.Contains(err.Error(), "key_provider.unavailable.kms") {
+		t.Fatalf("expected an error about the unavailable key provider, got: %v", err)
+	}
+
+	// If none of the keys is available, the failure is an error.
+	failing = true
+	if _, diags := newStateEncryption(t, "[key_provider.unavailable.kms]"); !diags.HasErrors() || !strings.Contains(diags.Error(), "the KMS is unreachable") {
+		t.Fatalf("expected an error about the unavailable key provider, got: %v", diags)
+	}
+}
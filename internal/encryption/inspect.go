@@ -0,0 +1,154 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Inspection describes how a state or plan file is encrypted, and whether it
+// can be decrypted with the current encryption configuration.
+type Inspection struct {
+	// Encrypted is true if the file is an encrypted payload or a state file
+	// with encrypted sensitive values, or false if it is a plain state or
+	// plan file.
+	Encrypted bool
+
+	// Version is the version of the format of the encrypted payload.
+	Version string
+
+	// KeyProviders lists the key provider metadata carried by the encrypted
+	// payload, sorted by the key it is stored under.
+	KeyProviders []InspectedKeyProvider
+
+	// Method is the address of the method in the current configuration that
+	// was able to decrypt the file, such as "method.aes_gcm.main". The
+	// encrypted payload doesn't record the method that encrypted it, so this
+	// is found by trying each of the configured methods in turn.
+	//
+	// Method is empty if the file could not be decrypted, or if encryption is
+	// not configured at all.
+	Method string
+
+	// Status is StatusSatisfied if the file was decrypted with the primary
+	// method, or is unencrypted and encryption is not configured,
+	// StatusMigration if it was decrypted with a fallback method, or is
+	// unencrypted and the unencrypted method is only configured as a
+	// fallback, and StatusUnknown if it could not be decrypted.
+	Status EncryptionStatus
+
+	// Err is the reason the file could not be decrypted, if Status is
+	// StatusUnknown.
+	Err error
+}
+
+// InspectedKeyProvider is the metadata of one of the key providers that was
+// used to encrypt a payload.
+type InspectedKeyProvider struct {
+	// Key is the key the metadata is stored under, which is the address of
+	// the key provider, such as "key_provider.pbkdf2.main", or the
+	// encrypted_metadata_alias given in its configuration.
+	Key string
+
+	// Metadata is the JSON-encoded metadata of the key provider, such as the
+	// salt and the number of iterations for pbkdf2. Key providers never
+	// store secrets in their metadata, because it is not encrypted.
+	Metadata json.RawMessage
+}
+
+// InspectState inspects a state file read from storage, reporting how it is
+// encrypted and whether it can be decrypted with the given state encryption.
+func InspectState(ctx context.Context, enc StateEncryption, data []byte) *Inspection {
+	if s, ok := enc.(*stateEncryption); ok {
+		return s.base.inspect(ctx, data, validateStatePayload)
+	}
+	return inspectDisabled(data)
+}
+
+// InspectPlan inspects a plan file, reporting how it is encrypted and whether
+// it can be decrypted with the given plan encryption.
+func InspectPlan(ctx context.Context, enc PlanEncryption, data []byte) *Inspection {
+	if p, ok := enc.(*planEncryption); ok {
+		return p.base.inspect(ctx, data, validatePlanPayload)
+	}
+	return inspectDisabled(data)
+}
+
+func (base *baseEncryption) inspect(ctx context.Context, data []byte, validator func([]byte) error) *Inspection {
+	ret := inspectPayload(data)
+
+	var methodIdx int
+	var err error
+	if header, headerErr := readSensitiveAttributesHeader(data); headerErr != nil {
+		err = headerErr
+	} else if header != nil {
+		_, methodIdx, err = base.decryptSensitiveAttributes(ctx, data, header)
+	} else {
+		_, methodIdx, err = base.decryptWithMethod(ctx, data, validator)
+	}
+	if err != nil {
+		ret.Status = StatusUnknown
+		ret.Err = err
+		return ret
+	}
+
+	m := base.methods[methodIdx]
+	ret.Method = fmt.Sprintf("method.%s.%s", m.Type, m.Name)
+	if methodIdx == 0 {
+		ret.Status = StatusSatisfied
+	} else {
+		ret.Status = StatusMigration
+	}
+	return ret
+}
+
+// inspectDisabled inspects a file for a target that doesn't have encryption
+// configured, which can therefore only read unencrypted files.
+func inspectDisabled(data []byte) *Inspection {
+	ret := inspectPayload(data)
+	if ret.Encrypted {
+		ret.Status = StatusUnknown
+		ret.Err = errors.New("the file is encrypted, but encryption is not configured")
+		return ret
+	}
+	ret.Status = StatusSatisfied
+	return ret
+}
+
+// inspectPayload returns an Inspection with the details of the encrypted
+// payload in the given data, if it is one, but without any of the details
+// of decrypting it.
+func inspectPayload(data []byte) *Inspection {
+	ret := &Inspection{}
+
+	var payload basedata
+	if err := json.Unmarshal(data, &payload); err != nil || payload.Version == "" {
+		header, err := readSensitiveAttributesHeader(data)
+		if err != nil || header == nil {
+			return ret
+		}
+		payload.Version = header.Version
+		payload.Meta = header.Meta
+	}
+
+	ret.Encrypted = true
+	ret.Version = payload.Version
+	for key, meta := range payload.Meta {
+		provider := InspectedKeyProvider{Key: string(key)}
+		if json.Valid(meta) {
+			provider.Metadata = meta
+		}
+		ret.KeyProviders = append(ret.KeyProviders, provider)
+	}
+	sort.Slice(ret.KeyProviders, func(i, j int) bool {
+		return ret.KeyProviders[i].Key < ret.KeyProviders[j].Key
+	})
+	return ret
+}
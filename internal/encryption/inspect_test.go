@@ -0,0 +1,164 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption/config"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/pbkdf2"
+	"github.com/opentofu/opentofu/internal/encryption/method/aesgcm"
+	"github.com/opentofu/opentofu/internal/encryption/method/unencrypted"
+	"github.com/opentofu/opentofu/internal/encryption/registry/lockingencryptionregistry"
+)
+
+func TestInspectState(t *testing.T) {
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(pbkdf2.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(unencrypted.New()); err != nil {
+		panic(err)
+	}
+
+	newEncryption := func(t *testing.T, state string) StateEncryption {
+		t.Helper()
+		cfg, diags := config.LoadConfigFromString("test", `
+			key_provider "pbkdf2" "old" {
+				passphrase = "Old passphrase 123"
+			}
+			key_provider "pbkdf2" "new" {
+				passphrase = "New passphrase 123"
+			}
+			method "aes_gcm" "old" {
+				keys = key_provider.pbkdf2.old
+			}
+			method "aes_gcm" "new" {
+				keys = key_provider.pbkdf2.new
+			}
+			method "unencrypted" "migrate" {}
+			`+state)
+		if diags.HasErrors() {
+			t.Fatalf("%v", diags.Error())
+		}
+		enc, diags := New(t.Context(), reg, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+		if diags.HasErrors() {
+			t.Fatalf("%v", diags.Error())
+		}
+		return enc.State()
+	}
+
+	plainState := []byte(`{"terraform_version": "1.9.0", "serial": 1, "lineage": "magic"}`)
+	oldEnc := newEncryption(t, `state {
+		method = method.aes_gcm.old
+	}`)
+	oldState, err := oldEnc.EncryptState(plainState)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		enc  StateEncryption
+		data []byte
+
+		wantEncrypted bool
+		wantMethod    string
+		wantStatus    EncryptionStatus
+		wantErr       bool
+	}{
+		"primary": {
+			enc:           oldEnc,
+			data:          oldState,
+			wantEncrypted: true,
+			wantMethod:    "method.aes_gcm.old",
+			wantStatus:    StatusSatisfied,
+		},
+		"fallback": {
+			enc: newEncryption(t, `state {
+				method = method.aes_gcm.new
+				fallback {
+					method = method.aes_gcm.old
+				}
+			}`),
+			data:          oldState,
+			wantEncrypted: true,
+			wantMethod:    "method.aes_gcm.old",
+			wantStatus:    StatusMigration,
+		},
+		"wrong key": {
+			enc: newEncryption(t, `state {
+				method = method.aes_gcm.new
+			}`),
+			data:          oldState,
+			wantEncrypted: true,
+			wantStatus:    StatusUnknown,
+			wantErr:       true,
+		},
+		"unencrypted fallback": {
+			enc: newEncryption(t, `state {
+				method = method.aes_gcm.new
+				fallback {
+					method = method.unencrypted.migrate
+				}
+			}`),
+			data:       plainState,
+			wantMethod: "method.unencrypted.migrate",
+			wantStatus: StatusMigration,
+		},
+		"disabled": {
+			enc:        StateEncryptionDisabled(),
+			data:       plainState,
+			wantStatus: StatusSatisfied,
+		},
+		"disabled encrypted": {
+			enc:           StateEncryptionDisabled(),
+			data:          oldState,
+			wantEncrypted: true,
+			wantStatus:    StatusUnknown,
+			wantErr:       true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := InspectState(t.Context(), tc.enc, tc.data)
+			if got.Encrypted != tc.wantEncrypted {
+				t.Errorf("wrong Encrypted %t; want %t", got.Encrypted, tc.wantEncrypted)
+			}
+			if got.Method != tc.wantMethod {
+				t.Errorf("wrong Method %q; want %q", got.Method, tc.wantMethod)
+			}
+			if got.Status != tc.wantStatus {
+				t.Errorf("wrong Status %d; want %d", got.Status, tc.wantStatus)
+			}
+			if (got.Err != nil) != tc.wantErr {
+				t.Errorf("wrong Err %v; want error: %t", got.Err, tc.wantErr)
+			}
+
+			if !tc.wantEncrypted {
+				return
+			}
+			if got.Version != encryptionVersion {
+				t.Errorf("wrong Version %q; want %q", got.Version, encryptionVersion)
+			}
+			if len(got.KeyProviders) != 1 || got.KeyProviders[0].Key != "key_provider.pbkdf2.old" {
+				t.Fatalf("wrong KeyProviders %#v", got.KeyProviders)
+			}
+			var meta map[string]any
+			if err := json.Unmarshal(got.KeyProviders[0].Metadata, &meta); err != nil {
+				t.Fatalf("invalid key provider metadata: %s", err)
+			}
+			if _, ok := meta["salt"]; !ok {
+				t.Errorf("key provider metadata has no salt: %#v", meta)
+			}
+		})
+	}
+}
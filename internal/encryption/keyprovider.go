@@ -18,6 +18,7 @@ import (
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/encryption/config"
 	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/pkcs11"
 	"github.com/opentofu/opentofu/internal/encryption/registry"
 	"github.com/opentofu/opentofu/internal/lang"
 	"github.com/opentofu/opentofu/internal/lang/marks"
@@ -138,6 +139,10 @@ func setupKeyProvider(ctx context.Context, enc *config.EncryptionConfig, cfg con
 	// The goal is to allow an early return via the above if statement to prevent duplicate errors if errors are encountered in the key loading stack.
 	kpData.set(cfg.Type, cfg.Name, cty.UnknownVal(cty.DynamicPseudoType))
 
+	// Only the key providers referenced by the method itself may be unavailable, the ones other key providers depend
+	// on must always provide a key.
+	direct := len(stack) == 0
+
 	// Check for circular references, this is done by inspecting the stack of key providers
 	// that are currently being setup. If we find a key provider in the stack that matches
 	// the current key provider, then we have a circular reference and we should return an error
@@ -170,11 +175,16 @@ func setupKeyProvider(ctx context.Context, enc *config.EncryptionConfig, cfg con
 	id := keyprovider.ID(cfg.Type)
 	keyProviderDescriptor, err := reg.GetKeyProviderDescriptor(id)
 	if err != nil {
-		if errors.Is(err, &registry.KeyProviderNotFoundError{}) {
+		var notFoundError *registry.KeyProviderNotFoundError
+		if errors.As(err, &notFoundError) {
+			detail := fmt.Sprintf("Can not find %q", cfg.Type)
+			if id == pkcs11.New().ID() && !pkcs11.Supported {
+				detail = "The pkcs11 key provider is not available in this build of OpenTofu, because it needs cgo to load the PKCS#11 library. The official OpenTofu releases are built without cgo, so you need to build OpenTofu from source with CGO_ENABLED=1 to use it."
+			}
 			return diags.Append(&hcl.Diagnostic{
 				Severity: hcl.DiagError,
 				Summary:  "Unknown key_provider type",
-				Detail:   fmt.Sprintf("Can not find %q", cfg.Type),
+				Detail:   detail,
 			})
 		}
 		return diags.Append(&hcl.Diagnostic{
@@ -259,11 +269,20 @@ func setupKeyProvider(ctx context.Context, enc *config.EncryptionConfig, cfg con
 
 	output, keyMetaOut, err := keyProvider.Provide(keyMetaIn)
 	if err != nil {
-		return diags.Append(&hcl.Diagnostic{
+		diag := &hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  "Unable to fetch encryption key data",
 			Detail:   fmt.Sprintf("%s failed with error: %s", metaKey, err.Error()),
-		})
+		}
+		if !direct || meta.unavailable == nil {
+			return diags.Append(diag)
+		}
+
+		// Methods with several keys, such as envelope, can do without some of them. The key provider is set to an
+		// output without any keys, and setupMethod decides whether the method can be used without it.
+		meta.unavailable[metaKey] = diag
+		kpData.set(cfg.Type, cfg.Name, (&keyprovider.Output{}).Cty())
+		return nil
 	}
 
 	if keyMetaOut != nil {
@@ -0,0 +1,196 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package age
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/compliancetest"
+)
+
+const (
+	testIdentity  = "AGE-SECRET-KEY-1EP9DDXZ5A6WL2ZR6CWURQVEHXHYM2PJNUVG84GG8LXNDRVWGPGXQHJUC8U"
+	testRecipient = "age1st34jtyu7tpt98jpg6dn8c3wf75kltrjcpmw5ky7wtevjdkvkeaqvds0na"
+)
+
+func TestKeyProvider(t *testing.T) {
+	validConfig := &Config{
+		Recipients: []string{testRecipient},
+		Identities: []string{testIdentity},
+	}
+
+	compliancetest.ComplianceTest(
+		t,
+		compliancetest.TestConfiguration[*descriptor, *Config, *Metadata, *keyProvider]{
+			Descriptor: New().(*descriptor),
+			HCLParseTestCases: map[string]compliancetest.HCLParseTestCase[*Config, *keyProvider]{
+				"success": {
+					HCL: fmt.Sprintf(`key_provider "age" "foo" {
+	recipients = ["%s"]
+	identities = ["%s"]
+}`, testRecipient, testIdentity),
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(config *Config, keyProvider *keyProvider) error {
+						if len(keyProvider.recipients) != 1 {
+							return fmt.Errorf("incorrect number of recipients: %d", len(keyProvider.recipients))
+						}
+						if len(keyProvider.identities) != 1 {
+							return fmt.Errorf("incorrect number of identities: %d", len(keyProvider.identities))
+						}
+						return nil
+					},
+				},
+				"identities-only": {
+					HCL: fmt.Sprintf(`key_provider "age" "foo" {
+	identities = ["%s"]
+}`, testIdentity),
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(config *Config, keyProvider *keyProvider) error {
+						if len(keyProvider.recipients) != 1 {
+							return fmt.Errorf("the recipient was not derived from the identity")
+						}
+						if keyProvider.recipients[0].(fmt.Stringer).String() != testRecipient {
+							return fmt.Errorf("incorrect recipient derived from the identity")
+						}
+						return nil
+					},
+				},
+				"recipients-only": {
+					HCL: fmt.Sprintf(`key_provider "age" "foo" {
+	recipients = ["%s"]
+}`, testRecipient),
+					ValidHCL:   true,
+					ValidBuild: true,
+				},
+				"empty": {
+					HCL:        `key_provider "age" "foo" {}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"invalid-recipient": {
+					HCL: `key_provider "age" "foo" {
+	recipients = ["age1invalid"]
+}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"invalid-identity": {
+					HCL: `key_provider "age" "foo" {
+	identities = ["AGE-SECRET-KEY-1INVALID"]
+}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"unknown-property": {
+					HCL: fmt.Sprintf(`key_provider "age" "foo" {
+	recipients = ["%s"]
+	unknown_property = "foo"
+}`, testRecipient),
+					ValidHCL:   false,
+					ValidBuild: false,
+				},
+			},
+			JSONParseTestCases: map[string]compliancetest.JSONParseTestCase[*Config, *keyProvider]{
+				"success": {
+					JSON: fmt.Sprintf(`{
+	"key_provider": {
+		"age": {
+			"foo": {
+				"recipients": ["%s"],
+				"identities": ["%s"]
+			}
+		}
+	}
+}`, testRecipient, testIdentity),
+					ValidJSON:  true,
+					ValidBuild: true,
+				},
+				"empty": {
+					JSON: `{
+	"key_provider": {
+		"age": {
+			"foo": {
+			}
+		}
+	}
+}`,
+					ValidJSON:  true,
+					ValidBuild: false,
+				},
+				"unknown-property": {
+					JSON: fmt.Sprintf(`{
+	"key_provider": {
+		"age": {
+			"foo": {
+				"recipients": ["%s"],
+				"unknown_property": "foo"
+			}
+		}
+	}
+}`, testRecipient),
+					ValidJSON:  false,
+					ValidBuild: false,
+				},
+			},
+			ConfigStructTestCases: map[string]compliancetest.ConfigStructTestCase[*Config, *keyProvider]{
+				"success": {
+					Config:     validConfig,
+					ValidBuild: true,
+				},
+				"empty": {
+					Config:     &Config{},
+					ValidBuild: false,
+				},
+			},
+			MetadataStructTestCases: map[string]compliancetest.MetadataStructTestCase[*Config, *Metadata]{
+				"empty": {
+					ValidConfig: validConfig,
+					Meta:        &Metadata{},
+					IsPresent:   false,
+					IsValid:     false,
+				},
+				"invalid": {
+					ValidConfig: validConfig,
+					Meta: &Metadata{
+						Ciphertext: []byte("not an age file"),
+					},
+					IsPresent: true,
+					IsValid:   false,
+				},
+				"valid": {
+					ValidConfig: validConfig,
+					Meta:        presentValidMetadata(t),
+					IsPresent:   true,
+					IsValid:     true,
+				},
+			},
+			ProvideTestCase: compliancetest.ProvideTestCase[*Config, *Metadata]{
+				ValidConfig: validConfig,
+				ValidateMetadata: func(meta *Metadata) error {
+					if !meta.isPresent() {
+						return fmt.Errorf("ciphertext is empty")
+					}
+					return nil
+				},
+			},
+		},
+	)
+}
+
+func presentValidMetadata(t *testing.T) *Metadata {
+	provider, metaIn, err := (&Config{Recipients: []string{testRecipient}}).Build()
+	if err != nil {
+		t.Fatalf("failed to build key provider for present-valid metadata: %s", err)
+	}
+	_, meta, err := provider.Provide(metaIn)
+	if err != nil {
+		t.Fatalf("failed to generate present-valid metadata: %s", err)
+	}
+	return meta.(*Metadata)
+}
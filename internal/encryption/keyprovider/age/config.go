@@ -0,0 +1,68 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package age
+
+import (
+	"fmt"
+
+	"filippo.io/age"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+// dataKeyLength is the length of the generated data keys. 32 bytes is suitable for all built-in encryption methods.
+const dataKeyLength = 32
+
+// Config contains the configuration for this key provider supplied by the user.
+type Config struct {
+	// Recipients contains the age X25519 public keys (age1...) the data key is encrypted to.
+	Recipients []string `hcl:"recipients,optional"`
+	// Identities contains the age X25519 secret keys (AGE-SECRET-KEY-1...) used to decrypt the data key. If no
+	// recipients are given, the data key is encrypted to the public keys of these identities.
+	Identities []string `hcl:"identities,optional"`
+}
+
+// Build will create the usable key provider.
+func (c Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
+	if len(c.Recipients) == 0 && len(c.Identities) == 0 {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "at least one recipient or identity must be provided",
+		}
+	}
+
+	identities := make([]age.Identity, 0, len(c.Identities))
+	var derivedRecipients []age.Recipient
+	for i, rawIdentity := range c.Identities {
+		identity, err := age.ParseX25519Identity(rawIdentity)
+		if err != nil {
+			// Note: the error is not included as the cause to avoid leaking the secret key.
+			return nil, nil, &keyprovider.ErrInvalidConfiguration{
+				Message: fmt.Sprintf("identity %d is not a valid age X25519 secret key", i+1),
+			}
+		}
+		identities = append(identities, identity)
+		derivedRecipients = append(derivedRecipients, identity.Recipient())
+	}
+
+	recipients := make([]age.Recipient, 0, len(c.Recipients))
+	for _, rawRecipient := range c.Recipients {
+		recipient, err := age.ParseX25519Recipient(rawRecipient)
+		if err != nil {
+			return nil, nil, &keyprovider.ErrInvalidConfiguration{
+				Message: fmt.Sprintf("invalid age X25519 recipient: %s", rawRecipient),
+				Cause:   err,
+			}
+		}
+		recipients = append(recipients, recipient)
+	}
+	if len(recipients) == 0 {
+		recipients = derivedRecipients
+	}
+
+	return &keyProvider{
+		recipients: recipients,
+		identities: identities,
+	}, new(Metadata), nil
+}
@@ -0,0 +1,16 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package age
+
+// Metadata contains the age-encrypted data key. Only the holder of one of the recipients' identities can recover the
+// key from it.
+type Metadata struct {
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func (m Metadata) isPresent() bool {
+	return len(m.Ciphertext) != 0
+}
@@ -0,0 +1,117 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package age contains a key provider that encrypts a randomly generated data key to one or more age X25519
+// recipients and decrypts it with the matching identities.
+package age
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+
+	"filippo.io/age"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+type keyProvider struct {
+	recipients []age.Recipient
+	identities []age.Identity
+}
+
+func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	if rawMeta == nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+			Message: "bug: no metadata struct provided",
+		}
+	}
+	inMeta, ok := rawMeta.(*Metadata)
+	if !ok {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+			Message: fmt.Sprintf("bug: invalid metadata type received: %T", rawMeta),
+		}
+	}
+
+	dataKey := make([]byte, dataKeyLength)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
+			Message: "failed to generate data key",
+			Cause:   err,
+		}
+	}
+	ciphertext, err := p.encryptDataKey(dataKey)
+	if err != nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
+			Message: "failed to encrypt data key to the age recipients",
+			Cause:   err,
+		}
+	}
+
+	out := keyprovider.Output{
+		EncryptionKey: dataKey,
+	}
+
+	// We only set the decryption key if we received metadata, which means OpenTofu is decrypting something.
+	if inMeta.isPresent() {
+		if len(p.identities) == 0 {
+			return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
+				Message: "no age identities configured, cannot decrypt the data key",
+			}
+		}
+		out.DecryptionKey, err = p.decryptDataKey(inMeta.Ciphertext)
+		if err != nil {
+			return keyprovider.Output{}, nil, err
+		}
+	}
+
+	return out, &Metadata{Ciphertext: ciphertext}, nil
+}
+
+func (p keyProvider) encryptDataKey(dataKey []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	w, err := age.Encrypt(buf, p.recipients...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(dataKey); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (p keyProvider) decryptDataKey(ciphertext []byte) ([]byte, error) {
+	r, err := age.Decrypt(bytes.NewReader(ciphertext), p.identities...)
+	if err != nil {
+		var noMatch *age.NoIdentityMatchError
+		if errors.As(err, &noMatch) {
+			return nil, &keyprovider.ErrKeyProviderFailure{
+				Message: "none of the configured age identities can decrypt the data key",
+				Cause:   err,
+			}
+		}
+		return nil, &keyprovider.ErrInvalidMetadata{
+			Message: "failed to decrypt the age-encrypted data key",
+			Cause:   err,
+		}
+	}
+	dataKey, err := io.ReadAll(r)
+	if err != nil {
+		return nil, &keyprovider.ErrInvalidMetadata{
+			Message: "failed to decrypt the age-encrypted data key",
+			Cause:   err,
+		}
+	}
+	if len(dataKey) != dataKeyLength {
+		return nil, &keyprovider.ErrInvalidMetadata{
+			Message: fmt.Sprintf("invalid data key length: %d", len(dataKey)),
+		}
+	}
+	return dataKey, nil
+}
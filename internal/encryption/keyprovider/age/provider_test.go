@@ -0,0 +1,84 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package age
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+const (
+	otherIdentity  = "AGE-SECRET-KEY-1HKW5CX3ARMLEFG4ZQVTL2DDY46HAHKA5WC2R046CHL20G9ZF628QM2JSEC"
+	otherRecipient = "age1u9dptv09v7mayd2j6ljk8nwz5dfqntwaa7hqs5l9v8pm34a2hptq3u3ml7"
+)
+
+func provide(t *testing.T, cfg Config, meta *Metadata) (keyprovider.Output, *Metadata, error) {
+	t.Helper()
+	provider, emptyMeta, err := cfg.Build()
+	if err != nil {
+		t.Fatalf("failed to build key provider: %v", err)
+	}
+	if meta == nil {
+		meta = emptyMeta.(*Metadata)
+	}
+	out, outMeta, err := provider.Provide(meta)
+	if err != nil {
+		return out, nil, err
+	}
+	return out, outMeta.(*Metadata), nil
+}
+
+func TestProvide_multipleRecipients(t *testing.T) {
+	// Encrypt-only configuration, for example on a machine that should never read the state back.
+	encOut, meta, err := provide(t, Config{Recipients: []string{testRecipient, otherRecipient}}, nil)
+	if err != nil {
+		t.Fatalf("failed to provide encryption key: %v", err)
+	}
+	if len(encOut.DecryptionKey) != 0 {
+		t.Fatalf("decryption key returned without metadata")
+	}
+
+	for name, identity := range map[string]string{"first": testIdentity, "second": otherIdentity} {
+		t.Run(name, func(t *testing.T) {
+			out, _, err := provide(t, Config{Identities: []string{identity}}, meta)
+			if err != nil {
+				t.Fatalf("failed to decrypt data key: %v", err)
+			}
+			if !bytes.Equal(out.DecryptionKey, encOut.EncryptionKey) {
+				t.Fatalf("decryption key does not match the encryption key")
+			}
+		})
+	}
+}
+
+func TestProvide_wrongIdentity(t *testing.T) {
+	_, meta, err := provide(t, Config{Recipients: []string{testRecipient}}, nil)
+	if err != nil {
+		t.Fatalf("failed to provide encryption key: %v", err)
+	}
+
+	_, _, err = provide(t, Config{Identities: []string{otherIdentity}}, meta)
+	var typedErr *keyprovider.ErrKeyProviderFailure
+	if !errors.As(err, &typedErr) {
+		t.Fatalf("expected %T, got %T: %v", typedErr, err, err)
+	}
+}
+
+func TestProvide_noIdentities(t *testing.T) {
+	_, meta, err := provide(t, Config{Recipients: []string{testRecipient}}, nil)
+	if err != nil {
+		t.Fatalf("failed to provide encryption key: %v", err)
+	}
+
+	_, _, err = provide(t, Config{Recipients: []string{testRecipient}}, meta)
+	var typedErr *keyprovider.ErrKeyProviderFailure
+	if !errors.As(err, &typedErr) {
+		t.Fatalf("expected %T, got %T: %v", typedErr, err, err)
+	}
+}
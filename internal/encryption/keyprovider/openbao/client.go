@@ -8,22 +8,26 @@ package openbao
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
 	"path"
+	"strconv"
 
 	openbao "github.com/openbao/openbao/api/v2"
 )
 
 type client interface {
 	WriteWithContext(ctx context.Context, path string, data map[string]interface{}) (*openbao.Secret, error)
+	ReadWithDataWithContext(ctx context.Context, path string, data map[string][]string) (*openbao.Secret, error)
 }
 
 // service implements missing utility functions from openbao/api such as routing and serialization.
 type service struct {
 	c           client
 	transitPath string
+	kvPath      string
 }
 
 type dataKey struct {
@@ -81,6 +85,78 @@ func (s service) decryptData(ctx context.Context, keyName string, ciphertext []b
 	return retrievePlaintext(secret)
 }
 
+// readKVKey reads the base64-encoded key material stored in the given field of a KV v2 secret. If version is 0, the
+// latest version of the secret is read. It returns the key along with the version it was read from.
+func (s service) readKVKey(ctx context.Context, secretName string, field string, version int) ([]byte, int, error) {
+	path := path.Join(s.kvPath, "data", secretName)
+
+	var data map[string][]string
+	if version != 0 {
+		data = map[string][]string{
+			"version": {strconv.Itoa(version)},
+		}
+	}
+
+	secret, err := s.c.ReadWithDataWithContext(ctx, path, data)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error reading KV secret from OpenBao: %w", err)
+	}
+	// OpenBao responds with a 404 and no data for deleted and destroyed versions as well.
+	if secret == nil || secret.Data["data"] == nil {
+		if version != 0 {
+			return nil, 0, fmt.Errorf("version %d of the KV secret %q not found (it may have been deleted or destroyed)", version, secretName)
+		}
+		return nil, 0, fmt.Errorf("KV secret %q not found", secretName)
+	}
+
+	secretData, ok := secret.Data["data"].(map[string]interface{})
+	if !ok {
+		return nil, 0, errors.New("failed to deserialize 'data' (check if the KV engine is version 2)")
+	}
+	base64Key, ok := secretData[field].(string)
+	if !ok {
+		return nil, 0, fmt.Errorf("the KV secret %q has no string field %q", secretName, field)
+	}
+	key, err := base64.StdEncoding.DecodeString(base64Key)
+	if err != nil {
+		return nil, 0, fmt.Errorf("base64 decoding the field %q of the KV secret %q: %w", field, secretName, err)
+	}
+	if len(key) == 0 {
+		return nil, 0, fmt.Errorf("the field %q of the KV secret %q is empty", field, secretName)
+	}
+
+	readVersion, err := retrieveKVVersion(secret)
+	if err != nil {
+		return nil, 0, err
+	}
+	if version != 0 && readVersion != version {
+		return nil, 0, fmt.Errorf("requested version %d of the KV secret %q, got version %d", version, secretName, readVersion)
+	}
+
+	return key, readVersion, nil
+}
+
+func retrieveKVVersion(s *openbao.Secret) (int, error) {
+	metadata, ok := s.Data["metadata"].(map[string]interface{})
+	if !ok {
+		return 0, errors.New("failed to deserialize 'metadata' (check if the KV engine is version 2)")
+	}
+	var version int64
+	var err error
+	switch v := metadata["version"].(type) {
+	case json.Number:
+		version, err = v.Int64()
+	case float64:
+		version = int64(v)
+	default:
+		err = fmt.Errorf("unexpected type %T", v)
+	}
+	if err != nil || version <= 0 {
+		return 0, errors.New("failed to deserialize 'metadata.version' (it's either OpenTofu bug or incompatible OpenBao version)")
+	}
+	return int(version), nil
+}
+
 func retrievePlaintext(s *openbao.Secret) ([]byte, error) {
 	base64Plaintext, ok := s.Data["plaintext"].(string)
 	if !ok {
@@ -18,14 +18,20 @@ type Config struct {
 	Token   string `hcl:"token,optional"`
 
 	KeyName           string        `hcl:"key_name"`
+	KeySource         KeySource     `hcl:"key_source,optional"`
 	KeyLength         DataKeyLength `hcl:"key_length,optional"`
 	TransitEnginePath string        `hcl:"transit_engine_path,optional"`
 	AssociatedData    string        `hcl:"associated_data,optional"`
+
+	KVEnginePath string `hcl:"kv_engine_path,optional"`
+	KVKeyField   string `hcl:"kv_key_field,optional"`
 }
 
 const (
 	defaultDataKeyLength     DataKeyLength = 32
 	defaultTransitEnginePath string        = "/transit"
+	defaultKVEnginePath      string        = "/secret"
+	defaultKVKeyField        string        = "key"
 )
 
 func (c Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
@@ -35,6 +41,22 @@ func (c Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
 		}
 	}
 
+	if c.KeySource == "" {
+		c.KeySource = KeySourceTransit
+	}
+
+	if err := c.KeySource.Validate(); err != nil {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Cause: err,
+		}
+	}
+
+	if err := c.validateKeySourceOptions(); err != nil {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Cause: err,
+		}
+	}
+
 	if c.KeyLength == 0 {
 		c.KeyLength = defaultDataKeyLength
 	}
@@ -49,6 +71,14 @@ func (c Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
 		c.TransitEnginePath = defaultTransitEnginePath
 	}
 
+	if c.KVEnginePath == "" {
+		c.KVEnginePath = defaultKVEnginePath
+	}
+
+	if c.KVKeyField == "" {
+		c.KVKeyField = defaultKVKeyField
+	}
+
 	if c.AssociatedData != "" {
 		if _, err := base64.StdEncoding.DecodeString(c.AssociatedData); err != nil {
 			return nil, nil, &keyprovider.ErrInvalidConfiguration{
@@ -82,13 +112,61 @@ func (c Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
 		svc: service{
 			c:           client,
 			transitPath: c.TransitEnginePath,
+			kvPath:      c.KVEnginePath,
 		},
 		keyName:        c.KeyName,
+		keySource:      c.KeySource,
 		keyLength:      c.KeyLength,
 		associatedData: c.AssociatedData,
+		kvKeyField:     c.KVKeyField,
 	}, new(keyMeta), nil
 }
 
+// validateKeySourceOptions checks that only the options applicable to the selected key source are set.
+func (c Config) validateKeySourceOptions() error {
+	switch c.KeySource {
+	case KeySourceKV:
+		if c.KeyLength != 0 {
+			return fmt.Errorf("key_length cannot be used with the %q key source, the key length is determined by the stored key", KeySourceKV)
+		}
+		if c.TransitEnginePath != "" {
+			return fmt.Errorf("transit_engine_path cannot be used with the %q key source", KeySourceKV)
+		}
+		if c.AssociatedData != "" {
+			return fmt.Errorf("associated_data cannot be used with the %q key source", KeySourceKV)
+		}
+	default:
+		if c.KVEnginePath != "" {
+			return fmt.Errorf("kv_engine_path can only be used with the %q key source", KeySourceKV)
+		}
+		if c.KVKeyField != "" {
+			return fmt.Errorf("kv_key_field can only be used with the %q key source", KeySourceKV)
+		}
+	}
+	return nil
+}
+
+// KeySource selects where the key provider obtains keys from.
+type KeySource string
+
+const (
+	// KeySourceTransit generates a new data key with the Transit Secret Engine for every encryption.
+	KeySourceTransit KeySource = "transit"
+	// KeySourceKV reads the key material from a secret in a KV version 2 Secret Engine. New encryptions use the
+	// latest version of the secret, and the version used is recorded so that older data can still be decrypted after
+	// rotating the key.
+	KeySourceKV KeySource = "kv"
+)
+
+func (s KeySource) Validate() error {
+	switch s {
+	case KeySourceTransit, KeySourceKV:
+		return nil
+	default:
+		return fmt.Errorf("key source should be one of %q or %q: got %q", KeySourceTransit, KeySourceKV, s)
+	}
+}
+
 type DataKeyLength int
 
 func (l DataKeyLength) Validate() error {
@@ -0,0 +1,138 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package openbao
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeServer is a minimal in-memory implementation of the OpenBao HTTP API for a KV version 2 secret engine, so that
+// the key provider can be tested offline with the real OpenBao client.
+type fakeServer struct {
+	*httptest.Server
+
+	token  string
+	mount  string
+	mu     sync.Mutex
+	kvData map[string][]*fakeKVVersion
+}
+
+type fakeKVVersion struct {
+	data    map[string]any
+	created time.Time
+	deleted bool
+}
+
+func newFakeServer(t *testing.T, token string, mount string) *fakeServer {
+	f := &fakeServer{
+		token:  token,
+		mount:  strings.Trim(mount, "/"),
+		kvData: map[string][]*fakeKVVersion{},
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.handle))
+	t.Cleanup(f.Close)
+	return f
+}
+
+// putKV writes a new version of the secret and returns its version number.
+func (f *fakeServer) putKV(name string, data map[string]any) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.kvData[name] = append(f.kvData[name], &fakeKVVersion{data: data, created: time.Now()})
+	return len(f.kvData[name])
+}
+
+// deleteKV soft-deletes the given version of the secret.
+func (f *fakeServer) deleteKV(name string, version int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.kvData[name][version-1].deleted = true
+}
+
+func (f *fakeServer) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Vault-Token") != f.token {
+		writeFakeResponse(w, http.StatusForbidden, map[string]any{"errors": []string{"permission denied"}})
+		return
+	}
+
+	// The client may send paths with duplicate slashes, which OpenBao accepts.
+	prefix := "/v1/" + f.mount + "/data/"
+	requestPath := path.Clean(r.URL.Path)
+	if !strings.HasPrefix(requestPath, prefix) {
+		writeFakeResponse(w, http.StatusNotFound, map[string]any{"errors": []string{"no handler for route"}})
+		return
+	}
+	name := strings.TrimPrefix(requestPath, prefix)
+
+	switch r.Method {
+	case http.MethodGet:
+		f.handleKVRead(w, r, name)
+	case http.MethodPost, http.MethodPut:
+		var body struct {
+			Data map[string]any `json:"data"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeFakeResponse(w, http.StatusBadRequest, map[string]any{"errors": []string{err.Error()}})
+			return
+		}
+		version := f.putKV(name, body.Data)
+		writeFakeResponse(w, http.StatusOK, map[string]any{"data": map[string]any{"version": version}})
+	default:
+		writeFakeResponse(w, http.StatusMethodNotAllowed, map[string]any{"errors": []string{"unsupported operation"}})
+	}
+}
+
+func (f *fakeServer) handleKVRead(w http.ResponseWriter, r *http.Request, name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	versions := f.kvData[name]
+	if len(versions) == 0 {
+		writeFakeResponse(w, http.StatusNotFound, map[string]any{"errors": []string{}})
+		return
+	}
+
+	version := len(versions)
+	if rawVersion := r.URL.Query().Get("version"); rawVersion != "" && rawVersion != "0" {
+		var err error
+		version, err = strconv.Atoi(rawVersion)
+		if err != nil || version < 1 || version > len(versions) {
+			writeFakeResponse(w, http.StatusNotFound, map[string]any{"errors": []string{}})
+			return
+		}
+	}
+
+	v := versions[version-1]
+	metadata := map[string]any{
+		"version":      version,
+		"created_time": v.created.Format(time.RFC3339Nano),
+		"destroyed":    false,
+	}
+	if v.deleted {
+		// OpenBao responds with a 404 and the metadata only for deleted versions.
+		metadata["deletion_time"] = v.created.Format(time.RFC3339Nano)
+		writeFakeResponse(w, http.StatusNotFound, map[string]any{"data": map[string]any{"data": nil, "metadata": metadata}})
+		return
+	}
+	metadata["deletion_time"] = ""
+	writeFakeResponse(w, http.StatusOK, map[string]any{"data": map[string]any{"data": v.data, "metadata": metadata}})
+}
+
+func writeFakeResponse(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,332 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package openbao
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/compliancetest"
+)
+
+const (
+	fakeServerToken    = "s.faketoken"
+	defaultTestKVName  = "tofu/state-key"
+	defaultTestKVField = "key"
+)
+
+// The KV tests run against an in-memory fake server speaking the OpenBao HTTP API, using the real OpenBao client.
+
+func newKVTestServer(t *testing.T) *fakeServer {
+	server := newFakeServer(t, fakeServerToken, defaultKVEnginePath)
+	server.putKV(defaultTestKVName, map[string]any{
+		defaultTestKVField: base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32)),
+	})
+	return server
+}
+
+func TestKeyProvider_kv(t *testing.T) {
+	server := newKVTestServer(t)
+
+	validConfig := &Config{
+		Address:   server.URL,
+		Token:     fakeServerToken,
+		KeyName:   defaultTestKVName,
+		KeySource: KeySourceKV,
+	}
+
+	compliancetest.ComplianceTest(
+		t,
+		compliancetest.TestConfiguration[*descriptor, *Config, *keyMeta, *keyProvider]{
+			Descriptor: New().(*descriptor),
+			HCLParseTestCases: map[string]compliancetest.HCLParseTestCase[*Config, *keyProvider]{
+				"success": {
+					HCL: fmt.Sprintf(`key_provider "openbao" "foo" {
+							address = "%s"
+							token = "%s"
+							key_name = "%s"
+							key_source = "kv"
+						}`, server.URL, fakeServerToken, defaultTestKVName),
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(config *Config, keyProvider *keyProvider) error {
+						if keyProvider.svc.kvPath != defaultKVEnginePath {
+							return fmt.Errorf("invalid default kv path: %v", keyProvider.svc.kvPath)
+						}
+						if keyProvider.kvKeyField != defaultKVKeyField {
+							return fmt.Errorf("invalid default kv key field: %v", keyProvider.kvKeyField)
+						}
+						return nil
+					},
+				},
+				"custom-kv-options": {
+					HCL: fmt.Sprintf(`key_provider "openbao" "foo" {
+							key_name = "%s"
+							key_source = "kv"
+							kv_engine_path = "/kv"
+							kv_key_field = "material"
+						}`, defaultTestKVName),
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(config *Config, keyProvider *keyProvider) error {
+						if keyProvider.svc.kvPath != "/kv" {
+							return fmt.Errorf("invalid kv path: %v", keyProvider.svc.kvPath)
+						}
+						if keyProvider.kvKeyField != "material" {
+							return fmt.Errorf("invalid kv key field: %v", keyProvider.kvKeyField)
+						}
+						return nil
+					},
+				},
+				"invalid-key-source": {
+					HCL: fmt.Sprintf(`key_provider "openbao" "foo" {
+							key_name = "%s"
+							key_source = "pki"
+						}`, defaultTestKVName),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"kv-with-key-length": {
+					HCL: fmt.Sprintf(`key_provider "openbao" "foo" {
+							key_name = "%s"
+							key_source = "kv"
+							key_length = 32
+						}`, defaultTestKVName),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"kv-with-transit-path": {
+					HCL: fmt.Sprintf(`key_provider "openbao" "foo" {
+							key_name = "%s"
+							key_source = "kv"
+							transit_engine_path = "/transit"
+						}`, defaultTestKVName),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"unknown-kv-property": {
+					HCL: fmt.Sprintf(`key_provider "openbao" "foo" {
+							key_name = "%s"
+							key_source = "kv"
+							kv_version = 1
+						}`, defaultTestKVName),
+					ValidHCL:   false,
+					ValidBuild: false,
+				},
+				"transit-with-kv-path": {
+					HCL: fmt.Sprintf(`key_provider "openbao" "foo" {
+							key_name = "%s"
+							kv_engine_path = "/kv"
+						}`, defaultTestKVName),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+			},
+			JSONParseTestCases: map[string]compliancetest.JSONParseTestCase[*Config, *keyProvider]{
+				"success": {
+					JSON: fmt.Sprintf(`{
+	"key_provider": {
+		"openbao": {
+			"foo": {
+				"address": "%s",
+				"token": "%s",
+				"key_name": "%s",
+				"key_source": "kv"
+			}
+		}
+	}
+}`, server.URL, fakeServerToken, defaultTestKVName),
+					ValidJSON:  true,
+					ValidBuild: true,
+				},
+				"kv-with-associated-data": {
+					JSON: fmt.Sprintf(`{
+	"key_provider": {
+		"openbao": {
+			"foo": {
+				"key_name": "%s",
+				"key_source": "kv",
+				"associated_data": "b3BlbnRvZnU="
+			}
+		}
+	}
+}`, defaultTestKVName),
+					ValidJSON:  true,
+					ValidBuild: false,
+				},
+			},
+			ConfigStructTestCases: map[string]compliancetest.ConfigStructTestCase[*Config, *keyProvider]{
+				"success": {
+					Config:     validConfig,
+					ValidBuild: true,
+					Validate: func(p *keyProvider) error {
+						if p.keySource != KeySourceKV {
+							return fmt.Errorf("invalid key source: %v", p.keySource)
+						}
+						return nil
+					},
+				},
+				"invalid-key-source": {
+					Config: &Config{
+						KeyName:   defaultTestKVName,
+						KeySource: "pki",
+					},
+					ValidBuild: false,
+				},
+			},
+			MetadataStructTestCases: map[string]compliancetest.MetadataStructTestCase[*Config, *keyMeta]{
+				"empty": {
+					ValidConfig: validConfig,
+					Meta:        &keyMeta{},
+					IsPresent:   false,
+					IsValid:     false,
+				},
+				"transit-metadata": {
+					ValidConfig: validConfig,
+					Meta:        &keyMeta{Ciphertext: []byte("vault:v1:abcd")},
+					IsPresent:   true,
+					IsValid:     false,
+				},
+				"valid": {
+					ValidConfig: validConfig,
+					Meta:        &keyMeta{KVVersion: 1},
+					IsPresent:   true,
+					IsValid:     true,
+				},
+			},
+			ProvideTestCase: compliancetest.ProvideTestCase[*Config, *keyMeta]{
+				ValidConfig: validConfig,
+				ExpectedOutput: &keyprovider.Output{
+					EncryptionKey: bytes.Repeat([]byte{1}, 32),
+					DecryptionKey: bytes.Repeat([]byte{1}, 32),
+				},
+				ValidateMetadata: func(meta *keyMeta) error {
+					if meta.KVVersion != 1 {
+						return fmt.Errorf("incorrect KV version: %d", meta.KVVersion)
+					}
+					return nil
+				},
+			},
+		},
+	)
+}
+
+func TestKeyProvider_kvRotation(t *testing.T) {
+	server := newKVTestServer(t)
+	oldKey := bytes.Repeat([]byte{1}, 32)
+	newKey := bytes.Repeat([]byte{2}, 32)
+
+	provider, emptyMeta, err := Config{
+		Address:   server.URL,
+		Token:     fakeServerToken,
+		KeyName:   defaultTestKVName,
+		KeySource: KeySourceKV,
+	}.Build()
+	if err != nil {
+		t.Fatalf("failed to build key provider: %v", err)
+	}
+
+	out, meta, err := provider.Provide(emptyMeta)
+	if err != nil {
+		t.Fatalf("failed to provide key: %v", err)
+	}
+	if !bytes.Equal(out.EncryptionKey, oldKey) {
+		t.Fatalf("incorrect encryption key before rotation: %x", out.EncryptionKey)
+	}
+
+	// Rotate the key by writing a new version of the secret.
+	server.putKV(defaultTestKVName, map[string]any{
+		defaultTestKVField: base64.StdEncoding.EncodeToString(newKey),
+	})
+
+	out, newMeta, err := provider.Provide(meta)
+	if err != nil {
+		t.Fatalf("failed to provide key after rotation: %v", err)
+	}
+	if !bytes.Equal(out.EncryptionKey, newKey) {
+		t.Errorf("the new key version was not picked up for encryption: %x", out.EncryptionKey)
+	}
+	if !bytes.Equal(out.DecryptionKey, oldKey) {
+		t.Errorf("the old key version was not used for decryption: %x", out.DecryptionKey)
+	}
+	if v := newMeta.(*keyMeta).KVVersion; v != 2 {
+		t.Errorf("incorrect KV version in the metadata after rotation: %d", v)
+	}
+}
+
+func TestKeyProvider_kvErrors(t *testing.T) {
+	server := newKVTestServer(t)
+	server.putKV(defaultTestKVName, map[string]any{
+		defaultTestKVField: base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 32)),
+	})
+	server.deleteKV(defaultTestKVName, 1)
+	server.putKV("tofu/no-field", map[string]any{
+		"other": base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{3}, 32)),
+	})
+	server.putKV("tofu/not-base64", map[string]any{
+		defaultTestKVField: "not base64!",
+	})
+
+	tests := map[string]struct {
+		keyName string
+		token   string
+		meta    *keyMeta
+	}{
+		"deleted-version": {
+			keyName: defaultTestKVName,
+			meta:    &keyMeta{KVVersion: 1},
+		},
+		"missing-version": {
+			keyName: defaultTestKVName,
+			meta:    &keyMeta{KVVersion: 3},
+		},
+		"missing-secret": {
+			keyName: "tofu/missing",
+			meta:    &keyMeta{},
+		},
+		"missing-field": {
+			keyName: "tofu/no-field",
+			meta:    &keyMeta{},
+		},
+		"not-base64": {
+			keyName: "tofu/not-base64",
+			meta:    &keyMeta{},
+		},
+		"wrong-token": {
+			keyName: defaultTestKVName,
+			token:   "s.wrongtoken",
+			meta:    &keyMeta{},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			token := tc.token
+			if token == "" {
+				token = fakeServerToken
+			}
+			provider, _, err := Config{
+				Address:   server.URL,
+				Token:     token,
+				KeyName:   tc.keyName,
+				KeySource: KeySourceKV,
+			}.Build()
+			if err != nil {
+				t.Fatalf("failed to build key provider: %v", err)
+			}
+
+			_, _, err = provider.Provide(tc.meta)
+			var typedErr *keyprovider.ErrKeyProviderFailure
+			if !errors.As(err, &typedErr) {
+				t.Fatalf("expected %T, got %T: %v", typedErr, err, err)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ package openbao
 
 import (
 	"context"
+	"fmt"
 
 	openbao "github.com/openbao/openbao/api/v2"
 )
@@ -17,6 +18,10 @@ func (f mockClientFunc) WriteWithContext(ctx context.Context, path string, data
 	return f(ctx, path, data)
 }
 
+func (f mockClientFunc) ReadWithDataWithContext(_ context.Context, path string, _ map[string][]string) (*openbao.Secret, error) {
+	return nil, fmt.Errorf("reading %s is not supported by the mock", path)
+}
+
 func injectMock(m mockClientFunc) {
 	newClient = func(_ *openbao.Config, _ string) (client, error) {
 		return m, nil
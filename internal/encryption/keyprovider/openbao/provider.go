@@ -7,6 +7,7 @@ package openbao
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
 )
@@ -14,17 +15,21 @@ import (
 type keyMeta struct {
 	Ciphertext     []byte `json:"ciphertext"`
 	AssociatedData string `json:"associated_data,omitempty"`
+	// KVVersion is the version of the KV secret the key was read from when using the "kv" key source.
+	KVVersion int `json:"kv_version,omitempty"`
 }
 
 func (m keyMeta) isPresent() bool {
-	return len(m.Ciphertext) != 0
+	return len(m.Ciphertext) != 0 || m.KVVersion != 0
 }
 
 type keyProvider struct {
 	svc            service
 	keyName        string
+	keySource      KeySource
 	keyLength      DataKeyLength
 	associatedData string
+	kvKeyField     string
 }
 
 func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
@@ -43,6 +48,10 @@ func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, k
 
 	ctx := context.Background()
 
+	if p.keySource == KeySourceKV {
+		return p.provideFromKV(ctx, inMeta)
+	}
+
 	dataKey, err := p.svc.generateDataKey(ctx, p.keyName, p.keyLength.Bits(), p.associatedData)
 	if err != nil {
 		return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
@@ -72,3 +81,40 @@ func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, k
 
 	return out, outMeta, nil
 }
+
+// provideFromKV returns the latest version of the key stored in the KV secret as the encryption key, and the version
+// recorded in the metadata as the decryption key.
+func (p keyProvider) provideFromKV(ctx context.Context, inMeta *keyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	key, version, err := p.svc.readKVKey(ctx, p.keyName, p.kvKeyField, 0)
+	if err != nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
+			Message: "failed to read the latest key from the OpenBao KV secret (check if the configuration is valid and OpenBao server accessible)",
+			Cause:   err,
+		}
+	}
+
+	out := keyprovider.Output{
+		EncryptionKey: key,
+	}
+
+	if inMeta.isPresent() {
+		if inMeta.KVVersion == 0 {
+			return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+				Message: "the metadata contains no KV secret version, the data may have been encrypted using the transit key source",
+			}
+		}
+		if inMeta.KVVersion == version {
+			out.DecryptionKey = key
+		} else {
+			out.DecryptionKey, _, err = p.svc.readKVKey(ctx, p.keyName, p.kvKeyField, inMeta.KVVersion)
+			if err != nil {
+				return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
+					Message: fmt.Sprintf("failed to read version %d of the key from the OpenBao KV secret (check if the configuration is valid and OpenBao server accessible)", inMeta.KVVersion),
+					Cause:   err,
+				}
+			}
+		}
+	}
+
+	return out, &keyMeta{KVVersion: version}, nil
+}
@@ -0,0 +1,213 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package pkcs11
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/compliancetest"
+)
+
+// By default the tests in here behave like unit tests, running against a
+// mock token implemented in software.
+//
+// It's also possible to run them as acceptance tests, against a real PKCS#11
+// token such as SoftHSM. The rest of this comment describes how to do that.
+//
+// Create a token and an AES key on it:
+//     softhsm2-util --init-token --free --label tofu --pin 1234 --so-pin 1234
+//     pkcs11-tool --module /usr/lib/softhsm/libsofthsm2.so --token-label tofu \
+//         --login --pin 1234 --keygen --key-type AES:32 --label tofu-state
+//
+// Now run the compliance tests in this package against the token:
+//     TF_ACC=1 TF_ACC_PKCS11_MODULE=/usr/lib/softhsm/libsofthsm2.so \
+//         TF_ACC_PKCS11_TOKEN_LABEL=tofu TF_ACC_PKCS11_PIN=1234 \
+//         TF_ACC_PKCS11_KEY_LABEL=tofu-state \
+//         go test ./internal/encryption/keyprovider/pkcs11
+
+func getTestConfig() *Config {
+	// Acceptance tests are disabled, running with mock.
+	if os.Getenv("TF_ACC") == "" || os.Getenv("TF_ACC_PKCS11_MODULE") == "" {
+		return nil
+	}
+	return &Config{
+		Module:     os.Getenv("TF_ACC_PKCS11_MODULE"),
+		TokenLabel: os.Getenv("TF_ACC_PKCS11_TOKEN_LABEL"),
+		PIN:        os.Getenv("TF_ACC_PKCS11_PIN"),
+		KeyLabel:   os.Getenv("TF_ACC_PKCS11_KEY_LABEL"),
+	}
+}
+
+func TestKeyProvider(t *testing.T) {
+	validConfig := getTestConfig()
+	if validConfig == nil {
+		validConfig = &Config{
+			Module:     "/usr/lib/softhsm/libsofthsm2.so",
+			TokenLabel: "tofu",
+			PIN:        "1234",
+			KeyLabel:   "tofu-state",
+		}
+		injectMock(t, newMockToken(t))
+	}
+
+	compliancetest.ComplianceTest(
+		t,
+		compliancetest.TestConfiguration[*descriptor, *Config, *Metadata, *keyProvider]{
+			Descriptor: New().(*descriptor),
+			HCLParseTestCases: map[string]compliancetest.HCLParseTestCase[*Config, *keyProvider]{
+				"success": {
+					HCL: fmt.Sprintf(`key_provider "pkcs11" "foo" {
+	module      = "%s"
+	token_label = "%s"
+	pin         = "%s"
+	key_label   = "%s"
+}`, validConfig.Module, validConfig.TokenLabel, validConfig.PIN, validConfig.KeyLabel),
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(config *Config, keyProvider *keyProvider) error {
+						if config.KeyLabel != validConfig.KeyLabel {
+							return fmt.Errorf("incorrect key label returned")
+						}
+						return nil
+					},
+				},
+				"empty": {
+					HCL:        `key_provider "pkcs11" "foo" {}`,
+					ValidHCL:   false,
+					ValidBuild: false,
+				},
+				"empty-module": {
+					HCL: `key_provider "pkcs11" "foo" {
+	module      = ""
+	token_label = "tofu"
+	pin         = "1234"
+	key_label   = "tofu-state"
+}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"empty-key-label": {
+					HCL: `key_provider "pkcs11" "foo" {
+	module      = "/usr/lib/softhsm/libsofthsm2.so"
+	token_label = "tofu"
+	pin         = "1234"
+	key_label   = ""
+}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"unknown-property": {
+					HCL: `key_provider "pkcs11" "foo" {
+	module           = "/usr/lib/softhsm/libsofthsm2.so"
+	token_label      = "tofu"
+	pin              = "1234"
+	key_label        = "tofu-state"
+	unknown_property = "foo"
+}`,
+					ValidHCL:   false,
+					ValidBuild: false,
+				},
+			},
+			JSONParseTestCases: map[string]compliancetest.JSONParseTestCase[*Config, *keyProvider]{
+				"success": {
+					JSON: fmt.Sprintf(`{
+	"key_provider": {
+		"pkcs11": {
+			"foo": {
+				"module": "%s",
+				"token_label": "%s",
+				"pin": "%s",
+				"key_label": "%s"
+			}
+		}
+	}
+}`, validConfig.Module, validConfig.TokenLabel, validConfig.PIN, validConfig.KeyLabel),
+					ValidJSON:  true,
+					ValidBuild: true,
+				},
+				"empty": {
+					JSON: `{
+	"key_provider": {
+		"pkcs11": {
+			"foo": {
+			}
+		}
+	}
+}`,
+					ValidJSON:  false,
+					ValidBuild: false,
+				},
+			},
+			ConfigStructTestCases: map[string]compliancetest.ConfigStructTestCase[*Config, *keyProvider]{
+				"success": {
+					Config:     validConfig,
+					ValidBuild: true,
+				},
+				"empty": {
+					Config:     &Config{},
+					ValidBuild: false,
+				},
+			},
+			MetadataStructTestCases: map[string]compliancetest.MetadataStructTestCase[*Config, *Metadata]{
+				"empty": {
+					ValidConfig: validConfig,
+					Meta:        &Metadata{},
+					IsPresent:   false,
+					IsValid:     false,
+				},
+				"invalid": {
+					ValidConfig: validConfig,
+					Meta: &Metadata{
+						Ciphertext: []byte("not a valid ciphertext"),
+						IV:         make([]byte, 12),
+					},
+					IsPresent: true,
+					IsValid:   false,
+				},
+				"valid": {
+					ValidConfig: validConfig,
+					Meta:        presentValidMetadata(t, validConfig),
+					IsPresent:   true,
+					IsValid:     true,
+				},
+				"no-iv": {
+					ValidConfig: validConfig,
+					Meta: &Metadata{
+						Ciphertext: []byte("not a valid ciphertext"),
+					},
+					IsPresent: true,
+					IsValid:   false,
+				},
+			},
+			ProvideTestCase: compliancetest.ProvideTestCase[*Config, *Metadata]{
+				ValidConfig: validConfig,
+				ValidateMetadata: func(meta *Metadata) error {
+					if !meta.isPresent() {
+						return fmt.Errorf("ciphertext is empty")
+					}
+					if len(meta.IV) == 0 {
+						return fmt.Errorf("IV is empty")
+					}
+					return nil
+				},
+			},
+		},
+	)
+}
+
+func presentValidMetadata(t *testing.T, cfg *Config) *Metadata {
+	provider, metaIn, err := cfg.Build()
+	if err != nil {
+		t.Fatalf("failed to build key provider for present-valid metadata: %s", err)
+	}
+	_, meta, err := provider.Provide(metaIn)
+	if err != nil {
+		t.Fatalf("failed to generate present-valid metadata: %s", err)
+	}
+	return meta.(*Metadata)
+}
@@ -0,0 +1,51 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package pkcs11
+
+import (
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+// dataKeyLength is the length of the generated data keys. 32 bytes is suitable for all built-in encryption methods.
+const dataKeyLength = 32
+
+// Can be overridden for test mocking
+var newToken func(Config) token = newPKCS11Token
+
+// Config contains the configuration for this key provider supplied by the user.
+type Config struct {
+	// Module is the path to the PKCS#11 library (shared object) of the token vendor.
+	Module string `hcl:"module"`
+	// TokenLabel is the label of the token holding the key.
+	TokenLabel string `hcl:"token_label"`
+	// PIN is the user PIN used to log in to the token.
+	PIN string `hcl:"pin"`
+	// KeyLabel is the label of the AES secret key on the token used to encrypt the data keys.
+	KeyLabel string `hcl:"key_label"`
+}
+
+// Build will create the usable key provider. It does not access the token, this only happens when a key is requested.
+func (c Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
+	if c.Module == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "no module provided",
+		}
+	}
+	if c.TokenLabel == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "no token_label provided",
+		}
+	}
+	if c.KeyLabel == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "no key_label provided",
+		}
+	}
+
+	return &keyProvider{
+		token: newToken(c),
+	}, new(Metadata), nil
+}
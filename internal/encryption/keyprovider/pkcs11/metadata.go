@@ -0,0 +1,17 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package pkcs11
+
+// Metadata contains the data key encrypted with the AES key stored on the token, along with the IV used for the
+// encryption.
+type Metadata struct {
+	Ciphertext []byte `json:"ciphertext"`
+	IV         []byte `json:"iv"`
+}
+
+func (m Metadata) isPresent() bool {
+	return len(m.Ciphertext) != 0
+}
@@ -0,0 +1,58 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package pkcs11
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"io"
+	"testing"
+)
+
+// mockToken implements the token interface in software, with the same AES-GCM construction the PKCS#11 token uses.
+type mockToken struct {
+	aead cipher.AEAD
+}
+
+func newMockToken(t testing.TB) *mockToken {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		t.Fatal(err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &mockToken{aead}
+}
+
+func (m *mockToken) encrypt(plaintext []byte) ([]byte, []byte, error) {
+	iv := make([]byte, m.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, nil, err
+	}
+	return iv, m.aead.Seal(nil, iv, plaintext, nil), nil
+}
+
+func (m *mockToken) decrypt(iv []byte, ciphertext []byte) ([]byte, error) {
+	return m.aead.Open(nil, iv, ciphertext, nil)
+}
+
+func injectMock(t testing.TB, m token) {
+	original := newToken
+	t.Cleanup(func() {
+		newToken = original
+	})
+
+	newToken = func(_ Config) token {
+		return m
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package pkcs11 contains a key provider that generates a random data key and encrypts it with an AES key stored on a
+// PKCS#11 token, such as a hardware security module or SoftHSM.
+package pkcs11
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+type keyProvider struct {
+	token token
+}
+
+func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	if rawMeta == nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+			Message: "bug: no metadata struct provided",
+		}
+	}
+	inMeta, ok := rawMeta.(*Metadata)
+	if !ok {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+			Message: fmt.Sprintf("bug: invalid metadata type received: %T", rawMeta),
+		}
+	}
+
+	dataKey := make([]byte, dataKeyLength)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
+			Message: "failed to generate data key",
+			Cause:   err,
+		}
+	}
+	iv, ciphertext, err := p.token.encrypt(dataKey)
+	if err != nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
+			Message: "failed to encrypt data key with the PKCS#11 token (check if the configuration is valid and the token accessible)",
+			Cause:   err,
+		}
+	}
+
+	out := keyprovider.Output{
+		EncryptionKey: dataKey,
+	}
+
+	// We only set the decryption key if we received metadata, which means OpenTofu is decrypting something.
+	if inMeta.isPresent() {
+		if len(inMeta.IV) == 0 {
+			return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+				Message: "no IV found in the metadata",
+			}
+		}
+		out.DecryptionKey, err = p.token.decrypt(inMeta.IV, inMeta.Ciphertext)
+		if err != nil {
+			return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+				Message: "failed to decrypt data key with the PKCS#11 token",
+				Cause:   err,
+			}
+		}
+	}
+
+	return out, &Metadata{Ciphertext: ciphertext, IV: iv}, nil
+}
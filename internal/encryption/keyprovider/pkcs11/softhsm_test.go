@@ -0,0 +1,194 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build cgo
+
+package pkcs11
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/miekg/pkcs11"
+)
+
+// softHSMModules are the usual install locations of the SoftHSM library. TF_PKCS11_SOFTHSM_MODULE takes precedence
+// over them.
+var softHSMModules = []string{
+	"/usr/lib/softhsm/libsofthsm2.so",
+	"/usr/lib/x86_64-linux-gnu/softhsm/libsofthsm2.so",
+	"/usr/lib/aarch64-linux-gnu/softhsm/libsofthsm2.so",
+	"/usr/lib64/pkcs11/libsofthsm2.so",
+	"/usr/local/lib/softhsm/libsofthsm2.so",
+	"/opt/homebrew/lib/softhsm/libsofthsm2.so",
+}
+
+const (
+	softHSMTokenLabel = "tofu-test"
+	softHSMPIN        = "1234"
+	softHSMKeyLabel   = "tofu-test-state"
+)
+
+// setupSoftHSM initializes a token with an AES key in a fresh SoftHSM token directory, and returns the configuration
+// of the key provider for it. The test is skipped if SoftHSM isn't installed.
+func setupSoftHSM(t *testing.T) Config {
+	t.Helper()
+
+	module := os.Getenv("TF_PKCS11_SOFTHSM_MODULE")
+	if module == "" {
+		for _, candidate := range softHSMModules {
+			if _, err := os.Stat(candidate); err == nil {
+				module = candidate
+				break
+			}
+		}
+	}
+	if module == "" {
+		t.Skip("SoftHSM is not installed, set TF_PKCS11_SOFTHSM_MODULE to the path of libsofthsm2.so to run this test")
+	}
+
+	modulesMu.Lock()
+	_, loaded := modules[module]
+	modulesMu.Unlock()
+	if loaded {
+		// SoftHSM only reads its configuration when it's initialized, which happens once per process.
+		t.Skip("SoftHSM was already loaded with a different configuration")
+	}
+
+	dir := t.TempDir()
+	tokens := filepath.Join(dir, "tokens")
+	if err := os.Mkdir(tokens, 0700); err != nil {
+		t.Fatal(err)
+	}
+	conf := filepath.Join(dir, "softhsm2.conf")
+	if err := os.WriteFile(conf, []byte(fmt.Sprintf("directories.tokendir = %s\nobjectstore.backend = file\nlog.level = ERROR\n", tokens)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SOFTHSM2_CONF", conf)
+
+	ctx, err := loadModule(module)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var slot uint
+	found := false
+	for _, candidate := range slots {
+		info, err := ctx.GetTokenInfo(candidate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Flags&pkcs11.CKF_TOKEN_INITIALIZED == 0 {
+			slot, found = candidate, true
+			break
+		}
+	}
+	if !found {
+		t.Fatal("SoftHSM has no free slot")
+	}
+	if err := ctx.InitToken(slot, softHSMPIN, softHSMTokenLabel); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := Config{
+		Module:     module,
+		TokenLabel: softHSMTokenLabel,
+		PIN:        softHSMPIN,
+		KeyLabel:   softHSMKeyLabel,
+	}
+
+	// SoftHSM moves the token to a new slot once it's initialized.
+	slot, err = (&pkcs11Token{cfg}).findSlot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = ctx.CloseSession(session)
+	}()
+
+	if err := ctx.Login(session, pkcs11.CKU_SO, softHSMPIN); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.InitPIN(session, softHSMPIN); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.Logout(session); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.Login(session, pkcs11.CKU_USER, softHSMPIN); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = ctx.Logout(session)
+	}()
+
+	_, err = ctx.GenerateKey(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_KEY_GEN, nil)}, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_AES),
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE_LEN, 32),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, softHSMKeyLabel),
+		pkcs11.NewAttribute(pkcs11.CKA_ENCRYPT, true),
+		pkcs11.NewAttribute(pkcs11.CKA_DECRYPT, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cfg
+}
+
+// TestKeyProvider_softHSM runs the key provider against a token created in SoftHSM, whenever SoftHSM is installed.
+func TestKeyProvider_softHSM(t *testing.T) {
+	cfg := setupSoftHSM(t)
+
+	kp, meta, err := cfg.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encryptOut, encryptMeta, err := kp.Provide(meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(encryptOut.EncryptionKey) != dataKeyLength {
+		t.Fatalf("expected a %d byte encryption key, got %d bytes", dataKeyLength, len(encryptOut.EncryptionKey))
+	}
+	if encryptOut.DecryptionKey != nil {
+		t.Fatal("expected no decryption key without metadata")
+	}
+
+	decryptOut, _, err := kp.Provide(encryptMeta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decryptOut.DecryptionKey, encryptOut.EncryptionKey) {
+		t.Fatal("the decryption key doesn't match the encryption key")
+	}
+
+	// The key provider fails if the key isn't on the token.
+	wrongCfg := cfg
+	wrongCfg.KeyLabel = "missing"
+	wrongKp, _, err := wrongCfg.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := wrongKp.Provide(encryptMeta); err == nil {
+		t.Fatal("expected an error for a missing key")
+	}
+}
@@ -0,0 +1,14 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package pkcs11
+
+// token describes the operations the key provider performs with the AES key stored on the PKCS#11 token.
+type token interface {
+	// encrypt encrypts the plaintext with AES-GCM and returns the IV used along with the ciphertext.
+	encrypt(plaintext []byte) (iv []byte, ciphertext []byte, err error)
+	// decrypt decrypts an AES-GCM ciphertext with the given IV.
+	decrypt(iv []byte, ciphertext []byte) ([]byte, error)
+}
@@ -0,0 +1,191 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build cgo
+
+package pkcs11
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+)
+
+// Supported is true when OpenTofu was built with cgo, which is needed to load PKCS#11 libraries.
+const Supported = true
+
+const (
+	gcmIVLength = 12
+	gcmTagBits  = 128
+)
+
+var (
+	// modules holds the loaded and initialized PKCS#11 libraries. A library can only be initialized once per process,
+	// so they are shared between all key providers and never finalized.
+	modules   = map[string]*pkcs11.Ctx{}
+	modulesMu sync.Mutex
+)
+
+func loadModule(path string) (*pkcs11.Ctx, error) {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+
+	if ctx, ok := modules[path]; ok {
+		return ctx, nil
+	}
+	ctx := pkcs11.New(path)
+	if ctx == nil {
+		return nil, fmt.Errorf("failed to load PKCS#11 module %s", path)
+	}
+	if err := ctx.Initialize(); err != nil {
+		var p11Err pkcs11.Error
+		if !errors.As(err, &p11Err) || p11Err != pkcs11.CKR_CRYPTOKI_ALREADY_INITIALIZED {
+			ctx.Destroy()
+			return nil, fmt.Errorf("failed to initialize PKCS#11 module %s: %w", path, err)
+		}
+	}
+	modules[path] = ctx
+	return ctx, nil
+}
+
+type pkcs11Token struct {
+	Config
+}
+
+func newPKCS11Token(cfg Config) token {
+	return &pkcs11Token{cfg}
+}
+
+// withKey opens a session on the configured token, logs in, and calls fn with the handle of the configured key.
+func (t *pkcs11Token) withKey(fn func(ctx *pkcs11.Ctx, session pkcs11.SessionHandle, key pkcs11.ObjectHandle) error) error {
+	ctx, err := loadModule(t.Module)
+	if err != nil {
+		return err
+	}
+
+	slot, err := t.findSlot(ctx)
+	if err != nil {
+		return err
+	}
+
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION)
+	if err != nil {
+		return fmt.Errorf("failed to open session on token %q: %w", t.TokenLabel, err)
+	}
+	defer func() {
+		_ = ctx.CloseSession(session)
+	}()
+
+	if err := ctx.Login(session, pkcs11.CKU_USER, t.PIN); err != nil {
+		var p11Err pkcs11.Error
+		if !errors.As(err, &p11Err) || p11Err != pkcs11.CKR_USER_ALREADY_LOGGED_IN {
+			return fmt.Errorf("failed to log in to token %q: %w", t.TokenLabel, err)
+		}
+	}
+
+	key, err := t.findKey(ctx, session)
+	if err != nil {
+		return err
+	}
+	return fn(ctx, session, key)
+}
+
+func (t *pkcs11Token) findSlot(ctx *pkcs11.Ctx) (uint, error) {
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list PKCS#11 slots: %w", err)
+	}
+	for _, slot := range slots {
+		info, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get token info for slot %d: %w", slot, err)
+		}
+		if info.Label == t.TokenLabel {
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("no token with the label %q found", t.TokenLabel)
+}
+
+func (t *pkcs11Token) findKey(ctx *pkcs11.Ctx, session pkcs11.SessionHandle) (pkcs11.ObjectHandle, error) {
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_AES),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, t.KeyLabel),
+	}
+	if err := ctx.FindObjectsInit(session, template); err != nil {
+		return 0, fmt.Errorf("failed to search for key %q: %w", t.KeyLabel, err)
+	}
+	objects, _, err := ctx.FindObjects(session, 2)
+	finalErr := ctx.FindObjectsFinal(session)
+	if err != nil {
+		return 0, fmt.Errorf("failed to search for key %q: %w", t.KeyLabel, err)
+	}
+	if finalErr != nil {
+		return 0, fmt.Errorf("failed to search for key %q: %w", t.KeyLabel, finalErr)
+	}
+	switch len(objects) {
+	case 0:
+		return 0, fmt.Errorf("no AES key with the label %q found on token %q", t.KeyLabel, t.TokenLabel)
+	case 1:
+		return objects[0], nil
+	default:
+		return 0, fmt.Errorf("multiple AES keys with the label %q found on token %q", t.KeyLabel, t.TokenLabel)
+	}
+}
+
+func (t *pkcs11Token) encrypt(plaintext []byte) ([]byte, []byte, error) {
+	iv := make([]byte, gcmIVLength)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate IV: %w", err)
+	}
+
+	var ciphertext []byte
+	err := t.withKey(func(ctx *pkcs11.Ctx, session pkcs11.SessionHandle, key pkcs11.ObjectHandle) error {
+		params := pkcs11.NewGCMParams(iv, nil, gcmTagBits)
+		defer params.Free()
+
+		if err := ctx.EncryptInit(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_GCM, params)}, key); err != nil {
+			return fmt.Errorf("failed to initialize encryption: %w", err)
+		}
+		var err error
+		ciphertext, err = ctx.Encrypt(session, plaintext)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt: %w", err)
+		}
+		// Some tokens ignore the IV passed in and generate their own, so read back the one actually used.
+		if actualIV := params.IV(); len(actualIV) != 0 {
+			iv = actualIV
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return iv, ciphertext, nil
+}
+
+func (t *pkcs11Token) decrypt(iv []byte, ciphertext []byte) ([]byte, error) {
+	var plaintext []byte
+	err := t.withKey(func(ctx *pkcs11.Ctx, session pkcs11.SessionHandle, key pkcs11.ObjectHandle) error {
+		params := pkcs11.NewGCMParams(iv, nil, gcmTagBits)
+		defer params.Free()
+
+		if err := ctx.DecryptInit(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_GCM, params)}, key); err != nil {
+			return fmt.Errorf("failed to initialize decryption: %w", err)
+		}
+		var err error
+		plaintext, err = ctx.Decrypt(session, ciphertext)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt: %w", err)
+		}
+		return nil
+	})
+	return plaintext, err
+}
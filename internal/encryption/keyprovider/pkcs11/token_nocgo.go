@@ -0,0 +1,33 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !cgo
+
+package pkcs11
+
+import (
+	"errors"
+)
+
+// Supported is false when OpenTofu was built without cgo, which is needed to load PKCS#11 libraries. The key provider
+// is not registered in that case.
+const Supported = false
+
+// errNoCgo is returned when OpenTofu was built without cgo, which is needed to load PKCS#11 libraries.
+var errNoCgo = errors.New("this build of OpenTofu does not support PKCS#11 because it was built without cgo")
+
+type unsupportedToken struct{}
+
+func newPKCS11Token(_ Config) token {
+	return unsupportedToken{}
+}
+
+func (unsupportedToken) encrypt(_ []byte) ([]byte, []byte, error) {
+	return nil, nil, errNoCgo
+}
+
+func (unsupportedToken) decrypt(_ []byte, _ []byte) ([]byte, error) {
+	return nil, errNoCgo
+}
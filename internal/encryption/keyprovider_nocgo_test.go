@@ -0,0 +1,44 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !cgo
+
+package encryption
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption/config"
+)
+
+func TestPKCS11WithoutCgo(t *testing.T) {
+	cfg, diags := config.LoadConfigFromString("test", `
+		key_provider "pkcs11" "hsm" {
+			module      = "/usr/lib/softhsm/libsofthsm2.so"
+			token_label = "tofu"
+			pin         = "1234"
+			key_label   = "tofu-state"
+		}
+		method "aes_gcm" "main" {
+			keys = key_provider.pkcs11.hsm
+		}
+		state {
+			method = method.aes_gcm.main
+		}
+	`)
+	if diags.HasErrors() {
+		t.Fatalf("%v", diags.Error())
+	}
+
+	_, diags = New(t.Context(), DefaultRegistry, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+	if !diags.HasErrors() {
+		t.Fatal("expected an error for the pkcs11 key provider")
+	}
+	if got, want := diags.Error(), "build OpenTofu from source with CGO_ENABLED=1"; !strings.Contains(got, want) {
+		t.Errorf("unexpected error %q; want it to contain %q", got, want)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aesgcmsiv
+
+import (
+	"github.com/tink-crypto/tink-go/v2/aead/subtle"
+
+	"github.com/opentofu/opentofu/internal/encryption/method"
+)
+
+const (
+	// nonceSize is the nonce length defined by RFC 8452. The nonce is prepended to the ciphertext.
+	nonceSize = 12
+	// tagSize is the length of the authentication tag appended to the ciphertext.
+	tagSize = 16
+)
+
+// aesgcmsiv contains the encryption/decryption methods according to AES-GCM-SIV (RFC 8452).
+type aesgcmsiv struct {
+	encryptionKey []byte
+	decryptionKey []byte
+	aad           []byte
+}
+
+// Encrypt encrypts the passed data with AES-GCM-SIV. If the encryption fails, it returns an error.
+func (a aesgcmsiv) Encrypt(data []byte) ([]byte, error) {
+	aead, err := a.getAEAD(a.encryptionKey)
+	if err != nil {
+		return nil, &method.ErrEncryptionFailed{Cause: err}
+	}
+
+	// AES-GCM-SIV is resistant to nonce misuse: a repeated nonce only reveals whether the same data was encrypted
+	// twice, so a random nonce is safe to use even for very frequently written data. The library generates the
+	// nonce and prepends it to the ciphertext.
+	encrypted, err := aead.Encrypt(data, a.aad)
+	if err != nil {
+		return nil, &method.ErrEncryptionFailed{Cause: err}
+	}
+	return encrypted, nil
+}
+
+// Decrypt decrypts an AES-GCM-SIV-encrypted data set. If the data set fails decryption, it returns an error.
+func (a aesgcmsiv) Decrypt(data []byte) ([]byte, error) {
+	if len(a.decryptionKey) == 0 {
+		return nil, &method.ErrDecryptionKeyUnavailable{}
+	}
+	if len(data) == 0 {
+		return nil, &method.ErrDecryptionFailed{
+			Cause: method.ErrCryptoFailure{
+				Message: "cannot decrypt empty data",
+				Cause:   nil,
+			},
+		}
+	}
+
+	if len(data) < nonceSize+tagSize {
+		return nil, &method.ErrDecryptionFailed{
+			Cause: method.ErrCryptoFailure{
+				Message: "cannot decrypt data because it is too small (likely data corruption)",
+				Cause:   nil,
+			},
+		}
+	}
+
+	aead, err := a.getAEAD(a.decryptionKey)
+	if err != nil {
+		return nil, &method.ErrDecryptionFailed{Cause: err}
+	}
+
+	decrypted, err := aead.Decrypt(data, a.aad)
+	if err != nil {
+		return nil, &method.ErrDecryptionFailed{Cause: err}
+	}
+	return decrypted, nil
+}
+
+func (a aesgcmsiv) getAEAD(key []byte) (*subtle.AESGCMSIV, error) {
+	aead, err := subtle.NewAESGCMSIV(key)
+	if err != nil {
+		return nil, &method.ErrCryptoFailure{
+			Message: "failed to create AES-GCM-SIV cipher",
+			Cause:   err,
+		}
+	}
+	return aead, nil
+}
+
+func Is(m method.Method) bool {
+	_, ok := m.(*aesgcmsiv)
+	return ok
+}
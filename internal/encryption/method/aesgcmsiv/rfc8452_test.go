@@ -0,0 +1,385 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aesgcmsiv
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("invalid hex %q: %v", s, err)
+	}
+	return b
+}
+
+// TestRFC8452 checks that the method decrypts all the test vectors from RFC 8452, appendix C, including the ones with
+// additional data, multiple blocks and a wrapping counter, when stored as the nonce followed by the ciphertext.
+func TestRFC8452(t *testing.T) {
+	testCases := map[string]struct {
+		key       string
+		nonce     string
+		plaintext string
+		aad       string
+		result    string
+	}{
+		"aes-128-empty": {
+			key:    "01000000000000000000000000000000",
+			nonce:  "030000000000000000000000",
+			result: "dc20e2d83f25705bb49e439eca56de25",
+		},
+		"aes-128-8-bytes": {
+			key:       "01000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "0100000000000000",
+			result:    "b5d839330ac7b786578782fff6013b815b287c22493a364c",
+		},
+		"aes-128-12-bytes": {
+			key:       "01000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "010000000000000000000000",
+			result:    "7323ea61d05932260047d942a4978db357391a0bc4fdec8b0d106639",
+		},
+		"aes-128-16-bytes": {
+			key:       "01000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "01000000000000000000000000000000",
+			result:    "743f7c8077ab25f8624e2e948579cf77303aaf90f6fe21199c6068577437a0c4",
+		},
+		"aes-128-32-bytes": {
+			key:       "01000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "0100000000000000000000000000000002000000000000000000000000000000",
+			result:    "84e07e62ba83a6585417245d7ec413a9fe427d6315c09b57ce45f2e3936a94451a8e45dcd4578c667cd86847bf6155ff",
+		},
+		"aes-128-48-bytes": {
+			key:       "01000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "010000000000000000000000000000000200000000000000000000000000000003000000000000000000000000000000",
+			result:    "3fd24ce1f5a67b75bf2351f181a475c7b800a5b4d3dcf70106b1eea82fa1d64df42bf7226122fa92e17a40eeaac1201b5e6e311dbf395d35b0fe39c2714388f8",
+		},
+		"aes-128-64-bytes": {
+			key:       "01000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "01000000000000000000000000000000020000000000000000000000000000000300000000000000000000000000000004000000000000000000000000000000",
+			result:    "2433668f1058190f6d43e360f4f35cd8e475127cfca7028ea8ab5c20f7ab2af02516a2bdcbc08d521be37ff28c152bba36697f25b4cd169c6590d1dd39566d3f8a263dd317aa88d56bdf3936dba75bb8",
+		},
+		"aes-128-aad-8-bytes": {
+			key:       "01000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "0200000000000000",
+			aad:       "01",
+			result:    "1e6daba35669f4273b0a1a2560969cdf790d99759abd1508",
+		},
+		"aes-128-aad-12-bytes": {
+			key:       "01000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "020000000000000000000000",
+			aad:       "01",
+			result:    "296c7889fd99f41917f4462008299c5102745aaa3a0c469fad9e075a",
+		},
+		"aes-128-aad-16-bytes": {
+			key:       "01000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "02000000000000000000000000000000",
+			aad:       "01",
+			result:    "e2b0c5da79a901c1745f700525cb335b8f8936ec039e4e4bb97ebd8c4457441f",
+		},
+		"aes-128-aad-32-bytes": {
+			key:       "01000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "0200000000000000000000000000000003000000000000000000000000000000",
+			aad:       "01",
+			result:    "620048ef3c1e73e57e02bb8562c416a319e73e4caac8e96a1ecb2933145a1d71e6af6a7f87287da059a71684ed3498e1",
+		},
+		"aes-128-aad-48-bytes": {
+			key:       "01000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "020000000000000000000000000000000300000000000000000000000000000004000000000000000000000000000000",
+			aad:       "01",
+			result:    "50c8303ea93925d64090d07bd109dfd9515a5a33431019c17d93465999a8b0053201d723120a8562b838cdff25bf9d1e6a8cc3865f76897c2e4b245cf31c51f2",
+		},
+		"aes-128-aad-64-bytes": {
+			key:       "01000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "02000000000000000000000000000000030000000000000000000000000000000400000000000000000000000000000005000000000000000000000000000000",
+			aad:       "01",
+			result:    "2f5c64059db55ee0fb847ed513003746aca4e61c711b5de2e7a77ffd02da42feec601910d3467bb8b36ebbaebce5fba30d36c95f48a3e7980f0e7ac299332a80cdc46ae475563de037001ef84ae21744",
+		},
+		"aes-128-aad-12-bytes-4-bytes": {
+			key:       "01000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "02000000",
+			aad:       "010000000000000000000000",
+			result:    "a8fe3e8707eb1f84fb28f8cb73de8e99e2f48a14",
+		},
+		"aes-128-aad-18-bytes-20-bytes": {
+			key:       "01000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "0300000000000000000000000000000004000000",
+			aad:       "010000000000000000000000000000000200",
+			result:    "6bb0fecf5ded9b77f902c7d5da236a4391dd029724afc9805e976f451e6d87f6fe106514",
+		},
+		"aes-128-aad-20-bytes-18-bytes": {
+			key:       "01000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "030000000000000000000000000000000400",
+			aad:       "0100000000000000000000000000000002000000",
+			result:    "44d0aaf6fb2f1f34add5e8064e83e12a2adabff9b2ef00fb47920cc72a0c0f13b9fd",
+		},
+		"aes-256-empty": {
+			key:    "0100000000000000000000000000000000000000000000000000000000000000",
+			nonce:  "030000000000000000000000",
+			result: "07f5f4169bbf55a8400cd47ea6fd400f",
+		},
+		"aes-256-8-bytes": {
+			key:       "0100000000000000000000000000000000000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "0100000000000000",
+			result:    "c2ef328e5c71c83b843122130f7364b761e0b97427e3df28",
+		},
+		"aes-256-12-bytes": {
+			key:       "0100000000000000000000000000000000000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "010000000000000000000000",
+			result:    "9aab2aeb3faa0a34aea8e2b18ca50da9ae6559e48fd10f6e5c9ca17e",
+		},
+		"aes-256-16-bytes": {
+			key:       "0100000000000000000000000000000000000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "01000000000000000000000000000000",
+			result:    "85a01b63025ba19b7fd3ddfc033b3e76c9eac6fa700942702e90862383c6c366",
+		},
+		"aes-256-32-bytes": {
+			key:       "0100000000000000000000000000000000000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "0100000000000000000000000000000002000000000000000000000000000000",
+			result:    "4a6a9db4c8c6549201b9edb53006cba821ec9cf850948a7c86c68ac7539d027fe819e63abcd020b006a976397632eb5d",
+		},
+		"aes-256-48-bytes": {
+			key:       "0100000000000000000000000000000000000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "010000000000000000000000000000000200000000000000000000000000000003000000000000000000000000000000",
+			result:    "c00d121893a9fa603f48ccc1ca3c57ce7499245ea0046db16c53c7c66fe717e39cf6c748837b61f6ee3adcee17534ed5790bc96880a99ba804bd12c0e6a22cc4",
+		},
+		"aes-256-64-bytes": {
+			key:       "0100000000000000000000000000000000000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "01000000000000000000000000000000020000000000000000000000000000000300000000000000000000000000000004000000000000000000000000000000",
+			result:    "c2d5160a1f8683834910acdafc41fbb1632d4a353e8b905ec9a5499ac34f96c7e1049eb080883891a4db8caaa1f99dd004d80487540735234e3744512c6f90ce112864c269fc0d9d88c61fa47e39aa08",
+		},
+		"aes-256-aad-8-bytes": {
+			key:       "0100000000000000000000000000000000000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "0200000000000000",
+			aad:       "01",
+			result:    "1de22967237a813291213f267e3b452f02d01ae33e4ec854",
+		},
+		"aes-256-aad-12-bytes": {
+			key:       "0100000000000000000000000000000000000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "020000000000000000000000",
+			aad:       "01",
+			result:    "163d6f9cc1b346cd453a2e4cc1a4a19ae800941ccdc57cc8413c277f",
+		},
+		"aes-256-aad-16-bytes": {
+			key:       "0100000000000000000000000000000000000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "02000000000000000000000000000000",
+			aad:       "01",
+			result:    "c91545823cc24f17dbb0e9e807d5ec17b292d28ff61189e8e49f3875ef91aff7",
+		},
+		"aes-256-aad-32-bytes": {
+			key:       "0100000000000000000000000000000000000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "0200000000000000000000000000000003000000000000000000000000000000",
+			aad:       "01",
+			result:    "07dad364bfc2b9da89116d7bef6daaaf6f255510aa654f920ac81b94e8bad365aea1bad12702e1965604374aab96dbbc",
+		},
+		"aes-256-aad-48-bytes": {
+			key:       "0100000000000000000000000000000000000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "020000000000000000000000000000000300000000000000000000000000000004000000000000000000000000000000",
+			aad:       "01",
+			result:    "c67a1f0f567a5198aa1fcc8e3f21314336f7f51ca8b1af61feac35a86416fa47fbca3b5f749cdf564527f2314f42fe2503332742b228c647173616cfd44c54eb",
+		},
+		"aes-256-aad-64-bytes": {
+			key:       "0100000000000000000000000000000000000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "02000000000000000000000000000000030000000000000000000000000000000400000000000000000000000000000005000000000000000000000000000000",
+			aad:       "01",
+			result:    "67fd45e126bfb9a79930c43aad2d36967d3f0e4d217c1e551f59727870beefc98cb933a8fce9de887b1e40799988db1fc3f91880ed405b2dd298318858467c895bde0285037c5de81e5b570a049b62a0",
+		},
+		"aes-256-aad-12-bytes-4-bytes": {
+			key:       "0100000000000000000000000000000000000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "02000000",
+			aad:       "010000000000000000000000",
+			result:    "22b3f4cd1835e517741dfddccfa07fa4661b74cf",
+		},
+		"aes-256-aad-18-bytes-20-bytes": {
+			key:       "0100000000000000000000000000000000000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "0300000000000000000000000000000004000000",
+			aad:       "010000000000000000000000000000000200",
+			result:    "43dd0163cdb48f9fe3212bf61b201976067f342bb879ad976d8242acc188ab59cabfe307",
+		},
+		"aes-256-aad-20-bytes-18-bytes": {
+			key:       "0100000000000000000000000000000000000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "030000000000000000000000000000000400",
+			aad:       "0100000000000000000000000000000002000000",
+			result:    "462401724b5ce6588d5a54aae5375513a075cfcdf5042112aa29685c912fc2056543",
+		},
+		"counter-wrap-32-bytes": {
+			key:       "0000000000000000000000000000000000000000000000000000000000000000",
+			nonce:     "000000000000000000000000",
+			plaintext: "000000000000000000000000000000004db923dc793ee6497c76dcc03a98e108",
+			result:    "f3f80f2cf0cb2dd9c5984fcda908456cc537703b5ba70324a6793a7bf218d3eaffffffff000000000000000000000000",
+		},
+		"counter-wrap-24-bytes": {
+			key:       "0000000000000000000000000000000000000000000000000000000000000000",
+			nonce:     "000000000000000000000000",
+			plaintext: "eb3640277c7ffd1303c7a542d02d3e4c0000000000000000",
+			result:    "18ce4f0b8cb4d0cac65fea8f79257b20888e53e72299e56dffffffff000000000000000000000000",
+		},
+		"aes-128-random-1": {
+			key:    "e66021d5eb8e4f4066d4adb9c33560e4",
+			nonce:  "f46e44bb3da0015c94f70887",
+			result: "a4194b79071b01a87d65f706e3949578",
+		},
+		"aes-128-random-2": {
+			key:       "36864200e0eaf5284d884a0e77d31646",
+			nonce:     "bae8e37fc83441b16034566b",
+			plaintext: "7a806c",
+			aad:       "46bb91c3c5",
+			result:    "af60eb711bd85bc1e4d3e0a462e074eea428a8",
+		},
+		"aes-128-random-3": {
+			key:       "aedb64a6c590bc84d1a5e269e4b47801",
+			nonce:     "afc0577e34699b9e671fdd4f",
+			plaintext: "bdc66f146545",
+			aad:       "fc880c94a95198874296",
+			result:    "bb93a3e34d3cd6a9c45545cfc11f03ad743dba20f966",
+		},
+		"aes-128-random-4": {
+			key:       "d5cc1fd161320b6920ce07787f86743b",
+			nonce:     "275d1ab32f6d1f0434d8848c",
+			plaintext: "1177441f195495860f",
+			aad:       "046787f3ea22c127aaf195d1894728",
+			result:    "4f37281f7ad12949d01d02fd0cd174c84fc5dae2f60f52fd2b",
+		},
+		"aes-128-random-5": {
+			key:       "b3fed1473c528b8426a582995929a149",
+			nonce:     "9e9ad8780c8d63d0ab4149c0",
+			plaintext: "9f572c614b4745914474e7c7",
+			aad:       "c9882e5386fd9f92ec489c8fde2be2cf97e74e93",
+			result:    "f54673c5ddf710c745641c8bc1dc2f871fb7561da1286e655e24b7b0",
+		},
+		"aes-128-random-6": {
+			key:       "2d4ed87da44102952ef94b02b805249b",
+			nonce:     "ac80e6f61455bfac8308a2d4",
+			plaintext: "0d8c8451178082355c9e940fea2f58",
+			aad:       "2950a70d5a1db2316fd568378da107b52b0da55210cc1c1b0a",
+			result:    "c9ff545e07b88a015f05b274540aa183b3449b9f39552de99dc214a1190b0b",
+		},
+		"aes-128-random-7": {
+			key:       "bde3b2f204d1e9f8b06bc47f9745b3d1",
+			nonce:     "ae06556fb6aa7890bebc18fe",
+			plaintext: "6b3db4da3d57aa94842b9803a96e07fb6de7",
+			aad:       "1860f762ebfbd08284e421702de0de18baa9c9596291b08466f37de21c7f",
+			result:    "6298b296e24e8cc35dce0bed484b7f30d5803e377094f04709f64d7b985310a4db84",
+		},
+		"aes-128-random-8": {
+			key:       "f901cfe8a69615a93fdf7a98cad48179",
+			nonce:     "6245709fb18853f68d833640",
+			plaintext: "e42a3c02c25b64869e146d7b233987bddfc240871d",
+			aad:       "7576f7028ec6eb5ea7e298342a94d4b202b370ef9768ec6561c4fe6b7e7296fa859c21",
+			result:    "391cc328d484a4f46406181bcd62efd9b3ee197d052d15506c84a9edd65e13e9d24a2a6e70",
+		},
+		"aes-256-random-1": {
+			key:    "e66021d5eb8e4f4066d4adb9c33560e4f46e44bb3da0015c94f7088736864200",
+			nonce:  "e0eaf5284d884a0e77d31646",
+			result: "169fbb2fbf389a995f6390af22228a62",
+		},
+		"aes-256-random-2": {
+			key:       "bae8e37fc83441b16034566b7a806c46bb91c3c5aedb64a6c590bc84d1a5e269",
+			nonce:     "e4b47801afc0577e34699b9e",
+			plaintext: "671fdd",
+			aad:       "4fbdc66f14",
+			result:    "0eaccb93da9bb81333aee0c785b240d319719d",
+		},
+		"aes-256-random-3": {
+			key:       "6545fc880c94a95198874296d5cc1fd161320b6920ce07787f86743b275d1ab3",
+			nonce:     "2f6d1f0434d8848c1177441f",
+			plaintext: "195495860f04",
+			aad:       "6787f3ea22c127aaf195",
+			result:    "a254dad4f3f96b62b84dc40c84636a5ec12020ec8c2c",
+		},
+		"aes-256-random-4": {
+			key:       "d1894728b3fed1473c528b8426a582995929a1499e9ad8780c8d63d0ab4149c0",
+			nonce:     "9f572c614b4745914474e7c7",
+			plaintext: "c9882e5386fd9f92ec",
+			aad:       "489c8fde2be2cf97e74e932d4ed87d",
+			result:    "0df9e308678244c44bc0fd3dc6628dfe55ebb0b9fb2295c8c2",
+		},
+		"aes-256-random-5": {
+			key:       "a44102952ef94b02b805249bac80e6f61455bfac8308a2d40d8c845117808235",
+			nonce:     "5c9e940fea2f582950a70d5a",
+			plaintext: "1db2316fd568378da107b52b",
+			aad:       "0da55210cc1c1b0abde3b2f204d1e9f8b06bc47f",
+			result:    "8dbeb9f7255bf5769dd56692404099c2587f64979f21826706d497d5",
+		},
+		"aes-256-random-6": {
+			key:       "9745b3d1ae06556fb6aa7890bebc18fe6b3db4da3d57aa94842b9803a96e07fb",
+			nonce:     "6de71860f762ebfbd08284e4",
+			plaintext: "21702de0de18baa9c9596291b08466",
+			aad:       "f37de21c7ff901cfe8a69615a93fdf7a98cad481796245709f",
+			result:    "793576dfa5c0f88729a7ed3c2f1bffb3080d28f6ebb5d3648ce97bd5ba67fd",
+		},
+		"aes-256-random-7": {
+			key:       "b18853f68d833640e42a3c02c25b64869e146d7b233987bddfc240871d7576f7",
+			nonce:     "028ec6eb5ea7e298342a94d4",
+			plaintext: "b202b370ef9768ec6561c4fe6b7e7296fa85",
+			aad:       "9c2159058b1f0fe91433a5bdc20e214eab7fecef4454a10ef0657df21ac7",
+			result:    "857e16a64915a787637687db4a9519635cdd454fc2a154fea91f8363a39fec7d0a49",
+		},
+		"aes-256-random-8": {
+			key:       "3c535de192eaed3822a2fbbe2ca9dfc88255e14a661b8aa82cc54236093bbc23",
+			nonce:     "688089e55540db1872504e1c",
+			plaintext: "ced532ce4159b035277d4dfbb7db62968b13cd4eec",
+			aad:       "734320ccc9d9bbbb19cb81b2af4ecbc3e72834321f7aa0f70b7282b4f33df23f167541",
+			result:    "626660c26ea6612fb17ad91e8e767639edd6c9faee9d6c7029675b89eaf4ba1ded1a286594",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			plaintext := mustDecodeHex(t, tc.plaintext)
+			m := &aesgcmsiv{
+				decryptionKey: mustDecodeHex(t, tc.key),
+				aad:           mustDecodeHex(t, tc.aad),
+			}
+			data := append(mustDecodeHex(t, tc.nonce), mustDecodeHex(t, tc.result)...)
+
+			decrypted, err := m.Decrypt(data)
+			if err != nil {
+				t.Fatalf("unexpected error (%v)", err)
+			}
+			if !bytes.Equal(decrypted, plaintext) {
+				t.Fatalf("incorrect plaintext: %x, expected: %x", decrypted, plaintext)
+			}
+
+			data[len(data)-1] ^= 1
+			if _, err := m.Decrypt(data); err == nil {
+				t.Fatalf("expected error when decrypting modified data, none returned")
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package envelope
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/method/compliancetest"
+)
+
+var testKey = []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32}
+
+func TestCompliance(t *testing.T) {
+	compliancetest.ComplianceTest(t, compliancetest.TestConfiguration[*descriptor, *Config, *envelope]{
+		Descriptor: New().(*descriptor),
+		HCLParseTestCases: map[string]compliancetest.HCLParseTestCase[*descriptor, *Config, *envelope]{
+			"empty": {
+				HCL:        `method "envelope" "foo" {}`,
+				ValidHCL:   false,
+				ValidBuild: false,
+				Validate:   nil,
+			},
+			"no-keys": {
+				HCL: `method "envelope" "foo" {
+						keys = []
+					}`,
+				ValidHCL:   true,
+				ValidBuild: false,
+				Validate:   nil,
+			},
+			"empty-keys": {
+				HCL: `method "envelope" "foo" {
+						keys = [{
+							encryption_key = []
+							decryption_key = []
+						}]
+					}`,
+				ValidHCL:   true,
+				ValidBuild: false,
+				Validate:   nil,
+			},
+			"short-second-key": {
+				HCL: `method "envelope" "foo" {
+						keys = [{
+							encryption_key = [1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29,30,31,32]
+							decryption_key = [1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29,30,31,32]
+						}, {
+							encryption_key = [1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29,30,31]
+							decryption_key = [1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29,30,31]
+						}]
+					}`,
+				ValidHCL:   true,
+				ValidBuild: false,
+				Validate:   nil,
+			},
+			"short-decryption-key": {
+				HCL: `method "envelope" "foo" {
+						keys = [{
+							encryption_key = [1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29,30,31,32]
+							decryption_key = [1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29,30,31]
+						}]
+					}`,
+				ValidHCL:   true,
+				ValidBuild: false,
+				Validate:   nil,
+			},
+			"single-key": {
+				HCL: `method "envelope" "foo" {
+						keys = {
+							encryption_key = [1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29,30,31,32]
+							decryption_key = [1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29,30,31,32]
+						}
+					}`,
+				ValidHCL:   true,
+				ValidBuild: true,
+				Validate: func(config *Config, method *envelope) error {
+					if len(config.Keys) != 1 || !bytes.Equal(config.Keys[0].EncryptionKey, testKey) {
+						return fmt.Errorf("incorrect keys found after HCL parsing in config")
+					}
+					if len(method.recipients) != 1 {
+						return fmt.Errorf("incorrect number of recipients found after Build() in method: %d", len(method.recipients))
+					}
+					return nil
+				},
+			},
+			"multiple-keys": {
+				HCL: `method "envelope" "foo" {
+						keys = [{
+							encryption_key = [1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29,30,31,32]
+							decryption_key = [1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29,30,31,32]
+						}, {
+							encryption_key = [1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16]
+						}]
+						aad = [1,2,3,4]
+					}`,
+				ValidHCL:   true,
+				ValidBuild: true,
+				Validate: func(config *Config, method *envelope) error {
+					if len(config.Keys) != 2 {
+						return fmt.Errorf("incorrect number of keys found after HCL parsing in config: %d", len(config.Keys))
+					}
+					if len(config.Keys[1].DecryptionKey) > 0 {
+						return fmt.Errorf("decryption key found in config despite no decryption key being provided")
+					}
+					if len(method.recipients) != 2 {
+						return fmt.Errorf("incorrect number of recipients found after Build() in method: %d", len(method.recipients))
+					}
+					if !bytes.Equal(method.aad, []byte{1, 2, 3, 4}) {
+						return fmt.Errorf("invalid AAD in method after Build()")
+					}
+					return nil
+				},
+			},
+		},
+		EncryptDecryptTestCase: compliancetest.EncryptDecryptTestCase[*Config, *envelope]{
+			ValidEncryptOnlyConfig: &Config{
+				Keys: []keyprovider.Output{
+					{
+						EncryptionKey: testKey,
+						DecryptionKey: nil,
+					},
+				},
+			},
+			ValidFullConfig: &Config{
+				Keys: []keyprovider.Output{
+					{
+						EncryptionKey: []byte{33, 34, 35, 36, 37, 38, 39, 40, 41, 42, 43, 44, 45, 46, 47, 48, 49, 50, 51, 52, 53, 54, 55, 56, 57, 58, 59, 60, 61, 62, 63, 64},
+						DecryptionKey: testKey,
+					},
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,84 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package envelope
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/collections"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/method"
+	"github.com/opentofu/opentofu/internal/encryption/method/aesgcm"
+)
+
+// validKeyLengths holds the valid lengths of the keys used to wrap the data key, which are AES keys.
+var validKeyLengths = collections.NewSet[int](16, 24, 32)
+
+// Config is the configuration for the envelope method.
+type Config struct {
+	// Keys are the keys the data key is wrapped with, one for each recipient. Each of them has to be 16, 24, or 32
+	// bytes long for AES-128, 192, or 256, respectively. The data is encrypted for all recipients, and any one of
+	// them can decrypt it. A key without an encryption or decryption key is unavailable, because its key provider
+	// failed, and is skipped as long as at least one of the other keys is available.
+	Keys []keyprovider.Output
+
+	// AAD is the Additional Authenticated Data that is authenticated, but not encrypted, when encrypting the data
+	// with the data key. The AAD value on decryption must match this setting, otherwise the decryption will fail.
+	AAD []byte
+}
+
+// Build checks the validity of the configuration and returns a ready-to-use envelope implementation.
+func (c *Config) Build() (method.Method, error) {
+	if len(c.Keys) == 0 {
+		return nil, &method.ErrInvalidConfiguration{
+			Cause: errors.New("the envelope method requires at least one key"),
+		}
+	}
+
+	recipients := make([]method.Method, 0, len(c.Keys))
+	for i, keys := range c.Keys {
+		if len(keys.EncryptionKey) == 0 && len(keys.DecryptionKey) == 0 {
+			continue
+		}
+		if !validKeyLengths.Has(len(keys.EncryptionKey)) {
+			return nil, &method.ErrInvalidConfiguration{
+				Cause: fmt.Errorf(
+					"the envelope method requires the key length to be one of: %s, received %d bytes in the encryption key of key %d",
+					validKeyLengths.String(),
+					len(keys.EncryptionKey),
+					i,
+				),
+			}
+		}
+		if len(keys.DecryptionKey) > 0 && !validKeyLengths.Has(len(keys.DecryptionKey)) {
+			return nil, &method.ErrInvalidConfiguration{
+				Cause: fmt.Errorf(
+					"the envelope method requires the key length to be one of: %s, received %d bytes in the decryption key of key %d",
+					validKeyLengths.String(),
+					len(keys.DecryptionKey),
+					i,
+				),
+			}
+		}
+
+		recipient, err := (&aesgcm.Config{Keys: keys}).Build()
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, recipient)
+	}
+	if len(recipients) == 0 {
+		return nil, &method.ErrInvalidConfiguration{
+			Cause: errors.New("none of the keys of the envelope method is available"),
+		}
+	}
+
+	return &envelope{
+		recipients: recipients,
+		aad:        c.AAD,
+	}, nil
+}
@@ -0,0 +1,77 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package envelope
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/method"
+)
+
+// New creates a new descriptor for the envelope encryption method, which encrypts each payload with a fresh data key
+// and wraps the data key with each of the configured keys.
+func New() method.Descriptor {
+	return &descriptor{}
+}
+
+type descriptor struct {
+}
+
+func (f *descriptor) ID() method.ID {
+	return "envelope"
+}
+
+func (f *descriptor) DecodeConfig(methodCtx method.EvalContext, body hcl.Body) (method.Config, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	methodCfg := &Config{}
+
+	content, contentDiags := body.Content(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "keys", Required: true},
+			{Name: "aad", Required: false},
+		},
+	})
+	diags = diags.Extend(contentDiags)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	keysExpr := content.Attributes["keys"].Expr
+	// keysExpr is usually a list of key providers, but we also accept a single key provider for consistency with the
+	// other methods.
+	keyExprs, listDiags := hcl.ExprList(keysExpr)
+	if listDiags.HasErrors() {
+		keyExprs = []hcl.Expression{keysExpr}
+	}
+
+	for _, keyExpr := range keyExprs {
+		// keyExpr can either be raw data/references to raw data or a string reference to a key provider (JSON support)
+		keyVal, keyDiags := methodCtx.ValueForExpression(keyExpr)
+		diags = diags.Extend(keyDiags)
+		if keyDiags.HasErrors() {
+			continue
+		}
+
+		keys, keyDiags := keyprovider.DecodeOutput(keyVal, keyExpr.Range())
+		diags = diags.Extend(keyDiags)
+		methodCfg.Keys = append(methodCfg.Keys, keys)
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	if attr, ok := content.Attributes["aad"]; ok {
+		attrVal, attrDiags := methodCtx.ValueForExpression(attr.Expr)
+		diags = diags.Extend(attrDiags)
+
+		decodeDiags := gohcl.DecodeExpression(&hclsyntax.LiteralValueExpr{Val: attrVal, SrcRange: attr.Expr.Range()}, nil, &methodCfg.AAD)
+		diags = diags.Extend(decodeDiags)
+	}
+
+	return methodCfg, diags
+}
@@ -0,0 +1,18 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package envelope_test
+
+import (
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/encryption/method/envelope"
+)
+
+func TestDescriptor(t *testing.T) {
+	if id := envelope.New().ID(); id != "envelope" {
+		t.Fatalf("Incorrect descriptor ID returned: %s", id)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package envelope
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/method"
+	"github.com/opentofu/opentofu/internal/encryption/method/aesgcm"
+)
+
+// dataKeySize is the size of the data keys, which are AES-256 keys.
+const dataKeySize = 32
+
+// envelope encrypts each payload with a fresh AES-256-GCM data key, and stores the data key wrapped with each of the
+// recipient keys, so that any of the recipients can decrypt the payload.
+type envelope struct {
+	// recipients wrap and unwrap the data key.
+	recipients []method.Method
+	aad        []byte
+}
+
+// encryptedEnvelope is the encrypted form produced by the envelope method.
+type encryptedEnvelope struct {
+	// Keys contains the data key wrapped with each of the recipient keys. The wrapped keys are not labeled, so on
+	// decryption each of the configured keys is tried with each of the wrapped keys.
+	Keys [][]byte `json:"keys"`
+	// Data is the payload encrypted with the data key.
+	Data []byte `json:"data"`
+}
+
+// Encrypt generates a fresh data key, encrypts the passed data with it, and wraps the data key with each of the
+// recipient keys.
+func (e *envelope) Encrypt(data []byte) ([]byte, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, &method.ErrEncryptionFailed{Cause: &method.ErrCryptoFailure{
+			Message: "could not generate data key",
+			Cause:   err,
+		}}
+	}
+
+	dataMethod, err := e.dataMethod(dataKey)
+	if err != nil {
+		return nil, &method.ErrEncryptionFailed{Cause: err}
+	}
+	encrypted := encryptedEnvelope{
+		Keys: make([][]byte, len(e.recipients)),
+	}
+	encrypted.Data, err = dataMethod.Encrypt(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, recipient := range e.recipients {
+		encrypted.Keys[i], err = recipient.Encrypt(dataKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := json.Marshal(encrypted)
+	if err != nil {
+		return nil, &method.ErrEncryptionFailed{Cause: err}
+	}
+	return result, nil
+}
+
+// Decrypt unwraps the data key with any of the recipient keys and decrypts the data with it. If none of the recipient
+// keys can unwrap the data key, it returns an error.
+func (e *envelope) Decrypt(data []byte) ([]byte, error) {
+	var encrypted encryptedEnvelope
+	if err := json.Unmarshal(data, &encrypted); err != nil {
+		return nil, &method.ErrDecryptionFailed{Cause: &method.ErrCryptoFailure{
+			Message: "cannot decode the envelope (likely data corruption)",
+			Cause:   err,
+		}}
+	}
+
+	keyAvailable := false
+	for _, recipient := range e.recipients {
+		for _, wrappedKey := range encrypted.Keys {
+			dataKey, err := recipient.Decrypt(wrappedKey)
+			if err != nil {
+				var keyUnavailable *method.ErrDecryptionKeyUnavailable
+				if errors.As(err, &keyUnavailable) {
+					// This recipient has no decryption key, so there's no
+					// point in trying the other wrapped keys.
+					break
+				}
+				keyAvailable = true
+				continue
+			}
+
+			dataMethod, err := e.dataMethod(dataKey)
+			if err != nil {
+				return nil, &method.ErrDecryptionFailed{Cause: err}
+			}
+			return dataMethod.Decrypt(encrypted.Data)
+		}
+	}
+
+	if !keyAvailable {
+		return nil, &method.ErrDecryptionKeyUnavailable{}
+	}
+	return nil, &method.ErrDecryptionFailed{Cause: &method.ErrCryptoFailure{
+		Message: "none of the configured keys can unwrap the data key",
+	}}
+}
+
+// dataMethod returns the method to encrypt and decrypt the payload with the given data key.
+func (e *envelope) dataMethod(dataKey []byte) (method.Method, error) {
+	return (&aesgcm.Config{
+		Keys: keyprovider.Output{
+			EncryptionKey: dataKey,
+			DecryptionKey: dataKey,
+		},
+		AAD: e.aad,
+	}).Build()
+}
+
+// ToleratesUnavailableKeys returns true, because the data key can be unwrapped with any of the recipient keys.
+func (e *envelope) ToleratesUnavailableKeys() bool {
+	return true
+}
+
+func Is(m method.Method) bool {
+	_, ok := m.(*envelope)
+	return ok
+}
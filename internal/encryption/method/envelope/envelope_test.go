@@ -0,0 +1,104 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package envelope
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/method"
+)
+
+var (
+	mainKey       = bytes.Repeat([]byte{1}, 32)
+	breakGlassKey = bytes.Repeat([]byte{2}, 16)
+	otherKey      = bytes.Repeat([]byte{3}, 32)
+)
+
+func build(t *testing.T, keys ...[]byte) method.Method {
+	t.Helper()
+	cfg := &Config{}
+	for _, key := range keys {
+		cfg.Keys = append(cfg.Keys, keyprovider.Output{EncryptionKey: key, DecryptionKey: key})
+	}
+	m, err := cfg.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestEnvelope_anyRecipient(t *testing.T) {
+	plaintext := []byte("Hello world!")
+	encrypted, err := build(t, mainKey, breakGlassKey).Encrypt(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string][][]byte{
+		"both":        {mainKey, breakGlassKey},
+		"main":        {mainKey},
+		"break glass": {breakGlassKey},
+		"other first": {otherKey, breakGlassKey},
+	}
+	for name, keys := range tests {
+		t.Run(name, func(t *testing.T) {
+			decrypted, err := build(t, keys...).Decrypt(encrypted)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(decrypted, plaintext) {
+				t.Fatalf("incorrect decrypted data: %s", decrypted)
+			}
+		})
+	}
+
+	t.Run("no recipient", func(t *testing.T) {
+		_, err := build(t, otherKey).Decrypt(encrypted)
+		var decryptionFailed *method.ErrDecryptionFailed
+		if !errors.As(err, &decryptionFailed) {
+			t.Fatalf("expected a decryption failure, got %v", err)
+		}
+	})
+}
+
+func TestEnvelope_freshDataKey(t *testing.T) {
+	m := build(t, mainKey)
+
+	var wrappedKeys [][]byte
+	for range 2 {
+		encrypted, err := m.Encrypt([]byte("Hello world!"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var env encryptedEnvelope
+		if err := json.Unmarshal(encrypted, &env); err != nil {
+			t.Fatal(err)
+		}
+		if len(env.Keys) != 1 {
+			t.Fatalf("expected one wrapped key, got %d", len(env.Keys))
+		}
+		wrappedKeys = append(wrappedKeys, env.Keys[0])
+	}
+
+	// The wrapped keys always differ because of the random nonce, so unwrap
+	// them to compare the data keys themselves.
+	recipient := m.(*envelope).recipients[0]
+	first, err := recipient.Decrypt(wrappedKeys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := recipient.Decrypt(wrappedKeys[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(first, second) {
+		t.Fatal("the same data key was used for two payloads")
+	}
+}
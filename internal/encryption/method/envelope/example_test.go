@@ -0,0 +1,48 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package envelope_test
+
+import (
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/method/envelope"
+)
+
+func Example_config() {
+	// Set up two recipients, each of which can decrypt the data on its own:
+	mainKey := keyprovider.Output{
+		EncryptionKey: []byte("AiphoogheuwohShal8Aefohy7ooLeeyu"),
+		DecryptionKey: []byte("AiphoogheuwohShal8Aefohy7ooLeeyu"),
+	}
+	breakGlassKey := keyprovider.Output{
+		EncryptionKey: []byte("eiH9quai5Saeg4ohzahphoo6Ohr9ceiX"),
+		DecryptionKey: []byte("eiH9quai5Saeg4ohzahphoo6Ohr9ceiX"),
+	}
+
+	// Encrypt something for both recipients:
+	method, err := (&envelope.Config{Keys: []keyprovider.Output{mainKey, breakGlassKey}}).Build()
+	if err != nil {
+		panic(err)
+	}
+	encrypted, err := method.Encrypt([]byte("Hello world!"))
+	if err != nil {
+		panic(err)
+	}
+
+	// Decrypt it with only the break-glass key:
+	method, err = (&envelope.Config{Keys: []keyprovider.Output{breakGlassKey}}).Build()
+	if err != nil {
+		panic(err)
+	}
+	decrypted, err := method.Decrypt(encrypted)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("%s", decrypted)
+	// Output: Hello world!
+}
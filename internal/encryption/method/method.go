@@ -17,3 +17,12 @@ type Method interface {
 	// interface.
 	Decrypt(data []byte) ([]byte, error)
 }
+
+// UnavailableKeysTolerant is an optional interface for methods that can decrypt data without some of their keys, such
+// as methods that encrypt the data for several recipients. If a key provider referenced by such a method fails to
+// provide a key, the method is built without it and can still decrypt with its other keys. It is never used to encrypt
+// in that case, because the data written would not be decryptable with the missing key.
+type UnavailableKeysTolerant interface {
+	// ToleratesUnavailableKeys returns true if the method can decrypt data without some of its keys.
+	ToleratesUnavailableKeys() bool
+}
@@ -0,0 +1,59 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package xchacha20poly1305
+
+import (
+	"fmt"
+
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/method"
+)
+
+// Config is the configuration for the XChaCha20-Poly1305 method.
+type Config struct {
+	// Keys is the encryption key for the XChaCha20-Poly1305 encryption. It has to be 32 bytes long.
+	Keys keyprovider.Output
+
+	// AAD is the Additional Authenticated Data that is authenticated, but not encrypted. The AAD value on decryption
+	// must match this setting, otherwise the decryption will fail.
+	AAD []byte
+}
+
+// Build checks the validity of the configuration and returns a ready-to-use XChaCha20-Poly1305 implementation.
+func (c *Config) Build() (method.Method, error) {
+	encryptionKey := c.Keys.EncryptionKey
+	decryptionKey := c.Keys.DecryptionKey
+
+	if len(encryptionKey) != chacha20poly1305.KeySize {
+		return nil, &method.ErrInvalidConfiguration{
+			Cause: fmt.Errorf(
+				"XChaCha20-Poly1305 requires the key length to be %d bytes, received %d bytes in the encryption key",
+				chacha20poly1305.KeySize,
+				len(encryptionKey),
+			),
+		}
+	}
+
+	if len(decryptionKey) > 0 {
+		if len(decryptionKey) != chacha20poly1305.KeySize {
+			return nil, &method.ErrInvalidConfiguration{
+				Cause: fmt.Errorf(
+					"XChaCha20-Poly1305 requires the key length to be %d bytes, received %d bytes in the decryption key",
+					chacha20poly1305.KeySize,
+					len(decryptionKey),
+				),
+			}
+		}
+	}
+
+	return &xchacha20poly1305{
+		encryptionKey,
+		decryptionKey,
+		c.AAD,
+	}, nil
+}
@@ -0,0 +1,96 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package xchacha20poly1305
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/opentofu/opentofu/internal/encryption/method"
+)
+
+// xchacha20poly1305 contains the encryption/decryption methods according to XChaCha20-Poly1305
+// (draft-irtf-cfrg-xchacha).
+type xchacha20poly1305 struct {
+	encryptionKey []byte
+	decryptionKey []byte
+	aad           []byte
+}
+
+// Encrypt encrypts the passed data with XChaCha20-Poly1305. If the encryption fails, it returns an error.
+func (x xchacha20poly1305) Encrypt(data []byte) ([]byte, error) {
+	aead, err := x.getAEAD(x.encryptionKey)
+	if err != nil {
+		return nil, &method.ErrEncryptionFailed{Cause: err}
+	}
+
+	// The 192-bit nonce is large enough to be safely chosen at random for any number of messages.
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(data)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, &method.ErrEncryptionFailed{Cause: &method.ErrCryptoFailure{
+			Message: "could not generate nonce",
+			Cause:   err,
+		}}
+	}
+
+	return aead.Seal(nonce, nonce, data, x.aad), nil
+}
+
+// Decrypt decrypts an XChaCha20-Poly1305-encrypted data set. If the data set fails decryption, it returns an error.
+func (x xchacha20poly1305) Decrypt(data []byte) ([]byte, error) {
+	if len(x.decryptionKey) == 0 {
+		return nil, &method.ErrDecryptionKeyUnavailable{}
+	}
+	if len(data) == 0 {
+		return nil, &method.ErrDecryptionFailed{
+			Cause: method.ErrCryptoFailure{
+				Message: "cannot decrypt empty data",
+				Cause:   nil,
+			},
+		}
+	}
+
+	aead, err := x.getAEAD(x.decryptionKey)
+	if err != nil {
+		return nil, &method.ErrDecryptionFailed{Cause: err}
+	}
+
+	if len(data) < aead.NonceSize()+aead.Overhead() {
+		return nil, &method.ErrDecryptionFailed{
+			Cause: method.ErrCryptoFailure{
+				Message: "cannot decrypt data because it is too small (likely data corruption)",
+				Cause:   nil,
+			},
+		}
+	}
+
+	nonce := data[:aead.NonceSize()]
+	data = data[aead.NonceSize():]
+
+	decrypted, err := aead.Open(nil, nonce, data, x.aad)
+	if err != nil {
+		return nil, &method.ErrDecryptionFailed{Cause: err}
+	}
+	return decrypted, nil
+}
+
+func (x xchacha20poly1305) getAEAD(key []byte) (cipher.AEAD, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, &method.ErrCryptoFailure{
+			Message: "failed to create XChaCha20-Poly1305 cipher",
+			Cause:   err,
+		}
+	}
+	return aead, nil
+}
+
+func Is(m method.Method) bool {
+	_, ok := m.(*xchacha20poly1305)
+	return ok
+}
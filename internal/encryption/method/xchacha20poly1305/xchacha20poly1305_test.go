@@ -0,0 +1,112 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package xchacha20poly1305_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+
+	"github.com/opentofu/opentofu/internal/encryption/method"
+	"github.com/opentofu/opentofu/internal/encryption/method/xchacha20poly1305"
+)
+
+var config = &xchacha20poly1305.Config{
+	Keys: keyprovider.Output{
+		EncryptionKey: []byte("aeshi1quahb2Rua0ooquaiwahbonedoh"),
+		DecryptionKey: []byte("aeshi1quahb2Rua0ooquaiwahbonedoh"),
+	},
+}
+
+func TestDecryptShortData(t *testing.T) {
+	m, err := config.Build()
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+
+	// Passing a non-empty, but shorter-than-nonce data
+	_, err = m.Decrypt([]byte("1"))
+	if err == nil {
+		t.Fatalf("Expected error, none returned.")
+	}
+
+	var e *method.ErrDecryptionFailed
+	if !errors.As(err, &e) {
+		t.Fatalf("Incorrect error type returned: %T (%v)", err, err)
+	}
+}
+
+func TestDecryptCorruptData(t *testing.T) {
+	m, err := config.Build()
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+
+	encrypted, err := m.Encrypt([]byte("Hello world!"))
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+
+	encrypted = encrypted[:len(encrypted)-1]
+	decrypted, err := m.Decrypt(encrypted)
+	if err == nil {
+		t.Fatalf("Expected error, got: %v", decrypted)
+	}
+	var e *method.ErrDecryptionFailed
+	if !errors.As(err, &e) {
+		t.Fatalf("Incorrect error type returned: %T (%v)", err, err)
+	}
+}
+
+func TestDecryptAADMismatch(t *testing.T) {
+	encryptConfig := *config
+	encryptConfig.AAD = []byte("foo")
+	decryptConfig := *config
+	decryptConfig.AAD = []byte("bar")
+
+	encryptMethod, err := encryptConfig.Build()
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+	decryptMethod, err := decryptConfig.Build()
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+
+	encrypted, err := encryptMethod.Encrypt([]byte("Hello world!"))
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+	decrypted, err := decryptMethod.Decrypt(encrypted)
+	if err == nil {
+		t.Fatalf("Expected error, got: %v", decrypted)
+	}
+	var e *method.ErrDecryptionFailed
+	if !errors.As(err, &e) {
+		t.Fatalf("Incorrect error type returned: %T (%v)", err, err)
+	}
+}
+
+func TestEncryptUniqueNonce(t *testing.T) {
+	m, err := config.Build()
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+
+	first, err := m.Encrypt([]byte("Hello world!"))
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+	second, err := m.Encrypt([]byte("Hello world!"))
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+	if bytes.Equal(first, second) {
+		t.Fatalf("Encrypting the same data twice returned the same ciphertext.")
+	}
+}
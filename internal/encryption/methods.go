@@ -9,6 +9,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
@@ -16,6 +17,7 @@ import (
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/encryption/config"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
 	"github.com/opentofu/opentofu/internal/encryption/method"
 	"github.com/opentofu/opentofu/internal/encryption/registry"
 	"github.com/zclconf/go-cty/cty"
@@ -104,6 +106,9 @@ func setupMethod(ctx context.Context, enc *config.EncryptionConfig, cfg config.M
 	}
 
 	m, err := methodConfig.Build()
+	if len(meta.unavailable) > 0 {
+		return unavailableKeyProviders(cfg, m, err, meta, diags)
+	}
 	if err != nil {
 		// Convert the error to a diagnostic
 		diags = diags.Append(errorToDiagnostic(err))
@@ -112,6 +117,51 @@ func setupMethod(ctx context.Context, enc *config.EncryptionConfig, cfg config.M
 	return m, diags
 }
 
+// unavailableKeyProviders handles the key providers that failed to provide a key while setting up the given method.
+// Methods implementing method.UnavailableKeysTolerant can still decrypt with their other keys, in which case the
+// failures are reported as warnings, and the method returned refuses to encrypt. Otherwise, they are reported as errors.
+func unavailableKeyProviders(cfg config.MethodConfig, m method.Method, buildErr error, meta keyProviderMetadata, diags hcl.Diagnostics) (method.Method, hcl.Diagnostics) {
+	keys := make([]keyprovider.MetaStorageKey, 0, len(meta.unavailable))
+	for key := range meta.unavailable {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	tolerant, ok := m.(method.UnavailableKeysTolerant)
+	if buildErr != nil || !ok || !tolerant.ToleratesUnavailableKeys() {
+		for _, key := range keys {
+			diags = diags.Append(meta.unavailable[key])
+		}
+		return nil, diags
+	}
+
+	for _, key := range keys {
+		diag := *meta.unavailable[key]
+		diag.Severity = hcl.DiagWarning
+		diag.Summary = "Encryption key unavailable"
+		diag.Detail = fmt.Sprintf("%s The method %s.%s decrypts with its other keys instead, but it can't encrypt until this key is available again.", diag.Detail, cfg.Type, cfg.Name)
+		diags = diags.Append(&diag)
+	}
+	return &decryptOnlyMethod{Method: m, unavailable: keys}, diags
+}
+
+// decryptOnlyMethod is a method that was set up without some of its keys. It can decrypt, but it refuses to encrypt,
+// so that a key provider failing temporarily never silently removes a key from the files written.
+type decryptOnlyMethod struct {
+	method.Method
+	unavailable []keyprovider.MetaStorageKey
+}
+
+func (m *decryptOnlyMethod) Encrypt(_ []byte) ([]byte, error) {
+	names := make([]string, len(m.unavailable))
+	for i, key := range m.unavailable {
+		names[i] = string(key)
+	}
+	return nil, &method.ErrEncryptionFailed{
+		Cause: fmt.Errorf("the keys of %s are unavailable, and the data must be decryptable with all of the configured keys", strings.Join(names, ", ")),
+	}
+}
+
 func errorToDiagnostic(err error) *hcl.Diagnostic {
 	originalErr := err
 
@@ -0,0 +1,82 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption/config"
+)
+
+// ModuleManifestEncryption describes the methods that you can use for encrypting the module manifest that "tofu init"
+// stores in the data directory (.terraform/modules/modules.json). This file records the source address of every
+// installed module, which may be derived from variables and include credentials.
+type ModuleManifestEncryption interface {
+	// EncryptModuleManifest encrypts a JSON-serialized module manifest and returns the encrypted form.
+	//
+	// If the user configured no encryption, this function should be a no-op and return the input unchanged.
+	EncryptModuleManifest([]byte) ([]byte, error)
+
+	// DecryptModuleManifest decrypts a potentially encrypted module manifest and returns the JSON-serialized module
+	// manifest.
+	//
+	// The module manifest is only written when modules are installed, so a manifest read with a fallback method is
+	// encrypted with the primary method the next time "tofu init" or "tofu get" runs.
+	//
+	// If the user configured no encryption, return the input as-is regardless if the file is valid.
+	DecryptModuleManifest([]byte) ([]byte, error)
+}
+
+type moduleManifestEncryption struct {
+	base *baseEncryption
+}
+
+func newModuleManifestEncryption(ctx context.Context, enc *encryption, target *config.TargetConfig, enforced bool, name string, staticEval *configs.StaticEvaluator) (ModuleManifestEncryption, hcl.Diagnostics) {
+	base, diags := newBaseEncryption(ctx, enc, target, enforced, name, staticEval)
+	return &moduleManifestEncryption{base}, diags
+}
+
+func (m *moduleManifestEncryption) EncryptModuleManifest(data []byte) ([]byte, error) {
+	return m.base.encrypt(data, func(base basedata) interface{} { return base })
+}
+
+func (m *moduleManifestEncryption) DecryptModuleManifest(data []byte) ([]byte, error) {
+	decrypted, _, err := m.base.decrypt(context.TODO(), data, validateModuleManifestPayload)
+	return decrypted, err
+}
+
+// validateModuleManifestPayload checks whether the given data is an unencrypted
+// module manifest.
+func validateModuleManifestPayload(data []byte) error {
+	tmp := struct {
+		Modules *json.RawMessage `json:"Modules"`
+	}{}
+	err := json.Unmarshal(data, &tmp)
+	if err != nil {
+		return err
+	}
+	if tmp.Modules == nil {
+		return fmt.Errorf("Given payload is not a module manifest")
+	}
+	return nil
+}
+
+func ModuleManifestEncryptionDisabled() ModuleManifestEncryption {
+	return &moduleManifestDisabled{}
+}
+
+type moduleManifestDisabled struct{}
+
+func (m *moduleManifestDisabled) EncryptModuleManifest(plainManifest []byte) ([]byte, error) {
+	return plainManifest, nil
+}
+func (m *moduleManifestDisabled) DecryptModuleManifest(encryptedManifest []byte) ([]byte, error) {
+	return encryptedManifest, nil
+}
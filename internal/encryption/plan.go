@@ -61,16 +61,20 @@ func (p planEncryption) EncryptPlan(data []byte) ([]byte, error) {
 }
 
 func (p planEncryption) DecryptPlan(data []byte) ([]byte, error) {
-	data, _, err := p.base.decrypt(context.TODO(), data, func(data []byte) error {
-		// Check magic bytes
-		if len(data) < 2 || string(data[:2]) != "PK" {
-			return fmt.Errorf("Invalid plan file %v", string(data[:2]))
-		}
-		return nil
-	})
+	data, _, err := p.base.decrypt(context.TODO(), data, validatePlanPayload)
 	return data, err
 }
 
+// validatePlanPayload checks whether the given data is an unencrypted plan
+// file.
+func validatePlanPayload(data []byte) error {
+	// Check magic bytes
+	if len(data) < 2 || string(data[:2]) != "PK" {
+		return fmt.Errorf("Invalid plan file %v", string(data[:2]))
+	}
+	return nil
+}
+
 func PlanEncryptionDisabled() PlanEncryption {
 	return &planDisabled{}
 }
@@ -0,0 +1,83 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/opentofu/internal/encryption/config"
+)
+
+// ErrUnencryptedRemoteState is returned when a remote state data source reads an unencrypted state file, but the
+// configuration requires the remote state to be encrypted.
+type ErrUnencryptedRemoteState struct {
+	// Name is the name of the remote state data source, in the same form as used in the remote_state_data_source
+	// blocks.
+	Name string
+}
+
+func (e *ErrUnencryptedRemoteState) Error() string {
+	return fmt.Sprintf("the remote state read by %s is not encrypted, but encryption is enforced for this remote state data source", e.Name)
+}
+
+// remoteStatePolicy describes which remote state data sources must refuse to read unencrypted state.
+type remoteStatePolicy struct {
+	// enforced enables enforcement for all remote state data sources not listed in allowUnencrypted.
+	enforced         bool
+	allowUnencrypted map[string]bool
+	// enforcedTargets contains the remote state data sources that have enforcement enabled individually.
+	enforcedTargets map[string]bool
+}
+
+func newRemoteStatePolicy(cfg *config.RemoteConfig) (remoteStatePolicy, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	policy := remoteStatePolicy{
+		enforced:         cfg.Enforced,
+		allowUnencrypted: make(map[string]bool, len(cfg.AllowUnencrypted)),
+		enforcedTargets:  make(map[string]bool),
+	}
+	for _, name := range cfg.AllowUnencrypted {
+		policy.allowUnencrypted[name] = true
+	}
+	for _, target := range cfg.Targets {
+		if !target.Enforced {
+			continue
+		}
+		policy.enforcedTargets[target.Name] = true
+		if policy.allowUnencrypted[target.Name] {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Conflicting remote state encryption policy",
+				Detail:   fmt.Sprintf("The remote state data source %q is listed in allow_unencrypted, but its remote_state_data_source block sets enforced = true.", target.Name),
+			})
+		}
+	}
+	return policy, diags
+}
+
+func (p remoteStatePolicy) isEnforced(name string) bool {
+	if p.enforcedTargets[name] {
+		return true
+	}
+	return p.enforced && !p.allowUnencrypted[name]
+}
+
+// enforcedRemoteStateEncryption refuses to decrypt state files that are not encrypted, regardless of whether the
+// underlying StateEncryption has an unencrypted method configured or no encryption configured at all.
+type enforcedRemoteStateEncryption struct {
+	StateEncryption
+	name string
+}
+
+func (e *enforcedRemoteStateEncryption) DecryptState(data []byte) ([]byte, EncryptionStatus, error) {
+	// State files with encrypted sensitive values are accepted, as they don't expose any secrets
+	encrypted, _ := IsEncryptionPayload(data)
+	if header, _ := readSensitiveAttributesHeader(data); !encrypted && header == nil {
+		return nil, StatusUnknown, &ErrUnencryptedRemoteState{Name: e.name}
+	}
+	return e.StateEncryption.DecryptState(data)
+}
@@ -0,0 +1,178 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption/config"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/static"
+	"github.com/opentofu/opentofu/internal/encryption/method/aesgcm"
+	"github.com/opentofu/opentofu/internal/encryption/method/unencrypted"
+	"github.com/opentofu/opentofu/internal/encryption/registry/lockingencryptionregistry"
+)
+
+func TestRemoteStateEnforcement(t *testing.T) {
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(static.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(unencrypted.New()); err != nil {
+		panic(err)
+	}
+
+	const methods = `
+		key_provider "static" "main" {
+			key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+		}
+		method "aes_gcm" "main" {
+			keys = key_provider.static.main
+		}
+		method "unencrypted" "migrate" {}
+	`
+	newEncryption := func(t *testing.T, remote string) (Encryption, error) {
+		t.Helper()
+		cfg, diags := config.LoadConfigFromString("test", methods+remote)
+		if diags.HasErrors() {
+			t.Fatalf("%v", diags.Error())
+		}
+		enc, diags := New(t.Context(), reg, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		return enc, nil
+	}
+
+	plainState := []byte(`{"terraform_version": "1.9.0", "serial": 1, "lineage": "magic"}`)
+	writer, err := newEncryption(t, `state {
+		method = method.aes_gcm.main
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encryptedState, err := writer.State().EncryptState(plainState)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		remote string
+
+		// The remote state data sources expected to refuse unencrypted state.
+		enforced []string
+		// The remote state data sources expected to read unencrypted state.
+		allowed []string
+	}{
+		"not enforced": {
+			remote: `remote_state_data_sources {
+				default {
+					method = method.aes_gcm.main
+					fallback {
+						method = method.unencrypted.migrate
+					}
+				}
+			}`,
+			allowed: []string{"foo", "bar"},
+		},
+		"enforced without encryption": {
+			remote: `remote_state_data_sources {
+				enforced = true
+			}`,
+			enforced: []string{"foo", "bar"},
+		},
+		"enforced with unencrypted fallback": {
+			remote: `remote_state_data_sources {
+				enforced = true
+				allow_unencrypted = ["legacy", "module.child.legacy"]
+				default {
+					method = method.aes_gcm.main
+					fallback {
+						method = method.unencrypted.migrate
+					}
+				}
+			}`,
+			enforced: []string{"foo", "child.legacy"},
+			allowed:  []string{"legacy", "module.child.legacy"},
+		},
+		"enforced per data source": {
+			remote: `remote_state_data_sources {
+				default {
+					method = method.aes_gcm.main
+					fallback {
+						method = method.unencrypted.migrate
+					}
+				}
+				remote_state_data_source "foo" {
+					enforced = true
+					method = method.aes_gcm.main
+				}
+			}`,
+			enforced: []string{"foo"},
+			allowed:  []string{"bar"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			enc, err := newEncryption(t, tc.remote)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, source := range tc.enforced {
+				_, _, err := enc.RemoteState(source).DecryptState(plainState)
+				var unencryptedErr *ErrUnencryptedRemoteState
+				if !errors.As(err, &unencryptedErr) {
+					t.Errorf("%s: expected %T, got %v", source, unencryptedErr, err)
+				} else if unencryptedErr.Name != source {
+					t.Errorf("%s: wrong name in error %q", source, unencryptedErr.Name)
+				}
+			}
+			for _, source := range tc.allowed {
+				if _, _, err := enc.RemoteState(source).DecryptState(plainState); err != nil {
+					t.Errorf("%s: unexpected error reading unencrypted state: %v", source, err)
+				}
+			}
+			for _, source := range append(tc.enforced, tc.allowed...) {
+				if _, _, err := enc.RemoteState(source).DecryptState(encryptedState); err != nil {
+					t.Errorf("%s: unexpected error reading encrypted state: %v", source, err)
+				}
+			}
+		})
+	}
+
+	t.Run("unencrypted method in enforced data source", func(t *testing.T) {
+		_, err := newEncryption(t, `remote_state_data_sources {
+			remote_state_data_source "foo" {
+				enforced = true
+				method = method.aes_gcm.main
+				fallback {
+					method = method.unencrypted.migrate
+				}
+			}
+		}`)
+		if err == nil {
+			t.Fatal("expected an error with the unencrypted method enforced")
+		}
+	})
+
+	t.Run("enforced data source in allow list", func(t *testing.T) {
+		_, err := newEncryption(t, `remote_state_data_sources {
+			allow_unencrypted = ["foo"]
+			remote_state_data_source "foo" {
+				enforced = true
+				method = method.aes_gcm.main
+			}
+		}`)
+		if err == nil {
+			t.Fatal("expected an error with conflicting enforcement")
+		}
+	})
+}
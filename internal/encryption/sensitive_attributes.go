@@ -0,0 +1,490 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/encryption/config"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/method"
+	"github.com/opentofu/opentofu/internal/encryption/method/unencrypted"
+)
+
+// sensitiveAttributesField is the top-level field of a state file in which only the sensitive values are encrypted. It
+// holds the key provider metadata needed to decrypt the values and is removed again on decryption.
+const sensitiveAttributesField = "encryption"
+
+type sensitiveAttributesData struct {
+	Meta    keyProviderMetamap `json:"meta"`
+	Version string             `json:"encryption_version"`
+	Mode    config.TargetMode  `json:"mode"`
+}
+
+// readSensitiveAttributesHeader returns the encryption header of a state file in which only the sensitive values are
+// encrypted, or nil if the state file is not encrypted this way.
+func readSensitiveAttributesHeader(data []byte) (*sensitiveAttributesData, error) {
+	// Avoid parsing the whole state file again in the common case
+	if !bytes.Contains(data, []byte(`"`+sensitiveAttributesField+`"`)) {
+		return nil, nil
+	}
+
+	tmp := struct {
+		Header *sensitiveAttributesData `json:"encryption"`
+	}{}
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		// Not our concern, the caller will report invalid state files
+		return nil, nil
+	}
+	if tmp.Header == nil {
+		return nil, nil
+	}
+	if tmp.Header.Mode != config.TargetModeSensitiveAttributes {
+		return nil, fmt.Errorf("unsupported state encryption mode: %q", tmp.Header.Mode)
+	}
+	if tmp.Header.Version != encryptionVersion {
+		return nil, fmt.Errorf("invalid encrypted payload version: %s != %s", tmp.Header.Version, encryptionVersion)
+	}
+	return tmp.Header, nil
+}
+
+// encryptSensitiveAttributes encrypts each sensitive resource attribute and sensitive output value in the given
+// state file separately, leaving the rest of the state file readable.
+func (base *baseEncryption) encryptSensitiveAttributes(data []byte) ([]byte, error) {
+	encryptor := base.encMethod
+
+	if unencrypted.Is(encryptor) {
+		return data, nil
+	}
+
+	doc, err := transformSensitiveValues(data, false, func(value any) (any, error) {
+		plain, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		encd, err := encryptor.Encrypt(plain)
+		if err != nil {
+			return nil, fmt.Errorf("encryption failed for %s: %w", base.name, err)
+		}
+		return base64.StdEncoding.EncodeToString(encd), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = doc.set(sensitiveAttributesField, sensitiveAttributesData{
+		Meta:    base.encMeta.output,
+		Version: encryptionVersion,
+		Mode:    config.TargetModeSensitiveAttributes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode encrypted data as json: %w", err)
+	}
+	return marshalLike(data, doc)
+}
+
+// decryptSensitiveAttributes decrypts a state file written by encryptSensitiveAttributes, returning the index in
+// base.methods of the method that was used to decrypt it.
+func (base *baseEncryption) decryptSensitiveAttributes(ctx context.Context, data []byte, header *sensitiveAttributesData) ([]byte, int, error) {
+	errs := make([]error, 0)
+	for i, methodCfg := range base.methods {
+		if unencrypted.IsConfig(methodCfg) {
+			// Not applicable
+			continue
+		}
+
+		decMethod, diags := setupMethod(ctx, base.enc.cfg, methodCfg, keyProviderMetadata{
+			input:       header.Meta,
+			output:      make(keyProviderMetamap),
+			unavailable: make(map[keyprovider.MetaStorageKey]*hcl.Diagnostic),
+		}, base.enc.reg, base.staticEval)
+		if diags.HasErrors() {
+			// This cast to error here is safe as we know that at least one error exists
+			return nil, -1, diags
+		}
+
+		doc, err := transformSensitiveValues(data, true, decryptSensitiveValue(decMethod))
+		if err != nil {
+			// Record the failure
+			errs = append(errs, fmt.Errorf("attempted decryption failed for %s: %w", base.name, err))
+			continue
+		}
+
+		doc.remove(sensitiveAttributesField)
+		uncd, err := marshalLike(data, doc)
+		if err != nil {
+			return nil, -1, err
+		}
+		return uncd, i, nil
+	}
+
+	errs = append([]error{fmt.Errorf("decryption failed for all provided methods")}, errs...)
+
+	return nil, -1, errors.New(errors.Join(errs...).Error())
+}
+
+func decryptSensitiveValue(decMethod method.Method) func(any) (any, error) {
+	return func(value any) (any, error) {
+		encoded, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected an encrypted value, found %T", value)
+		}
+		encd, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encrypted value: %w", err)
+		}
+		plain, err := decMethod.Decrypt(encd)
+		if err != nil {
+			return nil, err
+		}
+		var ret any
+		if err := unmarshalJSONValue(plain, &ret); err != nil {
+			return nil, fmt.Errorf("invalid decrypted value: %w", err)
+		}
+		return ret, nil
+	}
+}
+
+// sensitivePathStep is a step of one of the paths in the sensitive_attributes of a resource instance in the state.
+type sensitivePathStep struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// key returns the attribute name of a get_attr step, or the key of an index step, which is either a string, a
+// json.Number or, for elements of sets, the element itself.
+func (s sensitivePathStep) key() (any, error) {
+	var key any
+	if s.Type != "index" {
+		err := unmarshalJSONValue(s.Value, &key)
+		return key, err
+	}
+
+	// Index keys are stored with their type, as they can be of any type
+	var typed struct {
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(s.Value, &typed); err != nil {
+		return nil, err
+	}
+	err := unmarshalJSONValue(typed.Value, &key)
+	return key, err
+}
+
+// transformSensitiveValues calls fn for each value in the given state file that is marked as sensitive, and replaces
+// the value with the result.
+//
+// Sensitive paths of a resource instance may overlap, in which case a value is transformed more than once. When
+// decrypting, reverse must be set so the paths are visited in the opposite order to encryption, which guarantees that
+// each of the paths resolves to the same value it did when it was encrypted.
+func transformSensitiveValues(data []byte, reverse bool, fn func(any) (any, error)) (*jsonObject, error) {
+	doc := &jsonObject{}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("invalid state file: %w", err)
+	}
+
+	if raw, ok := doc.get("resources"); ok && !isJSONNull(raw) {
+		var resources []*jsonObject
+		if err := json.Unmarshal(raw, &resources); err != nil {
+			return nil, fmt.Errorf("invalid resources in state file: %w", err)
+		}
+		for _, resource := range resources {
+			if err := transformResourceInstances(resource, reverse, fn); err != nil {
+				return nil, err
+			}
+		}
+		if err := doc.set("resources", resources); err != nil {
+			return nil, err
+		}
+	}
+
+	if raw, ok := doc.get("outputs"); ok && !isJSONNull(raw) {
+		outputs := &jsonObject{}
+		if err := json.Unmarshal(raw, outputs); err != nil {
+			return nil, fmt.Errorf("invalid outputs in state file: %w", err)
+		}
+		for _, name := range outputs.keys {
+			if err := transformOutput(outputs, name, fn); err != nil {
+				return nil, fmt.Errorf("output %q: %w", name, err)
+			}
+		}
+		if err := doc.set("outputs", outputs); err != nil {
+			return nil, err
+		}
+	}
+
+	return doc, nil
+}
+
+func transformResourceInstances(resource *jsonObject, reverse bool, fn func(any) (any, error)) error {
+	raw, ok := resource.get("instances")
+	if !ok || isJSONNull(raw) {
+		return nil
+	}
+	var instances []*jsonObject
+	if err := json.Unmarshal(raw, &instances); err != nil {
+		return fmt.Errorf("invalid resource instances in state file: %w", err)
+	}
+
+	for _, instance := range instances {
+		rawPaths, ok := instance.get("sensitive_attributes")
+		if !ok {
+			continue
+		}
+		rawAttrs, ok := instance.get("attributes")
+		if !ok {
+			// Legacy flatmap attributes can't carry sensitive paths
+			continue
+		}
+
+		var paths [][]sensitivePathStep
+		if err := json.Unmarshal(rawPaths, &paths); err != nil {
+			return fmt.Errorf("invalid sensitive attributes in state file: %w", err)
+		}
+		if len(paths) == 0 {
+			continue
+		}
+		var attrs any
+		if err := unmarshalJSONValue(rawAttrs, &attrs); err != nil {
+			return fmt.Errorf("invalid attributes in state file: %w", err)
+		}
+
+		for i := range paths {
+			path := paths[i]
+			if reverse {
+				path = paths[len(paths)-1-i]
+			}
+			var err error
+			attrs, err = transformPath(attrs, path, fn)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := instance.set("attributes", attrs); err != nil {
+			return err
+		}
+	}
+
+	return resource.set("instances", instances)
+}
+
+func transformOutput(outputs *jsonObject, name string, fn func(any) (any, error)) error {
+	output := &jsonObject{}
+	if err := json.Unmarshal(outputs.values[name], output); err != nil {
+		return err
+	}
+	var sensitive bool
+	if raw, ok := output.get("sensitive"); ok {
+		if err := json.Unmarshal(raw, &sensitive); err != nil {
+			return err
+		}
+	}
+	if !sensitive {
+		return nil
+	}
+
+	var value any
+	if raw, ok := output.get("value"); ok {
+		if err := unmarshalJSONValue(raw, &value); err != nil {
+			return err
+		}
+	}
+	value, err := fn(value)
+	if err != nil {
+		return err
+	}
+	if err := output.set("value", value); err != nil {
+		return err
+	}
+	return outputs.set(name, output)
+}
+
+// transformPath resolves the given sensitive path in the value and replaces the value it points to with the result of
+// fn, returning the updated value.
+//
+// A path is resolved as far as the value allows. When it reaches a string, which is either the leaf value or a value
+// encrypted earlier, or a step indexes into a list with a key that is not a number, as happens for elements of sets,
+// the value reached so far is transformed as a whole. Paths that point to a value that doesn't exist are ignored.
+func transformPath(value any, path []sensitivePathStep, fn func(any) (any, error)) (any, error) {
+	if len(path) == 0 {
+		return fn(value)
+	}
+	if _, ok := value.(string); ok {
+		return fn(value)
+	}
+
+	key, err := path[0].key()
+	if err != nil {
+		return nil, fmt.Errorf("invalid sensitive attribute path: %w", err)
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		name, ok := key.(string)
+		if !ok {
+			return value, nil
+		}
+		child, ok := v[name]
+		if !ok {
+			return value, nil
+		}
+		child, err := transformPath(child, path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		v[name] = child
+		return v, nil
+	case []any:
+		num, ok := key.(json.Number)
+		if !ok {
+			return fn(value)
+		}
+		idx, err := num.Int64()
+		if err != nil || idx < 0 || idx >= int64(len(v)) {
+			return value, nil
+		}
+		child, err := transformPath(v[idx], path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		v[idx] = child
+		return v, nil
+	default:
+		return value, nil
+	}
+}
+
+// marshalLike serializes the given state file in the same style as the original, which is indented by
+// statefile.WriteIndent and compact otherwise.
+func marshalLike(original []byte, doc *jsonObject) ([]byte, error) {
+	var ret []byte
+	var err error
+	if bytes.HasPrefix(original, []byte("{\n")) {
+		ret, err = json.MarshalIndent(doc, "", "  ")
+	} else {
+		ret, err = json.Marshal(doc)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasSuffix(original, []byte("\n")) {
+		ret = append(ret, '\n')
+	}
+	return ret, nil
+}
+
+// unmarshalJSONValue decodes arbitrary JSON, keeping numbers as json.Number so that they survive being serialized
+// again unchanged.
+func unmarshalJSONValue(data []byte, v *any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+func isJSONNull(data json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(data), []byte("null"))
+}
+
+// jsonObject is a JSON object that preserves the order of its keys, so that parts of a state file can be replaced
+// without reordering the whole file.
+type jsonObject struct {
+	keys   []string
+	values map[string]json.RawMessage
+}
+
+func (o *jsonObject) get(key string) (json.RawMessage, bool) {
+	value, ok := o.values[key]
+	return value, ok
+}
+
+func (o *jsonObject) set(key string, value any) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	if o.values == nil {
+		o.values = make(map[string]json.RawMessage)
+	}
+	o.values[key] = raw
+	return nil
+}
+
+func (o *jsonObject) remove(key string) {
+	if _, ok := o.values[key]; !ok {
+		return
+	}
+	delete(o.values, key)
+	for i, k := range o.keys {
+		if k == key {
+			o.keys = append(o.keys[:i], o.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+func (o *jsonObject) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("expected a JSON object")
+	}
+
+	o.keys = nil
+	o.values = make(map[string]json.RawMessage)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("expected a JSON object key")
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		if _, ok := o.values[key]; !ok {
+			o.keys = append(o.keys, key)
+		}
+		o.values[key] = value
+	}
+	_, err = dec.Token()
+	return err
+}
+
+func (o *jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(o.values[key])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,337 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption/config"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/pbkdf2"
+	"github.com/opentofu/opentofu/internal/encryption/method/aesgcm"
+	"github.com/opentofu/opentofu/internal/encryption/method/unencrypted"
+	"github.com/opentofu/opentofu/internal/encryption/registry/lockingencryptionregistry"
+)
+
+const sensitiveTestState = `{
+  "version": 4,
+  "terraform_version": "1.9.0",
+  "serial": 3,
+  "lineage": "magic",
+  "outputs": {
+    "endpoint": {
+      "value": "db.example.com",
+      "type": "string"
+    },
+    "password": {
+      "value": "hunter2",
+      "type": "string",
+      "sensitive": true
+    }
+  },
+  "resources": [
+    {
+      "mode": "managed",
+      "type": "test_database",
+      "name": "main",
+      "provider": "provider[\"registry.opentofu.org/hashicorp/test\"]",
+      "instances": [
+        {
+          "schema_version": 0,
+          "attributes": {
+            "id": "db-1234",
+            "password": "hunter2",
+            "port": 5432,
+            "settings": {
+              "api_key": "s3cr3t",
+              "size": 10
+            },
+            "tags": [
+              "prod",
+              "classified"
+            ],
+            "users": [
+              "admin",
+              "root"
+            ]
+          },
+          "sensitive_attributes": [
+            [
+              {
+                "type": "get_attr",
+                "value": "password"
+              }
+            ],
+            [
+              {
+                "type": "get_attr",
+                "value": "settings"
+              },
+              {
+                "type": "index",
+                "value": {
+                  "value": "api_key",
+                  "type": "string"
+                }
+              }
+            ],
+            [
+              {
+                "type": "get_attr",
+                "value": "tags"
+              },
+              {
+                "type": "index",
+                "value": {
+                  "value": 1,
+                  "type": "number"
+                }
+              }
+            ],
+            [
+              {
+                "type": "get_attr",
+                "value": "users"
+              },
+              {
+                "type": "index",
+                "value": {
+                  "value": 0,
+                  "type": "number"
+                }
+              }
+            ],
+            [
+              {
+                "type": "get_attr",
+                "value": "users"
+              }
+            ]
+          ]
+        }
+      ]
+    }
+  ],
+  "check_results": null
+}
+`
+
+func TestSensitiveAttributesEncryption(t *testing.T) {
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(pbkdf2.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(unencrypted.New()); err != nil {
+		panic(err)
+	}
+
+	newEncryption := func(t *testing.T, target string) StateEncryption {
+		t.Helper()
+		cfg, diags := config.LoadConfigFromString("test", `
+			key_provider "pbkdf2" "main" {
+				passphrase = "Correct passphrase 123"
+			}
+			key_provider "pbkdf2" "other" {
+				passphrase = "Other passphrase 123"
+			}
+			method "aes_gcm" "main" {
+				keys = key_provider.pbkdf2.main
+			}
+			method "aes_gcm" "other" {
+				keys = key_provider.pbkdf2.other
+			}
+			method "unencrypted" "migrate" {}
+			`+target)
+		if diags.HasErrors() {
+			t.Fatalf("%v", diags.Error())
+		}
+		enc, diags := New(t.Context(), reg, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+		if diags.HasErrors() {
+			t.Fatalf("%v", diags.Error())
+		}
+		return enc.State()
+	}
+
+	partial := newEncryption(t, `state {
+		mode = "sensitive_attributes"
+		method = method.aes_gcm.main
+	}`)
+	encrypted, err := partial.EncryptState([]byte(sensitiveTestState))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("secrets are encrypted", func(t *testing.T) {
+		for _, secret := range []string{"hunter2", "s3cr3t", "classified", "admin", "root"} {
+			if bytes.Contains(encrypted, []byte(secret)) {
+				t.Errorf("encrypted state contains the secret %q:\n%s", secret, encrypted)
+			}
+		}
+		for _, visible := range []string{"db-1234", "db.example.com", "test_database", `"lineage": "magic"`, "prod"} {
+			if !bytes.Contains(encrypted, []byte(visible)) {
+				t.Errorf("encrypted state doesn't contain %q:\n%s", visible, encrypted)
+			}
+		}
+		if ok, _ := IsEncryptionPayload(encrypted); ok {
+			t.Errorf("state with encrypted sensitive values is not expected to be an encrypted payload")
+		}
+		if err := validateStatePayload(encrypted); err != nil {
+			t.Errorf("state with encrypted sensitive values is not a valid state file: %s", err)
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		decrypted, status, err := partial.DecryptState(encrypted)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status != StatusSatisfied {
+			t.Errorf("wrong status %d; want %d", status, StatusSatisfied)
+		}
+		if string(decrypted) != sensitiveTestState {
+			t.Errorf("wrong decrypted state\ngot:  %s\nwant: %s", decrypted, sensitiveTestState)
+		}
+	})
+
+	t.Run("compact", func(t *testing.T) {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, []byte(sensitiveTestState)); err != nil {
+			t.Fatal(err)
+		}
+		encrypted, err := partial.EncryptState(compact.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(encrypted, []byte("\n")) {
+			t.Errorf("compact state was indented: %s", encrypted)
+		}
+		decrypted, _, err := partial.DecryptState(encrypted)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decrypted, compact.Bytes()) {
+			t.Errorf("wrong decrypted state\ngot:  %s\nwant: %s", decrypted, compact.Bytes())
+		}
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		enc := newEncryption(t, `state {
+			mode = "sensitive_attributes"
+			method = method.aes_gcm.other
+		}`)
+		if _, _, err := enc.DecryptState(encrypted); err == nil {
+			t.Fatal("expected an error decrypting with the wrong key")
+		}
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		enc := newEncryption(t, `state {
+			mode = "sensitive_attributes"
+			method = method.aes_gcm.other
+			fallback {
+				method = method.aes_gcm.main
+			}
+		}`)
+		decrypted, status, err := enc.DecryptState(encrypted)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status != StatusMigration {
+			t.Errorf("wrong status %d; want %d", status, StatusMigration)
+		}
+		if string(decrypted) != sensitiveTestState {
+			t.Errorf("wrong decrypted state\ngot:  %s\nwant: %s", decrypted, sensitiveTestState)
+		}
+	})
+
+	t.Run("migrate to full", func(t *testing.T) {
+		full := newEncryption(t, `state {
+			method = method.aes_gcm.main
+		}`)
+		decrypted, status, err := full.DecryptState(encrypted)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status != StatusMigration {
+			t.Errorf("wrong status %d; want %d", status, StatusMigration)
+		}
+		if string(decrypted) != sensitiveTestState {
+			t.Errorf("wrong decrypted state\ngot:  %s\nwant: %s", decrypted, sensitiveTestState)
+		}
+	})
+
+	t.Run("migrate from full", func(t *testing.T) {
+		full := newEncryption(t, `state {
+			method = method.aes_gcm.main
+		}`)
+		fullEncrypted, err := full.EncryptState([]byte(sensitiveTestState))
+		if err != nil {
+			t.Fatal(err)
+		}
+		decrypted, status, err := partial.DecryptState(fullEncrypted)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status != StatusMigration {
+			t.Errorf("wrong status %d; want %d", status, StatusMigration)
+		}
+		if string(decrypted) != sensitiveTestState {
+			t.Errorf("wrong decrypted state\ngot:  %s\nwant: %s", decrypted, sensitiveTestState)
+		}
+	})
+
+	t.Run("unencrypted without fallback", func(t *testing.T) {
+		if _, _, err := partial.DecryptState([]byte(sensitiveTestState)); err == nil {
+			t.Fatal("expected an error reading an unencrypted state")
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		_, _, err := StateEncryptionDisabled().DecryptState(encrypted)
+		if err == nil || !strings.Contains(err.Error(), "encrypted sensitive values") {
+			t.Fatalf("expected an error reading encrypted sensitive values without encryption, got %v", err)
+		}
+	})
+
+	t.Run("inspect", func(t *testing.T) {
+		got := InspectState(t.Context(), partial, encrypted)
+		if !got.Encrypted || got.Status != StatusSatisfied || got.Method != "method.aes_gcm.main" {
+			t.Errorf("wrong inspection %#v", got)
+		}
+	})
+}
+
+func TestSensitiveAttributesEncryption_invalidMode(t *testing.T) {
+	tests := map[string]string{
+		"plan": `plan {
+			mode = "sensitive_attributes"
+			method = method.unencrypted.migrate
+		}`,
+		"backend_config": `backend_config {
+			mode = "sensitive_attributes"
+			method = method.unencrypted.migrate
+		}`,
+		"unknown": `state {
+			mode = "everything"
+			method = method.unencrypted.migrate
+		}`,
+	}
+	for name, target := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, diags := config.LoadConfigFromString("test", `method "unencrypted" "migrate" {}
+			`+target)
+			if !diags.HasErrors() {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
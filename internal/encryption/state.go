@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/hcl/v2"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/encryption/config"
+	"github.com/opentofu/opentofu/internal/encryption/method/unencrypted"
 )
 
 // StateEncryption describes the interface for encrypting state files.
@@ -57,11 +58,14 @@ type StateEncryption interface {
 
 type stateEncryption struct {
 	base *baseEncryption
+
+	// sensitiveAttributes is true if only the sensitive values in the state file should be encrypted.
+	sensitiveAttributes bool
 }
 
-func newStateEncryption(ctx context.Context, enc *encryption, target *config.TargetConfig, enforced bool, name string, staticEval *configs.StaticEvaluator) (StateEncryption, hcl.Diagnostics) {
+func newStateEncryption(ctx context.Context, enc *encryption, target *config.TargetConfig, enforced bool, sensitiveAttributes bool, name string, staticEval *configs.StaticEvaluator) (StateEncryption, hcl.Diagnostics) {
 	base, diags := newBaseEncryption(ctx, enc, target, enforced, name, staticEval)
-	return &stateEncryption{base}, diags
+	return &stateEncryption{base: base, sensitiveAttributes: sensitiveAttributes}, diags
 }
 
 type statedata struct {
@@ -76,6 +80,10 @@ func (s *stateEncryption) EncryptState(plainState []byte) ([]byte, error) {
 		return nil, err
 	}
 
+	if s.sensitiveAttributes {
+		return s.base.encryptSensitiveAttributes(plainState)
+	}
+
 	return s.base.encrypt(plainState, func(base basedata) interface{} {
 		// Merge together the base encryption data and the passthrough fields
 		return struct {
@@ -89,26 +97,33 @@ func (s *stateEncryption) EncryptState(plainState []byte) ([]byte, error) {
 }
 
 func (s *stateEncryption) DecryptState(encryptedState []byte) ([]byte, EncryptionStatus, error) {
-	decryptedState, status, err := s.base.decrypt(context.TODO(), encryptedState, func(data []byte) error {
-		tmp := struct {
-			FormatVersion string `json:"terraform_version"`
-		}{}
-		err := json.Unmarshal(data, &tmp)
+	header, err := readSensitiveAttributesHeader(encryptedState)
+	if err != nil {
+		return nil, StatusUnknown, err
+	}
+	if header != nil {
+		decryptedState, methodIdx, err := s.base.decryptSensitiveAttributes(context.TODO(), encryptedState, header)
 		if err != nil {
-			return err
+			return nil, StatusUnknown, err
 		}
-		if len(tmp.FormatVersion) == 0 {
-			// Not a state file
-			return fmt.Errorf("Given payload is not a state file")
+		if methodIdx == 0 && s.sensitiveAttributes {
+			return decryptedState, StatusSatisfied, nil
 		}
-		// Probably a state file
-		return nil
-	})
+		// Used a fallback, or the state file should be encrypted as a whole
+		return decryptedState, StatusMigration, nil
+	}
+
+	decryptedState, status, err := s.base.decrypt(context.TODO(), encryptedState, validateStatePayload)
 
 	if err != nil {
 		return nil, status, err
 	}
 
+	if s.sensitiveAttributes && status == StatusSatisfied && !unencrypted.Is(s.base.encMethod) {
+		// The primary method decrypted a state file that was encrypted as a whole
+		status = StatusMigration
+	}
+
 	// Make sure that the state passthrough fields match
 	var encrypted statedata
 	err = json.Unmarshal(encryptedState, &encrypted)
@@ -134,6 +149,24 @@ func (s *stateEncryption) DecryptState(encryptedState []byte) ([]byte, Encryptio
 	return decryptedState, status, nil
 }
 
+// validateStatePayload checks whether the given data is an unencrypted state
+// file.
+func validateStatePayload(data []byte) error {
+	tmp := struct {
+		FormatVersion string `json:"terraform_version"`
+	}{}
+	err := json.Unmarshal(data, &tmp)
+	if err != nil {
+		return err
+	}
+	if len(tmp.FormatVersion) == 0 {
+		// Not a state file
+		return fmt.Errorf("Given payload is not a state file")
+	}
+	// Probably a state file
+	return nil
+}
+
 func StateEncryptionDisabled() StateEncryption {
 	return &stateDisabled{}
 }
@@ -144,5 +177,10 @@ func (s *stateDisabled) EncryptState(plainState []byte) ([]byte, error) {
 	return plainState, nil
 }
 func (s *stateDisabled) DecryptState(encryptedState []byte) ([]byte, EncryptionStatus, error) {
+	// A state file with encrypted sensitive values is otherwise valid, so make sure it isn't silently read with the
+	// encrypted values in place of the real ones.
+	if header, err := readSensitiveAttributesHeader(encryptedState); err != nil || header != nil {
+		return nil, StatusUnknown, fmt.Errorf("the state file contains encrypted sensitive values, but state encryption is not configured")
+	}
 	return encryptedState, StatusSatisfied, nil
 }
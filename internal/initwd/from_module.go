@@ -388,7 +388,10 @@ func DirFromModule(ctx context.Context, loader configload.Loader, rootDir, modul
 		hooks.Install(newRecord.Key, newRecord.Version, newRecord.Dir)
 	}
 
-	err = retManifest.WriteSnapshotToDir(modulesDir)
+	// The encryption configuration of the new working directory can only be
+	// loaded once it has been copied, so the caller is responsible for
+	// encrypting the manifest afterwards if needed.
+	err = retManifest.WriteSnapshotToDir(modulesDir, nil)
 	if err != nil {
 		diags = diags.Append(tfdiags.Sourceless(
 			tfdiags.Error,
@@ -49,6 +49,11 @@ type ModuleInstaller struct {
 	registryPackageSources map[moduleVersion]registry.PackageLocation
 
 	ConfigInstance func(ctx context.Context, root *configs.Module, modules eval.ExternalModules) (*eval.ConfigInstance, tfdiags.Diagnostics)
+
+	// ManifestEncryption encrypts the module manifest when it is written and
+	// decrypts it when it is read. If nil, the module manifest is stored
+	// unencrypted.
+	ManifestEncryption modsdir.ManifestEncryption
 }
 
 type moduleVersion struct {
@@ -164,7 +169,7 @@ func (i *ModuleInstaller) InstallModules(ctx context.Context, rootDir, testsDir
 	log.Printf("[TRACE] ModuleInstaller: installing child modules for %s into %s", rootDir, i.modsDir)
 	var diags tfdiags.Diagnostics
 
-	manifest, err := modsdir.ReadManifestSnapshotForDir(i.modsDir)
+	manifest, err := modsdir.ReadManifestSnapshotForDir(i.modsDir, i.ManifestEncryption)
 	if err != nil {
 		diags = diags.Append(tfdiags.Sourceless(
 			tfdiags.Error,
@@ -406,7 +411,7 @@ func (i *ModuleInstaller) installDescendentModules(ctx context.Context, rootMod
 		diags = tfdiags.OverrideAll(diags, tfdiags.Warning, nil)
 	}
 
-	err := manifest.WriteSnapshotToDir(i.modsDir)
+	err := manifest.WriteSnapshotToDir(i.modsDir, i.ManifestEncryption)
 	if err != nil {
 		diags = diags.Append(tfdiags.Sourceless(
 			tfdiags.Error,
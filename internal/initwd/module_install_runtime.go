@@ -82,7 +82,7 @@ func (i *ModuleInstaller) installDescendentModulesNewRuntime(ctx context.Context
 		diags = tfdiags.OverrideAll(diags, tfdiags.Warning, nil)
 	}
 
-	err := manifest.WriteSnapshotToDir(i.modsDir)
+	err := manifest.WriteSnapshotToDir(i.modsDir, i.ManifestEncryption)
 	if err != nil {
 		diags = diags.Append(tfdiags.Sourceless(
 			tfdiags.Error,
@@ -7,6 +7,7 @@ package modsdir
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -69,14 +70,40 @@ func (m Manifest) ModuleKey(path addrs.Module) string {
 // purpose.
 type manifestSnapshotFile struct {
 	Records []Record `json:"Modules"`
+
+	// EncryptionVersion is only set in an encrypted manifest snapshot, and is
+	// used to detect one that is read without the encryption configured.
+	EncryptionVersion string `json:"encryption_version,omitempty"`
+}
+
+// ManifestEncryption encrypts manifest snapshots when they are written and
+// decrypts them when they are read. It is implemented by
+// encryption.ModuleManifestEncryption.
+type ManifestEncryption interface {
+	EncryptModuleManifest([]byte) ([]byte, error)
+	DecryptModuleManifest([]byte) ([]byte, error)
 }
 
-func ReadManifestSnapshot(r io.Reader) (Manifest, error) {
+// ErrManifestEncrypted is returned when reading an encrypted manifest snapshot
+// without a ManifestEncryption to decrypt it.
+var ErrManifestEncrypted = errors.New("the module manifest is encrypted, but no module_manifest encryption is configured")
+
+// ReadManifestSnapshot reads a manifest snapshot from the given reader,
+// decrypting it with the given encryption. If enc is nil, the snapshot is
+// expected to be unencrypted.
+func ReadManifestSnapshot(r io.Reader, enc ManifestEncryption) (Manifest, error) {
 	src, err := io.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
 
+	if len(src) != 0 && enc != nil {
+		src, err = enc.DecryptModuleManifest(src)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt module manifest: %w", err)
+		}
+	}
+
 	if len(src) == 0 {
 		// This should never happen, but we'll tolerate it as if it were
 		// a valid empty JSON object.
@@ -88,6 +115,9 @@ func ReadManifestSnapshot(r io.Reader) (Manifest, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error unmarshalling snapshot: %w", err)
 	}
+	if read.EncryptionVersion != "" {
+		return nil, ErrManifestEncrypted
+	}
 	new := make(Manifest)
 	for _, record := range read.Records {
 		if record.VersionStr != "" {
@@ -126,7 +156,7 @@ func ReadManifestSnapshot(r io.Reader) (Manifest, error) {
 	return new, nil
 }
 
-func ReadManifestSnapshotForDir(dir string) (Manifest, error) {
+func ReadManifestSnapshotForDir(dir string, enc ManifestEncryption) (Manifest, error) {
 	fn := filepath.Join(dir, ManifestSnapshotFilename)
 	r, err := os.Open(fn)
 	if err != nil {
@@ -136,10 +166,13 @@ func ReadManifestSnapshotForDir(dir string) (Manifest, error) {
 		return nil, err
 	}
 	defer r.Close()
-	return ReadManifestSnapshot(r)
+	return ReadManifestSnapshot(r, enc)
 }
 
-func (m Manifest) WriteSnapshot(w io.Writer) error {
+// WriteSnapshot writes a snapshot of the manifest to the given writer,
+// encrypted with the given encryption. If enc is nil, the snapshot is written
+// unencrypted.
+func (m Manifest) WriteSnapshot(w io.Writer, enc ManifestEncryption) error {
 	var write manifestSnapshotFile
 
 	var keys []string
@@ -168,12 +201,18 @@ func (m Manifest) WriteSnapshot(w io.Writer) error {
 	if err != nil {
 		return err
 	}
+	if enc != nil {
+		src, err = enc.EncryptModuleManifest(src)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt module manifest: %w", err)
+		}
+	}
 
 	_, err = w.Write(src)
 	return err
 }
 
-func (m Manifest) WriteSnapshotToDir(dir string) error {
+func (m Manifest) WriteSnapshotToDir(dir string, enc ManifestEncryption) error {
 	fn := filepath.Join(dir, ManifestSnapshotFilename)
 	log.Printf("[TRACE] modsdir: writing modules manifest to %s", fn)
 	w, err := os.Create(fn)
@@ -182,5 +221,5 @@ func (m Manifest) WriteSnapshotToDir(dir string) error {
 	}
 	defer w.Close()
 
-	return m.WriteSnapshot(w)
+	return m.WriteSnapshot(w, enc)
 }
@@ -0,0 +1,408 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package moduletest
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/checks"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+// CoverageKind is the kind of configuration object that a CoverageItem
+// tracks.
+type CoverageKind string
+
+const (
+	CoverageResource  CoverageKind = "resource"
+	CoverageOutput    CoverageKind = "output"
+	CoverageCheck     CoverageKind = "check"
+	CoverageCondition CoverageKind = "condition"
+)
+
+// CoverageKinds lists the kinds of configuration objects in the order they
+// are reported.
+var CoverageKinds = []CoverageKind{CoverageResource, CoverageOutput, CoverageCheck, CoverageCondition}
+
+// CoverageItem tracks how often a single object in the module under test
+// was exercised by the run blocks of the test suite.
+type CoverageItem struct {
+	Kind CoverageKind
+
+	// Address is the address of the object within the module under test,
+	// such as test_resource.foo or test_resource.foo.precondition[0].
+	Address string
+
+	// Range is the declaration of the object in the configuration.
+	Range hcl.Range
+
+	// Planned and Applied count the run blocks that planned and applied the
+	// object. Output values and check blocks are counted when they are
+	// evaluated, and conditions never are.
+	Planned int
+	Applied int
+
+	// Passed and Failed count the number of times a condition evaluated to
+	// true and to false. They are always zero for the other kinds.
+	Passed int
+	Failed int
+}
+
+// Covered returns true if the object was exercised by the test suite. Every
+// condition must have evaluated to both true and false to be covered.
+func (item *CoverageItem) Covered() bool {
+	if item.Kind == CoverageCondition {
+		return item.Passed > 0 && item.Failed > 0
+	}
+	return item.Planned > 0
+}
+
+// CoverageTotals counts the objects of a single kind that were exercised by
+// the test suite.
+type CoverageTotals struct {
+	// Total is the number of objects of the kind.
+	Total int
+
+	// Covered is the number of objects for which CoverageItem.Covered
+	// returns true.
+	Covered int
+
+	// Planned, Applied, Passed and Failed are the number of objects that
+	// were planned, applied, evaluated to true and evaluated to false at
+	// least once.
+	Planned int
+	Applied int
+	Passed  int
+	Failed  int
+}
+
+// Coverage records which objects in the module under test were exercised by
+// the run blocks of the test suite.
+//
+// Only run blocks executed against the main configuration contribute to the
+// coverage, and only the objects declared directly in the root module of the
+// main configuration are tracked. It is safe to record the results of run
+// blocks executed concurrently.
+type Coverage struct {
+	// Items holds every tracked object, ordered by their position in the
+	// configuration.
+	Items []*CoverageItem
+
+	items map[string]*CoverageItem
+	lock  sync.Mutex
+}
+
+// NewCoverage returns a Coverage that tracks the resources, output values,
+// check blocks and conditions declared in the root module of the given
+// configuration.
+func NewCoverage(config *configs.Config) *Coverage {
+	coverage := &Coverage{
+		items: make(map[string]*CoverageItem),
+	}
+
+	add := func(kind CoverageKind, addr string, rng hcl.Range) {
+		item := &CoverageItem{
+			Kind:    kind,
+			Address: addr,
+			Range:   rng,
+		}
+		coverage.items[addr] = item
+		coverage.Items = append(coverage.Items, item)
+	}
+	addConditions := func(container string, kind addrs.CheckRuleType, rules []*configs.CheckRule) {
+		for ix, rule := range rules {
+			add(CoverageCondition, conditionAddr(container, kind, ix), rule.DeclRange)
+		}
+	}
+
+	module := config.Module
+	for _, resources := range []map[string]*configs.Resource{module.ManagedResources, module.DataResources} {
+		for _, resource := range resources {
+			addr := resource.Addr().String()
+			add(CoverageResource, addr, resource.DeclRange)
+			addConditions(addr, addrs.ResourcePrecondition, resource.Preconditions)
+			addConditions(addr, addrs.ResourcePostcondition, resource.Postconditions)
+		}
+	}
+	for _, output := range module.Outputs {
+		addr := output.Addr().String()
+		add(CoverageOutput, addr, output.DeclRange)
+		addConditions(addr, addrs.OutputPrecondition, output.Preconditions)
+	}
+	for _, check := range module.Checks {
+		addr := check.Addr().String()
+		add(CoverageCheck, addr, check.DeclRange)
+		addConditions(addr, addrs.CheckAssertion, check.Asserts)
+	}
+	for _, variable := range module.Variables {
+		addConditions(variable.Addr().String(), addrs.InputValidation, variable.Validations)
+	}
+
+	sort.Slice(coverage.Items, func(i, j int) bool {
+		left, right := coverage.Items[i].Range, coverage.Items[j].Range
+		if left.Filename != right.Filename {
+			return left.Filename < right.Filename
+		}
+		if left.Start.Byte != right.Start.Byte {
+			return left.Start.Byte < right.Start.Byte
+		}
+		return coverage.Items[i].Address < coverage.Items[j].Address
+	})
+
+	return coverage
+}
+
+// RecordPlan records the objects planned by a run block, along with the
+// conditions evaluated in the given state of the checks of the plan walk, as
+// reported by tofu.PlanOpts.RecordChecks. The plan may be partial if planning
+// failed, and the state of the checks may be nil if it wasn't reported.
+func (c *Coverage) RecordPlan(plan *plans.Plan, checkState *checks.State) {
+	if plan == nil {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	planned := make(map[*CoverageItem]bool)
+	if plan.Changes != nil {
+		for _, change := range plan.Changes.Resources {
+			if change.Addr.Module.IsRoot() {
+				planned[c.items[change.Addr.Resource.Resource.String()]] = true
+			}
+		}
+		for _, change := range plan.Changes.Outputs {
+			if change.Addr.Module.IsRoot() {
+				planned[c.items[change.Addr.OutputValue.String()]] = true
+			}
+		}
+	}
+	for item := range c.checks(checkState) {
+		planned[item] = true
+	}
+
+	for item := range planned {
+		if item != nil {
+			item.Planned++
+		}
+	}
+}
+
+// RecordApply records the objects in the state after a run block applied
+// its plan, along with the conditions evaluated in the given state of the
+// checks of the apply walk, as reported by tofu.ApplyOpts.RecordChecks, which
+// may be nil if it wasn't reported.
+func (c *Coverage) RecordApply(state *states.State, checkState *checks.State) {
+	if state == nil {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	applied := c.objects(state)
+	for item := range c.checks(checkState) {
+		applied[item] = true
+	}
+
+	for item := range applied {
+		if item != nil {
+			item.Applied++
+		}
+	}
+}
+
+// objects returns the resources and output values of the root module in the
+// given state.
+func (c *Coverage) objects(state *states.State) map[*CoverageItem]bool {
+	ret := make(map[*CoverageItem]bool)
+	if state == nil || state.RootModule() == nil {
+		return ret
+	}
+
+	module := state.RootModule()
+	for _, resource := range module.Resources {
+		if len(resource.Instances) > 0 {
+			ret[c.items[resource.Addr.Resource.String()]] = true
+		}
+	}
+	for name := range module.OutputValues {
+		ret[c.items[addrs.OutputValue{Name: name}.String()]] = true
+	}
+	return ret
+}
+
+// checks records the outcome of the conditions in the given state of the
+// checks, and returns the objects whose checks were evaluated.
+func (c *Coverage) checks(checkState *checks.State) map[*CoverageItem]bool {
+	ret := make(map[*CoverageItem]bool)
+	if checkState == nil {
+		return ret
+	}
+
+	for _, configAddr := range checkState.AllConfigAddrs() {
+		container := configAddr.String()
+		if item, ok := c.items[container]; ok && checkState.AggregateCheckStatus(configAddr) != checks.StatusUnknown {
+			ret[item] = true
+		}
+
+		for _, objectAddr := range checkState.ObjectAddrs(configAddr) {
+			for kind, statuses := range checkState.ObjectCheckRuleStatuses(objectAddr) {
+				for ix, status := range statuses {
+					item, ok := c.items[conditionAddr(container, kind, ix)]
+					if !ok {
+						continue
+					}
+
+					switch status {
+					case checks.StatusPass:
+						item.Passed++
+					case checks.StatusFail:
+						item.Failed++
+					}
+				}
+			}
+		}
+	}
+	return ret
+}
+
+// Totals returns the number of objects of the given kind that were exercised
+// by the test suite.
+func (c *Coverage) Totals(kind CoverageKind) CoverageTotals {
+	var totals CoverageTotals
+	for _, item := range c.Items {
+		if item.Kind != kind {
+			continue
+		}
+
+		totals.Total++
+		if item.Covered() {
+			totals.Covered++
+		}
+		if item.Planned > 0 {
+			totals.Planned++
+		}
+		if item.Applied > 0 {
+			totals.Applied++
+		}
+		if item.Passed > 0 {
+			totals.Passed++
+		}
+		if item.Failed > 0 {
+			totals.Failed++
+		}
+	}
+	return totals
+}
+
+// LCOV returns the coverage in the lcov tracefile format.
+//
+// Each resource, output value and check block is reported as a function, and
+// each condition as a pair of branches for the outcomes true and false. The
+// lines declaring the objects are reported as the executable lines.
+func (c *Coverage) LCOV() []byte {
+	var files []string
+	items := make(map[string][]*CoverageItem)
+	for _, item := range c.Items {
+		if _, ok := items[item.Range.Filename]; !ok {
+			files = append(files, item.Range.Filename)
+		}
+		items[item.Range.Filename] = append(items[item.Range.Filename], item)
+	}
+
+	var buf bytes.Buffer
+	for _, file := range files {
+		buf.WriteString("TN:\n")
+		fmt.Fprintf(&buf, "SF:%s\n", file)
+
+		var functions, functionsHit int
+		for _, item := range items[file] {
+			if item.Kind == CoverageCondition {
+				continue
+			}
+			fmt.Fprintf(&buf, "FN:%d,%s\n", item.Range.Start.Line, item.Address)
+			fmt.Fprintf(&buf, "FNDA:%d,%s\n", item.Planned, item.Address)
+			functions++
+			if item.Planned > 0 {
+				functionsHit++
+			}
+		}
+		fmt.Fprintf(&buf, "FNF:%d\n", functions)
+		fmt.Fprintf(&buf, "FNH:%d\n", functionsHit)
+
+		var branches, branchesHit, block int
+		for _, item := range items[file] {
+			if item.Kind != CoverageCondition {
+				continue
+			}
+			for branch, count := range []int{item.Passed, item.Failed} {
+				taken := "-"
+				if item.Passed+item.Failed > 0 {
+					taken = fmt.Sprint(count)
+				}
+				fmt.Fprintf(&buf, "BRDA:%d,%d,%d,%s\n", item.Range.Start.Line, block, branch, taken)
+				branches++
+				if count > 0 {
+					branchesHit++
+				}
+			}
+			block++
+		}
+		fmt.Fprintf(&buf, "BRF:%d\n", branches)
+		fmt.Fprintf(&buf, "BRH:%d\n", branchesHit)
+
+		var lines []int
+		hits := make(map[int]int)
+		for _, item := range items[file] {
+			line := item.Range.Start.Line
+			if _, ok := hits[line]; !ok {
+				lines = append(lines, line)
+			}
+			hits[line] += item.Planned + item.Passed + item.Failed
+		}
+		sort.Ints(lines)
+
+		var linesHit int
+		for _, line := range lines {
+			fmt.Fprintf(&buf, "DA:%d,%d\n", line, hits[line])
+			if hits[line] > 0 {
+				linesHit++
+			}
+		}
+		fmt.Fprintf(&buf, "LF:%d\n", len(lines))
+		fmt.Fprintf(&buf, "LH:%d\n", linesHit)
+		buf.WriteString("end_of_record\n")
+	}
+	return buf.Bytes()
+}
+
+// conditionAddr returns the address of a condition within the given
+// container, matching the format of addrs.CheckRule.
+func conditionAddr(container string, kind addrs.CheckRuleType, index int) string {
+	switch kind {
+	case addrs.ResourcePrecondition, addrs.OutputPrecondition:
+		return fmt.Sprintf("%s.precondition[%d]", container, index)
+	case addrs.ResourcePostcondition:
+		return fmt.Sprintf("%s.postcondition[%d]", container, index)
+	case addrs.CheckDataResource:
+		return fmt.Sprintf("%s.data[%d]", container, index)
+	case addrs.CheckAssertion:
+		return fmt.Sprintf("%s.assert[%d]", container, index)
+	case addrs.InputValidation:
+		return fmt.Sprintf("%s.validation[%d]", container, index)
+	default:
+		return fmt.Sprintf("%s.condition[%d]", container, index)
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package moduletest
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/checks"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func TestCoverage(t *testing.T) {
+	rng := func(line int) hcl.Range {
+		return hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: line, Byte: line}}
+	}
+
+	config := configs.NewEmptyConfig()
+	config.Module.Outputs = map[string]*configs.Output{
+		"id": {
+			Name:      "id",
+			DeclRange: rng(1),
+			Preconditions: []*configs.CheckRule{
+				{DeclRange: rng(2)},
+				{DeclRange: rng(3)},
+			},
+		},
+	}
+	config.Module.Variables = map[string]*configs.Variable{
+		"name": {
+			Name:        "name",
+			DeclRange:   rng(5),
+			Validations: []*configs.CheckRule{{DeclRange: rng(6)}},
+		},
+	}
+
+	coverage := NewCoverage(config)
+
+	output := addrs.OutputValue{Name: "id"}
+	checkState := checks.NewState(config)
+	checkState.ReportCheckableObjects(output.InModule(addrs.RootModule), addrs.MakeSet[addrs.Checkable](output.Absolute(addrs.RootModuleInstance)))
+	checkState.ReportCheckResult(output.Absolute(addrs.RootModuleInstance), addrs.OutputPrecondition, 0, checks.StatusPass)
+	checkState.ReportCheckResult(output.Absolute(addrs.RootModuleInstance), addrs.OutputPrecondition, 1, checks.StatusFail)
+
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetOutputValue(output.Absolute(addrs.RootModuleInstance), cty.StringVal("foo"), false, "")
+	})
+	coverage.RecordApply(state, checkState)
+
+	want := []*CoverageItem{
+		{Kind: CoverageOutput, Address: "output.id", Range: rng(1), Applied: 1},
+		{Kind: CoverageCondition, Address: "output.id.precondition[0]", Range: rng(2), Passed: 1},
+		{Kind: CoverageCondition, Address: "output.id.precondition[1]", Range: rng(3), Failed: 1},
+		{Kind: CoverageCondition, Address: "var.name.validation[0]", Range: rng(6)},
+	}
+	if diff := cmp.Diff(want, coverage.Items); len(diff) > 0 {
+		t.Errorf("wrong items\n%s", diff)
+	}
+
+	wantLCOV := `TN:
+SF:main.tf
+FN:1,output.id
+FNDA:0,output.id
+FNF:1
+FNH:0
+BRDA:2,0,0,1
+BRDA:2,0,1,0
+BRDA:3,1,0,0
+BRDA:3,1,1,1
+BRDA:6,2,0,-
+BRDA:6,2,1,-
+BRF:6
+BRH:2
+DA:1,0
+DA:2,1
+DA:3,1
+DA:6,0
+LF:4
+LH:2
+end_of_record
+`
+	if diff := cmp.Diff(wantLCOV, string(coverage.LCOV())); len(diff) > 0 {
+		t.Errorf("wrong lcov report\n%s", diff)
+	}
+}
+
+func TestCoverage_recordPlan(t *testing.T) {
+	rng := func(line int) hcl.Range {
+		return hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: line, Byte: line}}
+	}
+
+	config := configs.NewEmptyConfig()
+	config.Module.ManagedResources = map[string]*configs.Resource{
+		"test_resource.changed": {
+			Mode:      addrs.ManagedResourceMode,
+			Type:      "test_resource",
+			Name:      "changed",
+			DeclRange: rng(1),
+		},
+		"test_resource.unchanged": {
+			Mode:      addrs.ManagedResourceMode,
+			Type:      "test_resource",
+			Name:      "unchanged",
+			DeclRange: rng(5),
+		},
+	}
+
+	coverage := NewCoverage(config)
+
+	changed := addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "test_resource", Name: "changed"}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+	unchanged := addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "test_resource", Name: "unchanged"}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+
+	provider := addrs.AbsProviderConfig{
+		Module:   addrs.RootModule,
+		Provider: addrs.NewDefaultProvider("test"),
+	}
+	object := &states.ResourceInstanceObjectSrc{
+		Status:    states.ObjectReady,
+		AttrsJSON: []byte(`{}`),
+	}
+
+	// The planned state holds every resource of the previous runs, but only
+	// the resources in the changes were planned by this run block.
+	plan := &plans.Plan{
+		Changes: plans.NewChanges(),
+		PlannedState: states.BuildState(func(s *states.SyncState) {
+			s.SetResourceInstanceCurrent(changed, object, provider, addrs.NoKey)
+			s.SetResourceInstanceCurrent(unchanged, object, provider, addrs.NoKey)
+		}),
+	}
+	plan.Changes.Resources = append(plan.Changes.Resources, &plans.ResourceInstanceChangeSrc{
+		Addr:         changed,
+		PrevRunAddr:  changed,
+		ProviderAddr: provider,
+		ChangeSrc: plans.ChangeSrc{
+			Action: plans.Create,
+		},
+	})
+	coverage.RecordPlan(plan, nil)
+
+	want := []*CoverageItem{
+		{Kind: CoverageResource, Address: "test_resource.changed", Range: rng(1), Planned: 1},
+		{Kind: CoverageResource, Address: "test_resource.unchanged", Range: rng(5)},
+	}
+	if diff := cmp.Diff(want, coverage.Items); len(diff) > 0 {
+		t.Errorf("wrong items\n%s", diff)
+	}
+}
@@ -6,6 +6,8 @@
 package moduletest
 
 import (
+	"time"
+
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
@@ -18,5 +20,76 @@ type File struct {
 
 	Runs []*Run
 
+	// Duration is how long it took to execute the run blocks in the file,
+	// not including the destruction of the resources they created.
+	Duration time.Duration
+
 	Diagnostics tfdiags.Diagnostics
 }
+
+// Dependencies returns, for each run block in the file, the indices of the
+// earlier run blocks that must complete before it can be executed. The given
+// config is the main configuration, used by run blocks that don't load an
+// alternate module.
+//
+// Run blocks depend on each other when they touch a common state, either
+// because they operate on the same state or because one of them references
+// the outputs of a run block that operates on a state the other one uses.
+// They also depend on each other when both use real providers, as one of them
+// could read the infrastructure that the other one creates. Run blocks that
+// don't depend on each other can be executed concurrently, without changing
+// their results compared to executing them in order.
+//
+// The exception is a run block that errors. Executed in order, every run
+// block after it would be skipped, but the run blocks that don't depend on it
+// may already have started, and they still complete.
+func (file *File) Dependencies(config *configs.Config) [][]int {
+	keys := make(map[string]string)
+	for _, run := range file.Runs {
+		keys[run.Name] = run.StateKey()
+	}
+
+	// touched holds the keys of all the states that each run block uses, or
+	// nil if the run block could use any of them.
+	touched := make([]map[string]bool, len(file.Runs))
+	mocked := make([]bool, len(file.Runs))
+	for ix, run := range file.Runs {
+		mocked[ix] = run.mocked(file, config)
+
+		names, all := run.referencedRuns(file)
+		if all {
+			continue
+		}
+
+		touched[ix] = map[string]bool{run.StateKey(): true}
+		for _, name := range names {
+			if key, ok := keys[name]; ok {
+				touched[ix][key] = true
+			}
+		}
+	}
+
+	dependencies := make([][]int, len(file.Runs))
+	for ix := range file.Runs {
+		for earlier := 0; earlier < ix; earlier++ {
+			if overlaps(touched[ix], touched[earlier]) || (!mocked[ix] && !mocked[earlier]) {
+				dependencies[ix] = append(dependencies[ix], earlier)
+			}
+		}
+	}
+	return dependencies
+}
+
+// overlaps returns true if the two sets of state keys have any keys in
+// common, treating nil as the set of all keys.
+func overlaps(a, b map[string]bool) bool {
+	if a == nil || b == nil {
+		return true
+	}
+	for key := range a {
+		if b[key] {
+			return true
+		}
+	}
+	return false
+}
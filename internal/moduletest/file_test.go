@@ -0,0 +1,131 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package moduletest
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+)
+
+func TestFile_Dependencies(t *testing.T) {
+	newConfig := func() *configs.Config {
+		config := configs.NewEmptyConfig()
+		config.Module.ProviderRequirements = &configs.RequiredProviders{}
+		config.Module.ManagedResources = map[string]*configs.Resource{
+			"test_resource.foo": {
+				Mode:     addrs.ManagedResourceMode,
+				Type:     "test_resource",
+				Name:     "foo",
+				Provider: addrs.NewDefaultProvider("test"),
+			},
+		}
+		return config
+	}
+
+	// run creates a run block, which tests the given module source if it is
+	// not empty, and sets its variables to the given expressions.
+	run := func(t *testing.T, name, source string, variables map[string]string) *Run {
+		t.Helper()
+
+		config := &configs.TestRun{
+			Name:      name,
+			Variables: make(map[string]hcl.Expression),
+		}
+		if source != "" {
+			addr, err := addrs.ParseModuleSource(source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			config.Module = &configs.TestRunModuleCall{Source: addr}
+			config.ConfigUnderTest = newConfig()
+		}
+		for variable, src := range variables {
+			expr, diags := hclsyntax.ParseExpression([]byte(src), "test.tftest.hcl", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+			config.Variables[variable] = expr
+		}
+		return &Run{Config: config, Name: name}
+	}
+
+	mocks := map[string]*configs.MockProvider{
+		"test": {Name: "test"},
+	}
+
+	tcs := map[string]struct {
+		runs  func(t *testing.T) []*Run
+		mocks map[string]*configs.MockProvider
+		want  [][]int
+	}{
+		"real providers": {
+			runs: func(t *testing.T) []*Run {
+				return []*Run{
+					run(t, "first", "", nil),
+					run(t, "setup", "./setup", nil),
+					run(t, "second", "", nil),
+				}
+			},
+			want: [][]int{nil, {0}, {0, 1}},
+		},
+		"mock providers": {
+			runs: func(t *testing.T) []*Run {
+				return []*Run{
+					run(t, "first", "", nil),
+					run(t, "setup", "./setup", nil),
+					run(t, "second", "", nil),
+				}
+			},
+			mocks: mocks,
+			want:  [][]int{nil, nil, {0}},
+		},
+		"references": {
+			runs: func(t *testing.T) []*Run {
+				return []*Run{
+					run(t, "first", "", nil),
+					run(t, "setup", "./setup", nil),
+					run(t, "other", "./other", nil),
+					run(t, "second", "", map[string]string{"id": "run.setup.id"}),
+					run(t, "third", "./other", nil),
+				}
+			},
+			mocks: mocks,
+			want:  [][]int{nil, nil, nil, {0, 1}, {2}},
+		},
+		"dynamic references": {
+			runs: func(t *testing.T) []*Run {
+				return []*Run{
+					run(t, "first", "", nil),
+					run(t, "setup", "./setup", nil),
+					run(t, "second", "./other", map[string]string{"id": "run[var.name].id"}),
+					run(t, "third", "./third", nil),
+				}
+			},
+			mocks: mocks,
+			want:  [][]int{nil, nil, {0, 1}, {2}},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			file := &File{
+				Config: &configs.TestFile{MockProviders: tc.mocks},
+				Runs:   tc.runs(t),
+			}
+
+			got := file.Dependencies(newConfig())
+			if diff := cmp.Diff(tc.want, got); len(diff) > 0 {
+				t.Errorf("wrong dependencies\n%s", diff)
+			}
+		})
+	}
+}
@@ -7,8 +7,11 @@ package moduletest
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
@@ -28,6 +31,9 @@ type Run struct {
 	Index  int
 	Status Status
 
+	// Duration is how long it took to execute the run block.
+	Duration time.Duration
+
 	Diagnostics tfdiags.Diagnostics
 }
 
@@ -44,6 +50,152 @@ type Verbose struct {
 	Provisioners map[string]*configschema.Block
 }
 
+// StateKey returns the key of the state that the run block operates on.
+//
+// Run blocks that test the main configuration share the state with the empty
+// key, while run blocks that load an alternate module share the state with
+// the other run blocks that load the same module source.
+func (run *Run) StateKey() string {
+	if run.Config.ConfigUnderTest == nil {
+		return ""
+	}
+	return run.Config.Module.Source.String()
+}
+
+// referencedRuns returns the names of the run blocks that the given run block
+// references, from either its own expressions or the expressions in the file
+// that apply to it.
+//
+// If the references can't be determined precisely, all is true and the run
+// block should be considered to reference every other run block in the file.
+func (run *Run) referencedRuns(file *File) (names []string, all bool) {
+	var traversals []hcl.Traversal
+	for _, expr := range run.Config.Variables {
+		traversals = append(traversals, expr.Variables()...)
+	}
+	for _, rule := range run.Config.CheckRules {
+		traversals = append(traversals, rule.Condition.Variables()...)
+		if rule.ErrorMessage != nil {
+			traversals = append(traversals, rule.ErrorMessage.Variables()...)
+		}
+	}
+	if file.Config != nil {
+		for _, expr := range file.Config.Variables {
+			traversals = append(traversals, expr.Variables()...)
+		}
+		for _, provider := range file.Config.Providers {
+			more, ok := bodyTraversals(provider.Config)
+			if !ok {
+				return nil, true
+			}
+			traversals = append(traversals, more...)
+		}
+	}
+
+	for _, traversal := range traversals {
+		if traversal.RootName() != "run" {
+			continue
+		}
+		if len(traversal) < 2 {
+			return nil, true
+		}
+		switch step := traversal[1].(type) {
+		case hcl.TraverseAttr:
+			names = append(names, step.Name)
+		case hcl.TraverseIndex:
+			if step.Key.Type() != cty.String || !step.Key.IsKnown() || step.Key.IsNull() {
+				return nil, true
+			}
+			names = append(names, step.Key.AsString())
+		default:
+			return nil, true
+		}
+	}
+	return names, false
+}
+
+// mocked returns true if every provider used by the configuration that the
+// run block tests is replaced by a mock provider from the test file, so the
+// run block can't create or read any real infrastructure. The given config is
+// the main configuration, used if the run block doesn't load another module.
+func (run *Run) mocked(file *File, config *configs.Config) bool {
+	if run.Config.ConfigUnderTest != nil {
+		config = run.Config.ConfigUnderTest
+	}
+	if config == nil || file.Config == nil {
+		return false
+	}
+
+	// Run blocks can choose the providers from the test file that they use,
+	// otherwise they use all of them.
+	var names []string
+	if len(run.Config.Providers) > 0 {
+		for _, ref := range run.Config.Providers {
+			names = append(names, ref.InParent.String())
+		}
+	} else {
+		for name := range file.Config.MockProviders {
+			names = append(names, name)
+		}
+	}
+
+	mocks := make(map[addrs.Provider]bool)
+	for _, name := range names {
+		mock, ok := file.Config.MockProviders[name]
+		if !ok {
+			continue
+		}
+		mocks[config.Module.ProviderForLocalConfig(addrs.LocalProviderConfig{LocalName: mock.Name})] = true
+	}
+
+	mocked := true
+	config.DeepEach(func(c *configs.Config) {
+		for _, resources := range []map[string]*configs.Resource{c.Module.ManagedResources, c.Module.DataResources, c.Module.EphemeralResources} {
+			for _, resource := range resources {
+				if resource.Provider.IsBuiltIn() {
+					// The built-in provider doesn't manage any real
+					// infrastructure.
+					continue
+				}
+				if !mocks[resource.Provider] {
+					mocked = false
+				}
+			}
+		}
+	})
+	return mocked
+}
+
+// bodyTraversals returns the traversals from all the expressions within the
+// given body, and false if the body couldn't be inspected without a schema.
+func bodyTraversals(body hcl.Body) ([]hcl.Traversal, bool) {
+	if body == nil {
+		return nil, true
+	}
+
+	if body, ok := body.(*hclsyntax.Body); ok {
+		var traversals []hcl.Traversal
+		for _, attr := range body.Attributes {
+			traversals = append(traversals, attr.Expr.Variables()...)
+		}
+		for _, block := range body.Blocks {
+			more, _ := bodyTraversals(block.Body)
+			traversals = append(traversals, more...)
+		}
+		return traversals, true
+	}
+
+	attrs, diags := body.JustAttributes()
+	if diags.HasErrors() {
+		return nil, false
+	}
+	var traversals []hcl.Traversal
+	for _, attr := range attrs {
+		traversals = append(traversals, attr.Expr.Variables()...)
+	}
+	return traversals, true
+}
+
 func (run *Run) GetTargets() ([]addrs.Targetable, tfdiags.Diagnostics) {
 	var diagnostics tfdiags.Diagnostics
 	var targets []addrs.Targetable
@@ -129,10 +281,28 @@ func (run *Run) GetReferences() ([]*addrs.Reference, tfdiags.Diagnostics) {
 // failures is by using the tfdiags Extra functionality to detect which
 // diagnostics were generated by custom conditions. OpenTofu adds the
 // addrs.CheckRule that generated each diagnostic to the diagnostic itself so we
-// can tell which diagnostics can be expected.
+// can tell which diagnostics can be expected. Similarly, errors raised by the
+// prevent_destroy setting of a managed resource carry the address of the
+// protected resource so tests can expect them.
 func (run *Run) ValidateExpectedFailures(expectedFailures addrs.Map[addrs.Referenceable, bool], sourceRanges addrs.Map[addrs.Referenceable, tfdiags.SourceRange], originals tfdiags.Diagnostics) tfdiags.Diagnostics {
 	var diags tfdiags.Diagnostics
 	for _, diag := range originals {
+		if addr, ok := addrs.DiagnosticOriginatesFromPreventDestroy(diag); ok && addr.Module.IsRoot() {
+			if expectedFailures.Has(addr.Resource) {
+				// Then this resource was expected to refuse being destroyed,
+				// so we swallow the error.
+				expectedFailures.Put(addr.Resource, true)
+				continue
+			}
+
+			if expectedFailures.Has(addr.Resource.Resource) {
+				// We can also blanket expect failures in all instances for
+				// a resource.
+				expectedFailures.Put(addr.Resource.Resource, true)
+				continue
+			}
+		}
+
 		if rule, ok := addrs.DiagnosticOriginatesFromCheckRule(diag); ok {
 			switch rule.Container.CheckableKind() {
 			case addrs.CheckableOutputValue:
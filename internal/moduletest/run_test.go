@@ -554,6 +554,64 @@ func TestRun_ValidateExpectedFailures(t *testing.T) {
 				},
 			},
 		},
+		"prevent_destroy": {
+			ExpectedFailures: []string{
+				"test_instance.protected",
+			},
+			Input: createDiagnostics(func(diags tfdiags.Diagnostics) tfdiags.Diagnostics {
+				// First, a protected resource that we didn't expect to refuse
+				// being destroyed.
+				diags = diags.Append(
+					&hcl.Diagnostic{
+						Severity: hcl.DiagError,
+						Summary:  "unexpected failure",
+						Detail:   "this should not be removed",
+						Extra: &addrs.PreventDestroyDiagnosticExtra{
+							Resource: addrs.AbsResourceInstance{
+								Module: addrs.RootModuleInstance,
+								Resource: addrs.ResourceInstance{
+									Resource: addrs.Resource{
+										Mode: addrs.ManagedResourceMode,
+										Type: "test_instance",
+										Name: "unexpected",
+									},
+								},
+							},
+						},
+					})
+
+				// Second, the resource we expected to be protected.
+				diags = diags.Append(
+					&hcl.Diagnostic{
+						Severity: hcl.DiagError,
+						Summary:  "expected failure in test_instance.protected",
+						Detail:   "this should be removed",
+						Extra: &addrs.PreventDestroyDiagnosticExtra{
+							Resource: addrs.AbsResourceInstance{
+								Module: addrs.RootModuleInstance,
+								Resource: addrs.ResourceInstance{
+									Resource: addrs.Resource{
+										Mode: addrs.ManagedResourceMode,
+										Type: "test_instance",
+										Name: "protected",
+									},
+								},
+							},
+						},
+					})
+
+				return diags
+			}),
+			Output: []output{
+				{
+					Description: tfdiags.Description{
+						Summary: "unexpected failure",
+						Detail:  "this should not be removed",
+					},
+					Severity: tfdiags.Error,
+				},
+			},
+		},
 		"check_assertions": {
 			ExpectedFailures: []string{
 				"check.expected",
@@ -5,8 +5,88 @@
 
 package moduletest
 
+import (
+	"sync"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/logging"
+)
+
 type Suite struct {
 	Status Status
 
 	Files map[string]*File
+
+	// Coverage records which objects in the module under test were
+	// exercised by the test suite, if coverage was requested.
+	Coverage *Coverage
+
+	// slots holds a value for each run block that is currently executing,
+	// limiting them to the parallelism given to SetParallelism.
+	slots chan struct{}
+}
+
+// SetParallelism sets the number of run blocks, across all the test files in
+// the suite, that can be executed at the same time. It must be called before
+// any run blocks are executed, and until it is the number isn't limited.
+func (suite *Suite) SetParallelism(n int) {
+	suite.slots = make(chan struct{}, max(n, 1))
+}
+
+// Acquire waits until the suite can execute another run block, or perform
+// another operation that is limited in the same way, such as destroying the
+// resources created by a run block. Call Release once the operation is done.
+func (suite *Suite) Acquire() {
+	if suite.slots != nil {
+		suite.slots <- struct{}{}
+	}
+}
+
+// Release ends an operation started by Acquire.
+func (suite *Suite) Release() {
+	if suite.slots != nil {
+		<-suite.slots
+	}
+}
+
+// ExecuteRuns calls execute for each of the run blocks of the given file,
+// against the given main configuration, and waits for them to complete.
+//
+// Run blocks are started in order, but each one only waits for the earlier
+// run blocks it depends on, as returned by File.Dependencies, and for the
+// suite to be able to execute another run block. Without parallelism, this
+// means every run block is still executed one after the other. Before
+// starting each run block ExecuteRuns calls stop, and starts no further run
+// blocks once it returns true.
+func (suite *Suite) ExecuteRuns(file *File, config *configs.Config, stop func() bool, execute func(run *Run)) {
+	dependencies := file.Dependencies(config)
+	completed := make([]chan struct{}, len(file.Runs))
+	for ix := range completed {
+		completed[ix] = make(chan struct{})
+	}
+
+	var wg sync.WaitGroup
+	for ix, run := range file.Runs {
+		for _, dependency := range dependencies[ix] {
+			<-completed[dependency]
+		}
+		suite.Acquire()
+
+		if stop() {
+			suite.Release()
+			break
+		}
+
+		wg.Add(1)
+		panicHandler := logging.PanicHandlerWithTraceFn()
+		go func() {
+			defer panicHandler()
+			defer wg.Done()
+			defer close(completed[ix])
+			defer suite.Release()
+
+			execute(run)
+		}()
+	}
+	wg.Wait()
 }
@@ -0,0 +1,96 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package moduletest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+)
+
+func TestSuite_ExecuteRuns(t *testing.T) {
+	// Without a test file configuration, every run block could use real
+	// providers, so each one depends on all the run blocks before it.
+	file := &File{
+		Runs: []*Run{
+			{Name: "first", Config: &configs.TestRun{Name: "first"}},
+			{Name: "second", Config: &configs.TestRun{Name: "second"}},
+			{Name: "third", Config: &configs.TestRun{Name: "third"}},
+		},
+	}
+
+	suite := &Suite{}
+	suite.SetParallelism(4)
+
+	var lock sync.Mutex
+	var executed []string
+	suite.ExecuteRuns(file, configs.NewEmptyConfig(), func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(executed) == 2
+	}, func(run *Run) {
+		lock.Lock()
+		defer lock.Unlock()
+		executed = append(executed, run.Name)
+	})
+
+	if diff := cmp.Diff([]string{"first", "second"}, executed); diff != "" {
+		t.Errorf("wrong executed run blocks\n%s", diff)
+	}
+}
+
+func TestSuite_ExecuteRunsConcurrently(t *testing.T) {
+	newConfig := func() *configs.Config {
+		config := configs.NewEmptyConfig()
+		config.Module.ProviderRequirements = &configs.RequiredProviders{}
+		return config
+	}
+	run := func(t *testing.T, name, source string) *Run {
+		addr, err := addrs.ParseModuleSource(source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &Run{Name: name, Config: &configs.TestRun{
+			Name:            name,
+			Module:          &configs.TestRunModuleCall{Source: addr},
+			ConfigUnderTest: newConfig(),
+		}}
+	}
+
+	// The run blocks use different states and no real providers, so they
+	// don't depend on each other.
+	file := &File{
+		Config: &configs.TestFile{},
+		Runs: []*Run{
+			run(t, "first", "./first"),
+			run(t, "second", "./second"),
+		},
+	}
+
+	suite := &Suite{}
+	suite.SetParallelism(2)
+
+	// Each run block waits for the other one to start, so they only both
+	// complete when they are executed at the same time.
+	started := map[string]chan struct{}{
+		"first":  make(chan struct{}),
+		"second": make(chan struct{}),
+	}
+	other := map[string]string{"first": "second", "second": "first"}
+	suite.ExecuteRuns(file, newConfig(), func() bool { return false }, func(run *Run) {
+		close(started[run.Name])
+		select {
+		case <-started[other[run.Name]]:
+		case <-time.After(10 * time.Second):
+			t.Errorf("%s was not executed at the same time as %s", other[run.Name], run.Name)
+		}
+	})
+}
@@ -0,0 +1,322 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package cassette records the gRPC calls that OpenTofu makes to provider
+// plugins, and replays the recorded responses without launching the plugins.
+//
+// A cassette holds the calls made to each provider, with their requests and
+// responses in the binary protocol buffers encoding. When replaying, each call
+// is answered with the response of a recorded call to the same method with an
+// identical request, so replaying only works as long as OpenTofu makes the
+// same requests as when the cassette was recorded.
+//
+// The configuration of each provider and the values of sensitive attributes
+// are removed from the calls before they are recorded, as the cassette is
+// stored in plaintext.
+package cassette
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/plugin"
+	"github.com/opentofu/opentofu/internal/plugin6"
+	"github.com/opentofu/opentofu/internal/providers"
+)
+
+// Version is the version of the cassette file format.
+const Version = 1
+
+// Cassette holds the gRPC calls made to a set of providers. It is safe to
+// record and replay calls concurrently.
+type Cassette struct {
+	Version int `json:"version"`
+
+	// Providers holds the calls made to each provider, keyed by the string
+	// representation of the provider's address.
+	Providers map[string]*Provider `json:"providers"`
+
+	lock sync.Mutex
+}
+
+// Provider holds the gRPC calls made to a single provider.
+type Provider struct {
+	// Protocol is the major version of the plugin protocol the provider
+	// implements.
+	Protocol int `json:"protocol"`
+
+	Interactions []*Interaction `json:"interactions"`
+
+	// schemas holds the schemas of the provider's resource types, which
+	// are needed to redact the sensitive values.
+	schemas *schemas
+}
+
+// replaySchemas returns the schemas of the provider's resource types from
+// the recorded call to get the provider's schema.
+func (p *Provider) replaySchemas() *schemas {
+	if p.schemas != nil {
+		return p.schemas
+	}
+	for _, interaction := range p.Interactions {
+		if isSchemaMethod(interaction.Method) && interaction.Error == nil {
+			// A cassette with an invalid schema can't have recorded any
+			// values of resources, as they would all have been removed.
+			p.schemas, _ = decodeSchemas(p.Protocol, interaction.Response)
+			break
+		}
+	}
+	return p.schemas
+}
+
+// Interaction is a single recorded gRPC call.
+type Interaction struct {
+	// Method is the full name of the gRPC method, such as
+	// /tfplugin5.Provider/PlanResourceChange.
+	Method string `json:"method"`
+
+	Request  []byte `json:"request"`
+	Response []byte `json:"response,omitempty"`
+	Error    *Error `json:"error,omitempty"`
+
+	// replayed is true once the interaction has been used to answer a call.
+	replayed bool
+}
+
+// Error is the gRPC status returned by a recorded call that failed.
+type Error struct {
+	Code    codes.Code `json:"code"`
+	Message string     `json:"message"`
+}
+
+// New returns an empty cassette, ready for recording.
+func New() *Cassette {
+	return &Cassette{
+		Version:   Version,
+		Providers: make(map[string]*Provider),
+	}
+}
+
+// Load reads a cassette from the given file.
+func Load(path string) (*Cassette, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(src, &cassette); err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %w", path, err)
+	}
+	if cassette.Version != Version {
+		return nil, fmt.Errorf("unsupported cassette version %d in %s, expected %d", cassette.Version, path, Version)
+	}
+	for addr, provider := range cassette.Providers {
+		if _, diags := addrs.ParseProviderSourceString(addr); diags.HasErrors() {
+			return nil, fmt.Errorf("invalid provider address %q in cassette %s: %w", addr, path, diags.Err())
+		}
+		if provider.Protocol != 5 && provider.Protocol != 6 {
+			return nil, fmt.Errorf("unsupported protocol version %d for %s in cassette %s", provider.Protocol, addr, path)
+		}
+	}
+	return &cassette, nil
+}
+
+// Save writes the cassette to the given file. The file is only readable by
+// its owner, because the recorded calls can still include credentials that
+// the provider doesn't mark as sensitive.
+func (c *Cassette) Save(path string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	src, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(src, '\n'), 0600); err != nil {
+		return err
+	}
+	// WriteFile keeps the permissions of an existing file.
+	return os.Chmod(path, 0600)
+}
+
+// Interceptor returns a gRPC interceptor that records the calls made to the
+// given provider into the cassette, without the values that may hold
+// credentials.
+func (c *Cassette) Interceptor(addr addrs.Provider) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		callErr := invoker(ctx, method, req, reply, cc, opts...)
+
+		reqMsg, ok := req.(proto.Message)
+		if !ok {
+			return fmt.Errorf("unexpected gRPC message type %T", req)
+		}
+		replyMsg, ok := reply.(proto.Message)
+		if !ok {
+			return fmt.Errorf("unexpected gRPC message type %T", reply)
+		}
+
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		provider, ok := c.Providers[addr.String()]
+		if !ok {
+			provider = &Provider{Protocol: protocol(method)}
+			c.Providers[addr.String()] = provider
+		}
+
+		var err error
+		interaction := &Interaction{Method: method}
+		if interaction.Request, err = marshal(provider.schemas.redact(method, "", reqMsg)); err != nil {
+			return err
+		}
+		if callErr != nil {
+			st := status.Convert(callErr)
+			interaction.Error = &Error{Code: st.Code(), Message: st.Message()}
+		} else {
+			if isSchemaMethod(method) {
+				src, err := marshal(replyMsg)
+				if err != nil {
+					return err
+				}
+				if provider.schemas, err = decodeSchemas(provider.Protocol, src); err != nil {
+					return fmt.Errorf("invalid schema for %s: %w", addr, err)
+				}
+			}
+			if interaction.Response, err = marshal(provider.schemas.redact(method, typeName(reqMsg.ProtoReflect()), replyMsg)); err != nil {
+				return err
+			}
+		}
+
+		provider.Interactions = append(provider.Interactions, interaction)
+		return callErr
+	}
+}
+
+// Factories returns a provider factory for each provider in the cassette,
+// which replays the recorded calls instead of launching the provider.
+func (c *Cassette) Factories() map[addrs.Provider]providers.Factory {
+	factories := make(map[addrs.Provider]providers.Factory, len(c.Providers))
+	for str, provider := range c.Providers {
+		addr, diags := addrs.ParseProviderSourceString(str)
+		if diags.HasErrors() {
+			// Load has already validated the addresses.
+			panic(diags.Err())
+		}
+
+		conn := &player{cassette: c, addr: addr, provider: provider}
+		schemaCache := providers.NewSchemaCache()
+		factories[addr] = func() (providers.Interface, error) {
+			switch provider.Protocol {
+			case 5:
+				p := plugin.NewGRPCProvider(context.Background(), conn)
+				p.SchemaCache = schemaCache
+				return p, nil
+			case 6:
+				p := plugin6.NewGRPCProvider(context.Background(), conn)
+				p.SchemaCache = schemaCache
+				return p, nil
+			default:
+				return nil, fmt.Errorf("unsupported protocol version %d for %s", provider.Protocol, addr)
+			}
+		}
+	}
+	return factories
+}
+
+// player is a gRPC connection that answers the calls made to a provider with
+// the responses recorded in a cassette.
+type player struct {
+	cassette *Cassette
+	addr     addrs.Provider
+	provider *Provider
+}
+
+var _ grpc.ClientConnInterface = (*player)(nil)
+
+func (p *player) Invoke(_ context.Context, method string, args any, reply any, _ ...grpc.CallOption) error {
+	argsMsg, ok := args.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected request type %T", args)
+	}
+	msg, ok := reply.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected response type %T", reply)
+	}
+
+	p.cassette.lock.Lock()
+	defer p.cassette.lock.Unlock()
+
+	// The request is redacted in the same way as when it was recorded, so
+	// that it can match.
+	schemas := p.provider.replaySchemas()
+	req, err := marshal(schemas.redact(method, "", argsMsg))
+	if err != nil {
+		return err
+	}
+
+	// We use the first matching interaction that hasn't been replayed yet,
+	// so that calls repeated with different results are replayed in order.
+	// Once they are all used up we keep replaying the last one, as OpenTofu
+	// can make some calls, such as the ones for the schema, more often than
+	// when recording.
+	var match *Interaction
+	for _, interaction := range p.provider.Interactions {
+		if interaction.Method != method || !bytes.Equal(interaction.Request, req) {
+			continue
+		}
+		match = interaction
+		if !interaction.replayed {
+			break
+		}
+	}
+	if match == nil {
+		return status.Errorf(codes.FailedPrecondition, "the cassette has no recorded response for a %s call to %s with this request; record the cassette again to include it", method, p.addr)
+	}
+	match.replayed = true
+
+	if match.Error != nil {
+		return status.Error(match.Error.Code, match.Error.Message)
+	}
+	if err := proto.Unmarshal(match.Response, msg); err != nil {
+		return err
+	}
+	schemas.restore(method, argsMsg, msg)
+	return nil
+}
+
+func (p *player) NewStream(_ context.Context, _ *grpc.StreamDesc, method string, _ ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, status.Errorf(codes.Unimplemented, "cannot replay the streaming call %s to %s", method, p.addr)
+}
+
+// marshal encodes a gRPC message deterministically, so that identical
+// requests are always encoded the same way.
+func marshal(msg any) ([]byte, error) {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("unexpected gRPC message type %T", msg)
+	}
+	return proto.MarshalOptions{Deterministic: true}.Marshal(m)
+}
+
+// protocol returns the major version of the plugin protocol that the given
+// gRPC method belongs to.
+func protocol(method string) int {
+	if strings.HasPrefix(method, "/tfplugin6.") {
+		return 6
+	}
+	return 5
+}
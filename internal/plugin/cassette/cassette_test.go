@@ -0,0 +1,254 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cassette
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/plugin"
+	"github.com/opentofu/opentofu/internal/providers"
+	proto5 "github.com/opentofu/opentofu/internal/tfplugin5"
+)
+
+func TestCassette(t *testing.T) {
+	addr := addrs.NewDefaultProvider("test")
+	recording := New()
+	interceptor := recording.Interceptor(addr)
+
+	// record makes a call through the interceptor, with an invoker that
+	// responds in place of the provider.
+	record := func(t *testing.T, method string, req, reply proto.Message, response proto.Message, err error) {
+		t.Helper()
+		invoker := func(_ context.Context, _ string, _, reply any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+			if response != nil {
+				proto.Merge(reply.(proto.Message), response)
+			}
+			return err
+		}
+		if got := interceptor(t.Context(), method, req, reply, nil, invoker); got != err {
+			t.Fatalf("wrong error recording %s: %v", method, got)
+		}
+	}
+
+	record(t, proto5.Provider_GetSchema_FullMethodName, &proto5.GetProviderSchema_Request{}, &proto5.GetProviderSchema_Response{}, &proto5.GetProviderSchema_Response{
+		Provider: &proto5.Schema{Block: &proto5.Schema_Block{}},
+		ResourceSchemas: map[string]*proto5.Schema{
+			"test_resource": {
+				Block: &proto5.Schema_Block{
+					Attributes: []*proto5.Schema_Attribute{
+						{Name: "id", Type: []byte(`"string"`), Computed: true},
+					},
+				},
+			},
+		},
+	}, nil)
+	record(t, proto5.Provider_GetResourceIdentitySchemas_FullMethodName, &proto5.GetResourceIdentitySchemas_Request{}, &proto5.GetResourceIdentitySchemas_Response{}, nil, status.Error(codes.Unimplemented, "not implemented"))
+	record(t, proto5.Provider_Stop_FullMethodName, &proto5.Stop_Request{}, &proto5.Stop_Response{}, &proto5.Stop_Response{Error: "first"}, nil)
+	record(t, proto5.Provider_Stop_FullMethodName, &proto5.Stop_Request{}, &proto5.Stop_Response{}, &proto5.Stop_Response{Error: "second"}, nil)
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := recording.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	replay, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	factory, ok := replay.Factories()[addr]
+	if !ok {
+		t.Fatalf("no factory for %s", addr)
+	}
+	provider, err := factory()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("schema", func(t *testing.T) {
+		resp := provider.GetProviderSchema(t.Context())
+		if resp.Diagnostics.HasErrors() {
+			t.Fatal(resp.Diagnostics.Err())
+		}
+		if _, ok := resp.ResourceTypes["test_resource"]; !ok {
+			t.Errorf("missing test_resource schema in %#v", resp.ResourceTypes)
+		}
+	})
+
+	t.Run("repeated calls", func(t *testing.T) {
+		for _, want := range []string{"first", "second", "second"} {
+			if err := provider.Stop(t.Context()); err == nil || err.Error() != want {
+				t.Errorf("wrong error %v; want %s", err, want)
+			}
+		}
+	})
+
+	t.Run("unrecorded call", func(t *testing.T) {
+		resp := provider.GetFunctions(t.Context())
+		if !resp.Diagnostics.HasErrors() || !strings.Contains(resp.Diagnostics.Err().Error(), "no recorded response") {
+			t.Errorf("expected an error about the missing response, got %v", resp.Diagnostics.Err())
+		}
+	})
+}
+
+func TestCassette_redaction(t *testing.T) {
+	addr := addrs.NewDefaultProvider("test")
+	recording := New()
+
+	// The provider echoes the proposed new state as the planned state, so
+	// the sensitive password appears in both the request and the response.
+	conn := &recorderConn{
+		interceptor: recording.Interceptor(addr),
+		respond: func(method string, req proto.Message) (proto.Message, error) {
+			switch methodName(method) {
+			case "GetSchema":
+				return &proto5.GetProviderSchema_Response{
+					Provider: &proto5.Schema{Block: &proto5.Schema_Block{
+						Attributes: []*proto5.Schema_Attribute{
+							{Name: "token", Type: []byte(`"string"`), Optional: true},
+						},
+					}},
+					ResourceSchemas: map[string]*proto5.Schema{
+						"test_resource": {Block: &proto5.Schema_Block{
+							Attributes: []*proto5.Schema_Attribute{
+								{Name: "id", Type: []byte(`"string"`), Optional: true},
+								{Name: "password", Type: []byte(`"string"`), Optional: true, Sensitive: true},
+							},
+						}},
+					},
+				}, nil
+			case "Configure":
+				return &proto5.Configure_Response{}, nil
+			case "PlanResourceChange":
+				return &proto5.PlanResourceChange_Response{PlannedState: req.(*proto5.PlanResourceChange_Request).ProposedNewState}, nil
+			default:
+				return nil, status.Error(codes.Unimplemented, "not implemented")
+			}
+		},
+	}
+
+	// run configures the provider with the given token and plans a resource
+	// with the given password, returning the planned password.
+	run := func(t *testing.T, p providers.Interface, token, password string) cty.Value {
+		t.Helper()
+		configResp := p.ConfigureProvider(t.Context(), providers.ConfigureProviderRequest{
+			Config: cty.ObjectVal(map[string]cty.Value{"token": cty.StringVal(token)}),
+		})
+		if configResp.Diagnostics.HasErrors() {
+			t.Fatal(configResp.Diagnostics.Err())
+		}
+		val := cty.ObjectVal(map[string]cty.Value{
+			"id":       cty.StringVal("a"),
+			"password": cty.StringVal(password),
+		})
+		planResp := p.PlanResourceChange(t.Context(), providers.PlanResourceChangeRequest{
+			TypeName:         "test_resource",
+			PriorState:       cty.NullVal(val.Type()),
+			ProposedNewState: val,
+			Config:           val,
+		})
+		if planResp.Diagnostics.HasErrors() {
+			t.Fatal(planResp.Diagnostics.Err())
+		}
+		return planResp.PlannedState.GetAttr("password")
+	}
+
+	recorder := plugin.NewGRPCProvider(t.Context(), conn)
+	recorder.SchemaCache = providers.NewSchemaCache()
+	if got := run(t, recorder, "secret-token", "hunter2"); got != cty.StringVal("hunter2") {
+		t.Fatalf("wrong planned password while recording: %#v", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := recording.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := info.Mode().Perm(), os.FileMode(0600); got != want {
+			t.Errorf("wrong cassette permissions %s; want %s", got, want)
+		}
+	}
+
+	replay, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, interaction := range replay.Providers[addr.String()].Interactions {
+		for _, secret := range []string{"secret-token", "hunter2"} {
+			if bytes.Contains(interaction.Request, []byte(secret)) || bytes.Contains(interaction.Response, []byte(secret)) {
+				t.Errorf("the recorded %s call includes %q", interaction.Method, secret)
+			}
+		}
+	}
+
+	// The calls match even though the provider configuration and the
+	// sensitive value differ from the recording, and the sensitive value
+	// is restored from the request.
+	provider, err := replay.Factories()[addr]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := run(t, provider, "other-token", "correct horse"); got != cty.StringVal("correct horse") {
+		t.Errorf("wrong planned password while replaying: %#v", got)
+	}
+}
+
+// recorderConn is a gRPC connection that answers each call using a function,
+// through an interceptor.
+type recorderConn struct {
+	interceptor grpc.UnaryClientInterceptor
+	respond     func(method string, req proto.Message) (proto.Message, error)
+}
+
+func (c *recorderConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	invoker := func(_ context.Context, method string, req, reply any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		resp, err := c.respond(method, req.(proto.Message))
+		if resp != nil {
+			proto.Merge(reply.(proto.Message), resp)
+		}
+		return err
+	}
+	return c.interceptor(ctx, method, args, reply, nil, invoker, opts...)
+}
+
+func (c *recorderConn) NewStream(context.Context, *grpc.StreamDesc, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, status.Error(codes.Unimplemented, "not implemented")
+}
+
+func TestLoad_invalid(t *testing.T) {
+	tcs := map[string]string{
+		"version":  `{"version": 2, "providers": {}}`,
+		"address":  `{"version": 1, "providers": {"not a provider": {"protocol": 5}}}`,
+		"protocol": `{"version": 1, "providers": {"hashicorp/test": {"protocol": 4}}}`,
+	}
+	for name, src := range tcs {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cassette.json")
+			if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Load(path); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
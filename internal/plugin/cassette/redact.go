@@ -0,0 +1,421 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cassette
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	"github.com/zclconf/go-cty/cty/msgpack"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/lang/marks"
+	convert5 "github.com/opentofu/opentofu/internal/plugin/convert"
+	convert6 "github.com/opentofu/opentofu/internal/plugin6/convert"
+	proto5 "github.com/opentofu/opentofu/internal/tfplugin5"
+	proto6 "github.com/opentofu/opentofu/internal/tfplugin6"
+)
+
+// Cassettes are stored in plaintext, so the values most likely to hold
+// credentials are removed from the calls before they are recorded:
+//
+//   - The configuration of the provider, which is sent when configuring and
+//     validating it, is removed entirely.
+//   - The values of the attributes that the provider's schema marks as
+//     sensitive are replaced with null wherever they appear in the values of
+//     resources, data sources and ephemeral resources.
+//
+// The same redaction is applied to each call while replaying before looking
+// for the matching recorded call, so that the requests still match. The
+// redacted values of a response are then restored from the values in the
+// request where possible, which covers the sensitive values that were set in
+// the configuration. Sensitive values that the provider computed itself are
+// replayed as null.
+
+// schemas holds the schemas of the resource types of a provider, decoded from
+// a recorded call to get the provider's schema.
+type schemas struct {
+	managed      map[string]*configschema.Block
+	data         map[string]*configschema.Block
+	ephemeral    map[string]*configschema.Block
+	providerMeta *configschema.Block
+}
+
+// decodeSchemas decodes the encoded response of a call to get the schema of a
+// provider that implements the given protocol version.
+func decodeSchemas(protocol int, src []byte) (*schemas, error) {
+	s := &schemas{
+		managed:   make(map[string]*configschema.Block),
+		data:      make(map[string]*configschema.Block),
+		ephemeral: make(map[string]*configschema.Block),
+	}
+	switch protocol {
+	case 5:
+		var resp proto5.GetProviderSchema_Response
+		if err := proto.Unmarshal(src, &resp); err != nil {
+			return nil, err
+		}
+		decode := func(into map[string]*configschema.Block, from map[string]*proto5.Schema) {
+			for name, schema := range from {
+				if schema.GetBlock() != nil {
+					into[name] = convert5.ProtoToConfigSchema(schema.Block)
+				}
+			}
+		}
+		decode(s.managed, resp.ResourceSchemas)
+		decode(s.data, resp.DataSourceSchemas)
+		decode(s.ephemeral, resp.EphemeralResourceSchemas)
+		if resp.ProviderMeta.GetBlock() != nil {
+			s.providerMeta = convert5.ProtoToConfigSchema(resp.ProviderMeta.Block)
+		}
+	case 6:
+		var resp proto6.GetProviderSchema_Response
+		if err := proto.Unmarshal(src, &resp); err != nil {
+			return nil, err
+		}
+		decode := func(into map[string]*configschema.Block, from map[string]*proto6.Schema) {
+			for name, schema := range from {
+				if schema.GetBlock() != nil {
+					into[name] = convert6.ProtoToConfigSchema(schema.Block)
+				}
+			}
+		}
+		decode(s.managed, resp.ResourceSchemas)
+		decode(s.data, resp.DataSourceSchemas)
+		decode(s.ephemeral, resp.EphemeralResourceSchemas)
+		if resp.ProviderMeta.GetBlock() != nil {
+			s.providerMeta = convert6.ProtoToConfigSchema(resp.ProviderMeta.Block)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported protocol version %d", protocol)
+	}
+	return s, nil
+}
+
+// block returns the schema of the given resource type for a call to the given
+// method, or nil if the schema is unknown.
+func (s *schemas) block(method, typeName string) *configschema.Block {
+	if s == nil {
+		return nil
+	}
+	switch methodName(method) {
+	case "ReadDataSource", "ValidateDataSourceConfig", "ValidateDataResourceConfig":
+		return s.data[typeName]
+	case "OpenEphemeralResource", "ValidateEphemeralResourceConfig":
+		return s.ephemeral[typeName]
+	default:
+		return s.managed[typeName]
+	}
+}
+
+// redact returns a copy of the given request or response of a call to the
+// given method with the values that may hold credentials removed. For a
+// response, typeName is the resource type of the request.
+func (s *schemas) redact(method, typeName string, msg proto.Message) proto.Message {
+	msg = proto.Clone(msg)
+	forEachValue(msg.ProtoReflect(), typeName, func(parent protoreflect.Message, fd protoreflect.FieldDescriptor, typeName string) {
+		if isProviderConfigMethod(method) {
+			parent.Clear(fd)
+			return
+		}
+		var block *configschema.Block
+		switch {
+		case fd.Name() == "provider_meta":
+			block = s.providerMetaBlock()
+		case typeName == "":
+			// The values of provider functions and the like don't have
+			// a schema that could mark them as sensitive, but raw states
+			// without a resource type, such as the source of a moved
+			// resource, could hold anything.
+			if fd.Message().Name() == "RawState" {
+				parent.Clear(fd)
+			}
+			return
+		default:
+			block = s.block(method, typeName)
+		}
+
+		value := parent.Mutable(fd).Message()
+		if block == nil {
+			// We can't tell which of the values are sensitive.
+			parent.Clear(fd)
+			return
+		}
+		val, err := decodeValue(value, block.ImpliedType())
+		if err != nil {
+			parent.Clear(fd)
+			return
+		}
+		masked, changed := maskSensitive(block, val)
+		if !changed {
+			return
+		}
+		if err := encodeValue(value, masked, block.ImpliedType()); err != nil {
+			parent.Clear(fd)
+		}
+	})
+	return msg
+}
+
+// restore puts the sensitive values that were removed from the recorded
+// response to a call back into the response, using the values of the
+// unredacted request.
+func (s *schemas) restore(method string, req, resp proto.Message) {
+	reqMsg := req.ProtoReflect()
+	if isProviderConfigMethod(method) {
+		// The only configuration a provider returns is the prepared
+		// configuration of protocol version 5, which we replay as the
+		// configuration that was given.
+		respMsg := resp.ProtoReflect()
+		config := reqMsg.Descriptor().Fields().ByName("config")
+		prepared := respMsg.Descriptor().Fields().ByName("prepared_config")
+		if config != nil && prepared != nil && reqMsg.Has(config) {
+			respMsg.Set(prepared, protoreflect.ValueOfMessage(proto.Clone(reqMsg.Get(config).Message().Interface()).ProtoReflect()))
+		}
+		return
+	}
+	forEachValue(resp.ProtoReflect(), typeName(reqMsg), func(parent protoreflect.Message, fd protoreflect.FieldDescriptor, typeName string) {
+		if typeName == "" || fd.Message().Name() != "DynamicValue" {
+			return
+		}
+		block := s.block(method, typeName)
+		if block == nil {
+			return
+		}
+		ty := block.ImpliedType()
+
+		value := parent.Mutable(fd).Message()
+		val, err := decodeValue(value, ty)
+		if err != nil {
+			return
+		}
+		var sources []cty.Value
+		for _, name := range []protoreflect.Name{"planned_state", "proposed_new_state", "config", "current_state", "prior_state"} {
+			field := reqMsg.Descriptor().Fields().ByName(name)
+			if field == nil || field.Kind() != protoreflect.MessageKind || field.Message().Name() != "DynamicValue" || !reqMsg.Has(field) {
+				continue
+			}
+			if source, err := decodeValue(reqMsg.Get(field).Message(), ty); err == nil {
+				sources = append(sources, source)
+			}
+		}
+		restored, changed := restoreSensitive(block, val, sources)
+		if changed {
+			_ = encodeValue(value, restored, ty)
+		}
+	})
+}
+
+func (s *schemas) providerMetaBlock() *configschema.Block {
+	if s == nil {
+		return nil
+	}
+	return s.providerMeta
+}
+
+// forEachValue calls fn for each field holding an encoded value, such as the
+// state of a resource, in the given message and the messages it contains,
+// along with the type of the resource the value belongs to, if any.
+func forEachValue(msg protoreflect.Message, typeName string, fn func(parent protoreflect.Message, fd protoreflect.FieldDescriptor, typeName string)) {
+	switch msg.Descriptor().Name() {
+	case "ResourceIdentityData":
+		// Resource identities are never sensitive.
+		return
+	}
+	if name := typeNameField(msg); name != "" {
+		typeName = name
+	}
+
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.Kind() != protoreflect.MessageKind || fd.IsMap() || !msg.Has(fd) {
+			continue
+		}
+		if fd.IsList() {
+			list := msg.Get(fd).List()
+			for j := 0; j < list.Len(); j++ {
+				forEachValue(list.Get(j).Message(), typeName, fn)
+			}
+			continue
+		}
+		switch fd.Message().Name() {
+		case "DynamicValue", "RawState":
+			fn(msg, fd, typeName)
+		default:
+			forEachValue(msg.Get(fd).Message(), typeName, fn)
+		}
+	}
+}
+
+// typeName returns the resource type that a request applies to, if any.
+func typeName(msg protoreflect.Message) string {
+	if name := typeNameField(msg); name != "" {
+		return name
+	}
+	// A request to move a resource's state returns the state of the target
+	// resource type.
+	if fd := msg.Descriptor().Fields().ByName("target_type_name"); fd != nil && fd.Kind() == protoreflect.StringKind {
+		return msg.Get(fd).String()
+	}
+	return ""
+}
+
+func typeNameField(msg protoreflect.Message) string {
+	if fd := msg.Descriptor().Fields().ByName("type_name"); fd != nil && fd.Kind() == protoreflect.StringKind {
+		return msg.Get(fd).String()
+	}
+	return ""
+}
+
+// decodeValue decodes a DynamicValue or RawState message as a value of the
+// given type.
+func decodeValue(msg protoreflect.Message, ty cty.Type) (cty.Value, error) {
+	fields := msg.Descriptor().Fields()
+	if fd := fields.ByName("msgpack"); fd != nil {
+		if src := msg.Get(fd).Bytes(); len(src) > 0 {
+			return msgpack.Unmarshal(src, ty)
+		}
+	}
+	if fd := fields.ByName("flatmap"); fd != nil && msg.Get(fd).Map().Len() > 0 {
+		return cty.NilVal, fmt.Errorf("cannot decode a flatmap state")
+	}
+	if fd := fields.ByName("json"); fd != nil {
+		if src := msg.Get(fd).Bytes(); len(src) > 0 {
+			return ctyjson.Unmarshal(src, ty)
+		}
+	}
+	return cty.NullVal(ty), nil
+}
+
+// encodeValue replaces the encoded value held by a DynamicValue or RawState
+// message, keeping the encoding it used.
+func encodeValue(msg protoreflect.Message, val cty.Value, ty cty.Type) error {
+	fields := msg.Descriptor().Fields()
+	if fd := fields.ByName("msgpack"); fd != nil && len(msg.Get(fd).Bytes()) > 0 {
+		src, err := msgpack.Marshal(val, ty)
+		if err != nil {
+			return err
+		}
+		msg.Set(fd, protoreflect.ValueOfBytes(src))
+		return nil
+	}
+	fd := fields.ByName("json")
+	if fd == nil {
+		return fmt.Errorf("unexpected message %s", msg.Descriptor().FullName())
+	}
+	src, err := ctyjson.Marshal(val, ty)
+	if err != nil {
+		return err
+	}
+	msg.Set(fd, protoreflect.ValueOfBytes(src))
+	return nil
+}
+
+// sensitivePaths returns the paths of the attributes in the given value that
+// the schema marks as sensitive.
+func sensitivePaths(block *configschema.Block, val cty.Value) []cty.Path {
+	var paths []cty.Path
+	for _, pvm := range block.ValueMarks(val, nil, nil) {
+		if _, ok := pvm.Marks[marks.Sensitive]; ok {
+			paths = append(paths, pvm.Path)
+		}
+	}
+	return paths
+}
+
+// maskSensitive replaces the known values of the sensitive attributes in the
+// given value with null.
+func maskSensitive(block *configschema.Block, val cty.Value) (cty.Value, bool) {
+	paths := sensitivePaths(block, val)
+	if len(paths) == 0 {
+		return val, false
+	}
+	changed := false
+	ret, err := cty.Transform(val, func(path cty.Path, v cty.Value) (cty.Value, error) {
+		if v.IsKnown() && !v.IsNull() && containsPath(paths, path) {
+			changed = true
+			return cty.NullVal(v.Type()), nil
+		}
+		return v, nil
+	})
+	if err != nil {
+		// Transform only fails if the callback does.
+		panic(err)
+	}
+	return ret, changed
+}
+
+// restoreSensitive replaces the null values of the sensitive attributes in the
+// given value with the value at the same path in the first of the sources that
+// has one.
+func restoreSensitive(block *configschema.Block, val cty.Value, sources []cty.Value) (cty.Value, bool) {
+	paths := sensitivePaths(block, val)
+	if len(paths) == 0 || len(sources) == 0 {
+		return val, false
+	}
+	changed := false
+	ret, err := cty.Transform(val, func(path cty.Path, v cty.Value) (cty.Value, error) {
+		if !v.IsNull() || !containsPath(paths, path) {
+			return v, nil
+		}
+		for _, source := range sources {
+			sv, err := path.Apply(source)
+			if err != nil || !sv.IsKnown() || sv.IsNull() {
+				continue
+			}
+			if v.Type() != cty.DynamicPseudoType && !sv.Type().Equals(v.Type()) {
+				continue
+			}
+			changed = true
+			return sv, nil
+		}
+		return v, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	return ret, changed
+}
+
+func containsPath(paths []cty.Path, path cty.Path) bool {
+	for _, p := range paths {
+		if p.Equals(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// methodName returns the name of a gRPC method without its service, such as
+// PlanResourceChange for /tfplugin5.Provider/PlanResourceChange.
+func methodName(method string) string {
+	return method[strings.LastIndex(method, "/")+1:]
+}
+
+// isSchemaMethod returns true if the given gRPC method returns the schema of
+// a provider.
+func isSchemaMethod(method string) bool {
+	switch methodName(method) {
+	case "GetSchema", "GetProviderSchema":
+		return true
+	}
+	return false
+}
+
+// isProviderConfigMethod returns true if the values sent to and returned by
+// the given gRPC method are configuration of the provider itself.
+func isProviderConfigMethod(method string) bool {
+	switch methodName(method) {
+	case "Configure", "ConfigureProvider", "PrepareProviderConfig", "ValidateProviderConfig", "ConfigureStateStore", "ValidateStateStoreConfig":
+		return true
+	}
+	return false
+}
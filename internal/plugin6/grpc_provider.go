@@ -44,10 +44,17 @@ var clientCapabilities = &proto6.ClientCapabilities{
 }
 
 func (p *GRPCProviderPlugin) GRPCClient(ctx context.Context, broker *plugin.GRPCBroker, c *grpc.ClientConn) (any, error) {
+	return NewGRPCProvider(ctx, c), nil
+}
+
+// NewGRPCProvider returns a GRPCProvider that makes its calls through the
+// given connection, which doesn't need to be one established by go-plugin.
+// The caller must set the SchemaCache of the returned provider.
+func NewGRPCProvider(ctx context.Context, conn grpc.ClientConnInterface) *GRPCProvider {
 	return &GRPCProvider{
-		client: proto6.NewProviderClient(c),
+		client: proto6.NewProviderClient(conn),
 		ctx:    ctx,
-	}, nil
+	}
 }
 
 func (p *GRPCProviderPlugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
@@ -7,6 +7,7 @@ package remote
 
 import (
 	"context"
+	"time"
 
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 )
@@ -43,6 +44,66 @@ type OptionalClientLocker interface {
 	IsLockingEnabled() bool
 }
 
+// ClientLockInspector is an optional interface that allows a remote state
+// backend to report on the lock currently held on the state, if any,
+// without acquiring it.
+type ClientLockInspector interface {
+	ClientLocker
+
+	// CurrentLock returns the information recorded by whoever is currently
+	// holding the lock, or nil if the state is not locked.
+	CurrentLock(context.Context) (*statemgr.LockInfo, error)
+}
+
+// OptionalClientLockInspector is an optional interface that allows callers
+// to determine whether or not lock inspection is actually enabled, for
+// clients where it depends on the configuration.
+type OptionalClientLockInspector interface {
+	ClientLockInspector
+	IsLockInspectionEnabled() bool
+}
+
+// ClientHistory is an optional interface that allows a remote state
+// backend to expose earlier versions of the state retained by its storage,
+// such as the object versions kept by a versioned bucket.
+type ClientHistory interface {
+	Client
+
+	// Versions returns metadata about each of the versions of the state
+	// currently retained by the storage, ordered from newest to oldest.
+	Versions(context.Context) ([]*Version, error)
+
+	// GetVersion returns the payload of the version with the given ID, or
+	// nil if there is no such version.
+	GetVersion(ctx context.Context, id string) (*Payload, error)
+}
+
+// OptionalClientHistory is an optional interface that allows callers to
+// determine whether or not earlier versions of the state are actually
+// retained, for clients where it depends on the configuration.
+type OptionalClientHistory interface {
+	ClientHistory
+	IsHistoryEnabled() bool
+}
+
+// Version describes one of the versions of the state retained by a
+// ClientHistory.
+type Version struct {
+	// ID is the storage-specific identifier of the version, which can be
+	// passed to ClientHistory.GetVersion.
+	ID string
+
+	// Created is the time at which the storage recorded the version being
+	// written, if known.
+	Created time.Time
+
+	// Who identifies the writer of the version, if the storage records it.
+	Who string
+
+	// IsLatest is true for the version that Client.Get would currently return.
+	IsLatest bool
+}
+
 // Payload is the return value from the remote state storage.
 type Payload struct {
 	MD5  []byte
@@ -58,6 +58,8 @@ var _ statemgr.Full = (*State)(nil)
 var _ statemgr.Migrator = (*State)(nil)
 var _ statemgr.PersistentMeta = (*State)(nil)
 var _ local.IntermediateStateConditionalPersister = (*State)(nil)
+var _ statemgr.OptionalLockInspector = (*State)(nil)
+var _ statemgr.EncryptionStatusReporter = (*State)(nil)
 
 func NewState(client Client, enc encryption.StateEncryption) *State {
 	return &State{
@@ -193,6 +195,14 @@ func (s *State) refreshState(ctx context.Context) error {
 	return nil
 }
 
+// statemgr.EncryptionStatusReporter impl.
+func (s *State) StateEncryptionStatus() encryption.EncryptionStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.readEncryption
+}
+
 // statemgr.Persister impl.
 func (s *State) PersistState(ctx context.Context, schemas *tofu.Schemas) error {
 	s.mu.Lock()
@@ -312,6 +322,38 @@ func (s *State) IsLockingEnabled() bool {
 	}
 }
 
+// IsLockInspectionEnabled returns true if locking is enabled and the Client
+// is able to report on the lock currently held on the state.
+//
+// This is an implementation of statemgr.OptionalLockInspector.
+func (s *State) IsLockInspectionEnabled() bool {
+	if !s.IsLockingEnabled() {
+		return false
+	}
+
+	switch c := s.Client.(type) {
+	// Client supports optional lock inspection.
+	case OptionalClientLockInspector:
+		return c.IsLockInspectionEnabled()
+	// Client supports lock inspection whenever locking is enabled.
+	case ClientLockInspector:
+		return true
+	default:
+		return false
+	}
+}
+
+// CurrentLock calls the Client's CurrentLock method if it's implemented.
+//
+// This is an implementation of statemgr.LockInspector.
+func (s *State) CurrentLock(ctx context.Context) (*statemgr.LockInfo, error) {
+	c, ok := s.Client.(ClientLockInspector)
+	if !ok {
+		return nil, fmt.Errorf("the remote state storage cannot report on the current lock")
+	}
+	return c.CurrentLock(ctx)
+}
+
 // DisableLocks turns the Lock and Unlock methods into no-ops. This is intended
 // to be called during initialization of a state manager and should not be
 // called after any of the statemgr.Full interface methods have been called.
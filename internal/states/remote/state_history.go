@@ -0,0 +1,96 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package remote
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/states/statefile"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+var _ statemgr.OptionalHistory = (*State)(nil)
+
+// IsHistoryEnabled returns true if the Client retains earlier versions of
+// the state, and so implements ClientHistory.
+//
+// This is an implementation of statemgr.OptionalHistory.
+func (s *State) IsHistoryEnabled() bool {
+	switch c := s.Client.(type) {
+	// Client retains history depending on its configuration.
+	case OptionalClientHistory:
+		return c.IsHistoryEnabled()
+	// Client always retains history.
+	case ClientHistory:
+		return true
+	default:
+		return false
+	}
+}
+
+// StateHistory returns metadata about each of the state versions retained
+// by the Client.
+//
+// The list only uses the metadata the Client keeps about each version,
+// without reading the versions themselves, so the lineage and serial of each
+// version are left unset. Use StateSnapshot to read a single version.
+//
+// This is an implementation of statemgr.History.
+func (s *State) StateHistory(ctx context.Context) ([]*statemgr.SnapshotVersion, error) {
+	c, ok := s.Client.(ClientHistory)
+	if !ok {
+		return nil, fmt.Errorf("the remote state storage does not retain earlier versions of the state")
+	}
+
+	versions, err := c.Versions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list state versions: %w", err)
+	}
+
+	ret := make([]*statemgr.SnapshotVersion, 0, len(versions))
+	for _, v := range versions {
+		ret = append(ret, &statemgr.SnapshotVersion{
+			ID:      v.ID,
+			Created: v.Created,
+			Who:     v.Who,
+			Latest:  v.IsLatest,
+		})
+	}
+	return ret, nil
+}
+
+// StateSnapshot reads the state version with the given ID from the Client.
+//
+// This is an implementation of statemgr.History.
+func (s *State) StateSnapshot(ctx context.Context, versionID string) (*statefile.File, error) {
+	c, ok := s.Client.(ClientHistory)
+	if !ok {
+		return nil, fmt.Errorf("the remote state storage does not retain earlier versions of the state")
+	}
+
+	f, err := s.readVersion(ctx, c, versionID)
+	if err != nil {
+		return nil, err
+	}
+	if f == nil {
+		return nil, fmt.Errorf("no state snapshot with version ID %q", versionID)
+	}
+	return f, nil
+}
+
+func (s *State) readVersion(ctx context.Context, c ClientHistory, id string) (*statefile.File, error) {
+	payload, err := c.GetVersion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if payload == nil {
+		return nil, nil
+	}
+
+	return statefile.Read(bytes.NewReader(payload.Data), s.encryption)
+}
@@ -108,3 +108,113 @@ func TestRemoteLocks(t *testing.T, a, b Client) {
 
 	// TODO: Should we enforce that Unlock requires the correct ID?
 }
+
+// TestClientHistory is a generic function to test the ClientHistory
+// implementation of any client, for storage that retains every version of
+// the state it is given.
+func TestClientHistory(t *testing.T, c Client) {
+	h, ok := c.(ClientHistory)
+	if !ok {
+		t.Fatal("client is not a ClientHistory")
+	}
+
+	var payloads [][]byte
+	for serial := uint64(1); serial <= 2; serial++ {
+		var buf bytes.Buffer
+		sf := statefile.New(statemgr.TestFullInitialState(), "stub-lineage", serial)
+		if err := statefile.Write(sf, &buf, encryption.StateEncryptionDisabled()); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if err := c.Put(t.Context(), buf.Bytes()); err != nil {
+			t.Fatalf("put: %s", err)
+		}
+		payloads = append(payloads, buf.Bytes())
+	}
+
+	versions, err := h.Versions(t.Context())
+	if err != nil {
+		t.Fatalf("versions: %s", err)
+	}
+	if len(versions) < len(payloads) {
+		t.Fatalf("expected at least %d versions, got %d", len(payloads), len(versions))
+	}
+	if !versions[0].IsLatest {
+		t.Fatalf("expected the first version to be the latest")
+	}
+
+	// Versions are returned newest first, so the first two versions should
+	// match our payloads in reverse order.
+	for i, want := range [][]byte{payloads[1], payloads[0]} {
+		p, err := h.GetVersion(t.Context(), versions[i].ID)
+		if err != nil {
+			t.Fatalf("get version %q: %s", versions[i].ID, err)
+		}
+		if p == nil {
+			t.Fatalf("version %q not found", versions[i].ID)
+		}
+		if !bytes.Equal(p.Data, want) {
+			t.Fatalf("wrong data for version %q\nwant: %q\ngot:  %q", versions[i].ID, string(want), string(p.Data))
+		}
+	}
+}
+
+// TestLockInspector is a generic function to test the ClientLockInspector
+// implementation of any client. Like TestRemoteLocks, it requires two client
+// instances, so that it can check that a lock held through one of them can
+// be inspected through the other.
+func TestLockInspector(t *testing.T, a, b Client) {
+	lockerA, ok := a.(statemgr.Locker)
+	if !ok {
+		t.Fatal("client A not a statemgr.Locker")
+	}
+
+	inspectorB, ok := b.(ClientLockInspector)
+	if !ok {
+		t.Fatal("client B not a ClientLockInspector")
+	}
+
+	info, err := inspectorB.CurrentLock(t.Context())
+	if err != nil {
+		t.Fatal("unable to inspect the lock before locking:", err)
+	}
+	if info != nil {
+		t.Fatalf("expected no lock before locking, got:\n%s", info)
+	}
+
+	infoA := statemgr.NewLockInfo()
+	infoA.Operation = "test"
+	infoA.Who = "clientA"
+
+	lockIDA, err := lockerA.Lock(t.Context(), infoA)
+	if err != nil {
+		t.Fatal("unable to get initial lock:", err)
+	}
+
+	info, err = inspectorB.CurrentLock(t.Context())
+	if err != nil {
+		if err := lockerA.Unlock(t.Context(), lockIDA); err != nil {
+			t.Error(err)
+		}
+		t.Fatal("unable to inspect the lock held by client A:", err)
+	}
+	switch {
+	case info == nil:
+		t.Error("expected the lock held by client A, got no lock")
+	case info.ID != lockIDA:
+		t.Errorf("wrong lock ID %q; want %q", info.ID, lockIDA)
+	case info.Who != infoA.Who || info.Operation != infoA.Operation:
+		t.Errorf("wrong lock info; got:\n%s", info)
+	}
+
+	if err := lockerA.Unlock(t.Context(), lockIDA); err != nil {
+		t.Fatal("error unlocking client A", err)
+	}
+
+	info, err = inspectorB.CurrentLock(t.Context())
+	if err != nil {
+		t.Fatal("unable to inspect the lock after unlocking:", err)
+	}
+	if info != nil {
+		t.Fatalf("expected no lock after unlocking, got:\n%s", info)
+	}
+}
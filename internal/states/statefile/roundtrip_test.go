@@ -18,8 +18,10 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/zclconf/go-cty/cty"
 
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/encryption/enctest"
+	"github.com/opentofu/opentofu/internal/lang/marks"
 	"github.com/opentofu/opentofu/internal/states"
 )
 
@@ -186,3 +188,90 @@ func TestRoundtripEncryption(t *testing.T) {
 		t.Error("wrong result:\n" + diff)
 	}
 }
+
+func TestRoundtripSensitiveAttributesEncryption(t *testing.T) {
+	enc := enctest.EncryptionDirect(t, `
+		key_provider "static" "basic" {
+			key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+		}
+		method "aes_gcm" "example" {
+			keys = key_provider.static.basic
+		}
+		state {
+			mode = "sensitive_attributes"
+			method = method.aes_gcm.example
+		}
+	`).State()
+
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "test_database",
+				Name: "main",
+			}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+			&states.ResourceInstanceObjectSrc{
+				Status:       states.ObjectReady,
+				Dependencies: []addrs.ConfigResource{},
+				AttrsJSON:    []byte(`{"id":"db-1234","password":"hunter2","settings":{"api_key":"s3cr3t","size":10}}`),
+				AttrSensitivePaths: []cty.PathValueMarks{
+					{Path: cty.GetAttrPath("password"), Marks: cty.NewValueMarks(marks.Sensitive)},
+					{Path: cty.GetAttrPath("settings").IndexString("api_key"), Marks: cty.NewValueMarks(marks.Sensitive)},
+				},
+			},
+			addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+			addrs.NoKey,
+		)
+		s.SetOutputValue(addrs.OutputValue{Name: "password"}.Absolute(addrs.RootModuleInstance), cty.StringVal("hunter2"), true, "")
+		s.SetOutputValue(addrs.OutputValue{Name: "id"}.Absolute(addrs.RootModuleInstance), cty.StringVal("db-1234"), false, "")
+	})
+
+	// Read the state back unencrypted first, to compare it with the same serialization
+	var plain bytes.Buffer
+	if err := WriteIndent(New(state, "magic", 1), &plain, encryption.StateEncryptionDisabled()); err != nil {
+		t.Fatal(err)
+	}
+	originalState, err := Read(&plain, encryption.StateEncryptionDisabled())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var encrypted bytes.Buffer
+	if err := WriteIndent(originalState, &encrypted, enc); err != nil {
+		t.Fatal(err)
+	}
+
+	// Make sure the secrets are encrypted, but the rest is readable
+	for _, secret := range []string{"hunter2", "s3cr3t"} {
+		if strings.Contains(encrypted.String(), secret) {
+			t.Errorf("written state file contains the secret %q:\n%s", secret, encrypted.String())
+		}
+	}
+	if !strings.Contains(encrypted.String(), "db-1234") {
+		t.Errorf("written state file doesn't contain the resource id:\n%s", encrypted.String())
+	}
+
+	// Make sure the state isn't read with the encrypted values in place
+	encryptedCopy := bytes.NewReader(encrypted.Bytes())
+	if _, err := Read(encryptedCopy, encryption.StateEncryptionDisabled()); err == nil {
+		t.Fatal("expected an error reading encrypted sensitive values without encryption")
+	}
+
+	newState, err := Read(&encrypted, enc)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if newState.EncryptionStatus != encryption.StatusSatisfied {
+		t.Fatal("wrong status")
+	}
+
+	// Overwrite status for deep comparison
+	originalState.EncryptionStatus = newState.EncryptionStatus
+
+	if diff := cmp.Diff(originalState, newState, cmpTransformers...); diff != "" {
+		t.Error("wrong result:\n" + diff)
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statemgr
+
+import (
+	"github.com/opentofu/opentofu/internal/encryption"
+)
+
+// EncryptionStatusReporter is an optional extension to Refresher for state
+// managers that decrypt the snapshots they read, and so are able to report
+// which of the configured encryption methods was used to read the latest
+// snapshot.
+//
+// This allows callers to find out whether a snapshot is still encrypted with
+// a fallback method, and so must be written again to be encrypted with the
+// primary method.
+type EncryptionStatusReporter interface {
+	// StateEncryptionStatus returns the encryption status of the snapshot
+	// most recently read by RefreshState:
+	//
+	//   - encryption.StatusSatisfied if it was decrypted with the primary
+	//     method, or if encryption is not configured.
+	//   - encryption.StatusMigration if it was decrypted with the fallback
+	//     method, or was not encrypted although encryption is configured.
+	//   - encryption.StatusUnknown if no snapshot has been read.
+	StateEncryptionStatus() encryption.EncryptionStatus
+}
@@ -68,9 +68,10 @@ type Filesystem struct {
 }
 
 var (
-	_ Full           = (*Filesystem)(nil)
-	_ PersistentMeta = (*Filesystem)(nil)
-	_ Migrator       = (*Filesystem)(nil)
+	_ Full                     = (*Filesystem)(nil)
+	_ PersistentMeta           = (*Filesystem)(nil)
+	_ Migrator                 = (*Filesystem)(nil)
+	_ EncryptionStatusReporter = (*Filesystem)(nil)
 )
 
 // NewFilesystem creates a filesystem-based state manager that reads and writes
@@ -472,6 +473,17 @@ func (s *Filesystem) StateForMigration() *statefile.File {
 	return s.file.DeepCopy()
 }
 
+// StateEncryptionStatus is part of our implementation of
+// EncryptionStatusReporter.
+func (s *Filesystem) StateEncryptionStatus() encryption.EncryptionStatus {
+	defer s.mutex()()
+
+	if s.readFile == nil {
+		return encryption.StatusUnknown
+	}
+	return s.readFile.EncryptionStatus
+}
+
 // WriteStateForMigration is part of our implementation of Migrator.
 func (s *Filesystem) WriteStateForMigration(f *statefile.File, force bool) error {
 	defer s.mutex()()
@@ -0,0 +1,144 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statemgr
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/opentofu/opentofu/internal/states/statefile"
+)
+
+// filesystemBackupExtension is the suffix shared by the backup snapshots
+// written alongside a local state file, both by the local backend itself
+// and by the timestamped backups that the "tofu state" subcommands create.
+const filesystemBackupExtension = ".backup"
+
+var _ History = (*Filesystem)(nil)
+
+// StateHistory is an implementation of History.
+//
+// The history of a local state file consists of the file itself along with
+// any backup files found beside it, including the configured backup path.
+// The version ID of each snapshot is the base name of the file containing it.
+//
+// The files are not read, so the lineage and serial of each snapshot are left
+// unset. Use StateSnapshot to read a single snapshot.
+func (s *Filesystem) StateHistory(_ context.Context) ([]*SnapshotVersion, error) {
+	defer s.mutex()()
+
+	paths, err := s.historyPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*SnapshotVersion, 0, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		if info.Size() == 0 {
+			// An empty file contains no snapshot
+			continue
+		}
+
+		ret = append(ret, &SnapshotVersion{
+			ID:      filepath.Base(path),
+			Created: info.ModTime().UTC(),
+			Latest:  path == s.path,
+		})
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		if ret[i].Latest != ret[j].Latest {
+			return ret[i].Latest
+		}
+		return ret[i].Created.After(ret[j].Created)
+	})
+	return ret, nil
+}
+
+// StateSnapshot is an implementation of History.
+func (s *Filesystem) StateSnapshot(_ context.Context, versionID string) (*statefile.File, error) {
+	defer s.mutex()()
+
+	paths, err := s.historyPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	// We only accept IDs that correspond to one of the files we'd report in
+	// StateHistory, so that the version ID can't be used to read arbitrary
+	// files from elsewhere on the filesystem.
+	for _, path := range paths {
+		if filepath.Base(path) != versionID {
+			continue
+		}
+		f, err := s.readHistoryFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				break
+			}
+			return nil, fmt.Errorf("failed to read state snapshot %s: %w", path, err)
+		}
+		return f, nil
+	}
+	return nil, fmt.Errorf("no state snapshot with version ID %q", versionID)
+}
+
+// historyPaths returns the paths of all of the files that might contain
+// snapshots belonging to the history of this state file, starting with
+// the state file itself.
+func (s *Filesystem) historyPaths() ([]string, error) {
+	paths := []string{s.path}
+	seen := map[string]bool{s.path: true}
+
+	matches, err := filepath.Glob(s.path + "*" + filesystemBackupExtension)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for state backup files: %w", err)
+	}
+	if s.backupPath != "" {
+		matches = append(matches, s.backupPath)
+	}
+	for _, path := range matches {
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// readHistoryFile reads the snapshot at the given path without modifying
+// the transient or persistent snapshots tracked by the manager.
+func (s *Filesystem) readHistoryFile(path string) (*statefile.File, error) {
+	// If we're holding the state file open, we must read it through our
+	// existing handle because some platforms won't allow opening a locked
+	// file a second time.
+	if path == s.path && s.stateFileOut != nil {
+		if _, err := s.stateFileOut.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return statefile.Read(s.stateFileOut, s.encryption)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return statefile.Read(f, s.encryption)
+}
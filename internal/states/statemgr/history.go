@@ -0,0 +1,91 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statemgr
+
+import (
+	"context"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/states/statefile"
+)
+
+// History is an optional extension to Persistent for state managers whose
+// storage retains earlier persistent snapshots alongside the latest one,
+// such as versioned object storage or local backup files.
+//
+// Each retained snapshot is identified by an opaque version ID whose format
+// is decided by the implementation. Callers must not attempt to parse it,
+// and should only pass IDs previously returned by StateHistory back to
+// StateSnapshot.
+type History interface {
+	// StateHistory returns metadata about each of the snapshots currently
+	// retained by the storage, ordered from newest to oldest. It only uses
+	// the metadata kept by the storage, without reading the snapshots.
+	StateHistory(context.Context) ([]*SnapshotVersion, error)
+
+	// StateSnapshot reads the retained snapshot with the given version ID.
+	//
+	// The result is not affected by, and does not affect, the transient
+	// snapshot of the state manager.
+	StateSnapshot(ctx context.Context, versionID string) (*statefile.File, error)
+}
+
+// OptionalHistory extends History to allow callers to know whether or not
+// the underlying storage actually retains any history. This is useful for
+// state managers that wrap several different storage implementations, only
+// some of which are able to retain earlier snapshots.
+type OptionalHistory interface {
+	History
+	IsHistoryEnabled() bool
+}
+
+// HistoryFor returns the History implementation of the given state manager
+// and true, if it has one that is enabled, or nil and false otherwise.
+func HistoryFor(mgr Persistent) (History, bool) {
+	switch h := mgr.(type) {
+	case OptionalHistory:
+		if !h.IsHistoryEnabled() {
+			return nil, false
+		}
+		return h, true
+	case History:
+		return h, true
+	default:
+		return nil, false
+	}
+}
+
+// SnapshotVersion describes one of the persistent snapshots retained by a
+// History implementation.
+//
+// Not all storage retains all of this information, so fields other than ID
+// may be left at their zero values when the information is unavailable.
+type SnapshotVersion struct {
+	// ID is the opaque version ID that can be passed to
+	// History.StateSnapshot to retrieve this snapshot.
+	ID string
+
+	// Lineage and Serial are the values recorded in the snapshot itself,
+	// with the same meaning as the fields of the same name in SnapshotMeta.
+	//
+	// History.StateHistory doesn't read the snapshots, so these are only set
+	// by callers that read a snapshot with History.StateSnapshot.
+	Lineage string
+	Serial  uint64
+
+	// Created is the time at which the storage recorded this snapshot
+	// being written.
+	Created time.Time
+
+	// Who identifies whoever wrote this snapshot, in whatever form the
+	// storage records it. Where OpenTofu itself records this information it
+	// uses the same "user@hostname" format as LockInfo.Who.
+	Who string
+
+	// Latest is true for the snapshot that a call to Refresher.RefreshState
+	// would currently read.
+	Latest bool
+}
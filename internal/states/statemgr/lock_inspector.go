@@ -0,0 +1,51 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statemgr
+
+import (
+	"context"
+)
+
+// LockInspector is an optional extension to Locker for state managers that
+// are able to report on a lock held by any process, without attempting to
+// acquire it.
+//
+// This allows an operator to find out who is holding a lock, and since when,
+// before deciding whether to forcibly release it.
+type LockInspector interface {
+	// CurrentLock returns the information recorded by whoever is currently
+	// holding the lock, or nil if the state is not locked.
+	//
+	// The ID of the result is the same ID that must be passed to Unlock to
+	// release the lock.
+	CurrentLock(ctx context.Context) (*LockInfo, error)
+}
+
+// OptionalLockInspector extends LockInspector to allow callers to know
+// whether or not lock inspection is actually available. This is useful for
+// state managers that wrap several different storage implementations, only
+// some of which are able to inspect locks, or for which locking is optional.
+type OptionalLockInspector interface {
+	LockInspector
+	IsLockInspectionEnabled() bool
+}
+
+// LockInspectorFor returns the LockInspector implementation of the given
+// state manager and true, if it has one that is enabled, or nil and false
+// otherwise.
+func LockInspectorFor(mgr Locker) (LockInspector, bool) {
+	switch i := mgr.(type) {
+	case OptionalLockInspector:
+		if !i.IsLockInspectionEnabled() {
+			return nil, false
+		}
+		return i, true
+	case LockInspector:
+		return i, true
+	default:
+		return nil, false
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"os"
 	"os/user"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 
@@ -22,7 +23,10 @@ import (
 	"github.com/opentofu/opentofu/version"
 )
 
+// rngSource is not safe for concurrent use, so rngMu must be held while
+// reading from it.
 var rngSource = rand.New(rand.NewSource(time.Now().UnixNano()))
+var rngMu sync.Mutex
 
 // Locker is the interface for state managers that are able to manage
 // mutual-exclusion locks for state.
@@ -165,7 +169,9 @@ func NewLockInfo() *LockInfo {
 	// Using math/rand alleviates the need to check handle the read error.
 	// Use a uuid format to match other IDs used throughout OpenTofu.
 	buf := make([]byte, 16)
+	rngMu.Lock()
 	rngSource.Read(buf)
+	rngMu.Unlock()
 
 	id, err := uuid.FormatUUID(buf)
 	if err != nil {
@@ -0,0 +1,102 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terminal
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// BufferedStreams returns a Streams object whose output is captured in memory
+// rather than being written directly to the terminal, along with a function
+// to call once the streams are no longer needed in order to obtain the
+// captured output.
+//
+// This is intended for situations where OpenTofu runs several operations
+// concurrently and must therefore hold back the output of each one until it
+// completes, so that the output of the different operations isn't
+// interleaved.
+//
+// The output streams report the given number of columns, so that the
+// captured output can be wrapped to suit the terminal it will eventually be
+// written to. The input stream is connected to the system's "null device".
+//
+// Once the close function has been called the Streams object becomes invalid
+// and must not be used anymore. Callers must always call the close function,
+// even if they don't intend to use the output, or else they will leak
+// resources.
+func BufferedStreams(columns int) (streams *Streams, close func() (stdout, stderr []byte, err error), err error) {
+	stdinR, err := os.Open(os.DevNull)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open %s to represent stdin: %w", os.DevNull, err)
+	}
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		stdinR.Close()
+		return nil, nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+	stderrR, stderrW, err := os.Pipe()
+	if err != nil {
+		stdinR.Close()
+		stdoutR.Close()
+		stdoutW.Close()
+		return nil, nil, fmt.Errorf("failed to create stderr pipe: %w", err)
+	}
+
+	// As with StreamsForTesting, we use OS-level pipes so that the code
+	// writing to the streams sees a realistic implementation, and copy
+	// anything written to them into memory concurrently.
+	var stdoutBuf, stderrBuf bytes.Buffer
+	var stdoutErr, stderrErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		_, stdoutErr = io.Copy(&stdoutBuf, stdoutR)
+		wg.Done()
+	}()
+	go func() {
+		_, stderrErr = io.Copy(&stderrBuf, stderrR)
+		wg.Done()
+	}()
+
+	close = func() ([]byte, []byte, error) {
+		// Closing the write sides of the pipes causes the copying
+		// goroutines above to encounter io.EOF and exit.
+		errs := []error{
+			stdinR.Close(),
+			stdoutW.Close(),
+			stderrW.Close(),
+		}
+		wg.Wait()
+		errs = append(errs, stdoutErr, stderrErr, stdoutR.Close(), stderrR.Close())
+		for _, err := range errs {
+			if err != nil {
+				return stdoutBuf.Bytes(), stderrBuf.Bytes(), err
+			}
+		}
+		return stdoutBuf.Bytes(), stderrBuf.Bytes(), nil
+	}
+
+	getColumns := func(*os.File) int {
+		return columns
+	}
+	return &Streams{
+		Stdout: &OutputStream{
+			File:       stdoutW,
+			getColumns: getColumns,
+		},
+		Stderr: &OutputStream{
+			File:       stderrW,
+			getColumns: getColumns,
+		},
+		Stdin: &InputStream{
+			File: stdinR,
+		},
+	}, close, nil
+}
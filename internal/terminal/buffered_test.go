@@ -0,0 +1,41 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terminal
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBufferedStreams(t *testing.T) {
+	streams, close, err := BufferedStreams(120)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := streams.Stdout.Columns(), 120; got != want {
+		t.Errorf("wrong stdout columns %d; want %d", got, want)
+	}
+	if got, want := streams.Stderr.Columns(), 120; got != want {
+		t.Errorf("wrong stderr columns %d; want %d", got, want)
+	}
+
+	streams.Println("stdout println", 1)
+	streams.Eprintln("stderr println", 2)
+	streams.Printf("stdout printf %d\n", 3)
+
+	stdout, stderr, err := close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("stdout println 1\nstdout printf 3\n", string(stdout)); diff != "" {
+		t.Errorf("wrong stdout\n%s", diff)
+	}
+	if diff := cmp.Diff("stderr println 2\n", string(stderr)); diff != "" {
+		t.Errorf("wrong stderr\n%s", diff)
+	}
+}
@@ -9,13 +9,16 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sync"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/checks"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/plans/objchange"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tracing"
@@ -45,6 +48,54 @@ type ApplyOpts struct {
 	// BackupStateForPanic is an optional handler that is called if a panic is encountered
 	// during the graph walk.
 	BackupStateForPanic func(*states.State)
+
+	// Progress, if set, is populated once the apply walk completes with the
+	// resource instances whose planned changes were applied, even if the
+	// apply failed. Pass the completed instances to Context.ResumePlan to
+	// apply the remaining changes later. ApplyProgress.Current reports the
+	// progress while the walk is still running.
+	Progress *ApplyProgress
+
+	// RecordChecks, if set, is called once the apply walk completes with the
+	// state of the checks evaluated during the walk. Unlike the check results
+	// in the new state, it includes the status of each of the individual
+	// checks of each checkable object.
+	RecordChecks func(*checks.State)
+}
+
+// ApplyProgress records which planned changes were applied by Context.Apply.
+type ApplyProgress struct {
+	// Completed lists the resource instances for which all planned changes
+	// were applied successfully, in the order they appear in the plan.
+	Completed []addrs.AbsResourceInstance
+
+	mu   sync.Mutex
+	hook *applyProgressHook
+}
+
+// Current returns the resource instances for which all planned changes have
+// been applied so far. Unlike Completed, it can be called while the apply
+// walk is still running, such as after the apply was canceled.
+func (p *ApplyProgress) Current() []addrs.AbsResourceInstance {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.hook == nil {
+		return p.Completed
+	}
+	return p.hook.Completed()
+}
+
+func (p *ApplyProgress) setHook(hook *applyProgressHook) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.hook = hook
+}
+
+func (p *ApplyProgress) setCompleted(completed []addrs.AbsResourceInstance) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Completed = completed
 }
 
 // Apply performs the actions described by the given Plan object and returns
@@ -137,8 +188,15 @@ func (c *Context) Apply(ctx context.Context, plan *plans.Plan, config *configs.C
 	}
 
 	var backupStateFunc func(*states.State)
+	var progressHook *applyProgressHook
+	var walkHooks []Hook
 	if opts != nil {
 		backupStateFunc = opts.BackupStateForPanic
+		if opts.Progress != nil {
+			progressHook = newApplyProgressHook(plan.Changes)
+			opts.Progress.setHook(progressHook)
+			walkHooks = append(walkHooks, progressHook)
+		}
 	}
 
 	workingState := plan.PriorState.DeepCopy()
@@ -158,10 +216,19 @@ func (c *Context) Apply(ctx context.Context, plan *plans.Plan, config *configs.C
 
 		// Include state backup handler in case of panic
 		BackupStateForPanic: backupStateFunc,
+
+		Hooks: walkHooks,
 	})
 	diags = diags.Append(walker.NonFatalDiagnostics)
 	diags = diags.Append(walkDiags)
 
+	if progressHook != nil {
+		opts.Progress.setCompleted(progressHook.Completed())
+	}
+	if opts != nil && opts.RecordChecks != nil {
+		opts.RecordChecks(walker.Checks)
+	}
+
 	// After the walk is finished, we capture a simplified snapshot of the
 	// check result data as part of the new state.
 	walker.State.RecordCheckResults(walker.Checks)
@@ -221,6 +288,162 @@ Note that the -target and -exclude options are not suitable for routine use, and
 	return newState, diags
 }
 
+// ResumePlan returns a copy of the given plan which contains only the changes
+// that were not yet applied by an earlier call to Apply that failed partway
+// through, so that applying it completes the earlier apply.
+//
+// The given state must be the state produced by that earlier apply, and the
+// completed instances must be those it reported in ApplyOpts.Progress.
+// ResumePlan checks that the objects of the completed instances match the
+// values in the plan and that the objects of all of the other instances with
+// planned changes are unchanged, returning errors if not, because the
+// remaining changes are only valid for the objects they were planned against.
+func (c *Context) ResumePlan(ctx context.Context, plan *plans.Plan, config *configs.Config, state *states.State, completed []addrs.AbsResourceInstance) (*plans.Plan, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	if plan.Errored {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Cannot resume failed plan",
+			`The given plan is incomplete due to errors during planning, and so it cannot be applied.`,
+		))
+		return nil, diags
+	}
+
+	schemas, moreDiags := c.Schemas(ctx, config, state)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return nil, diags
+	}
+
+	completedAddrs := addrs.MakeSet(completed...)
+	changes := &plans.Changes{
+		Outputs: plan.Changes.Outputs,
+	}
+	for _, rc := range plan.Changes.Resources {
+		if applyOperationCount(rc.Action) == 0 {
+			// There is nothing to resume for changes that aren't applied by
+			// the provider, so we keep them as they are.
+			changes.Resources = append(changes.Resources, rc)
+			continue
+		}
+
+		schema, _ := schemas.ResourceTypeConfig(rc.ProviderAddr.Provider, rc.Addr.Resource.Resource.Mode, rc.Addr.Resource.Resource.Type)
+		if schema == nil || schema.Block == nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Missing resource type schema",
+				fmt.Sprintf("Provider %s did not return a schema for %s, so OpenTofu cannot resume the apply. This is a bug in OpenTofu; please report it!", rc.ProviderAddr.Provider, rc.Addr),
+			))
+			continue
+		}
+		ty := schema.Block.ImpliedType()
+
+		current, err := resumeObjectValue(state, rc.Addr, rc.DeposedKey, ty)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid resource instance object",
+				fmt.Sprintf("Failed to decode the object for %s in the current state: %s.", rc.Addr, err),
+			))
+			continue
+		}
+
+		if completedAddrs.Has(rc.Addr) {
+			change, err := rc.Decode(schema)
+			if err != nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid planned change",
+					fmt.Sprintf("Failed to decode the planned change for %s: %s.", rc.Addr, err),
+				))
+				continue
+			}
+			planned, _ := change.After.UnmarkDeep()
+			actual, _ := current.UnmarkDeep()
+			if !actual.IsKnown() {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Applied change does not match the plan",
+					fmt.Sprintf("The earlier apply reported that the planned change for %s was completed, but the object in the current state is tainted.\n\nCreate a new plan instead of resuming this one.", rc.Addr),
+				))
+				continue
+			}
+			if errs := objchange.AssertObjectCompatible(schema.Block, planned, actual); len(errs) > 0 {
+				for _, err := range errs {
+					diags = diags.Append(tfdiags.Sourceless(
+						tfdiags.Error,
+						"Applied change does not match the plan",
+						fmt.Sprintf(
+							"The earlier apply reported that the planned change for %s was completed, but the object in the current state does not match the plan: %s.\n\nCreate a new plan instead of resuming this one.",
+							rc.Addr, tfdiags.FormatErrorPrefixed(err, rc.Addr.String()),
+						),
+					))
+				}
+			}
+			continue
+		}
+
+		prior, err := resumeObjectValue(plan.PriorState, rc.Addr, rc.DeposedKey, ty)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid resource instance object",
+				fmt.Sprintf("Failed to decode the object for %s in the prior state of the plan: %s.", rc.Addr, err),
+			))
+			continue
+		}
+		if !prior.RawEquals(current) {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Planned change was partially applied",
+				fmt.Sprintf(
+					"The object for %s has changed since the plan was created, but the earlier apply did not complete its planned change. OpenTofu cannot safely apply the rest of this change.\n\nCreate a new plan instead of resuming this one.",
+					rc.Addr,
+				),
+			))
+			continue
+		}
+		changes.Resources = append(changes.Resources, rc)
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	ret := *plan
+	ret.Changes = changes
+	ret.PriorState = state.DeepCopy()
+	return &ret, diags
+}
+
+// resumeObjectValue returns the value of the object with the given deposed key
+// (or the current object, for states.NotDeposed) of the given resource
+// instance, or a null value if there is no such object.
+func resumeObjectValue(state *states.State, addr addrs.AbsResourceInstance, key states.DeposedKey, ty cty.Type) (cty.Value, error) {
+	var src *states.ResourceInstanceObjectSrc
+	if ri := state.ResourceInstance(addr); ri != nil {
+		if key == states.NotDeposed {
+			src = ri.Current
+		} else {
+			src = ri.Deposed[key]
+		}
+	}
+	if src == nil {
+		return cty.NullVal(ty), nil
+	}
+
+	obj, err := src.Decode(ty)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if obj.Status == states.ObjectTainted {
+		// A tainted object never matches the plan, because its creation or
+		// update was not completed.
+		return cty.UnknownVal(ty), nil
+	}
+	return obj.Value, nil
+}
+
 func (c *Context) applyGraph(ctx context.Context, plan *plans.Plan, config *configs.Config, providerFunctionTracker ProviderFunctionMapping, applyOpts *ApplyOpts) (*Graph, walkOperation, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
@@ -7353,3 +7353,270 @@ func TestContext2Apply_ephemeralInModuleWithExpansion(t *testing.T) {
 
 	}
 }
+
+func TestContext2Apply_resume(t *testing.T) {
+	// The new runtime doesn't report the progress of the apply yet.
+	SkipExperimental(t, ExperimentalFeatureApplyProgress)
+
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+  test_string = "a"
+}
+
+resource "test_object" "b" {
+  test_string = "b"
+}
+`,
+	})
+
+	fail := true
+	var applied []string
+	p := simpleMockProvider()
+	p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) (resp providers.ApplyResourceChangeResponse) {
+		name := req.PlannedState.GetAttr("test_string").AsString()
+		if fail && name == "b" {
+			resp.NewState = cty.NullVal(req.PlannedState.Type())
+			resp.Diagnostics = resp.Diagnostics.Append(errors.New("transient failure"))
+			return resp
+		}
+		applied = append(applied, name)
+		resp.NewState = req.PlannedState
+		return resp
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Plugins: plugins.NewLibrary(map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		}, nil),
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	// Applying a plan consumes its changes, so we resume with an identical
+	// plan instead, as if it had been read again from a saved plan file.
+	saved, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	progress := &ApplyProgress{}
+	state, diags := ctx.Apply(context.Background(), plan, m, &ApplyOpts{Progress: progress})
+	if !diags.HasErrors() {
+		t.Fatal("expected apply to fail")
+	}
+	if got := progress.Completed; len(got) != 1 || !got[0].Equal(mustResourceInstanceAddr("test_object.a")) {
+		t.Fatalf("wrong completed instances: %s", got)
+	}
+
+	resumed, diags := ctx.ResumePlan(context.Background(), saved, m, state, progress.Completed)
+	assertNoErrors(t, diags)
+	if got := len(resumed.Changes.Resources); got != 1 || !resumed.Changes.Resources[0].Addr.Equal(mustResourceInstanceAddr("test_object.b")) {
+		t.Fatalf("wrong resumed changes: %s", spew.Sdump(resumed.Changes.Resources))
+	}
+
+	fail = false
+	applied = nil
+	state, diags = ctx.Apply(context.Background(), resumed, m, nil)
+	assertNoErrors(t, diags)
+	if diff := cmp.Diff([]string{"b"}, applied); diff != "" {
+		t.Fatalf("wrong applied changes\n%s", diff)
+	}
+	for _, addr := range []string{"test_object.a", "test_object.b"} {
+		if state.ResourceInstance(mustResourceInstanceAddr(addr)) == nil {
+			t.Errorf("%s is missing from the final state", addr)
+		}
+	}
+}
+
+func TestContext2Apply_progressStopped(t *testing.T) {
+	// The new runtime doesn't report the progress of the apply yet.
+	SkipExperimental(t, ExperimentalFeatureApplyProgress)
+
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+  test_string = "a"
+}
+
+resource "test_object" "b" {
+  test_string = "b"
+
+  depends_on = [test_object.a]
+}
+`,
+	})
+
+	var ctx *Context
+	stopped := make(chan struct{})
+	var stopOnce sync.Once
+	p := simpleMockProvider()
+	p.StopFn = func() error {
+		stopOnce.Do(func() { close(stopped) })
+		return nil
+	}
+	p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) (resp providers.ApplyResourceChangeResponse) {
+		// The apply is stopped while test_object.a is being applied, so
+		// its change completes only after the stop hook started halting
+		// the walk.
+		go ctx.Stop()
+		<-stopped
+		resp.NewState = req.PlannedState
+		return resp
+	}
+
+	ctx = testContext2(t, &ContextOpts{
+		Plugins: plugins.NewLibrary(map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		}, nil),
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	progress := &ApplyProgress{}
+	_, diags = ctx.Apply(context.Background(), plan, m, &ApplyOpts{Progress: progress})
+	if !diags.HasErrors() {
+		t.Fatal("expected apply to be halted")
+	}
+	if got := progress.Current(); len(got) != 1 || !got[0].Equal(mustResourceInstanceAddr("test_object.a")) {
+		t.Fatalf("wrong completed instances: %s", got)
+	}
+}
+
+func TestContext2Apply_resumeStateMismatch(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+  test_string = "a"
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Plugins: plugins.NewLibrary(map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		}, nil),
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			mustResourceInstanceAddr("test_object.a"),
+			&states.ResourceInstanceObjectSrc{
+				Status:    states.ObjectReady,
+				AttrsJSON: []byte(`{"test_string":"other"}`),
+			},
+			mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`),
+			addrs.NoKey,
+		)
+	})
+
+	tests := map[string]struct {
+		completed []addrs.AbsResourceInstance
+		wantErr   string
+	}{
+		"completed": {
+			completed: []addrs.AbsResourceInstance{mustResourceInstanceAddr("test_object.a")},
+			wantErr:   "Applied change does not match the plan",
+		},
+		"not completed": {
+			wantErr: "Planned change was partially applied",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, diags := ctx.ResumePlan(context.Background(), plan, m, state, test.completed)
+			if !diags.HasErrors() {
+				t.Fatal("expected errors")
+			}
+			if got := diags.Err().Error(); !strings.Contains(got, test.wantErr) {
+				t.Fatalf("wrong error\ngot: %s\nwant substring: %s", got, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestContext2Apply_recordChecks(t *testing.T) {
+	// The new runtime doesn't report the state of the checks yet.
+	SkipExperimental(t, ExperimentalFeatureChecks)
+
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+  test_string = "a"
+
+  lifecycle {
+    postcondition {
+      condition     = self.test_string == "a"
+      error_message = "Wrong string."
+    }
+  }
+}
+
+check "b" {
+  assert {
+    condition     = test_object.a.test_string == "a"
+    error_message = "Wrong string."
+  }
+  assert {
+    condition     = test_object.a.test_string == "b"
+    error_message = "Wrong string."
+  }
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Plugins: plugins.NewLibrary(map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		}, nil),
+	})
+
+	resource := mustResourceInstanceAddr("test_object.a")
+	check := addrs.Check{Name: "b"}.Absolute(addrs.RootModuleInstance)
+	want := map[string]map[addrs.CheckRuleType][]checks.Status{
+		resource.String(): {
+			addrs.ResourcePostcondition: {checks.StatusPass},
+		},
+		check.String(): {
+			addrs.CheckAssertion: {checks.StatusPass, checks.StatusFail},
+		},
+	}
+	ruleStatuses := func(t *testing.T, state *checks.State) map[string]map[addrs.CheckRuleType][]checks.Status {
+		t.Helper()
+		if state == nil {
+			t.Fatal("the state of the checks was not recorded")
+		}
+		return map[string]map[addrs.CheckRuleType][]checks.Status{
+			resource.String(): state.ObjectCheckRuleStatuses(resource),
+			check.String():    state.ObjectCheckRuleStatuses(check),
+		}
+	}
+
+	var planChecks *checks.State
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), &PlanOpts{
+		Mode: plans.NormalMode,
+		RecordChecks: func(state *checks.State) {
+			planChecks = state
+		},
+	})
+	assertNoErrors(t, diags)
+	if diff := cmp.Diff(want, ruleStatuses(t, planChecks)); diff != "" {
+		t.Errorf("wrong check statuses after plan\n%s", diff)
+	}
+
+	var applyChecks *checks.State
+	_, diags = ctx.Apply(context.Background(), plan, m, &ApplyOpts{
+		RecordChecks: func(state *checks.State) {
+			applyChecks = state
+		},
+	})
+	assertNoErrors(t, diags)
+	if diff := cmp.Diff(want, ruleStatuses(t, applyChecks)); diff != "" {
+		t.Errorf("wrong check statuses after apply\n%s", diff)
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/checks"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/instances"
 	"github.com/opentofu/opentofu/internal/lang/globalref"
@@ -109,6 +110,12 @@ type PlanOpts struct {
 	//
 	// If empty, then no config will be generated.
 	GenerateConfigPath string
+
+	// RecordChecks, if set, is called once the plan walk completes with the
+	// state of the checks evaluated during the walk. Unlike the check results
+	// in the plan, it includes the status of each of the individual checks of
+	// each checkable object.
+	RecordChecks func(*checks.State)
 }
 
 // Plan generates an execution plan by comparing the given configuration
@@ -447,6 +454,7 @@ func (c *Context) destroyPlan(ctx context.Context, config *configs.Config, prevR
 		refreshOpts := *opts
 		refreshOpts.Mode = plans.NormalMode
 		refreshOpts.PreDestroyRefresh = true
+		refreshOpts.RecordChecks = nil
 
 		// FIXME: A normal plan is required here to refresh the state, because
 		// the state and configuration may not match during a destroy, and a
@@ -822,6 +830,10 @@ func (c *Context) planWalk(ctx context.Context, config *configs.Config, prevRunS
 	diags = diags.Append(walker.NonFatalDiagnostics)
 	diags = diags.Append(walkDiags)
 
+	if opts.RecordChecks != nil {
+		opts.RecordChecks(walker.Checks)
+	}
+
 	allInsts := walker.InstanceExpander.AllInstances()
 
 	importValidateDiags := c.postExpansionImportValidation(walker.ImportResolver, allInsts)
@@ -49,6 +49,10 @@ type graphWalkOpts struct {
 	ProviderFunctionTracker ProviderFunctionMapping
 
 	BackupStateForPanic func(*states.State)
+
+	// Hooks are called during the walk in addition to the hooks of the
+	// Context.
+	Hooks []Hook
 }
 
 func (c *Context) walk(ctx context.Context, graph *Graph, operation walkOperation, opts *graphWalkOpts) (*ContextGraphWalker, tfdiags.Diagnostics) {
@@ -160,5 +164,6 @@ func (c *Context) graphWalker(operation walkOperation, opts *graphWalkOpts) *Con
 		PlanTimestamp:           opts.PlanTimeTimestamp,
 		Encryption:              c.encryption,
 		ProviderFunctionTracker: opts.ProviderFunctionTracker,
+		Hooks:                   opts.Hooks,
 	}
 }
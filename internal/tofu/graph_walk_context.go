@@ -46,6 +46,10 @@ type ContextGraphWalker struct {
 	Encryption              encryption.Encryption
 	ProviderFunctionTracker ProviderFunctionMapping
 
+	// Hooks are called in addition to the hooks of the Context, for hooks
+	// that are only relevant to a single graph walk.
+	Hooks []Hook
+
 	// This is an output. Do not set this, nor read it while a graph walk
 	// is in progress.
 	NonFatalDiagnostics tfdiags.Diagnostics
@@ -96,9 +100,19 @@ func (w *ContextGraphWalker) EvalContext() EvalContext {
 		PlanTimestamp:      w.PlanTimestamp,
 	}
 
+	hooks := w.Context.hooks
+	if len(w.Hooks) != 0 {
+		// The hooks of the walk go just before the stop hook, which is
+		// always the last hook of the Context, because once the walk is
+		// being stopped the stop hook halts each event before any hooks
+		// after it can observe it.
+		last := len(hooks) - 1
+		hooks = append(append(append([]Hook(nil), hooks[:last]...), w.Hooks...), hooks[last:]...)
+	}
+
 	ctx := &BuiltinEvalContext{
 		StopContext:             w.StopContext,
-		Hooks:                   w.Context.hooks,
+		Hooks:                   hooks,
 		InputValue:              w.Context.uiInput,
 		InstanceExpanderValue:   w.InstanceExpander,
 		Plugins:                 w.Context.plugins,
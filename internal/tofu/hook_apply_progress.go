@@ -0,0 +1,107 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"sync"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+// applyProgressHook is a private Hook implementation that OpenTofu uses to
+// record which of the planned resource instance changes were completed
+// during an apply walk, so that an apply that failed partway through can be
+// resumed later.
+type applyProgressHook struct {
+	NilHook
+
+	// expected and order are recorded from the planned changes before the
+	// walk starts, because the apply walk removes each change from the plan
+	// once it has been applied.
+	expected addrs.Map[addrs.AbsResourceInstance, int]
+	order    []addrs.AbsResourceInstance
+
+	mu        sync.Mutex
+	succeeded addrs.Map[addrs.AbsResourceInstance, int]
+	failed    addrs.Set[addrs.AbsResourceInstance]
+}
+
+var _ Hook = (*applyProgressHook)(nil)
+
+func newApplyProgressHook(changes *plans.Changes) *applyProgressHook {
+	h := &applyProgressHook{
+		expected:  addrs.MakeMap[addrs.AbsResourceInstance, int](),
+		succeeded: addrs.MakeMap[addrs.AbsResourceInstance, int](),
+		failed:    addrs.MakeSet[addrs.AbsResourceInstance](),
+	}
+	for _, rc := range changes.Resources {
+		n := applyOperationCount(rc.Action)
+		if n == 0 {
+			continue
+		}
+		if !h.expected.Has(rc.Addr) {
+			h.order = append(h.order, rc.Addr)
+		}
+		h.expected.Put(rc.Addr, h.expected.Get(rc.Addr)+n)
+	}
+	return h
+}
+
+func (h *applyProgressHook) PostApply(addr addrs.AbsResourceInstance, gen states.Generation, newState cty.Value, err error) (HookAction, error) {
+	if addr.Resource.Resource.Mode != addrs.ManagedResourceMode {
+		return HookActionContinue, nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err != nil {
+		h.failed.Add(addr)
+		return HookActionContinue, nil
+	}
+	h.succeeded.Put(addr, h.succeeded.Get(addr)+1)
+	return HookActionContinue, nil
+}
+
+// Completed returns the addresses of the resource instances whose planned
+// changes were all applied successfully.
+//
+// A resource instance may have more than one planned change, one for its
+// current object and one for each of its deposed objects, and the replace
+// actions consist of two separate operations, so a resource instance is
+// completed only once every one of those operations has succeeded.
+func (h *applyProgressHook) Completed() []addrs.AbsResourceInstance {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var ret []addrs.AbsResourceInstance
+	for _, addr := range h.order {
+		if h.failed.Has(addr) || h.succeeded.Get(addr) < h.expected.Get(addr) {
+			continue
+		}
+		ret = append(ret, addr)
+	}
+	return ret
+}
+
+// applyOperationCount returns the number of operations, each reported to the
+// PostApply hook, that are needed to apply a change with the given action.
+func applyOperationCount(action plans.Action) int {
+	switch {
+	case action.IsReplace():
+		return 2
+	case action == plans.Create, action == plans.Update, action == plans.Delete, action == plans.ForgetThenCreate:
+		return 1
+	default:
+		// NoOp, Read and Forget changes are not applied by a provider, and
+		// so they can be repeated safely when resuming.
+		return 0
+	}
+}
@@ -104,6 +104,9 @@ func (n *NodeApplyableProvider) Execute(ctx context.Context, evalCtx EvalContext
 		log.Printf("[TRACE] NodeApplyableProvider: validating configuration for %s", n.Addr)
 		instance, newDiags := evalCtx.Providers().NewProvider(ctx, n.Addr.Provider)
 		if !newDiags.HasErrors() { // We can't validate if we didn't successfully start the provider plugin
+			if n.Config != nil && n.Config.IsMocked {
+				instance = newProviderWithMockFunctions(instance, n.Config.MockFunctions)
+			}
 			n.instances[addrs.NoKey] = instance
 			for key, data := range instanceData {
 				diags = diags.Append(n.ValidateProvider(ctx, evalCtx, instance, key, data))
@@ -206,6 +209,9 @@ func (n *NodeApplyableProvider) ConfigureProvider(ctx context.Context, evalCtx E
 	if n.Config != nil && n.Config.IsMocked {
 		// Mocked for testing
 		instance, diags := evalCtx.Providers().NewProvider(ctx, n.Addr.Provider)
+		if !diags.HasErrors() {
+			instance = newProviderWithMockFunctions(instance, n.Config.MockFunctions)
+		}
 		n.instances[providerKey] = instance
 		return diags
 	}
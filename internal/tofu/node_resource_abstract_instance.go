@@ -612,6 +612,9 @@ func (n *NodeAbstractResourceInstance) checkPreventDestroy(ctx context.Context,
 				n.Addr.String(), n.Addr.ContainingResource().String(),
 			),
 			Subject: &n.Config.DeclRange,
+			Extra: &addrs.PreventDestroyDiagnosticExtra{
+				Resource: n.Addr,
+			},
 		})
 	}
 	return diags
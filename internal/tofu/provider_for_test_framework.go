@@ -9,10 +9,13 @@ import (
 	"context"
 	"fmt"
 	"hash/fnv"
+	"maps"
 
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/configs/hcl2shim"
 	"github.com/opentofu/opentofu/internal/providers"
@@ -211,6 +214,84 @@ func (p providerForTest) Close(ctx context.Context) error {
 	return p.internal.Close(ctx)
 }
 
+var _ providers.Interface = providerWithMockFunctions{}
+
+// providerWithMockFunctions is a wrapper around a mocked provider that answers the calls to the
+// provider-defined functions mocked by the mock_function blocks of the mock_provider block. It is
+// used by [NodeApplyableProvider] for the instances of mocked providers, which are never configured,
+// so that configurations calling provider-defined functions can be tested with mock providers.
+type providerWithMockFunctions struct {
+	// Unlike providerForTest, this wrapper only changes the behaviour of the function calls,
+	// so everything else is delegated to the embedded provider.
+	providers.Interface
+
+	functions map[string]*configs.MockFunction
+}
+
+// newProviderWithMockFunctions returns the given provider unchanged if no functions are mocked.
+func newProviderWithMockFunctions(internal providers.Interface, functions []*configs.MockFunction) providers.Interface {
+	if len(functions) == 0 {
+		return internal
+	}
+
+	p := providerWithMockFunctions{
+		Interface: internal,
+		functions: make(map[string]*configs.MockFunction, len(functions)),
+	}
+	for _, fn := range functions {
+		p.functions[fn.Name] = fn
+	}
+	return p
+}
+
+// GetProviderSchema adds the mocked functions to the functions declared by the provider. A mocked
+// function keeps the signature declared by the provider, so that its calls are still validated,
+// while a mocked function that the provider doesn't declare accepts any arguments.
+func (p providerWithMockFunctions) GetProviderSchema(ctx context.Context) providers.GetProviderSchemaResponse {
+	schema := p.Interface.GetProviderSchema(ctx)
+
+	// The schema may be shared with other callers, so we must not modify its functions in place.
+	functions := make(map[string]providers.FunctionSpec, len(schema.Functions)+len(p.functions))
+	maps.Copy(functions, schema.Functions)
+	for name, fn := range p.functions {
+		if _, ok := functions[name]; ok {
+			continue
+		}
+		functions[name] = providers.FunctionSpec{
+			VariadicParameter: &providers.FunctionParameterSpec{
+				Name:           "args",
+				Type:           cty.DynamicPseudoType,
+				AllowNullValue: true,
+			},
+			Return:  fn.Returns.Type(),
+			Summary: "Mocked function",
+		}
+	}
+	schema.Functions = functions
+
+	return schema
+}
+
+func (p providerWithMockFunctions) CallFunction(ctx context.Context, r providers.CallFunctionRequest) providers.CallFunctionResponse {
+	fn, ok := p.functions[r.Name]
+	if !ok {
+		return p.Interface.CallFunction(ctx, r)
+	}
+
+	result := fn.Returns
+	if spec, ok := p.Interface.GetProviderSchema(ctx).Functions[r.Name]; ok {
+		var err error
+		result, err = convert.Convert(result, spec.Return)
+		if err != nil {
+			return providers.CallFunctionResponse{
+				Error: fmt.Errorf("the value returned by the mock of %q doesn't match the return type of the function: %w", r.Name, err),
+			}
+		}
+	}
+
+	return providers.CallFunctionResponse{Result: result}
+}
+
 func newMockValueComposer(typeName string) hcl2shim.MockValueComposer {
 	hash := fnv.New32()
 	hash.Write([]byte(typeName))
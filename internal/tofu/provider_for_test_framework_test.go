@@ -9,6 +9,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/zclconf/go-cty/cty"
@@ -155,3 +156,81 @@ func TestFilterComputedOnlyAttributes(t *testing.T) {
 		})
 	}
 }
+
+func TestProviderWithMockFunctions(t *testing.T) {
+	mockProvider := &MockProvider{
+		GetProviderSchemaResponse: &providers.GetProviderSchemaResponse{
+			Functions: map[string]providers.FunctionSpec{
+				"declared": {
+					Parameters: []providers.FunctionParameterSpec{{Name: "input", Type: cty.String}},
+					Return:     cty.String,
+				},
+				"list": {
+					Return: cty.List(cty.String),
+				},
+				"real": {
+					Return: cty.String,
+				},
+			},
+		},
+		CallFunctionResponse: &providers.CallFunctionResponse{
+			Result: cty.StringVal("real"),
+		},
+	}
+
+	provider := newProviderWithMockFunctions(mockProvider, []*configs.MockFunction{
+		{Name: "declared", Returns: cty.NumberIntVal(1)},
+		{Name: "list", Returns: cty.StringVal("invalid")},
+		{Name: "undeclared", Returns: cty.True},
+	})
+
+	schema := provider.GetProviderSchema(t.Context())
+	if got := schema.Functions["declared"].Parameters; len(got) != 1 {
+		t.Errorf("expected the declared function to keep its parameters, got %#v", got)
+	}
+	if got := schema.Functions["undeclared"].Return; !got.Equals(cty.Bool) {
+		t.Errorf("wrong return type for the undeclared function %#v", got)
+	}
+	if _, ok := mockProvider.GetProviderSchemaResponse.Functions["undeclared"]; ok {
+		t.Errorf("expected the schema of the provider not to be modified")
+	}
+
+	tcs := map[string]struct {
+		want    cty.Value
+		wantErr string
+	}{
+		"declared": {
+			want: cty.StringVal("1"),
+		},
+		"list": {
+			wantErr: "doesn't match the return type",
+		},
+		"undeclared": {
+			want: cty.True,
+		},
+		"real": {
+			want: cty.StringVal("real"),
+		},
+	}
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			resp := provider.CallFunction(t.Context(), providers.CallFunctionRequest{Name: name})
+			if tc.wantErr != "" {
+				if resp.Error == nil || !strings.Contains(resp.Error.Error(), tc.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", tc.wantErr, resp.Error)
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+			if !resp.Result.RawEquals(tc.want) {
+				t.Errorf("wrong result %#v; want %#v", resp.Result, tc.want)
+			}
+		})
+	}
+
+	if provider := newProviderWithMockFunctions(mockProvider, nil); provider != mockProvider {
+		t.Errorf("expected the provider to be returned unchanged without mocked functions")
+	}
+}
@@ -0,0 +1,15 @@
+terraform {
+  required_providers {
+    aws = {
+      source = "hashicorp/aws"
+    }
+  }
+}
+
+variable "bucket_arn" {
+  type = string
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "${provider::aws::arn_parse(var.bucket_arn).resource}-logs"
+}
@@ -0,0 +1,24 @@
+mock_provider "aws" {
+  mock_function "arn_parse" {
+    returns = {
+      partition  = "aws"
+      service    = "s3"
+      region     = ""
+      account_id = ""
+      resource   = "my-bucket"
+    }
+  }
+}
+
+run "logs_bucket" {
+  command = plan
+
+  variables {
+    bucket_arn = "arn:aws:s3:::my-bucket"
+  }
+
+  assert {
+    condition     = aws_s3_bucket.logs.bucket == "my-bucket-logs"
+    error_message = "The logs bucket name is incorrect"
+  }
+}
@@ -0,0 +1,27 @@
+terraform {
+  encryption {
+    key_provider "age" "my_age" {
+      # The data key is encrypted to all recipients. Any of them can
+      # decrypt the state or plan.
+      recipients = [
+        "age1st34jtyu7tpt98jpg6dn8c3wf75kltrjcpmw5ky7wtevjdkvkeaqvds0na",
+        "age1u9dptv09v7mayd2j6ljk8nwz5dfqntwaa7hqs5l9v8pm34a2hptq3u3ml7",
+      ]
+
+      # Secret keys used to decrypt. Leave this out on machines
+      # that should only be able to write encrypted data.
+      identities = [var.age_identity]
+    }
+    method "aes_gcm" "my_method" {
+      keys = key_provider.age.my_age
+    }
+    state {
+      method = method.aes_gcm.my_method
+    }
+  }
+}
+
+variable "age_identity" {
+  type      = string
+  sensitive = true
+}
\ No newline at end of file
@@ -0,0 +1,28 @@
+terraform {
+  encryption {
+    # Key provider and method configuration here
+
+    backend_config {
+      # Encryption/decryption for the backend configuration in .terraform/terraform.tfstate
+      method = method.method_type.my_method_name
+      fallback {
+        # Read the backend configuration written before encryption was enabled
+        method = method.unencrypted.migrate
+      }
+    }
+
+    module_manifest {
+      # Encryption/decryption for the module manifest in .terraform/modules/modules.json
+      method = method.method_type.my_method_name
+      fallback {
+        # Read the module manifest written before encryption was enabled
+        method = method.unencrypted.migrate
+      }
+    }
+
+    state_pull {
+      # Encryption of the output of "tofu state pull", decrypted by "tofu state push"
+      method = method.method_type.my_method_name
+    }
+  }
+}
\ No newline at end of file
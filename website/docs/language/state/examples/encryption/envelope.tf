@@ -0,0 +1,23 @@
+terraform {
+  encryption {
+    key_provider "aws_kms" "main" {
+      # AWS KMS options here
+    }
+
+    key_provider "pbkdf2" "break_glass" {
+      passphrase = var.break_glass_passphrase
+    }
+
+    method "envelope" "yourname" {
+      # Any one of these key providers can decrypt the data on its own:
+      keys = [
+        key_provider.aws_kms.main,
+        key_provider.pbkdf2.break_glass,
+      ]
+    }
+
+    state {
+      method = method.envelope.yourname
+    }
+  }
+}
\ No newline at end of file
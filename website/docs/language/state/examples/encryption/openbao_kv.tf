@@ -0,0 +1,24 @@
+terraform {
+  encryption {
+    key_provider "openbao" "my_bao" {
+      # Required. Path of the secret within the KV engine.
+      key_name = "tofu/state-key"
+
+      # Read the key from a KV version 2 secret instead of
+      # generating data keys with the transit engine.
+      key_source = "kv"
+
+      # Optional. Path at which the KV engine is mounted. Default: /secret
+      kv_engine_path = "/secret"
+
+      # Optional. Field holding the base64-encoded key. Default: key
+      kv_key_field = "key"
+    }
+    method "aes_gcm" "my_method" {
+      keys = key_provider.openbao.my_bao
+    }
+    state {
+      method = method.aes_gcm.my_method
+    }
+  }
+}
@@ -0,0 +1,28 @@
+terraform {
+  encryption {
+    key_provider "pkcs11" "my_hsm" {
+      # Path to the PKCS#11 library of the token vendor.
+      module = "/usr/lib/softhsm/libsofthsm2.so"
+
+      # Label of the token holding the key.
+      token_label = "tofu"
+
+      # User PIN to log in to the token.
+      pin = var.pkcs11_pin
+
+      # Label of the AES key on the token.
+      key_label = "tofu-state"
+    }
+    method "aes_gcm" "my_method" {
+      keys = key_provider.pkcs11.my_hsm
+    }
+    state {
+      method = method.aes_gcm.my_method
+    }
+  }
+}
+
+variable "pkcs11_pin" {
+  type      = string
+  sensitive = true
+}
\ No newline at end of file
@@ -0,0 +1,17 @@
+terraform {
+  encryption {
+    key_provider "pbkdf2" "my_passphrase" {
+      passphrase = var.passphrase
+    }
+
+    method "aes_gcm" "my_method" {
+      keys = key_provider.pbkdf2.my_passphrase
+    }
+
+    state {
+      # Only encrypt sensitive attributes and outputs
+      mode   = "sensitive_attributes"
+      method = method.aes_gcm.my_method
+    }
+  }
+}
@@ -0,0 +1,30 @@
+terraform {
+  encryption {
+    # Key provider and method configuration here
+
+    remote_state_data_sources {
+      # Refuse to read unencrypted remote state...
+      enforced = true
+
+      # ...except for these data sources.
+      allow_unencrypted = ["legacy_network"]
+
+      default {
+        method = method.method_type.my_method_name
+      }
+      remote_state_data_source "my_state" {
+        # You can also enforce encryption for individual data sources.
+        enforced = true
+        method   = method.method_type.my_other_method_name
+      }
+    }
+  }
+}
+
+data "terraform_remote_state" "my_state" {
+  # ...
+}
+
+data "terraform_remote_state" "legacy_network" {
+  # ...
+}
\ No newline at end of file